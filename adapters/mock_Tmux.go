@@ -0,0 +1,641 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package adapters
+
+import (
+	models "github.com/garrettkrohn/treekanga/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockTmux is an autogenerated mock type for the Tmux type
+type MockTmux struct {
+	mock.Mock
+}
+
+type MockTmux_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTmux) EXPECT() *MockTmux_Expecter {
+	return &MockTmux_Expecter{mock: &_m.Mock}
+}
+
+// AttachSession provides a mock function with given fields: targetSession
+func (_m *MockTmux) AttachSession(targetSession string) error {
+	ret := _m.Called(targetSession)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(targetSession)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTmux_AttachSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AttachSession'
+type MockTmux_AttachSession_Call struct {
+	*mock.Call
+}
+
+// AttachSession is a helper method to define mock.On call
+//   - targetSession string
+func (_e *MockTmux_Expecter) AttachSession(targetSession interface{}) *MockTmux_AttachSession_Call {
+	return &MockTmux_AttachSession_Call{Call: _e.mock.On("AttachSession", targetSession)}
+}
+
+func (_c *MockTmux_AttachSession_Call) Run(run func(targetSession string)) *MockTmux_AttachSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockTmux_AttachSession_Call) Return(_a0 error) *MockTmux_AttachSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTmux_AttachSession_Call) RunAndReturn(run func(string) error) *MockTmux_AttachSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ChangeDirectory provides a mock function with given fields: paneID, dir
+func (_m *MockTmux) ChangeDirectory(paneID string, dir string) error {
+	ret := _m.Called(paneID, dir)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeDirectory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(paneID, dir)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTmux_ChangeDirectory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangeDirectory'
+type MockTmux_ChangeDirectory_Call struct {
+	*mock.Call
+}
+
+// ChangeDirectory is a helper method to define mock.On call
+//   - paneID string
+//   - dir string
+func (_e *MockTmux_Expecter) ChangeDirectory(paneID interface{}, dir interface{}) *MockTmux_ChangeDirectory_Call {
+	return &MockTmux_ChangeDirectory_Call{Call: _e.mock.On("ChangeDirectory", paneID, dir)}
+}
+
+func (_c *MockTmux_ChangeDirectory_Call) Run(run func(paneID string, dir string)) *MockTmux_ChangeDirectory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockTmux_ChangeDirectory_Call) Return(_a0 error) *MockTmux_ChangeDirectory_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTmux_ChangeDirectory_Call) RunAndReturn(run func(string, string) error) *MockTmux_ChangeDirectory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindSession provides a mock function with given fields: name
+func (_m *MockTmux) FindSession(name string) (models.Session, bool) {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSession")
+	}
+
+	var r0 models.Session
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string) (models.Session, bool)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) models.Session); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(models.Session)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// MockTmux_FindSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindSession'
+type MockTmux_FindSession_Call struct {
+	*mock.Call
+}
+
+// FindSession is a helper method to define mock.On call
+//   - name string
+func (_e *MockTmux_Expecter) FindSession(name interface{}) *MockTmux_FindSession_Call {
+	return &MockTmux_FindSession_Call{Call: _e.mock.On("FindSession", name)}
+}
+
+func (_c *MockTmux_FindSession_Call) Run(run func(name string)) *MockTmux_FindSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockTmux_FindSession_Call) Return(_a0 models.Session, _a1 bool) *MockTmux_FindSession_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTmux_FindSession_Call) RunAndReturn(run func(string) (models.Session, bool)) *MockTmux_FindSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCurrentSessionName provides a mock function with no fields
+func (_m *MockTmux) GetCurrentSessionName() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCurrentSessionName")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTmux_GetCurrentSessionName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCurrentSessionName'
+type MockTmux_GetCurrentSessionName_Call struct {
+	*mock.Call
+}
+
+// GetCurrentSessionName is a helper method to define mock.On call
+func (_e *MockTmux_Expecter) GetCurrentSessionName() *MockTmux_GetCurrentSessionName_Call {
+	return &MockTmux_GetCurrentSessionName_Call{Call: _e.mock.On("GetCurrentSessionName")}
+}
+
+func (_c *MockTmux_GetCurrentSessionName_Call) Run(run func()) *MockTmux_GetCurrentSessionName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockTmux_GetCurrentSessionName_Call) Return(_a0 string, _a1 error) *MockTmux_GetCurrentSessionName_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTmux_GetCurrentSessionName_Call) RunAndReturn(run func() (string, error)) *MockTmux_GetCurrentSessionName_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsAttached provides a mock function with no fields
+func (_m *MockTmux) IsAttached() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsAttached")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockTmux_IsAttached_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsAttached'
+type MockTmux_IsAttached_Call struct {
+	*mock.Call
+}
+
+// IsAttached is a helper method to define mock.On call
+func (_e *MockTmux_Expecter) IsAttached() *MockTmux_IsAttached_Call {
+	return &MockTmux_IsAttached_Call{Call: _e.mock.On("IsAttached")}
+}
+
+func (_c *MockTmux_IsAttached_Call) Run(run func()) *MockTmux_IsAttached_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockTmux_IsAttached_Call) Return(_a0 bool) *MockTmux_IsAttached_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTmux_IsAttached_Call) RunAndReturn(run func() bool) *MockTmux_IsAttached_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// KillSession provides a mock function with given fields: sessionName
+func (_m *MockTmux) KillSession(sessionName string) error {
+	ret := _m.Called(sessionName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for KillSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(sessionName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTmux_KillSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'KillSession'
+type MockTmux_KillSession_Call struct {
+	*mock.Call
+}
+
+// KillSession is a helper method to define mock.On call
+//   - sessionName string
+func (_e *MockTmux_Expecter) KillSession(sessionName interface{}) *MockTmux_KillSession_Call {
+	return &MockTmux_KillSession_Call{Call: _e.mock.On("KillSession", sessionName)}
+}
+
+func (_c *MockTmux_KillSession_Call) Run(run func(sessionName string)) *MockTmux_KillSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockTmux_KillSession_Call) Return(_a0 error) *MockTmux_KillSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTmux_KillSession_Call) RunAndReturn(run func(string) error) *MockTmux_KillSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPanes provides a mock function with no fields
+func (_m *MockTmux) ListPanes() ([]models.Pane, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPanes")
+	}
+
+	var r0 []models.Pane
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]models.Pane, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []models.Pane); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Pane)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTmux_ListPanes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPanes'
+type MockTmux_ListPanes_Call struct {
+	*mock.Call
+}
+
+// ListPanes is a helper method to define mock.On call
+func (_e *MockTmux_Expecter) ListPanes() *MockTmux_ListPanes_Call {
+	return &MockTmux_ListPanes_Call{Call: _e.mock.On("ListPanes")}
+}
+
+func (_c *MockTmux_ListPanes_Call) Run(run func()) *MockTmux_ListPanes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockTmux_ListPanes_Call) Return(_a0 []models.Pane, _a1 error) *MockTmux_ListPanes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTmux_ListPanes_Call) RunAndReturn(run func() ([]models.Pane, error)) *MockTmux_ListPanes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSessions provides a mock function with no fields
+func (_m *MockTmux) ListSessions() ([]models.Session, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessions")
+	}
+
+	var r0 []models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]models.Session, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []models.Session); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTmux_ListSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSessions'
+type MockTmux_ListSessions_Call struct {
+	*mock.Call
+}
+
+// ListSessions is a helper method to define mock.On call
+func (_e *MockTmux_Expecter) ListSessions() *MockTmux_ListSessions_Call {
+	return &MockTmux_ListSessions_Call{Call: _e.mock.On("ListSessions")}
+}
+
+func (_c *MockTmux_ListSessions_Call) Run(run func()) *MockTmux_ListSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockTmux_ListSessions_Call) Return(_a0 []models.Session, _a1 error) *MockTmux_ListSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTmux_ListSessions_Call) RunAndReturn(run func() ([]models.Session, error)) *MockTmux_ListSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListSessionsByRecency provides a mock function with no fields
+func (_m *MockTmux) ListSessionsByRecency() ([]models.Session, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessionsByRecency")
+	}
+
+	var r0 []models.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]models.Session, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []models.Session); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTmux_ListSessionsByRecency_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListSessionsByRecency'
+type MockTmux_ListSessionsByRecency_Call struct {
+	*mock.Call
+}
+
+// ListSessionsByRecency is a helper method to define mock.On call
+func (_e *MockTmux_Expecter) ListSessionsByRecency() *MockTmux_ListSessionsByRecency_Call {
+	return &MockTmux_ListSessionsByRecency_Call{Call: _e.mock.On("ListSessionsByRecency")}
+}
+
+func (_c *MockTmux_ListSessionsByRecency_Call) Run(run func()) *MockTmux_ListSessionsByRecency_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockTmux_ListSessionsByRecency_Call) Return(_a0 []models.Session, _a1 error) *MockTmux_ListSessionsByRecency_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTmux_ListSessionsByRecency_Call) RunAndReturn(run func() ([]models.Session, error)) *MockTmux_ListSessionsByRecency_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSession provides a mock function with given fields: sessionName, startDir
+func (_m *MockTmux) NewSession(sessionName string, startDir string) error {
+	ret := _m.Called(sessionName, startDir)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(sessionName, startDir)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTmux_NewSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewSession'
+type MockTmux_NewSession_Call struct {
+	*mock.Call
+}
+
+// NewSession is a helper method to define mock.On call
+//   - sessionName string
+//   - startDir string
+func (_e *MockTmux_Expecter) NewSession(sessionName interface{}, startDir interface{}) *MockTmux_NewSession_Call {
+	return &MockTmux_NewSession_Call{Call: _e.mock.On("NewSession", sessionName, startDir)}
+}
+
+func (_c *MockTmux_NewSession_Call) Run(run func(sessionName string, startDir string)) *MockTmux_NewSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockTmux_NewSession_Call) Return(_a0 error) *MockTmux_NewSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTmux_NewSession_Call) RunAndReturn(run func(string, string) error) *MockTmux_NewSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SwitchClient provides a mock function with given fields: targetSession
+func (_m *MockTmux) SwitchClient(targetSession string) error {
+	ret := _m.Called(targetSession)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SwitchClient")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(targetSession)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTmux_SwitchClient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SwitchClient'
+type MockTmux_SwitchClient_Call struct {
+	*mock.Call
+}
+
+// SwitchClient is a helper method to define mock.On call
+//   - targetSession string
+func (_e *MockTmux_Expecter) SwitchClient(targetSession interface{}) *MockTmux_SwitchClient_Call {
+	return &MockTmux_SwitchClient_Call{Call: _e.mock.On("SwitchClient", targetSession)}
+}
+
+func (_c *MockTmux_SwitchClient_Call) Run(run func(targetSession string)) *MockTmux_SwitchClient_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockTmux_SwitchClient_Call) Return(_a0 error) *MockTmux_SwitchClient_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTmux_SwitchClient_Call) RunAndReturn(run func(string) error) *MockTmux_SwitchClient_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SwitchOrAttach provides a mock function with given fields: name, opts
+func (_m *MockTmux) SwitchOrAttach(name string, opts models.ConnectOpts) error {
+	ret := _m.Called(name, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SwitchOrAttach")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, models.ConnectOpts) error); ok {
+		r0 = rf(name, opts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTmux_SwitchOrAttach_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SwitchOrAttach'
+type MockTmux_SwitchOrAttach_Call struct {
+	*mock.Call
+}
+
+// SwitchOrAttach is a helper method to define mock.On call
+//   - name string
+//   - opts models.ConnectOpts
+func (_e *MockTmux_Expecter) SwitchOrAttach(name interface{}, opts interface{}) *MockTmux_SwitchOrAttach_Call {
+	return &MockTmux_SwitchOrAttach_Call{Call: _e.mock.On("SwitchOrAttach", name, opts)}
+}
+
+func (_c *MockTmux_SwitchOrAttach_Call) Run(run func(name string, opts models.ConnectOpts)) *MockTmux_SwitchOrAttach_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(models.ConnectOpts))
+	})
+	return _c
+}
+
+func (_c *MockTmux_SwitchOrAttach_Call) Return(_a0 error) *MockTmux_SwitchOrAttach_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTmux_SwitchOrAttach_Call) RunAndReturn(run func(string, models.ConnectOpts) error) *MockTmux_SwitchOrAttach_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTmux creates a new instance of MockTmux. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTmux(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTmux {
+	mock := &MockTmux{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,299 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package adapters
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockZoxide is an autogenerated mock type for the Zoxide type
+type MockZoxide struct {
+	mock.Mock
+}
+
+type MockZoxide_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockZoxide) EXPECT() *MockZoxide_Expecter {
+	return &MockZoxide_Expecter{mock: &_m.Mock}
+}
+
+// Add provides a mock function with given fields: paths
+func (_m *MockZoxide) Add(paths ...string) error {
+	_va := make([]interface{}, len(paths))
+	for _i := range paths {
+		_va[_i] = paths[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(...string) error); ok {
+		r0 = rf(paths...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockZoxide_Add_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Add'
+type MockZoxide_Add_Call struct {
+	*mock.Call
+}
+
+// Add is a helper method to define mock.On call
+//   - paths ...string
+func (_e *MockZoxide_Expecter) Add(paths ...interface{}) *MockZoxide_Add_Call {
+	return &MockZoxide_Add_Call{Call: _e.mock.On("Add",
+		append([]interface{}{}, paths...)...)}
+}
+
+func (_c *MockZoxide_Add_Call) Run(run func(paths ...string)) *MockZoxide_Add_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockZoxide_Add_Call) Return(_a0 error) *MockZoxide_Add_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockZoxide_Add_Call) RunAndReturn(run func(...string) error) *MockZoxide_Add_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddWithScore provides a mock function with given fields: path, score
+func (_m *MockZoxide) AddWithScore(path string, score float64) error {
+	ret := _m.Called(path, score)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddWithScore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, float64) error); ok {
+		r0 = rf(path, score)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockZoxide_AddWithScore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddWithScore'
+type MockZoxide_AddWithScore_Call struct {
+	*mock.Call
+}
+
+// AddWithScore is a helper method to define mock.On call
+//   - path string
+//   - score float64
+func (_e *MockZoxide_Expecter) AddWithScore(path interface{}, score interface{}) *MockZoxide_AddWithScore_Call {
+	return &MockZoxide_AddWithScore_Call{Call: _e.mock.On("AddWithScore", path, score)}
+}
+
+func (_c *MockZoxide_AddWithScore_Call) Run(run func(path string, score float64)) *MockZoxide_AddWithScore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(float64))
+	})
+	return _c
+}
+
+func (_c *MockZoxide_AddWithScore_Call) Return(_a0 error) *MockZoxide_AddWithScore_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockZoxide_AddWithScore_Call) RunAndReturn(run func(string, float64) error) *MockZoxide_AddWithScore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Available provides a mock function with no fields
+func (_m *MockZoxide) Available() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Available")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockZoxide_Available_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Available'
+type MockZoxide_Available_Call struct {
+	*mock.Call
+}
+
+// Available is a helper method to define mock.On call
+func (_e *MockZoxide_Expecter) Available() *MockZoxide_Available_Call {
+	return &MockZoxide_Available_Call{Call: _e.mock.On("Available")}
+}
+
+func (_c *MockZoxide_Available_Call) Run(run func()) *MockZoxide_Available_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockZoxide_Available_Call) Return(_a0 bool) *MockZoxide_Available_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockZoxide_Available_Call) RunAndReturn(run func() bool) *MockZoxide_Available_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Remove provides a mock function with given fields: paths
+func (_m *MockZoxide) Remove(paths ...string) error {
+	_va := make([]interface{}, len(paths))
+	for _i := range paths {
+		_va[_i] = paths[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(...string) error); ok {
+		r0 = rf(paths...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockZoxide_Remove_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Remove'
+type MockZoxide_Remove_Call struct {
+	*mock.Call
+}
+
+// Remove is a helper method to define mock.On call
+//   - paths ...string
+func (_e *MockZoxide_Expecter) Remove(paths ...interface{}) *MockZoxide_Remove_Call {
+	return &MockZoxide_Remove_Call{Call: _e.mock.On("Remove",
+		append([]interface{}{}, paths...)...)}
+}
+
+func (_c *MockZoxide_Remove_Call) Run(run func(paths ...string)) *MockZoxide_Remove_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-0)
+		for i, a := range args[0:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockZoxide_Remove_Call) Return(_a0 error) *MockZoxide_Remove_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockZoxide_Remove_Call) RunAndReturn(run func(...string) error) *MockZoxide_Remove_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Scores provides a mock function with no fields
+func (_m *MockZoxide) Scores() (map[string]float64, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Scores")
+	}
+
+	var r0 map[string]float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (map[string]float64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() map[string]float64); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]float64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockZoxide_Scores_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Scores'
+type MockZoxide_Scores_Call struct {
+	*mock.Call
+}
+
+// Scores is a helper method to define mock.On call
+func (_e *MockZoxide_Expecter) Scores() *MockZoxide_Scores_Call {
+	return &MockZoxide_Scores_Call{Call: _e.mock.On("Scores")}
+}
+
+func (_c *MockZoxide_Scores_Call) Run(run func()) *MockZoxide_Scores_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockZoxide_Scores_Call) Return(_a0 map[string]float64, _a1 error) *MockZoxide_Scores_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockZoxide_Scores_Call) RunAndReturn(run func() (map[string]float64, error)) *MockZoxide_Scores_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockZoxide creates a new instance of MockZoxide. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockZoxide(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockZoxide {
+	mock := &MockZoxide{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,6 +2,8 @@ package adapters
 
 import (
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/garrettkrohn/treekanga/models"
@@ -10,6 +12,7 @@ import (
 
 type Tmux interface {
 	ListSessions() ([]models.Session, error)
+	ListSessionsByRecency() ([]models.Session, error)
 	NewSession(sessionName string, startDir string) error
 	IsAttached() bool
 	AttachSession(targetSession string) error
@@ -18,6 +21,8 @@ type Tmux interface {
 	FindSession(name string) (models.Session, bool)
 	KillSession(sessionName string) error
 	GetCurrentSessionName() (string, error)
+	ListPanes() ([]models.Pane, error)
+	ChangeDirectory(paneID string, dir string) error
 }
 
 type RealTmux struct {
@@ -58,6 +63,42 @@ func (t *RealTmux) ListSessions() ([]models.Session, error) {
 	return sessions, nil
 }
 
+// ListSessionsByRecency lists the sessions most recently attached first.
+func (t *RealTmux) ListSessionsByRecency() ([]models.Session, error) {
+	output, err := t.shell.Cmd("tmux", "list-sessions", "-F", "#{session_last_attached}:#{session_name}:#{session_path}")
+	if err != nil {
+		if strings.Contains(err.Error(), "no server running") {
+			return []models.Session{}, nil
+		}
+		return nil, err
+	}
+
+	type session struct {
+		models.Session
+		lastAttached int64
+	}
+	var sessions []session
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		// Sessions never attached to have no time
+		lastAttached, _ := strconv.ParseInt(parts[0], 10, 64)
+		sessions = append(sessions, session{
+			Session:      models.Session{Name: parts[1], Path: parts[2], Src: "tmux"},
+			lastAttached: lastAttached,
+		})
+	}
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].lastAttached > sessions[j].lastAttached })
+
+	result := make([]models.Session, len(sessions))
+	for i, s := range sessions {
+		result[i] = s.Session
+	}
+	return result, nil
+}
+
 func (t *RealTmux) FindSession(name string) (models.Session, bool) {
 	sessions, err := t.ListSessions()
 	if err != nil {
@@ -111,3 +152,31 @@ func (t *RealTmux) GetCurrentSessionName() (string, error) {
 	}
 	return strings.TrimSpace(output), nil
 }
+
+// ListPanes lists the panes of every session.
+func (t *RealTmux) ListPanes() ([]models.Pane, error) {
+	output, err := t.shell.Cmd("tmux", "list-panes", "-a", "-F", "#{pane_id}\t#{session_name}\t#{pane_current_command}\t#{pane_current_path}")
+	if err != nil {
+		if strings.Contains(err.Error(), "no server running") {
+			return []models.Pane{}, nil
+		}
+		return nil, err
+	}
+
+	var panes []models.Pane
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		parts := strings.SplitN(line, "\t", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		panes = append(panes, models.Pane{ID: parts[0], Session: parts[1], Command: parts[2], Path: parts[3]})
+	}
+	return panes, nil
+}
+
+// ChangeDirectory types a cd into dir at the shell prompt of a pane.
+func (t *RealTmux) ChangeDirectory(paneID string, dir string) error {
+	quoted := "'" + strings.ReplaceAll(dir, "'", `'\''`) + "'"
+	_, err := t.shell.Cmd("tmux", "send-keys", "-t", paneID, "cd "+quoted, "Enter")
+	return err
+}
@@ -0,0 +1,80 @@
+package adapters
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/garrettkrohn/treekanga/shell"
+)
+
+// Zoxide keeps zoxide's database in step with the worktrees.
+type Zoxide interface {
+	Available() bool
+	Add(paths ...string) error
+	AddWithScore(path string, score float64) error
+	Remove(paths ...string) error
+	Scores() (map[string]float64, error)
+}
+
+type RealZoxide struct {
+	shell shell.Shell
+}
+
+func NewZoxide(shell shell.Shell) Zoxide {
+	return &RealZoxide{shell}
+}
+
+// Available reports whether zoxide is installed.
+func (z *RealZoxide) Available() bool {
+	_, err := exec.LookPath("zoxide")
+	return err == nil
+}
+
+// Add registers paths, or bumps their score when zoxide knows them already.
+func (z *RealZoxide) Add(paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	_, err := z.shell.Cmd("zoxide", append([]string{"add"}, paths...)...)
+	return err
+}
+
+// AddWithScore registers path with score, or adds score to it when zoxide
+// knows it already.
+func (z *RealZoxide) AddWithScore(path string, score float64) error {
+	_, err := z.shell.Cmd("zoxide", "add", "--score", strconv.FormatFloat(score, 'f', -1, 64), path)
+	return err
+}
+
+// Remove drops paths from the database.
+func (z *RealZoxide) Remove(paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	_, err := z.shell.Cmd("zoxide", append([]string{"remove"}, paths...)...)
+	return err
+}
+
+// Scores returns the score of every path in the database, including the
+// ones that don't exist anymore.
+func (z *RealZoxide) Scores() (map[string]float64, error) {
+	output, err := z.shell.Cmd("zoxide", "query", "--list", "--score", "--all")
+	if err != nil {
+		return nil, err
+	}
+
+	scores := map[string]float64{}
+	for _, line := range strings.Split(output, "\n") {
+		score, path, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(score, 64)
+		if err != nil {
+			continue
+		}
+		scores[strings.TrimSpace(path)] = value
+	}
+	return scores, nil
+}
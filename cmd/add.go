@@ -8,6 +8,7 @@ import (
 
 	"github.com/charmbracelet/log"
 	"github.com/garrettkrohn/treekanga/services"
+	"github.com/garrettkrohn/treekanga/shellinit"
 	util "github.com/garrettkrohn/treekanga/utility"
 
 	"github.com/spf13/cobra"
@@ -26,7 +27,35 @@ var addCmd = &cobra.Command{
     By default, creates a new branch off of the defaultBranch defined in
     the config, or you can specify a base branch with the -b flag.
 
-    Use --remote or --local to explicitly checkout an existing branch.`,
+    Use --remote or --local to explicitly checkout an existing branch.
+
+    Use --ref to start from a tag or commit instead of the base branch:
+    "add hotfix-2.3 --ref v2.3.1" cuts a new branch at v2.3.1, and
+    "add --ref v2.3.1 --detach" checks v2.3.1 out without any branch,
+    in a folder named after the ref.
+
+    Pass several branch names, or --from-file with one "branch [base]"
+    per line, to create many worktrees at once. Fetches run once, the
+    worktrees are created concurrently, and each branch reports its own
+    success or failure.
+
+    Use --issue to name the branch after an issue in the configured
+    issueTracker (jira, github or linear). The issue title is slugified
+    into the repo's branchTemplate, e.g. {{.Type}}/{{.Key}}-{{.Slug}}
+    turns JIRA-123 "Short slug" into feature/JIRA-123-short-slug.
+
+    Use --stack-on to stack the new branch on another local branch:
+    it is cut from that branch, which becomes its base, and is rebased
+    along with it by "treekanga stack restack".
+
+    Use --carry when you started on the wrong worktree: the changes of
+    the worktree you are in, untracked files included, are stashed and
+    popped in the new worktree.
+
+    Adding is all or nothing: if setting the upstream or starting the
+    post script fails, or you hit Ctrl-C, the new worktree, branch and
+    tmux session are removed again and carried changes are put back.
+    Use --keep-on-failure to leave them behind for debugging.`,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		directory, err := cmd.Flags().GetString("directory")
@@ -57,18 +86,25 @@ var addCmd = &cobra.Command{
 			deps.AppConfig.PullBeforeCuttingNewBranch = true
 		}
 
+		openEditor, err := cmd.Flags().GetString("open")
+		util.CheckError(err)
+
+		// --cursor and --vscode are the old spellings of --open
 		cursor, err := cmd.Flags().GetBool("cursor")
 		util.CheckError(err)
 		if cursor {
-			log.Debug("set CursorConnect = true from flags")
-			deps.AppConfig.CursorConnect = true
+			openEditor = "cursor"
 		}
 
 		vscode, err := cmd.Flags().GetBool("vscode")
 		util.CheckError(err)
 		if vscode {
-			log.Debug("set VsCodeConnect = true from flags")
-			deps.AppConfig.VsCodeConnect = true
+			openEditor = "code"
+		}
+
+		if openEditor != "" {
+			log.Debug(fmt.Sprintf("set OpenEditor = %s from flags", openEditor))
+			deps.AppConfig.OpenEditor = openEditor
 		}
 
 		specifiedWorktreeName, err := cmd.Flags().GetString("name")
@@ -105,17 +141,107 @@ var addCmd = &cobra.Command{
 			deps.AppConfig.CheckoutLocal = true
 		}
 
-		cfg := services.SetConfigForAddService(deps.AppConfig, args)
+		issueKey, err := cmd.Flags().GetString("issue")
+		util.CheckError(err)
+		if issueKey != "" {
+			if len(args) > 0 {
+				log.Fatal("--issue derives the branch name, please don't also pass one as an argument")
+			}
+			log.Debug(fmt.Sprintf("set IssueKey = %s from flags", issueKey))
+			deps.AppConfig.IssueKey = issueKey
+
+			issueTracker, err := services.NewTrackerFromConfig(deps.Git, deps.AppConfig)
+			util.CheckError(err)
+			branchName, err := services.BranchNameForIssue(issueTracker, deps.AppConfig, issueKey)
+			util.CheckError(err)
+			args = []string{branchName}
+		}
+
+		ref, err := cmd.Flags().GetString("ref")
+		util.CheckError(err)
+		if ref != "" {
+			if remote || local {
+				log.Fatal("--ref creates a worktree at a tag or commit, it can't be combined with --remote or --local")
+			}
+			log.Debug(fmt.Sprintf("set AddRef = %s from flags", ref))
+			deps.AppConfig.AddRef = ref
+		}
+
+		detach, err := cmd.Flags().GetBool("detach")
+		util.CheckError(err)
+		if detach {
+			if ref == "" {
+				log.Fatal("--detach needs a tag or commit to check out, pass it with --ref")
+			}
+			if issueKey != "" {
+				log.Fatal("--detach doesn't create a branch, it can't be combined with --issue")
+			}
+			log.Debug("set Detach = true from flags")
+			deps.AppConfig.Detach = true
+		}
 
-		services.AddWorktree(deps.Connector, deps.Shell, cfg)
+		stackOn, err := cmd.Flags().GetString("stack-on")
+		util.CheckError(err)
+		if stackOn != "" {
+			if baseBranch != "" || from || pull || remote || local || ref != "" {
+				log.Fatal("--stack-on cuts the branch from its parent, it can't be combined with --base, --from, --pull, --remote, --local or --ref")
+			}
+			log.Debug(fmt.Sprintf("set StackOn = %s from flags", stackOn))
+			deps.AppConfig.StackOn = stackOn
+			deps.AppConfig.BaseBranch = stackOn
+		}
+
+		carry, err := cmd.Flags().GetBool("carry")
+		util.CheckError(err)
+		if carry {
+			log.Debug("set Carry = true from flags")
+			deps.AppConfig.Carry = true
+		}
+
+		keepOnFailure, err := cmd.Flags().GetBool("keep-on-failure")
+		util.CheckError(err)
+		if keepOnFailure {
+			log.Debug("set KeepOnFailure = true from flags")
+			deps.AppConfig.KeepOnFailure = true
+		}
+
+		fromFile, err := cmd.Flags().GetString("from-file")
+		util.CheckError(err)
+		entries, err := batchAddEntries(args, fromFile)
+		util.CheckError(err)
+		if entries != nil {
+			util.CheckError(validateBatchAddFlags(deps.AppConfig))
+			runBatchAdd(deps.AppConfig, entries)
+			return
+		}
+
+		cfg := services.SetConfigForAddService(deps.Git, deps.AppConfig, args)
+
+		newRootDirectory, err := services.AddWorktree(deps.Git, deps.Connector, deps.Shell, cfg)
+		if newRootDirectory == "" {
+			log.Fatal("Failed to add worktree", "error", err)
+		}
+
+		// With the shell-init function the shell follows into the new
+		// worktree, unless it's a dry run
+		if recorder == nil {
+			if _, err := shellinit.RequestCd(newRootDirectory); err != nil {
+				log.Warn("Failed to change into the new worktree", "error", err)
+			}
+		}
+		util.CheckError(err)
 	},
 }
 
 func init() {
 
 	addCmd.Flags().BoolP("pull", "p", false, "Pull the base branch before creating new branch")
+	addCmd.Flags().StringP("open", "o", "", "Open the new worktree in an editor, a preset like code, zed, goland or nvim, or one from editors in the config")
 	addCmd.Flags().BoolP("cursor", "c", false, "Open up new worktree in cursor")
 	addCmd.Flags().BoolP("vscode", "v", false, "Open up new worktree in vs code")
+	addCmd.Flags().MarkDeprecated("cursor", "use --open cursor")
+	addCmd.Flags().MarkDeprecated("vscode", "use --open code")
+	addCmd.MarkFlagsMutuallyExclusive("open", "cursor", "vscode")
 	addCmd.Flags().BoolP("script", "x", false, "Execute Custom Script")
 	addCmd.Flags().BoolP("from", "f", false, "Select base branch from list of branches")
 	addCmd.Flags().BoolP("remote", "r", false, "Checkout existing branch from remote")
@@ -124,4 +250,11 @@ func init() {
 	addCmd.Flags().StringP("base", "b", "", "Specify the base branch for the new worktree")
 	addCmd.Flags().StringP("directory", "d", "", "Specify the directory to the bare repo where the worktree will be added")
 	addCmd.Flags().StringP("name", "n", "", "Specify a worktree name")
+	addCmd.Flags().StringP("issue", "i", "", "Name the new branch after an issue key from the configured issue tracker")
+	addCmd.Flags().String("ref", "", "Create the worktree at a tag or commit instead of the base branch")
+	addCmd.Flags().Bool("detach", false, "Check out --ref detached, without creating a branch")
+	addCmd.Flags().String("from-file", "", "Add a worktree for every 'branch [base]' line in a file")
+	addCmd.Flags().String("stack-on", "", "Stack the new branch on a local branch, see treekanga stack")
+	addCmd.Flags().Bool("carry", false, "Move the uncommitted changes of the worktree you are in into the new worktree")
+	addCmd.Flags().Bool("keep-on-failure", false, "Don't roll back a failed add, leave the worktree and branch behind for debugging")
 }
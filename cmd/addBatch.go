@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/adapters"
+	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/services"
+	"github.com/garrettkrohn/treekanga/tui"
+	"github.com/mattn/go-isatty"
+)
+
+// batchAddEntries collects the worktrees for a batch add from the branch
+// arguments and --from-file. It returns nil when this is a single add.
+func batchAddEntries(args []string, fromFile string) ([]services.BatchAddEntry, error) {
+	if fromFile == "" && len(args) < 2 {
+		return nil, nil
+	}
+
+	entries := services.BatchAddEntriesFromArgs(args)
+	if fromFile != "" {
+		file, err := os.Open(fromFile)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		fileEntries, err := services.ParseBatchAddFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", fromFile, err)
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no branches to add")
+	}
+	return entries, nil
+}
+
+// validateBatchAddFlags rejects flags that only make sense for one worktree.
+func validateBatchAddFlags(cfg config.AppConfig) error {
+	var conflicting []string
+	if cfg.NewWorktreeName != "" {
+		conflicting = append(conflicting, "--name")
+	}
+	if cfg.IssueKey != "" {
+		conflicting = append(conflicting, "--issue")
+	}
+	if cfg.Detach {
+		conflicting = append(conflicting, "--detach")
+	}
+	if cfg.Carry {
+		conflicting = append(conflicting, "--carry")
+	}
+	if cfg.StackOn != "" {
+		conflicting = append(conflicting, "--stack-on")
+	}
+	if cfg.UseFormToSetBaseBranch {
+		conflicting = append(conflicting, "--from")
+	}
+	if cfg.TmuxConnect != "" {
+		conflicting = append(conflicting, "--tmux")
+	}
+	if cfg.OpenEditor != "" {
+		conflicting = append(conflicting, "--open")
+	}
+	if len(conflicting) > 0 {
+		return fmt.Errorf("%s can't be used when adding several worktrees at once", strings.Join(conflicting, ", "))
+	}
+	return nil
+}
+
+// runBatchAdd creates all entries, showing a live progress table when
+// attached to a terminal, and exits non-zero if any of them failed.
+func runBatchAdd(cfg config.AppConfig, entries []services.BatchAddEntry) {
+	var results []services.BatchAddResult
+
+	if isatty.IsTerminal(os.Stdout.Fd()) {
+		// git output would tear through the table, keep it for --log debug
+		var logBuffer bytes.Buffer
+		log.SetOutput(&logBuffer)
+
+		// ctrl+c is a key press to the display, not a signal, so it stops
+		// the adds through ctx
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		program := tea.NewProgram(tui.NewBatchAddProgressModel(entries, cfg.BaseBranch, cfg.Theme, cancel))
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			results = services.AddWorktrees(ctx, deps.Git, adapters.NewZoxide(deps.Shell), cfg, entries, services.DefaultBatchAddConcurrency, func(i int, r services.BatchAddResult) {
+				program.Send(tui.BatchAddUpdateMsg{Index: i, Result: r})
+			})
+			program.Send(tui.BatchAddDoneMsg{})
+		}()
+		_, err := program.Run()
+		if err != nil {
+			// the adds roll back when stopped, wait for that before exiting
+			cancel()
+		}
+		<-done
+
+		log.SetOutput(os.Stderr)
+		if logBuffer.Len() > 0 {
+			log.Debug(logBuffer.String())
+		}
+		if err != nil {
+			log.Fatal("Error running progress display", "error", err)
+		}
+	} else {
+		results = services.AddWorktrees(context.Background(), deps.Git, adapters.NewZoxide(deps.Shell), cfg, entries, services.DefaultBatchAddConcurrency, nil)
+		for _, r := range results {
+			if r.Err != nil {
+				fmt.Printf("✗ %s: %s\n", r.Entry.Branch, strings.SplitN(r.Err.Error(), "\n", 2)[0])
+			} else {
+				fmt.Printf("✓ %s: %s\n", r.Entry.Branch, r.Path)
+			}
+		}
+	}
+
+	// entries without a result, e.g. never started, count as failed too
+	created := 0
+	for _, r := range results {
+		if r.State == services.BatchAddCreated {
+			created++
+		}
+	}
+	if failed := len(entries) - created; failed > 0 {
+		log.Fatal(fmt.Sprintf("%d of %d worktrees could not be added", failed, len(entries)))
+	}
+	log.Info("All worktrees added", "count", len(entries))
+}
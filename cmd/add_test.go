@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddEditorFlagsExclusive(t *testing.T) {
+	defer addCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+
+	require.NoError(t, addCmd.ParseFlags([]string{"--open", "zed", "--cursor"}))
+	assert.ErrorContains(t, addCmd.ValidateFlagGroups(), "[open cursor vscode]")
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/garrettkrohn/treekanga/services"
+	"github.com/garrettkrohn/treekanga/utility"
+	"github.com/spf13/cobra"
+)
+
+var cdCmd = &cobra.Command{
+	Use:   "cd <worktree>",
+	Short: "Change directory into a worktree",
+	Long: `Change the shell's directory into a worktree, named by branch or folder.
+
+This needs the shell function from shell-init. Without it the worktree's
+path is printed instead, for use as:
+
+    cd "$(treekanga cd feature/login)"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		wt, requested, err := services.CdWorktree(deps.Git, deps.AppConfig.BareRepoPath, args[0])
+		utility.CheckError(err)
+		if !requested {
+			fmt.Fprintln(cmd.OutOrStdout(), wt.FullPath)
+		}
+	},
+}
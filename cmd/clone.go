@@ -32,11 +32,11 @@ var cloneCmd = &cobra.Command{
     If no folder name is provided, it will use the repository name 
     with "_bare" suffix.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		CloneBareRepo(spinner.NewRealHuhSpinner(), args)
+		CloneBareRepo(deps.Git, spinner.NewRealHuhSpinner(), args)
 	},
 }
 
-func CloneBareRepo(spinner spinner.HuhSpinner, args []string) {
+func CloneBareRepo(git git.Git, spinner spinner.HuhSpinner, args []string) {
 	if len(args) == 0 {
 		fmt.Print("must include url to clone, folder name can be included optionally")
 	}
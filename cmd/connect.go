@@ -4,10 +4,15 @@ Copyright © 2024 Garrett Krohn <garrettkrohn@gmail.com>
 package cmd
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/adapters"
 	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/picker"
+	"github.com/garrettkrohn/treekanga/services"
+	"github.com/garrettkrohn/treekanga/utility"
 	"github.com/spf13/cobra"
 )
 
@@ -18,33 +23,52 @@ var connectCmd = &cobra.Command{
 	Short:   "Connect to a tmux session",
 	Long: `Connect to a tmux session by name, worktree path, or directory path.
 
+Without a name, connect opens a fuzzy finder over the open tmux sessions, the
+worktrees of every configured repo and the zoxideFolders inside the current
+repo's worktrees. Recently used ones come first, and the preview shows the
+branch status of the selected one.
+
 The connect command will try to find a session using the following strategies:
 1. Existing tmux session with the given name
-2. Worktree matching the given name or path
+2. Worktree matching the given name or path, see below
 3. Directory path (absolute or relative)
 
-If a session doesn't exist, it will be created automatically.
+Worktrees are matched by full path, folder or branch name first, then by an
+issue key like JIRA-123 in the branch or folder name, then by a prefix and
+finally by any part of it. When several worktrees match you are asked which
+one you meant, or with --first the most recently modified one is taken.
+
+If a session doesn't exist, it will be created automatically. With --open the
+worktree or directory is opened in an editor instead, a preset like code, zed,
+goland or nvim, or one from editors in the config.
 
 Examples:
+  # Pick what to connect to
+  treekanga connect
+
   # Connect to an existing tmux session
   treekanga connect my-session
 
   # Connect to a worktree by name
   treekanga connect feature-branch
 
+  # Connect to the worktree of an issue, without asking if several match
+  treekanga connect PROJ-123 --first
+
   # Connect to a directory
   treekanga connect ~/code/myproject
 
   # Switch to a session (when already in tmux)
-  treekanga connect my-session --switch`,
-	Args: cobra.MinimumNArgs(1),
+  treekanga connect my-session --switch
+
+  # Open a worktree in GoLand
+  treekanga connect PROJ-123 --open goland`,
+	Args: cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.Join(args, " ")
 		if len(args) == 0 {
-			log.Fatal("please provide a session name or path")
-			return
+			name = pickConnectTarget()
 		}
-
-		name := strings.Join(args, " ")
 		if name == "" {
 			return
 		}
@@ -65,8 +89,32 @@ Examples:
 			deps.AppConfig.RunPostScript = true
 		}
 
+		first, err := cmd.Flags().GetBool("first")
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+
 		opts := models.ConnectOpts{
 			Switch: switchFlag,
+			First:  first,
+		}
+
+		openEditor, err := cmd.Flags().GetString("open")
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		if openEditor != "" {
+			connection, err := deps.Connector.Find(name, opts)
+			if err != nil {
+				log.Fatal(err)
+				return
+			}
+			if err := deps.Connector.OpenEditor(openEditor, connection.Session.Path); err != nil {
+				log.Fatal(err)
+			}
+			return
 		}
 
 		log.Debug("Attempting to connect", "name", name, "switch", switchFlag)
@@ -80,7 +128,53 @@ Examples:
 	},
 }
 
+// connectIcons tell the sources of the picker's candidates apart
+var connectIcons = map[string]string{
+	"tmux":     "\ue795",
+	"worktree": "\ue725",
+	"dir":      "\uf07b",
+}
+
+// pickConnectTarget opens the connect picker and returns what to connect to,
+// or "" when the user cancelled.
+func pickConnectTarget() string {
+	tmux := adapters.NewTmux(deps.Shell)
+	sessions, err := tmux.ListSessionsByRecency()
+	if err != nil {
+		log.Debug("Failed to list tmux sessions", "error", err)
+	}
+	// Connecting to the session we're in does nothing
+	if tmux.IsAttached() {
+		if current, err := tmux.GetCurrentSessionName(); err == nil {
+			sessions = slices.DeleteFunc(sessions, func(s models.Session) bool { return s.Name == current })
+		}
+	}
+
+	candidates := services.ConnectCandidates(deps.Git, deps.DirectoryReader, adapters.NewZoxide(deps.Shell), sessions,
+		configuredBareRepoPaths(), deps.AppConfig.ZoxideFolders)
+	if len(candidates) == 0 {
+		log.Fatal("nothing to connect to")
+	}
+
+	items := make([]picker.Item, len(candidates))
+	for i, c := range candidates {
+		items[i] = picker.Item{Icon: connectIcons[c.Session.Src], Label: c.Session.Name, Detail: c.Session.Path}
+	}
+	preview := func(i int) string {
+		return services.ConnectPreview(deps.Git, candidates[i], currentBareRepoPath(), deps.AppConfig.BaseBranch)
+	}
+
+	chosen, err := picker.Run(items, preview, deps.AppConfig.Theme)
+	utility.CheckError(err)
+	if chosen < 0 {
+		return ""
+	}
+	return candidates[chosen].Target()
+}
+
 func init() {
 	connectCmd.Flags().BoolP("switch", "s", false, "Switch to the session (rather than attach). Useful when already inside tmux.")
 	connectCmd.Flags().BoolP("script", "x", false, "Execute Custom Script")
+	connectCmd.Flags().StringP("open", "o", "", "Open the worktree or directory in an editor instead of connecting to tmux")
+	connectCmd.Flags().Bool("first", false, "Take the most recently modified worktree when several match instead of asking")
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/adapters"
 	"github.com/garrettkrohn/treekanga/filter"
 	"github.com/garrettkrohn/treekanga/form"
 	"github.com/garrettkrohn/treekanga/services"
@@ -18,6 +19,9 @@ var deleteCmd = &cobra.Command{
     
     Available flags:
     -s, --stale: Only show worktrees where branches don't exist on remote
+    -m, --merged: Only show worktrees whose branches are merged into the
+                  base branch (including squash merges, and rebase merges
+                  when a forge is configured)
     -d, --delete: CAUTION - Also delete the local branches
     -f, --force: CAUTION - Forces delete of worktree and branch`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -28,6 +32,13 @@ var deleteCmd = &cobra.Command{
 			deps.AppConfig.FilterOnlyStaleBranches = true
 		}
 
+		merged, err := cmd.Flags().GetBool("merged")
+		util.CheckError(err)
+		if merged {
+			log.Debug("setting FilterOnlyMerged = true from flags")
+			deps.AppConfig.FilterOnlyMerged = true
+		}
+
 		deleteBranches, err := cmd.Flags().GetBool("delete")
 		util.CheckError(err)
 		if deleteBranches {
@@ -43,8 +54,11 @@ var deleteCmd = &cobra.Command{
 		}
 
 		numOfWorktreesRemoved, err := services.DeleteWorktrees(
+			deps.Git,
 			filter.NewFilter(),
 			form.NewHuhForm(),
+			deps.Forge,
+			adapters.NewZoxide(deps.Shell),
 			args,
 			deps.AppConfig)
 		if err != nil {
@@ -57,6 +71,7 @@ var deleteCmd = &cobra.Command{
 
 func init() {
 	deleteCmd.Flags().BoolP("stale", "s", false, "Only show worktrees where the branches don't exist on remote")
+	deleteCmd.Flags().BoolP("merged", "m", false, "Only show worktrees whose branches have been merged into the base branch")
 	deleteCmd.Flags().BoolP("delete", "d", false, "CAUTION: delete the local branch")
 	deleteCmd.Flags().BoolP("force", "f", false, "CAUTION: force delete the worktree and branch")
 }
@@ -0,0 +1,474 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/connector"
+	"github.com/garrettkrohn/treekanga/directoryReader"
+	"github.com/garrettkrohn/treekanga/execwrap"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/shell"
+	"github.com/garrettkrohn/treekanga/shellinit"
+	"github.com/garrettkrohn/treekanga/testfixture"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupEndToEnd clones a fixture remote with CloneBareRepo into an isolated
+// HOME, configures it and changes into the bare repo, like a user who just
+// ran treekanga clone.
+func setupEndToEnd(t *testing.T, remote *testfixture.Remote) (*testfixture.Env, string) {
+	t.Helper()
+
+	env := testfixture.NewEnv(t)
+	CloneBareRepo(git.NewGit(), &mockSpinner{}, []string{remote.URL(), "widget_bare"})
+	bareRepoPath := filepath.Join(env.Home, "widget_bare")
+
+	env.WriteConfig("widget", map[string]any{
+		"defaultBranch":     testfixture.DefaultBranch,
+		"worktreeTargetDir": "widget_work",
+	})
+	require.NoError(t, os.Chdir(bareRepoPath))
+	return env, bareRepoPath
+}
+
+// runTreekanga runs the root command with the real dependencies, the way
+// Execute does, and returns what it printed to stdout.
+func runTreekanga(t *testing.T, args ...string) string {
+	t.Helper()
+
+	sh := shell.NewShell(execwrap.NewExec())
+	gitClient := git.NewGit()
+	rootCmd := NewRootCmd(directoryReader.NewDirectoryReader(), connector.NewConnector(sh, gitClient), sh, gitClient, "test")
+	rootCmd.AddCommand(addCmd, listCmd, deleteCmd, connectCmd, renameCmd, moveCmd, pruneCmd, syncCmd, setBaseCmd, stackCmd, stashCmd, cdCmd, shellInitCmd)
+	rootCmd.SetArgs(args)
+	defer resetFlags(rootCmd)
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	os.Stdout = w
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+
+	err = rootCmd.Execute()
+	w.Close()
+	os.Stdout = stdout
+	require.NoError(t, err)
+	return <-output
+}
+
+// resetFlags puts every flag back to its default, the subcommands are
+// package level and would otherwise keep flags from an earlier run.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	for _, c := range cmd.Commands() {
+		c.Flags().VisitAll(reset)
+		resetFlags(c)
+	}
+	cmd.PersistentFlags().VisitAll(reset)
+}
+
+func TestEndToEndAddListRenameDelete(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	remote := testfixture.NewRemote(t, "widget").Branch("feature/shared", testfixture.DefaultBranch)
+	env, bareRepoPath := setupEndToEnd(t, remote)
+	worktrees := filepath.Join(env.Home, "widget_work")
+
+	runTreekanga(t, "add", "feature/login")
+	assert.DirExists(t, filepath.Join(worktrees, "feature-login"))
+	assert.Equal(t, "origin", testfixture.Git(t, bareRepoPath, "config", "branch.feature/login.remote"))
+
+	runTreekanga(t, "add", "feature/shared", "--remote")
+	assert.FileExists(t, filepath.Join(worktrees, "feature-shared", "02-start-feature-shared.txt"))
+
+	listed := strings.Fields(runTreekanga(t, "list"))
+	assert.Contains(t, listed, "feature/login")
+	assert.Contains(t, listed, "feature/shared")
+
+	require.NoError(t, os.Chdir(filepath.Join(worktrees, "feature-login")))
+	runTreekanga(t, "rename", "feature/signin")
+	assert.NoDirExists(t, filepath.Join(worktrees, "feature-login"))
+	assert.DirExists(t, filepath.Join(worktrees, "feature-signin"))
+
+	require.NoError(t, os.Chdir(bareRepoPath))
+	runTreekanga(t, "delete", "feature/shared")
+	assert.NoDirExists(t, filepath.Join(worktrees, "feature-shared"))
+
+	listed = strings.Fields(runTreekanga(t, "list"))
+	assert.Contains(t, listed, "feature/signin")
+	assert.NotContains(t, listed, "feature/login")
+	assert.NotContains(t, listed, "feature/shared")
+}
+
+func TestEndToEndRenameOtherWorktree(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	remote := testfixture.NewRemote(t, "widget").Branch("feature/shared", testfixture.DefaultBranch)
+	env, bareRepoPath := setupEndToEnd(t, remote)
+	worktrees := filepath.Join(env.Home, "widget_work")
+
+	runTreekanga(t, "add", "feature/shared", "--remote")
+
+	require.NoError(t, os.Chdir(bareRepoPath))
+	runTreekanga(t, "rename", "feature-shared", "feature/common", "--remote")
+	assert.NoDirExists(t, filepath.Join(worktrees, "feature-shared"))
+	assert.DirExists(t, filepath.Join(worktrees, "feature-common"))
+
+	heads := testfixture.Git(t, "", "ls-remote", "--heads", remote.URL())
+	assert.Contains(t, heads, "refs/heads/feature/common")
+	assert.NotContains(t, heads, "refs/heads/feature/shared")
+	assert.Equal(t, "refs/heads/feature/common", testfixture.Git(t, bareRepoPath, "config", "branch.feature/common.merge"))
+}
+
+func TestEndToEndDeleteMerged(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	remote := testfixture.NewRemote(t, "widget").
+		Branch("merged", testfixture.DefaultBranch).
+		Branch("squashed", testfixture.DefaultBranch).
+		Branch("open", testfixture.DefaultBranch)
+	_, bareRepoPath := setupEndToEnd(t, remote)
+	for _, branch := range []string{"merged", "squashed", "open"} {
+		runTreekanga(t, "add", branch, "--remote")
+	}
+
+	remote.Merge("merged", testfixture.DefaultBranch).
+		SquashMerge("squashed", testfixture.DefaultBranch)
+
+	// with arguments nothing is asked, only branches that are merged qualify
+	runTreekanga(t, "delete", "--merged", "merged", "squashed")
+
+	listed := strings.Fields(runTreekanga(t, "list"))
+	assert.Equal(t, []string{"open"}, listed)
+	assert.Contains(t, testfixture.Git(t, bareRepoPath, "branch"), "merged")
+}
+
+func TestEndToEndConnect(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env, _ := setupEndToEnd(t, testfixture.NewRemote(t, "widget"))
+	worktree := filepath.Join(env.Home, "widget_work", "feature-x")
+
+	runTreekanga(t, "add", "feature/x", "--tmux", ".")
+	assert.True(t, env.Tmux.Called("new-session", "widget-feature-x", worktree))
+	assert.True(t, env.Tmux.Called("attach-session", "widget-feature-x"))
+
+	// the session exists now, connecting again only attaches
+	runTreekanga(t, "connect", "widget-feature-x")
+	newSessions := 0
+	for _, call := range env.Tmux.Calls() {
+		if call[0] == "new-session" {
+			newSessions++
+		}
+	}
+	assert.Equal(t, 1, newSessions)
+	assert.Equal(t, []string{"widget-feature-x"}, env.Tmux.Sessions())
+}
+
+func TestEndToEndDeleteStale(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	remote := testfixture.NewRemote(t, "widget").
+		Branch("feature/kept", testfixture.DefaultBranch).
+		Branch("feature/shipped", testfixture.DefaultBranch)
+	_, bareRepoPath := setupEndToEnd(t, remote)
+	runTreekanga(t, "add", "feature/kept", "--remote")
+	runTreekanga(t, "add", "feature/shipped", "--remote")
+
+	// deleted on the remote after its pull request was merged
+	remote.DeleteBranch("feature/shipped")
+	testfixture.Git(t, bareRepoPath, "fetch", "-q", "--prune", "origin")
+
+	runTreekanga(t, "delete", "--stale", "feature/shipped")
+
+	listed := strings.Fields(runTreekanga(t, "list"))
+	assert.Equal(t, []string{"feature/kept"}, listed)
+}
+
+func TestEndToEndSync(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	remote := testfixture.NewRemote(t, "widget").
+		Branch("feature/behind", testfixture.DefaultBranch).
+		Branch("feature/dirty", testfixture.DefaultBranch)
+	env, _ := setupEndToEnd(t, remote)
+	worktrees := filepath.Join(env.Home, "widget_work")
+	runTreekanga(t, "add", "feature/behind", "--remote")
+	runTreekanga(t, "add", "feature/dirty", "--remote")
+
+	remote.Commit(testfixture.DefaultBranch, "move main ahead")
+	require.NoError(t, os.WriteFile(filepath.Join(worktrees, "feature-dirty", "wip.txt"), []byte("wip\n"), 0644))
+	testfixture.Git(t, filepath.Join(worktrees, "feature-dirty"), "add", "wip.txt")
+
+	output := runTreekanga(t, "sync", "feature/behind", "feature/dirty")
+	assert.Regexp(t, `feature-behind\s+feature/behind\s+synced`, output)
+	assert.Regexp(t, `feature-dirty\s+feature/dirty\s+skipped\s+uncommitted changes`, output)
+
+	behind := filepath.Join(worktrees, "feature-behind")
+	testfixture.Git(t, behind, "merge-base", "--is-ancestor", "origin/main", "HEAD")
+
+	// nothing left to do from inside the worktree
+	require.NoError(t, os.Chdir(behind))
+	assert.Regexp(t, `feature/behind\s+up to date`, runTreekanga(t, "sync"))
+}
+
+func TestEndToEndBaseBranch(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	remote := testfixture.NewRemote(t, "widget").Branch("release/2.0", testfixture.DefaultBranch)
+	_, bareRepoPath := setupEndToEnd(t, remote)
+
+	runTreekanga(t, "add", "fix/login", "-b", "release/2.0")
+	runTreekanga(t, "add", "feature/search")
+	assert.Equal(t, "release/2.0", testfixture.Git(t, bareRepoPath, "config", "branch.fix/login.treekangaBase"))
+	assert.Equal(t, "main", testfixture.Git(t, bareRepoPath, "config", "branch.feature/search.treekangaBase"))
+
+	// against its own base fix/login has nothing new, against main it has
+	// the release branch's commit
+	assert.NotRegexp(t, `branch: fix/login, base: release/2.0, .*↑`, runTreekanga(t, "list", "-v"))
+
+	runTreekanga(t, "set-base", "main", "fix/login")
+	assert.Equal(t, "main", testfixture.Git(t, bareRepoPath, "config", "branch.fix/login.treekangaBase"))
+	assert.Regexp(t, `branch: fix/login, base: main, .*status: ↑1`, runTreekanga(t, "list", "-v"))
+}
+
+func TestEndToEndStack(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env, bareRepoPath := setupEndToEnd(t, testfixture.NewRemote(t, "widget"))
+	worktrees := filepath.Join(env.Home, "widget_work")
+	commit := func(dir, name string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0644))
+		testfixture.Git(t, dir, "add", name)
+		testfixture.Git(t, dir, "commit", "-q", "-m", name)
+	}
+
+	runTreekanga(t, "add", "feature/api")
+	api := filepath.Join(worktrees, "feature-api")
+	commit(api, "api.txt")
+	runTreekanga(t, "add", "feature/ui", "--stack-on", "feature/api")
+	ui := filepath.Join(worktrees, "feature-ui")
+	commit(ui, "ui.txt")
+	assert.Equal(t, "feature/api", testfixture.Git(t, bareRepoPath, "config", "branch.feature/ui.treekangaParent"))
+	assert.Equal(t, "feature/api", testfixture.Git(t, bareRepoPath, "config", "branch.feature/ui.treekangaBase"))
+
+	assert.Equal(t, "main\n└── feature/api ↑1\n    └── feature/ui ↑1\n", runTreekanga(t, "stack", "show"))
+
+	commit(api, "api2.txt")
+	assert.Contains(t, runTreekanga(t, "stack", "show"), "└── feature/ui ↑1↓1")
+
+	assert.Regexp(t, `feature-ui\s+feature/ui\s+synced`, runTreekanga(t, "stack", "restack"))
+	assert.Contains(t, runTreekanga(t, "stack", "show"), "└── feature/ui ↑1\n")
+	testfixture.Git(t, ui, "merge-base", "--is-ancestor", "feature/api", "HEAD")
+
+	// feature/api was never pushed, sync leaves the stacked branch to restack
+	assert.Regexp(t, `feature-ui\s+feature/ui\s+skipped\s+stacked on feature/api, use stack restack`, runTreekanga(t, "sync", "feature/ui"))
+	testfixture.Git(t, ui, "merge-base", "--is-ancestor", "feature/api", "HEAD")
+
+	// renaming the parent keeps the stack together
+	runTreekanga(t, "rename", "feature/api", "feature/core")
+	assert.Equal(t, "feature/core", testfixture.Git(t, bareRepoPath, "config", "branch.feature/ui.treekangaParent"))
+	assert.Equal(t, "feature/core", testfixture.Git(t, bareRepoPath, "config", "branch.feature/ui.treekangaBase"))
+	assert.Equal(t, "main\n└── feature/core ↑2\n    └── feature/ui ↑1\n", runTreekanga(t, "stack", "show"))
+}
+
+func TestEndToEndStash(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env, _ := setupEndToEnd(t, testfixture.NewRemote(t, "widget"))
+	worktrees := filepath.Join(env.Home, "widget_work")
+	runTreekanga(t, "add", "feature/a")
+	runTreekanga(t, "add", "feature/b")
+	a := filepath.Join(worktrees, "feature-a")
+	require.NoError(t, os.WriteFile(filepath.Join(a, "wip.txt"), []byte("wip\n"), 0644))
+	testfixture.Git(t, a, "stash", "push", "-q", "-u", "-m", "meant for b")
+
+	assert.Regexp(t, `feature/a\s+feature-a\s+stash@\{0\}\s+\S+ \S+\s+meant for b`, runTreekanga(t, "stash", "list"))
+
+	runTreekanga(t, "stash", "move", "0", "feature/b")
+	assert.FileExists(t, filepath.Join(worktrees, "feature-b", "wip.txt"))
+	assert.NoFileExists(t, filepath.Join(a, "wip.txt"))
+	assert.Equal(t, "no stashes\n", runTreekanga(t, "stash", "list"))
+}
+
+func TestEndToEndAddCarry(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env, _ := setupEndToEnd(t, testfixture.NewRemote(t, "widget"))
+	worktrees := filepath.Join(env.Home, "widget_work")
+	runTreekanga(t, "add", "feature/wrong")
+	wrong := filepath.Join(worktrees, "feature-wrong")
+	tracked := testfixture.Git(t, wrong, "ls-files")
+	require.NoError(t, os.WriteFile(filepath.Join(wrong, tracked), []byte("changed\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(wrong, "new.txt"), []byte("new\n"), 0644))
+
+	require.NoError(t, os.Chdir(wrong))
+	runTreekanga(t, "add", "feature/right", "--carry")
+
+	right := filepath.Join(worktrees, "feature-right")
+	assert.Empty(t, testfixture.Git(t, wrong, "status", "--porcelain"))
+	status := testfixture.Git(t, right, "status", "--porcelain")
+	assert.Contains(t, status, "M "+tracked)
+	assert.Contains(t, status, "?? new.txt")
+	assert.Empty(t, testfixture.Git(t, right, "stash", "list"))
+}
+
+func TestEndToEndShellIntegration(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env, bareRepoPath := setupEndToEnd(t, testfixture.NewRemote(t, "widget"))
+	home, err := filepath.EvalSymlinks(env.Home)
+	require.NoError(t, err)
+	worktrees := filepath.Join(home, "widget_work")
+	bareRepoPath, err = filepath.EvalSymlinks(bareRepoPath)
+	require.NoError(t, err)
+
+	assert.Contains(t, runTreekanga(t, "shell-init", "bash"), "treekanga()")
+
+	// Without the shell function cd prints the path
+	runTreekanga(t, "add", "feature/login")
+	login := filepath.Join(worktrees, "feature-login")
+	assert.Equal(t, login+"\n", runTreekanga(t, "cd", "feature/login"))
+
+	cdFile := filepath.Join(t.TempDir(), "cd")
+	t.Setenv(shellinit.CdFileEnv, cdFile)
+	requestedCd := func() string {
+		t.Helper()
+		data, err := os.ReadFile(cdFile)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(cdFile))
+		return strings.TrimSpace(string(data))
+	}
+
+	runTreekanga(t, "add", "feature/api")
+	assert.Equal(t, filepath.Join(worktrees, "feature-api"), requestedCd())
+
+	assert.Empty(t, runTreekanga(t, "cd", "feature-login"))
+	assert.Equal(t, login, requestedCd())
+
+	require.NoError(t, os.MkdirAll(filepath.Join(login, "src"), 0755))
+	require.NoError(t, os.Chdir(filepath.Join(login, "src")))
+	runTreekanga(t, "rename", "feature/signin")
+	assert.Equal(t, filepath.Join(worktrees, "feature-signin", "src"), requestedCd())
+
+	require.NoError(t, os.Chdir(filepath.Join(worktrees, "feature-api")))
+	runTreekanga(t, "delete", "feature/api")
+	assert.Equal(t, bareRepoPath, requestedCd())
+}
+
+func TestEndToEndMove(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env, bareRepoPath := setupEndToEnd(t, testfixture.NewRemote(t, "widget"))
+	home, err := filepath.EvalSymlinks(env.Home)
+	require.NoError(t, err)
+	worktrees := filepath.Join(home, "widget_work")
+
+	runTreekanga(t, "add", "feature/login")
+	runTreekanga(t, "add", "feature/api")
+	login := filepath.Join(worktrees, "feature-login")
+	require.NoError(t, os.MkdirAll(filepath.Join(login, "src"), 0755))
+
+	// One worktree by name, into an existing folder
+	spikes := filepath.Join(home, "spikes")
+	require.NoError(t, os.Mkdir(spikes, 0755))
+	runTreekanga(t, "move", "feature/api", spikes)
+	assert.DirExists(t, filepath.Join(spikes, "feature-api"))
+	assert.NoDirExists(t, filepath.Join(worktrees, "feature-api"))
+
+	// Everything, following the shell and the tmux panes
+	cdFile := filepath.Join(t.TempDir(), "cd")
+	t.Setenv(shellinit.CdFileEnv, cdFile)
+	env.Tmux.AddPane("%1", "widget-feature-login", "zsh", filepath.Join(login, "src"))
+	env.Tmux.AddPane("%2", "widget-feature-login", "nvim", login)
+	require.NoError(t, os.Chdir(filepath.Join(login, "src")))
+
+	disk := filepath.Join(home, "disk", "widget_work")
+	output := runTreekanga(t, "move", "--all", "--to", disk)
+	assert.Contains(t, output, "feature-login")
+	assert.DirExists(t, filepath.Join(disk, "feature-login", "src"))
+	assert.DirExists(t, filepath.Join(disk, "feature-api"))
+	assert.NoDirExists(t, login)
+	assert.Contains(t, testfixture.Git(t, bareRepoPath, "worktree", "list"), filepath.Join(disk, "feature-login"))
+
+	data, err := os.ReadFile(cdFile)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(disk, "feature-login", "src")+"\n", string(data))
+	assert.True(t, env.Tmux.Called("send-keys", "%1", "cd '"+filepath.Join(disk, "feature-login", "src")+"'"))
+	assert.False(t, env.Tmux.Called("send-keys", "%2"))
+
+	// The config keeps its other settings, in the case viper wrote them,
+	// and now adds worktrees on the disk
+	config, err := os.ReadFile(filepath.Join(env.Home, ".config", "treekanga", "treekanga.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "repos:\n    widget:\n        defaultbranch: "+testfixture.DefaultBranch+"\n        worktreetargetdir: ~/disk/widget_work\n", string(config))
+	runTreekanga(t, "add", "feature/docs")
+	assert.DirExists(t, filepath.Join(disk, "feature-docs"))
+}
+
+func TestEndToEndPrune(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env, bareRepoPath := setupEndToEnd(t, testfixture.NewRemote(t, "widget"))
+	runTreekanga(t, "add", "feature/login")
+	require.NoError(t, os.RemoveAll(filepath.Join(env.Home, "widget_work", "feature-login")))
+
+	// A dry run plans the prune but leaves the record
+	runner := git.GetRunner()
+	output := runTreekanga(t, "prune", "--dry-run")
+	git.SetRunner(runner)
+	recorder = nil
+	assert.Contains(t, output, "worktree prune")
+	assert.Contains(t, testfixture.Git(t, bareRepoPath, "worktree", "list"), "feature-login")
+
+	runTreekanga(t, "prune")
+	assert.NotContains(t, testfixture.Git(t, bareRepoPath, "worktree", "list"), "feature-login")
+
+	// The branch isn't checked out anymore, so it can be added again
+	runTreekanga(t, "add", "feature/login", "--local")
+	assert.DirExists(t, filepath.Join(env.Home, "widget_work", "feature-login"))
+}
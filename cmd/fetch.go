@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/services"
+	"github.com/garrettkrohn/treekanga/utility"
+	"github.com/spf13/cobra"
+)
+
+var fetchCmd = &cobra.Command{
+	Use:   "fetch",
+	Short: "Fetch every branch from origin",
+	Long: `Fetch every branch of the repo from origin, pruning branches deleted
+there, and record when. list -v and the TUI show how long ago that was,
+so you know how fresh their ahead/behind and merge status is.
+
+    treekanga fetch               # the current repo
+    treekanga fetch --all-repos   # every repo in the config file
+
+The TUI fetches in the background when backgroundFetch is configured:
+  repos:
+    myrepo:
+      backgroundFetch: 10m`,
+	Run: func(cmd *cobra.Command, args []string) {
+		allRepos, err := cmd.Flags().GetBool("all-repos")
+		utility.CheckError(err)
+
+		concurrency, err := cmd.Flags().GetInt("concurrency")
+		utility.CheckError(err)
+
+		bareRepoPaths := []string{currentBareRepoPath()}
+		if allRepos {
+			bareRepoPaths = configuredBareRepoPaths()
+		}
+
+		results := services.FetchRepos(deps.Git, bareRepoPaths, concurrency)
+
+		failed := 0
+		for _, r := range results {
+			if r.Err != nil {
+				failed++
+				fmt.Printf("✗ %s: %s\n", r.BareRepoPath, strings.SplitN(r.Err.Error(), "\n", 2)[0])
+			} else {
+				fmt.Printf("✓ %s\n", r.BareRepoPath)
+			}
+		}
+		if failed > 0 {
+			log.Fatal(fmt.Sprintf("%d of %d repos could not be fetched", failed, len(results)))
+		}
+	},
+}
+
+func init() {
+	fetchCmd.Flags().Bool("all-repos", false, "Fetch every repo in the config file")
+	fetchCmd.Flags().Int("concurrency", services.DefaultFetchConcurrency, "How many repos to fetch at once")
+}
@@ -4,9 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
-	"github.com/garrettkrohn/treekanga/git"
+	"github.com/charmbracelet/log"
 	"github.com/garrettkrohn/treekanga/models"
 	"github.com/garrettkrohn/treekanga/transformer"
 )
@@ -25,7 +26,7 @@ func getFetcher(global bool) fetcher {
 }
 
 func (f *simpleFetcher) fetch() ([]models.Worktree, error) {
-	rawWorktrees, err := git.ListWorktrees(deps.AppConfig.BareRepoPath)
+	rawWorktrees, err := deps.Git.ListWorktrees(deps.AppConfig.BareRepoPath)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +48,7 @@ func (f *globalFetcher) fetch() ([]models.Worktree, error) {
 			continue
 		}
 
-		rawWorktrees, err := git.ListWorktrees(bareRepoPath)
+		rawWorktrees, err := deps.Git.ListWorktrees(bareRepoPath)
 		if err != nil {
 			continue
 		}
@@ -61,6 +62,43 @@ func (f *globalFetcher) fetch() ([]models.Worktree, error) {
 	return allWorktrees, nil
 }
 
+// configuredBareRepoPaths returns the bare repo of every repo in the config
+// file that has worktrees, and the current one.
+func configuredBareRepoPaths() []string {
+	bareRepoPaths := []string{currentBareRepoPath()}
+	for _, worktreeDir := range deps.AppConfig.AllBareRepoPaths {
+		gitDir, err := findBareRepoFromWorktreeDir(worktreeDir)
+		if err != nil {
+			log.Debug("Skipping repo without worktrees", "dir", worktreeDir, "error", err)
+			continue
+		}
+
+		bareRepoPath, err := deps.Git.GetBareRepoPath(gitDir)
+		if err != nil {
+			log.Debug("Skipping repo", "dir", worktreeDir, "error", err)
+			continue
+		}
+		bareRepoPath = strings.TrimSpace(bareRepoPath)
+		if !filepath.IsAbs(bareRepoPath) {
+			bareRepoPath = filepath.Join(gitDir, bareRepoPath)
+		}
+		if !slices.Contains(bareRepoPaths, bareRepoPath) {
+			bareRepoPaths = append(bareRepoPaths, bareRepoPath)
+		}
+	}
+	return bareRepoPaths
+}
+
+// currentBareRepoPath returns the absolute path of the current bare repo,
+// git reports it as "." from inside it.
+func currentBareRepoPath() string {
+	bareRepoPath, err := filepath.Abs(deps.AppConfig.BareRepoPath)
+	if err != nil {
+		return deps.AppConfig.BareRepoPath
+	}
+	return bareRepoPath
+}
+
 func findBareRepoFromWorktreeDir(targetDir string) (string, error) {
 	entries, err := os.ReadDir(targetDir)
 	if err != nil {
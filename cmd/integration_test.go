@@ -10,6 +10,7 @@ import (
 	"github.com/garrettkrohn/treekanga/git"
 	"github.com/garrettkrohn/treekanga/services"
 	spinnerhuh "github.com/garrettkrohn/treekanga/spinnerHuh"
+	"github.com/garrettkrohn/treekanga/testfixture"
 	"github.com/garrettkrohn/treekanga/transformer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,8 @@ func TestCloneAndAddIntegration(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := git.NewGit()
+
 	// Create a temporary directory for the test
 	tempDir, err := os.MkdirTemp("", "treekanga-integration-test-*")
 	require.NoError(t, err, "Failed to create temp directory")
@@ -40,15 +43,15 @@ func TestCloneAndAddIntegration(t *testing.T) {
 	// Set up real dependencies
 	mockSpinner := &mockSpinner{}
 
-	// Use a small public repository for testing
-	testRepoURL := "https://github.com/octocat/Hello-World.git"
+	// A local stand-in for the remote, so the test runs offline
+	testRepoURL := testfixture.NewRemote(t, "Hello-World").URL()
 	expectedFolderName := "Hello-World.git_bare"
 
 	t.Log("Step 1: Cloning bare repository...")
 
 	// Run the clone command
 	args := []string{testRepoURL}
-	CloneBareRepo(mockSpinner, args)
+	CloneBareRepo(g, mockSpinner, args)
 
 	// Verify the bare repository was created
 	bareRepoPath := filepath.Join(tempDir, expectedFolderName)
@@ -73,7 +76,7 @@ func TestCloneAndAddIntegration(t *testing.T) {
 	t.Log("Step 2: Creating worktree from bare repository...")
 
 	// First, fetch remote branches to determine what's available
-	remoteBranches, err := git.GetRemoteBranches(bareRepoPath)
+	remoteBranches, err := g.GetRemoteBranches(bareRepoPath)
 	require.NoError(t, err, "Should be able to get remote branches")
 	require.Greater(t, len(remoteBranches), 0, "Should have at least one remote branch")
 
@@ -112,7 +115,7 @@ func TestCloneAndAddIntegration(t *testing.T) {
 	branchArgs := services.GetAddWorktreeArguements(worktreeConfig)
 
 	// Add the worktree
-	err = git.AddWorktree(bareRepoPath, tempDir, testBranchName, branchArgs)
+	err = g.AddWorktree(bareRepoPath, tempDir, testBranchName, branchArgs)
 	require.NoError(t, err, "Should successfully add worktree")
 
 	t.Logf("✓ Successfully created worktree at: %s", worktreePath)
@@ -136,7 +139,7 @@ func TestCloneAndAddIntegration(t *testing.T) {
 	assert.Contains(t, string(gitFileContent), expectedFolderName, ".git file should reference the bare repo")
 
 	// Verify we can see the worktree in git worktree list
-	rawWorktrees, err := git.ListWorktrees(bareRepoPath)
+	rawWorktrees, err := g.ListWorktrees(bareRepoPath)
 	assert.NoError(t, err, "Should be able to list worktrees")
 	assert.Greater(t, len(rawWorktrees), 0, "Should have at least one worktree")
 
@@ -162,7 +165,7 @@ func TestCloneAndAddIntegration(t *testing.T) {
 	assert.True(t, foundWorktree, "Should find our worktree in git worktree list")
 
 	// Verify the worktree has actual git repository contents
-	assertPathExists(t, filepath.Join(worktreePath, "README"))
+	assertPathExists(t, filepath.Join(worktreePath, "01-initial-commit.txt"))
 
 	t.Logf("✓ Successfully verified worktree at: %s", worktreePath)
 
@@ -172,12 +175,15 @@ func TestCloneAndAddIntegration(t *testing.T) {
 	// We need to set up deps for the list command to work
 	// Save the original deps values
 	originalBareRepoPath := deps.AppConfig.BareRepoPath
+	originalGit := deps.Git
 	defer func() {
 		deps.AppConfig.BareRepoPath = originalBareRepoPath
+		deps.Git = originalGit
 	}()
 
 	// Set deps for the list command
 	deps.AppConfig.BareRepoPath = bareRepoPath
+	deps.Git = g
 
 	// Get the list of worktrees
 	worktreeList, err := buildWorktreeStrings(false, false, false, false)
@@ -227,7 +233,7 @@ func TestCloneAndAddIntegration(t *testing.T) {
 	t.Log("Step 5: Deleting the worktree and verifying it's gone...")
 
 	// Remove the worktree directly
-	err = git.RemoveWorktree(bareRepoPath, worktreePath, true)
+	err = g.RemoveWorktree(bareRepoPath, worktreePath, true)
 	assert.NoError(t, err, "Should successfully remove worktree")
 
 	t.Logf("✓ Successfully deleted worktree")
@@ -237,7 +243,7 @@ func TestCloneAndAddIntegration(t *testing.T) {
 	assert.True(t, os.IsNotExist(err), "Worktree directory should no longer exist")
 
 	// Verify the worktree is no longer in git worktree list
-	rawWorktreesAfterDelete, err := git.ListWorktrees(bareRepoPath)
+	rawWorktreesAfterDelete, err := g.ListWorktrees(bareRepoPath)
 	assert.NoError(t, err, "Should be able to list worktrees after deletion")
 
 	worktreesAfterDelete := transformer.TransformWorktrees(rawWorktreesAfterDelete)
@@ -279,6 +285,8 @@ func TestRenameWorktreeIntegration(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := git.NewGit()
+
 	// Create a temporary directory for the test
 	tempDir, err := os.MkdirTemp("", "treekanga-rename-integration-*")
 	require.NoError(t, err, "Failed to create temp directory")
@@ -292,8 +300,8 @@ func TestRenameWorktreeIntegration(t *testing.T) {
 	err = os.Chdir(tempDir)
 	require.NoError(t, err, "Failed to change to temp directory")
 
-	// Use a small public repository for testing
-	testRepoURL := "https://github.com/octocat/Hello-World.git"
+	// A local stand-in for the remote, so the test runs offline
+	testRepoURL := testfixture.NewRemote(t, "Hello-World").URL()
 	expectedFolderName := "Hello-World.git_bare"
 
 	t.Log("Step 1: Cloning bare repository...")
@@ -301,7 +309,7 @@ func TestRenameWorktreeIntegration(t *testing.T) {
 	// Clone the bare repo
 	mockSpinner := &mockSpinner{}
 	args := []string{testRepoURL}
-	CloneBareRepo(mockSpinner, args)
+	CloneBareRepo(g, mockSpinner, args)
 
 	bareRepoPath := filepath.Join(tempDir, expectedFolderName)
 	_, err = os.Stat(bareRepoPath)
@@ -313,7 +321,7 @@ func TestRenameWorktreeIntegration(t *testing.T) {
 	deps.AppConfig.BareRepoPath = bareRepoPath
 
 	// Get remote branches
-	remoteBranches, err := git.GetRemoteBranches(bareRepoPath)
+	remoteBranches, err := g.GetRemoteBranches(bareRepoPath)
 	require.NoError(t, err)
 	require.Greater(t, len(remoteBranches), 0)
 
@@ -344,13 +352,13 @@ func TestRenameWorktreeIntegration(t *testing.T) {
 	}
 
 	branchArgs := services.GetAddWorktreeArguements(worktreeConfig)
-	err = git.AddWorktree(bareRepoPath, tempDir, initialFolder, branchArgs)
+	err = g.AddWorktree(bareRepoPath, tempDir, initialFolder, branchArgs)
 	require.NoError(t, err)
 
 	t.Logf("✓ Created worktree at: %s", worktreePath)
 
 	// Verify initial state
-	branches, err := git.GetLocalBranches(bareRepoPath)
+	branches, err := g.GetLocalBranches(bareRepoPath)
 	require.NoError(t, err)
 	assert.Contains(t, branches, initialBranch, "Initial branch should exist")
 
@@ -373,14 +381,14 @@ func TestRenameWorktreeIntegration(t *testing.T) {
 		WorktreeTargetDir: tempDir,
 	}
 
-	// Execute rename (pass nil for connector and confirmer since we don't need tmux handling in tests)
-	err = services.RenameWorktree(cfg, newBranchName, worktreePath, nil, nil, false, false)
+	// Execute rename (pass nil for the shell since we don't need zoxide in tests)
+	_, err = services.RenameWorktree(cfg, newBranchName, worktreePath, g, nil, false, false)
 	assert.NoError(t, err, "Should successfully rename worktree")
 
 	t.Log("Step 4: Verifying the rename...")
 
 	// Verify branch was renamed
-	branches, err = git.GetLocalBranches(bareRepoPath)
+	branches, err = g.GetLocalBranches(bareRepoPath)
 	require.NoError(t, err)
 	assert.Contains(t, branches, newBranchName, "New branch should exist")
 	assert.NotContains(t, branches, initialBranch, "Old branch should not exist")
@@ -393,7 +401,7 @@ func TestRenameWorktreeIntegration(t *testing.T) {
 	assert.True(t, os.IsNotExist(err), "Old worktree folder should not exist")
 
 	// Verify worktree list shows the new branch
-	rawWorktrees, err := git.ListWorktrees(bareRepoPath)
+	rawWorktrees, err := g.ListWorktrees(bareRepoPath)
 	require.NoError(t, err)
 
 	worktrees := transformer.TransformWorktrees(rawWorktrees)
@@ -47,7 +47,12 @@ var listCmd = &cobra.Command{
     defined in the zoxideFolders configuration.
 
     Verbose output includes a compact git status indicator:
-      ` + transformer.StatusLegend,
+      ` + transformer.StatusLegend + `
+
+    When a forge is configured for the repo (e.g. forge: github), verbose
+    output also shows each branch's pull request: number, state
+    (open/draft/merged/closed), CI (✓ passing, ✗ failing, ● running)
+    and review decision.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		verbose, err := cmd.Flags().GetBool("verbose")
 		utilpkg.CheckError(err)
@@ -92,7 +97,7 @@ func getDisplayString(worktree models.Worktree, displayMode string) string {
 	if displayMode == "directory" {
 		return worktree.Folder
 	}
-	return worktree.BranchName
+	return transformer.DisplayBranch(worktree)
 }
 
 // sortWorktreesByModTime sorts worktrees by modification time (most recent first)
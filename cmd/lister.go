@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/charmbracelet/log"
 	"github.com/garrettkrohn/treekanga/models"
 	"github.com/garrettkrohn/treekanga/services"
 	"github.com/garrettkrohn/treekanga/transformer"
@@ -49,7 +51,7 @@ func (t *simpleTransformer) Transform(worktrees []models.Worktree) ([]string, er
 		if deps.AppConfig.ListDisplayMode == "directory" {
 			worktreeStrings = append(worktreeStrings, worktree.Folder)
 		} else {
-			worktreeStrings = append(worktreeStrings, worktree.BranchName)
+			worktreeStrings = append(worktreeStrings, transformer.DisplayBranch(worktree))
 		}
 	}
 
@@ -59,12 +61,23 @@ func (t *simpleTransformer) Transform(worktrees []models.Worktree) ([]string, er
 type verboseTransformer struct{}
 
 func (t *verboseTransformer) Transform(worktrees []models.Worktree) ([]string, error) {
-	worktrees = services.ComputeAllWorktreeStatuses(deps.AppConfig.BareRepoPath, deps.AppConfig.BaseBranch, worktrees)
+	worktrees = services.ComputeAllWorktreeStatuses(deps.Git, deps.AppConfig.BareRepoPath, deps.AppConfig.BaseBranch, worktrees)
+	if deps.Forge != nil {
+		worktrees = services.ComputeAllWorktreePullRequests(deps.Forge, worktrees)
+	}
+
+	lastFetch, err := deps.Git.GetLastFetch(deps.AppConfig.BareRepoPath)
+	if err != nil {
+		log.Debug("Failed to read last fetch", "error", err)
+	}
+	worktreeBranches := []string{fmt.Sprintf("repo: %s, %s", deps.AppConfig.RepoNameForConfig, transformer.LastFetchAge(lastFetch, time.Now()))}
 
-	var worktreeBranches []string
 	for _, worktree := range worktrees {
-		branchDisplay := fmt.Sprintf("worktree: %s, branch: %s, fullPath: %s, commitHash: %s, status: %s",
-			worktree.Folder, worktree.BranchName, worktree.FullPath, worktree.CommitHash, transformer.WorktreeStatusSymbols(worktree))
+		branchDisplay := fmt.Sprintf("worktree: %s, branch: %s, base: %s, fullPath: %s, commitHash: %s, status: %s",
+			worktree.Folder, transformer.DisplayBranch(worktree), worktree.BaseBranch, worktree.FullPath, worktree.CommitHash, transformer.WorktreeStatusSymbols(worktree))
+		if deps.Forge != nil {
+			branchDisplay += fmt.Sprintf(", pr: %s", transformer.PullRequestSymbols(worktree))
+		}
 		worktreeBranches = append(worktreeBranches, branchDisplay)
 	}
 	return worktreeBranches, nil
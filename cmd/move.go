@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/adapters"
+	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/services"
+	"github.com/garrettkrohn/treekanga/transformer"
+	"github.com/garrettkrohn/treekanga/utility"
+	"github.com/spf13/cobra"
+)
+
+var moveCmd = &cobra.Command{
+	Use:   "move <worktree> <dest>",
+	Short: "Move worktrees to another folder",
+	Long: `Move a worktree, named by branch or folder, to another folder. Like mv,
+a dest that is an existing folder means into it, keeping the worktree's
+folder name:
+
+    treekanga move feature/login ~/disk/widget_work   # into the folder
+    treekanga move feature/login ~/code/login-spike   # to a new path
+
+Or move every worktree into one folder, e.g. when the disk fills up, and
+make it the worktreeTargetDir new worktrees go to:
+
+    treekanga move --all --to /mnt/big/widget_work
+
+Worktrees can be moved to another filesystem. zoxide's entries follow
+them, and so do the tmux panes that were in them: panes at a shell prompt
+are sent a cd, the others are listed to change by hand. With the shell
+integration (see shell-init) your shell follows too.
+
+Use -f if your worktrees contain submodules (git doesn't allow moving
+those).`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return err
+		}
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return err
+		}
+
+		if all && len(args) > 0 {
+			return fmt.Errorf("--all moves every worktree, don't name any")
+		}
+		if all && to == "" {
+			return fmt.Errorf("--all needs the folder to move them to with --to")
+		}
+		if !all && to != "" {
+			return fmt.Errorf("--to only goes with --all, name the worktree and where to move it instead")
+		}
+		if !all {
+			return cobra.ExactArgs(2)(cmd, args)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		all, err := cmd.Flags().GetBool("all")
+		utility.CheckError(err)
+
+		to, err := cmd.Flags().GetString("to")
+		utility.CheckError(err)
+
+		forceSubmodules, err := cmd.Flags().GetBool("force-submodules")
+		utility.CheckError(err)
+
+		tmux := adapters.NewTmux(deps.Shell)
+		zoxide := adapters.NewZoxide(deps.Shell)
+
+		if !all {
+			result, err := services.MoveWorktree(deps.Git, tmux, zoxide, deps.AppConfig.BareRepoPath, args[0], args[1], forceSubmodules)
+			utility.CheckError(err)
+			log.Info("worktree moved", "worktree", result.Worktree.Folder, "path", result.NewPath)
+			return
+		}
+
+		dir, err := filepath.Abs(to)
+		utility.CheckError(err)
+
+		results, err := services.MoveAllWorktrees(deps.Git, tmux, zoxide, deps.AppConfig.BareRepoPath, dir, forceSubmodules)
+		utility.CheckError(err)
+
+		failed := printMoveResults(cmd.OutOrStdout(), results)
+		if failed > 0 {
+			log.Fatal(fmt.Sprintf("%d of %d worktrees could not be moved, worktreeTargetDir is left as it was", failed, len(results)))
+		}
+
+		var planner config.Planner
+		if recorder != nil {
+			planner = recorder
+		}
+		utility.CheckError(config.SaveWorktreeTargetDir(deps.AppConfig, dir, planner))
+		log.Info("worktreeTargetDir set", "dir", dir)
+	},
+}
+
+func init() {
+	moveCmd.Flags().BoolP("all", "a", false, "Move every worktree, into the folder given with --to")
+	moveCmd.Flags().StringP("to", "t", "", "Folder to move every worktree into with --all, saved as worktreeTargetDir")
+	moveCmd.Flags().BoolP("force-submodules", "f", false, "Move worktrees with submodules by moving their folders manually")
+}
+
+// printMoveResults writes a table of move results and returns how many
+// worktrees failed.
+func printMoveResults(out io.Writer, results []services.MoveResult) int {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WORKTREE\tBRANCH\tRESULT\tDETAIL")
+	failed := 0
+	for _, r := range results {
+		status, detail := "moved", r.NewPath
+		switch {
+		case r.Err != nil:
+			failed++
+			status, detail = "failed", r.Err.Error()
+		case r.Skipped:
+			status, detail = "skipped", "already there"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Worktree.Folder, transformer.DisplayBranch(r.Worktree), status, detail)
+	}
+	utility.CheckError(w.Flush())
+	return failed
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/utility"
+	"github.com/spf13/cobra"
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Forget worktrees whose folders were deleted",
+	Long: `Remove git's records of worktrees whose folders are gone, e.g. deleted
+with rm -rf instead of treekanga delete. Until they're pruned their
+branches count as checked out and can't be added again.
+
+    treekanga prune
+    treekanga prune --dry-run  # list them without pruning`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		pruned, err := deps.Git.PruneWorktrees(deps.AppConfig.BareRepoPath)
+		utility.CheckError(err)
+
+		if len(pruned) == 0 {
+			log.Info("no worktrees to prune")
+			return
+		}
+		for _, entry := range pruned {
+			if recorder != nil {
+				log.Info("would prune", "worktree", entry)
+			} else {
+				log.Info("pruned", "worktree", entry)
+			}
+		}
+	},
+}
@@ -7,11 +7,12 @@ import (
 )
 
 var renameCmd = &cobra.Command{
-	Use:   "rename <new-branch-name>",
-	Short: "Rename the current worktree and branch",
-	Long: `Rename the current worktree's branch and folder structure.
+	Use:   "rename [worktree] <new-branch-name>",
+	Short: "Rename a worktree and its branch",
+	Long: `Rename a worktree's branch and folder structure.
 
-    This command renames both the git branch and the worktree folder.
+    This command renames both the git branch and the worktree folder, of the
+    worktree you are in or of the one named by its folder or branch name.
     Branch names can contain slashes (e.g., feature/new-feature), which
     will be converted to dashes in the folder name (feature-new-feature).
 
@@ -20,16 +21,22 @@ var renameCmd = &cobra.Command{
       treekanga rename bugfix/issue-123
       treekanga rename feature/new-feature -s  # auto-switch tmux session
       treekanga rename feature/new-feature -f  # force rename with submodules
+      treekanga rename feature-old feature/new  # rename another worktree
+      treekanga rename feature/new-feature --remote  # rename on origin too
 
     Flags:
     -s, --switch: Automatically switch to new tmux session (skip prompt)
     -f, --force-submodules: Force rename by manually moving worktree with submodules
+    --remote: Push the new branch name to origin, track it and delete the old one
 
     Important notes:
-    - Only works from within a worktree (not from the bare repository)
+    - Without a worktree name it only works from within a worktree (not from
+      the bare repository)
     - The new branch name must not already exist locally or remotely
-    - After rename, you'll need to cd to the new folder path
-    - Your shell will be in an invalid directory after the rename
+    - branchNameTemplate, worktreeNameTemplate and branchNamePattern from the
+      config apply to the new name just like they do for add
+    - With the shell integration (see shell-init) your shell follows to the
+      new folder, otherwise you'll need to cd to it yourself
     - Use -f flag if your worktree contains submodules (git doesn't allow moving those)`,
 	Run: func(cmd *cobra.Command, args []string) {
 		autoSwitch, err := cmd.Flags().GetBool("switch")
@@ -44,13 +51,22 @@ var renameCmd = &cobra.Command{
 			return
 		}
 
+		renameRemote, err := cmd.Flags().GetBool("remote")
+		if err != nil {
+			cmd.PrintErrln("Error:", err)
+			return
+		}
+
 		err = services.ExecuteRename(
 			deps.AppConfig,
 			args,
+			deps.Git,
 			deps.Connector,
+			deps.Shell,
 			confirmer.NewConfirmer(),
 			autoSwitch,
 			forceSubmodules,
+			renameRemote,
 		)
 		if err != nil {
 			cmd.PrintErrln("Error:", err)
@@ -61,5 +77,6 @@ var renameCmd = &cobra.Command{
 
 func init() {
 	renameCmd.Flags().BoolP("switch", "s", false, "Automatically switch to new tmux session without prompting")
+	renameCmd.Flags().Bool("remote", false, "Rename the branch on origin too: push the new name, track it and delete the old one")
 	renameCmd.Flags().BoolP("force-submodules", "f", false, "Force rename by manually moving worktree with submodules (bypasses git worktree move)")
 }
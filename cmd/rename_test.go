@@ -10,36 +10,52 @@ import (
 func TestValidateRenameArgs(t *testing.T) {
 	t.Run("valid single argument", func(t *testing.T) {
 		args := []string{"new-branch"}
-		branchName, err := services.ValidateRenameArgs(args)
+		worktree, branchName, err := services.ValidateRenameArgs(args)
 		assert.NoError(t, err)
+		assert.Equal(t, "", worktree)
 		assert.Equal(t, "new-branch", branchName)
 	})
 
 	t.Run("valid argument with slashes", func(t *testing.T) {
 		args := []string{"feature/new-branch"}
-		branchName, err := services.ValidateRenameArgs(args)
+		_, branchName, err := services.ValidateRenameArgs(args)
 		assert.NoError(t, err)
 		assert.Equal(t, "feature/new-branch", branchName)
 	})
 
+	t.Run("valid worktree and new branch name", func(t *testing.T) {
+		args := []string{"feature-old", "feature/new-branch"}
+		worktree, branchName, err := services.ValidateRenameArgs(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "feature-old", worktree)
+		assert.Equal(t, "feature/new-branch", branchName)
+	})
+
 	t.Run("error when no arguments", func(t *testing.T) {
 		args := []string{}
-		_, err := services.ValidateRenameArgs(args)
+		_, _, err := services.ValidateRenameArgs(args)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "provide new branch name")
 	})
 
 	t.Run("error when too many arguments", func(t *testing.T) {
-		args := []string{"branch1", "branch2"}
-		_, err := services.ValidateRenameArgs(args)
+		args := []string{"worktree", "branch1", "branch2"}
+		_, _, err := services.ValidateRenameArgs(args)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "too many arguments")
 	})
 
 	t.Run("error when empty string", func(t *testing.T) {
 		args := []string{"  "}
-		_, err := services.ValidateRenameArgs(args)
+		_, _, err := services.ValidateRenameArgs(args)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "cannot be empty")
 	})
+
+	t.Run("error when empty worktree", func(t *testing.T) {
+		args := []string{" ", "new-branch"}
+		_, _, err := services.ValidateRenameArgs(args)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "worktree name cannot be empty")
+	})
 }
@@ -3,14 +3,20 @@ package cmd
 import (
 	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/charmbracelet/fang"
+	"github.com/charmbracelet/log"
 	"github.com/garrettkrohn/treekanga/config"
 	"github.com/garrettkrohn/treekanga/connector"
 	"github.com/garrettkrohn/treekanga/directoryReader"
 	"github.com/garrettkrohn/treekanga/execwrap"
+	"github.com/garrettkrohn/treekanga/forge"
 	"github.com/garrettkrohn/treekanga/git"
 	"github.com/garrettkrohn/treekanga/logger"
+	"github.com/garrettkrohn/treekanga/plan"
+	"github.com/garrettkrohn/treekanga/services"
 	"github.com/garrettkrohn/treekanga/shell"
 	"github.com/garrettkrohn/treekanga/utility"
 	"github.com/spf13/cobra"
@@ -20,18 +26,24 @@ type Dependencies struct {
 	DirectoryReader directoryReader.DirectoryReader
 	Connector       connector.Connector
 	Shell           shell.Shell
+	Git             git.Git
 	AppConfig       config.AppConfig
+	Forge           forge.Forge // nil when no forge is configured for the repo
 }
 
 var (
-	deps     Dependencies
-	logLevel string // Variable to store the log level
+	deps       Dependencies
+	logLevel   string // Variable to store the log level
+	dryRun     bool
+	planFormat string
+	recorder   *plan.Recorder // records the plan when --dry-run is set
 )
 
 func NewRootCmd(
 	directoryReader directoryReader.DirectoryReader,
 	conn connector.Connector,
 	shell shell.Shell,
+	gitClient git.Git,
 	version string) *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:     "treekanga",
@@ -41,20 +53,31 @@ func NewRootCmd(
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			logger.LoggerInit(logLevel)
 
+			// With --dry-run every git and shell command goes through the
+			// recorder: queries still run, changes are only planned
+			if dryRun {
+				utility.CheckError(plan.ValidateFormat(planFormat))
+				recorder = plan.NewRecorder(git.GetRunner(), shell)
+				git.SetRunner(recorder)
+				shell = recorder
+				conn = connector.NewConnector(recorder, gitClient)
+			}
+
 			deps = Dependencies{
 				DirectoryReader: directoryReader,
 				Connector:       conn,
 				Shell:           shell,
+				Git:             gitClient,
 			}
 
-			if cmd.Name() == "completion" || cmd.HasParent() && cmd.Parent().Name() == "completion" || cmd.Name() == "clone" {
+			if cmd.Name() == "completion" || cmd.HasParent() && cmd.Parent().Name() == "completion" || cmd.Name() == "clone" || cmd.Name() == "shell-init" {
 				return
 			}
 
-			bareRepoPath, err := git.GetBareRepoPath("")
+			bareRepoPath, err := gitClient.GetBareRepoPath("")
 			utility.CheckError(err)
 
-			projectName, err := git.GetProjectName()
+			projectName, err := gitClient.GetProjectName()
 			utility.CheckError(err)
 
 			// get app config
@@ -65,12 +88,33 @@ func NewRootCmd(
 			// import yaml config file
 			cfg, err = configuration.ImportYamlConfigFile(cfg)
 			deps.AppConfig = cfg
+			conn.SetSessionNameTemplate(cfg.SessionNameTemplate)
+			conn.SetEditors(cfg.Editors)
+			git.SetTimeouts(cfg.GitTimeouts)
+			if cfg.GitBackend == config.GitBackendGoGit {
+				deps.Git = git.NewGoGit(gitClient)
+			}
+
+			f, err := services.NewForgeFromConfig(gitClient, cfg)
+			if err != nil {
+				log.Warn("Pull request lookups disabled", "error", err)
+			}
+			deps.Forge = f
 
 		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			if recorder == nil {
+				return
+			}
+			err := plan.Write(cmd.OutOrStdout(), recorder.Plan(), planFormat)
+			utility.CheckError(err)
+		},
 	}
 
 	// Add the log level flag
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log", "l", "", "Set the log level (e.g., debug, info, warn, error)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the git commands, directories, branches and sessions a command would change without changing them")
+	rootCmd.PersistentFlags().StringVar(&planFormat, "plan-format", plan.FormatText, "Format of the --dry-run plan (text or json)")
 
 	return rootCmd
 }
@@ -79,12 +123,23 @@ func NewRootCmd(
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute(version string) {
 
+	// The first Ctrl-C kills the running git commands so the command fails
+	// (and rolls back) instead of hanging, a second one quits right away
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	git.SetContext(ctx)
+
 	execWrap := execwrap.NewExec()
 	shell := shell.NewShell(execWrap)
-	connector := connector.NewConnector(shell)
+	gitClient := git.NewGit()
+	connector := connector.NewConnector(shell, gitClient)
 	directoryReader := directoryReader.NewDirectoryReader()
 
-	rootCmd := NewRootCmd(directoryReader, connector, shell, version)
+	rootCmd := NewRootCmd(directoryReader, connector, shell, gitClient, version)
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(deleteCmd)
@@ -92,12 +147,21 @@ func Execute(version string) {
 	rootCmd.AddCommand(tuiCmd)
 	rootCmd.AddCommand(connectCmd)
 	rootCmd.AddCommand(renameCmd)
+	rootCmd.AddCommand(moveCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(fetchCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(setBaseCmd)
+	rootCmd.AddCommand(stackCmd)
+	rootCmd.AddCommand(stashCmd)
+	rootCmd.AddCommand(cdCmd)
+	rootCmd.AddCommand(shellInitCmd)
 
 	options := []fang.Option{
 		fang.WithVersion(version),
 	}
 
-	if err := fang.Execute(context.Background(), rootCmd, options...); err != nil {
+	if err := fang.Execute(ctx, rootCmd, options...); err != nil {
 		os.Exit(1)
 	}
 
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/services"
+	"github.com/garrettkrohn/treekanga/utility"
+	"github.com/spf13/cobra"
+)
+
+var setBaseCmd = &cobra.Command{
+	Use:   "set-base <base-branch> [worktree]",
+	Short: "Change the branch a worktree is compared against",
+	Long: `Record the branch a worktree's branch was cut from. Its ahead/behind
+and merged status, sync and the pull request column compare against it
+instead of the default branch.
+
+add records the base of every branch it creates, set-base changes it,
+e.g. after retargeting a pull request:
+
+    treekanga set-base release/2.0              # the worktree you are in
+    treekanga set-base release/2.0 feature/fix  # a worktree by branch or folder
+
+The base is kept in the git config as branch.<name>.treekangaBase.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := ""
+		if len(args) > 1 {
+			name = args[1]
+		}
+
+		dir, err := os.Getwd()
+		utility.CheckError(err)
+
+		wt, err := services.SetBase(deps.Git, deps.AppConfig.BareRepoPath, args[0], name, dir)
+		utility.CheckError(err)
+		log.Info("base branch set", "branch", wt.BranchName, "base", wt.BaseBranch)
+	},
+}
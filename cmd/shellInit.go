@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/garrettkrohn/treekanga/shellinit"
+	"github.com/garrettkrohn/treekanga/utility"
+	"github.com/spf13/cobra"
+)
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init <bash|zsh|fish>",
+	Short: "Print the shell function that lets treekanga change directory",
+	Long: `Print a treekanga shell function for your shell. Run through it,
+treekanga can change the shell's directory: add moves into the new worktree,
+rename into the renamed one, deleting the worktree you are in moves out of it,
+and cd moves into a worktree by name.
+
+Add it to your shell's startup file:
+
+    eval "$(treekanga shell-init bash)"         # ~/.bashrc
+    eval "$(treekanga shell-init zsh)"          # ~/.zshrc
+    treekanga shell-init fish | source          # ~/.config/fish/config.fish`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: shellinit.Shells(),
+	Run: func(cmd *cobra.Command, args []string) {
+		script, err := shellinit.Script(args[0])
+		utility.CheckError(err)
+		fmt.Fprint(cmd.OutOrStdout(), script)
+	},
+}
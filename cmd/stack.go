@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/services"
+	"github.com/garrettkrohn/treekanga/transformer"
+	"github.com/garrettkrohn/treekanga/utility"
+	"github.com/spf13/cobra"
+)
+
+var stackCmd = &cobra.Command{
+	Use:   "stack",
+	Short: "Show and restack stacked branches",
+	Long: `Work with stacks of branches, each cut from the one below it with
+add --stack-on:
+
+    treekanga add feature/api
+    treekanga add feature/ui --stack-on feature/api
+
+The parent is kept in the git config as branch.<name>.treekangaParent.`,
+}
+
+var stackShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show stacked branches as a tree",
+	Long: `Show every stack as a tree on top of the base branch it was cut
+from, with the commits each branch is ahead (↑) and behind (↓) its
+parent. A branch behind its parent needs a restack.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		stacks, err := services.BuildStacks(deps.Git, deps.AppConfig.BareRepoPath, deps.AppConfig.BaseBranch)
+		utility.CheckError(err)
+		if len(stacks) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "no stacked branches, stack one with add --stack-on")
+			return
+		}
+		for _, line := range transformer.StackLines(stacks) {
+			fmt.Fprintln(cmd.OutOrStdout(), line)
+		}
+	},
+}
+
+var stackRestackCmd = &cobra.Command{
+	Use:   "restack",
+	Short: "Rebase stacked branches onto their parents",
+	Long: `Rebase every stacked branch onto its parent, parents first, replaying
+only the commits the branch added on top of its parent. Run it after a
+parent gained commits or was rewritten, e.g. by sync.
+
+A conflict stops the restack with the rebase left in progress. Resolve
+it and run git rebase --continue in that worktree, then pick the
+restack up again:
+
+    treekanga stack restack --continue
+
+or drop the rest of it with --abort. Worktrees with uncommitted changes
+have to be committed or stashed first.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resume, err := cmd.Flags().GetBool("continue")
+		utility.CheckError(err)
+		abort, err := cmd.Flags().GetBool("abort")
+		utility.CheckError(err)
+		if resume && abort {
+			log.Fatal("--continue and --abort can't be combined")
+		}
+
+		bareRepoPath := deps.AppConfig.BareRepoPath
+		if abort {
+			utility.CheckError(services.AbortRestack(deps.Git, bareRepoPath))
+			log.Info("restack aborted")
+			return
+		}
+
+		var results []services.SyncResult
+		if resume {
+			results, err = services.ContinueRestack(deps.Git, bareRepoPath)
+		} else {
+			results, err = services.Restack(deps.Git, bareRepoPath)
+		}
+		if len(results) > 0 {
+			printSyncResults(cmd.OutOrStdout(), results)
+		}
+		utility.CheckError(err)
+	},
+}
+
+func init() {
+	stackRestackCmd.Flags().Bool("continue", false, "Resume a restack stopped by a conflict")
+	stackRestackCmd.Flags().Bool("abort", false, "Drop a restack stopped by a conflict")
+	stackCmd.AddCommand(stackShowCmd, stackRestackCmd)
+}
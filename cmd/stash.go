@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/services"
+	"github.com/garrettkrohn/treekanga/utility"
+	"github.com/spf13/cobra"
+)
+
+var stashCmd = &cobra.Command{
+	Use:   "stash",
+	Short: "List and move stashes across worktrees",
+	Long: `All worktrees of a repo share one stash list. List it grouped by the
+branch, and so the worktree, each stash was made on, or move a stash
+into another worktree.`,
+}
+
+var stashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stashes grouped by branch and worktree",
+	Long: `List every stash grouped by the branch it was made on, with the
+worktree that branch is checked out in. Worktrees come first, then
+branches without one, then stashes made on a detached HEAD.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		groups, err := services.ListStashGroups(deps.Git, deps.AppConfig.BareRepoPath)
+		utility.CheckError(err)
+		if len(groups) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "no stashes")
+			return
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "BRANCH\tWORKTREE\tSTASH\tCREATED\tMESSAGE")
+		for _, group := range groups {
+			branch, worktree := group.Branch, "(no worktree)"
+			if branch == "" {
+				branch, worktree = "(detached)", "-"
+			}
+			if group.Worktree != nil {
+				worktree = group.Worktree.Folder
+			}
+			for _, stash := range group.Stashes {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", branch, worktree, stash.Ref, stash.Created.Format("2006-01-02 15:04"), stash.Message)
+				// only name the group on its first line
+				branch, worktree = "", ""
+			}
+		}
+		utility.CheckError(w.Flush())
+	},
+}
+
+var stashMoveCmd = &cobra.Command{
+	Use:   "move <stash> <worktree>",
+	Short: "Apply a stash to another worktree",
+	Long: `Apply a stash to a worktree, named by branch or folder, and drop it
+from the stash list. The stash is given as stash@{n} or just n, as
+shown by stash list:
+
+    treekanga stash move stash@{2} feature/login
+    treekanga stash move 2 feature-login
+
+When applying it conflicts, the stash is kept.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, err := os.Getwd()
+		utility.CheckError(err)
+
+		stash, wt, err := services.MoveStash(deps.Git, deps.AppConfig.BareRepoPath, args[0], args[1], dir)
+		utility.CheckError(err)
+		log.Info("stash moved", "stash", stash.Message, "worktree", wt.Folder)
+	},
+}
+
+func init() {
+	stashCmd.AddCommand(stashListCmd, stashMoveCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/services"
+	"github.com/garrettkrohn/treekanga/transformer"
+	"github.com/garrettkrohn/treekanga/utility"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [worktree...]",
+	Short: "Rebase or merge worktrees onto the base branch",
+	Long: `Fetch the base branch once, then bring worktrees up to date with
+origin/<baseBranch> by rebasing them (the default) or merging it in.
+
+    treekanga sync                      # the worktree you are in
+    treekanga sync feature/a feature-b  # worktrees by branch or folder
+    treekanga sync --all                # every worktree
+
+Worktrees with uncommitted changes are skipped. A rebase or merge that
+conflicts is aborted, leaving the worktree as it was. Merge instead of
+rebasing with:
+  repos:
+    myrepo:
+      syncStrategy: merge`,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, err := cmd.Flags().GetBool("all")
+		utility.CheckError(err)
+
+		dir, err := os.Getwd()
+		utility.CheckError(err)
+
+		worktrees, err := services.SelectSyncWorktrees(deps.Git, deps.AppConfig.BareRepoPath, args, all, dir)
+		utility.CheckError(err)
+
+		results, err := services.SyncWorktrees(deps.Git, deps.AppConfig, worktrees)
+		utility.CheckError(err)
+
+		failed := printSyncResults(cmd.OutOrStdout(), results)
+
+		if failed > 0 {
+			log.Fatal(fmt.Sprintf("%d of %d worktrees could not be synced", failed, len(results)))
+		}
+	},
+}
+
+func init() {
+	syncCmd.Flags().BoolP("all", "a", false, "Sync every worktree")
+}
+
+// printSyncResults writes a table of sync results and returns how many
+// worktrees failed.
+func printSyncResults(out io.Writer, results []services.SyncResult) int {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WORKTREE\tBRANCH\tRESULT\tDETAIL")
+	failed := 0
+	for _, r := range results {
+		if r.Failed() {
+			failed++
+		}
+		detail := strings.SplitN(r.Reason, "\n", 2)[0]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Worktree.Folder, transformer.DisplayBranch(r.Worktree), r.Status, detail)
+	}
+	utility.CheckError(w.Flush())
+	return failed
+}
@@ -4,6 +4,7 @@ Copyright © 2024 Garrett Krohn <garrettkrohn@gmail.com>
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -14,6 +15,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	charmbraceletLog "github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/git"
 	"github.com/garrettkrohn/treekanga/tui"
 	"github.com/garrettkrohn/treekanga/utility"
 )
@@ -25,7 +27,8 @@ var tuiCmd = &cobra.Command{
 	Long: `Launch an interactive terminal user interface (TUI) for managing worktrees.
 
     The TUI provides:
-    - Interactive table view of all worktrees
+    - Interactive table view of all worktrees, with their status, pull
+      request and how many stashes were made on their branch
     - Real-time operation logs in the bottom pane
     - Add worktrees with the 'a' key
     - Delete worktrees with the 'd' key
@@ -44,13 +47,15 @@ var tuiCmd = &cobra.Command{
 			{Title: "Default", Width: 8},
 			{Title: "Remote", Width: 8},
 			{Title: "Merged", Width: 8},
+			{Title: "PR", Width: 24},
+			{Title: "Stash", Width: 6},
 		}
 
 		// Temporarily suppress logs during initial load to keep display clean
 		originalLevel := charmbraceletLog.GetLevel()
 		charmbraceletLog.SetLevel(charmbraceletLog.FatalLevel)
 
-		worktrees, err := tui.FetchWorktrees(deps.AppConfig)
+		worktrees, err := tui.FetchWorktrees(deps.Git, deps.AppConfig)
 		utility.CheckError(err)
 		rows := tui.WorktreeTableRows(worktrees)
 
@@ -84,9 +89,17 @@ var tuiCmd = &cobra.Command{
 		sp.Spinner = spinner.Dot
 		sp.Style = lipgloss.NewStyle().Foreground(theme.Accent)
 
-		m := tui.NewModel(t, sp, deps.Connector, deps.Shell, deps.AppConfig, deps.DirectoryReader, worktrees)
+		// Quitting kills the status and fetch commands still running in the
+		// background instead of leaving them behind
+		ctx, cancel := context.WithCancel(cmd.Context())
+		git.SetContext(ctx)
+
+		m := tui.NewModel(t, sp, deps.Connector, deps.Shell, deps.Git, deps.AppConfig, deps.DirectoryReader, deps.Forge, worktrees)
 		p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
-		if _, err := p.Run(); err != nil {
+		_, err = p.Run()
+		cancel()
+		git.Wait()
+		if err != nil {
 			fmt.Println("Error running program:", err)
 			os.Exit(1)
 		}
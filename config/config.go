@@ -5,16 +5,30 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/garrettkrohn/treekanga/models"
 	"github.com/spf13/viper"
 )
 
+// How sync brings a worktree up to date with its base branch
+const (
+	SyncRebase = "rebase"
+	SyncMerge  = "merge"
+)
+
+// Backends selectable with the gitBackend config
+const (
+	GitBackendExec  = "exec"
+	GitBackendGoGit = "go-git"
+)
+
 type AppConfig struct {
 	BareRepoPath               string // path to the bare repo, this is where the git commnand will be run from
 	AllBareRepoPaths           []string
 	RepoNameForConfig          string   // this is the git project name, used to find the config
+	RepoConfigKey              string   // where the repo's settings are in the config file, e.g. repos.treekanga
 	ParentDirOfBareRepo        string   // this is an option for configuration to allow the user to have multiple configs for multiple instances of one project
 	BaseBranch                 string   // default base branch
 	WorktreeTargetDir          string   // this is where the added worktree will be
@@ -24,16 +38,56 @@ type AppConfig struct {
 	RunPostScript              bool     // run the post script without the execute flag
 	PullBeforeCuttingNewBranch bool     // pull before cutting new branch
 	Theme                      *models.Theme
+	Editors                    map[string]string // editor name to command template, used by --open on top of the presets
+
+	// GIT
+	GitTimeouts map[string]time.Duration // per git subcommand, "default" for the rest, on top of git.DefaultTimeouts
+	GitBackend  string                   // exec, or go-git to answer the read-only queries in process
+
+	// TUI
+	BackgroundFetch time.Duration // how often the TUI fetches the repo while open, 0 disables
+
+	// FORGE
+	ForgeType    string // forge hosting the repo ("github"), empty disables PR lookups
+	ForgeBaseURL string // API base url, defaults to the public API for ForgeType
+	ForgeRepo    string // owner/repo on the forge, derived from remote.origin.url when empty
+	ForgeToken   string // API token, falls back to GITHUB_TOKEN / GH_TOKEN
+
+	// ISSUE TRACKER
+	IssueTracker        string            // jira, github or linear, used by add --issue
+	IssueTrackerBaseURL string            // API base url, required for jira
+	IssueTrackerUser    string            // jira account email for basic auth
+	IssueTrackerToken   string            // API token, falls back to JIRA_API_TOKEN / GITHUB_TOKEN / LINEAR_API_KEY
+	BranchTemplate      string            // template for branches created from issues, e.g. {{.Type}}/{{.Key}}-{{.Slug}}
+	IssueTypes          map[string]string // maps tracker issue types to the {{.Type}} used in BranchTemplate
+
+	// NAMING
+	BranchNameTemplate         string   // template applied to new branch names, e.g. {{.User}}/{{.Name}}
+	WorktreeNameTemplate       string   // template for worktree folder names, e.g. {{.Repo}}-{{.Branch}}
+	SessionNameTemplate        string   // template for tmux session names, e.g. {{.Repo}}-{{.Branch}}
+	BranchNameRegex            string   // new branches must match this regex
+	BranchNameMaxLength        int      // new branches can't be longer than this, 0 disables the check
+	BranchNameForbiddenChars   string   // new branches can't contain any of these characters
+	BranchNameRequiredPrefixes []string // new branches must start with one of these
 
 	// DELETE COMMAND
 	FilterOnlyStaleBranches bool // only show branches that don't exist on remote
+	FilterOnlyMerged        bool // only show branches merged into the base branch, locally or via their PR
 	DeleteBranch            bool // in addition to the worktree, delete the branch as well
 	ForceDelete             bool // use --force when deleting
 
+	// SYNC COMMAND
+	SyncStrategy string // rebase or merge, how sync brings worktrees up to date with the base branch
+
 	// ADD COMMAND
+	IssueKey                 string
+	AddRef                   string // tag or commit to create the worktree at, instead of the base branch
+	Detach                   bool   // check AddRef out detached, without creating a branch
+	KeepOnFailure            bool   // leave a failed add's worktree, branch and session behind for debugging
+	StackOn                  string // local branch the new branch is stacked on, recorded as its parent for stack restack
+	Carry                    bool   // move the current worktree's uncommitted changes into the new worktree
 	TmuxConnect              string
-	CursorConnect            bool
-	VsCodeConnect            bool
+	OpenEditor               string // editor to open the new worktree in, a preset or one of Editors
 	NewWorktreeName          string
 	NewBranchName            string
 	UseFormToSetBaseBranch   bool
@@ -74,6 +128,8 @@ func (c *ConfigInstance) GetDefaultConfig(bareRepoPath string, projectName strin
 		DeleteBranch:               false,
 		ForceDelete:                false,
 		Theme:                      GetTheme("default"),
+		GitBackend:                 GitBackendExec,
+		SyncStrategy:               SyncRebase,
 	}, nil
 }
 
@@ -112,11 +168,7 @@ func (c *ConfigInstance) ImportYamlConfigFile(cfg AppConfig) (AppConfig, error)
 		log.Debug(repoName)
 		worktreeTargetDir := viper.GetString("repos." + repoName + ".worktreeTargetDir")
 		if worktreeTargetDir != "" {
-			homeDir, err := os.UserHomeDir()
-			if err == nil {
-				worktreeTargetDir = filepath.Join(homeDir, strings.TrimPrefix(worktreeTargetDir, "~/"))
-			}
-			cfg.AllBareRepoPaths = append(cfg.AllBareRepoPaths, worktreeTargetDir)
+			cfg.AllBareRepoPaths = append(cfg.AllBareRepoPaths, expandTargetDir(worktreeTargetDir))
 		}
 	}
 	log.Debug(cfg.AllBareRepoPaths)
@@ -126,6 +178,7 @@ func (c *ConfigInstance) ImportYamlConfigFile(cfg AppConfig) (AppConfig, error)
 	if viperRepoPrefix == "" {
 		log.Fatal("error loading config")
 	}
+	cfg.RepoConfigKey = strings.TrimSuffix(viperRepoPrefix, ".")
 
 	if viper.IsSet(viperRepoPrefix + "autoPull") {
 		autoPull := viper.GetBool(viperRepoPrefix + "autoPull")
@@ -147,10 +200,7 @@ func (c *ConfigInstance) ImportYamlConfigFile(cfg AppConfig) (AppConfig, error)
 		worktreeTargetDir := viper.GetString(viperRepoPrefix + "worktreeTargetDir")
 		if worktreeTargetDir != "" {
 			// Expand tilde to home directory
-			homeDir, err := os.UserHomeDir()
-			if err == nil {
-				worktreeTargetDir = filepath.Join(homeDir, strings.TrimPrefix(worktreeTargetDir, "~/"))
-			}
+			worktreeTargetDir = expandTargetDir(worktreeTargetDir)
 			log.Debug(fmt.Sprintf("setting worktreeTargetDir: %s from config", worktreeTargetDir))
 			cfg.WorktreeTargetDir = worktreeTargetDir
 		}
@@ -188,6 +238,16 @@ func (c *ConfigInstance) ImportYamlConfigFile(cfg AppConfig) (AppConfig, error)
 		}
 	}
 
+	// editors apply to every repo, a repo's own editors take precedence
+	editors := viper.GetStringMapString("editors")
+	for name, command := range viper.GetStringMapString(viperRepoPrefix + "editors") {
+		editors[name] = command
+	}
+	if len(editors) > 0 {
+		log.Debug(fmt.Sprintf("setting editors: %v from config", editors))
+		cfg.Editors = editors
+	}
+
 	if viper.IsSet(viperRepoPrefix + "tuiTheme") {
 		tuiTheme := viper.GetString(viperRepoPrefix + "tuiTheme")
 		if tuiTheme != "" {
@@ -196,9 +256,212 @@ func (c *ConfigInstance) ImportYamlConfigFile(cfg AppConfig) (AppConfig, error)
 		}
 	}
 
+	if viper.IsSet(viperRepoPrefix + "forge") {
+		forgeType := viper.GetString(viperRepoPrefix + "forge")
+		if forgeType != "" {
+			log.Debug(fmt.Sprintf("setting forge: %s from config", forgeType))
+			cfg.ForgeType = forgeType
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "forgeBaseUrl") {
+		forgeBaseURL := viper.GetString(viperRepoPrefix + "forgeBaseUrl")
+		if forgeBaseURL != "" {
+			log.Debug(fmt.Sprintf("setting forgeBaseUrl: %s from config", forgeBaseURL))
+			cfg.ForgeBaseURL = forgeBaseURL
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "forgeRepo") {
+		forgeRepo := viper.GetString(viperRepoPrefix + "forgeRepo")
+		if forgeRepo != "" {
+			log.Debug(fmt.Sprintf("setting forgeRepo: %s from config", forgeRepo))
+			cfg.ForgeRepo = forgeRepo
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "forgeToken") {
+		forgeToken := viper.GetString(viperRepoPrefix + "forgeToken")
+		if forgeToken != "" {
+			log.Debug("setting forgeToken from config")
+			cfg.ForgeToken = forgeToken
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "issueTracker") {
+		issueTracker := viper.GetString(viperRepoPrefix + "issueTracker")
+		if issueTracker != "" {
+			log.Debug(fmt.Sprintf("setting issueTracker: %s from config", issueTracker))
+			cfg.IssueTracker = issueTracker
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "issueTrackerBaseUrl") {
+		issueTrackerBaseURL := viper.GetString(viperRepoPrefix + "issueTrackerBaseUrl")
+		if issueTrackerBaseURL != "" {
+			log.Debug(fmt.Sprintf("setting issueTrackerBaseUrl: %s from config", issueTrackerBaseURL))
+			cfg.IssueTrackerBaseURL = issueTrackerBaseURL
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "issueTrackerUser") {
+		issueTrackerUser := viper.GetString(viperRepoPrefix + "issueTrackerUser")
+		if issueTrackerUser != "" {
+			log.Debug(fmt.Sprintf("setting issueTrackerUser: %s from config", issueTrackerUser))
+			cfg.IssueTrackerUser = issueTrackerUser
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "issueTrackerToken") {
+		issueTrackerToken := viper.GetString(viperRepoPrefix + "issueTrackerToken")
+		if issueTrackerToken != "" {
+			log.Debug("setting issueTrackerToken from config")
+			cfg.IssueTrackerToken = issueTrackerToken
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "branchTemplate") {
+		branchTemplate := viper.GetString(viperRepoPrefix + "branchTemplate")
+		if branchTemplate != "" {
+			log.Debug(fmt.Sprintf("setting branchTemplate: %s from config", branchTemplate))
+			cfg.BranchTemplate = branchTemplate
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "issueTypes") {
+		issueTypes := viper.GetStringMapString(viperRepoPrefix + "issueTypes")
+		if len(issueTypes) > 0 {
+			log.Debug(fmt.Sprintf("setting issueTypes: %v from config", issueTypes))
+			cfg.IssueTypes = issueTypes
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "branchNameTemplate") {
+		branchNameTemplate := viper.GetString(viperRepoPrefix + "branchNameTemplate")
+		if branchNameTemplate != "" {
+			log.Debug(fmt.Sprintf("setting branchNameTemplate: %s from config", branchNameTemplate))
+			cfg.BranchNameTemplate = branchNameTemplate
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "worktreeNameTemplate") {
+		worktreeNameTemplate := viper.GetString(viperRepoPrefix + "worktreeNameTemplate")
+		if worktreeNameTemplate != "" {
+			log.Debug(fmt.Sprintf("setting worktreeNameTemplate: %s from config", worktreeNameTemplate))
+			cfg.WorktreeNameTemplate = worktreeNameTemplate
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "sessionNameTemplate") {
+		sessionNameTemplate := viper.GetString(viperRepoPrefix + "sessionNameTemplate")
+		if sessionNameTemplate != "" {
+			log.Debug(fmt.Sprintf("setting sessionNameTemplate: %s from config", sessionNameTemplate))
+			cfg.SessionNameTemplate = sessionNameTemplate
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "branchNamePattern.regex") {
+		branchNameRegex := viper.GetString(viperRepoPrefix + "branchNamePattern.regex")
+		if branchNameRegex != "" {
+			log.Debug(fmt.Sprintf("setting branchNamePattern.regex: %s from config", branchNameRegex))
+			cfg.BranchNameRegex = branchNameRegex
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "branchNamePattern.maxLength") {
+		branchNameMaxLength := viper.GetInt(viperRepoPrefix + "branchNamePattern.maxLength")
+		if branchNameMaxLength > 0 {
+			log.Debug(fmt.Sprintf("setting branchNamePattern.maxLength: %d from config", branchNameMaxLength))
+			cfg.BranchNameMaxLength = branchNameMaxLength
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "branchNamePattern.forbiddenChars") {
+		branchNameForbiddenChars := viper.GetString(viperRepoPrefix + "branchNamePattern.forbiddenChars")
+		if branchNameForbiddenChars != "" {
+			log.Debug(fmt.Sprintf("setting branchNamePattern.forbiddenChars: %s from config", branchNameForbiddenChars))
+			cfg.BranchNameForbiddenChars = branchNameForbiddenChars
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "branchNamePattern.requiredPrefixes") {
+		branchNameRequiredPrefixes := viper.GetStringSlice(viperRepoPrefix + "branchNamePattern.requiredPrefixes")
+		if len(branchNameRequiredPrefixes) > 0 {
+			log.Debug(fmt.Sprintf("setting branchNamePattern.requiredPrefixes: %s from config", branchNameRequiredPrefixes))
+			cfg.BranchNameRequiredPrefixes = branchNameRequiredPrefixes
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "gitTimeouts") {
+		gitTimeouts := map[string]time.Duration{}
+		for op, value := range viper.GetStringMapString(viperRepoPrefix + "gitTimeouts") {
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				log.Warn(fmt.Sprintf("ignoring gitTimeouts.%s: %v", op, err))
+				continue
+			}
+			gitTimeouts[op] = timeout
+		}
+		log.Debug(fmt.Sprintf("setting gitTimeouts: %v from config", gitTimeouts))
+		cfg.GitTimeouts = gitTimeouts
+	}
+
+	if viper.IsSet(viperRepoPrefix + "backgroundFetch") {
+		value := viper.GetString(viperRepoPrefix + "backgroundFetch")
+		backgroundFetch, err := time.ParseDuration(value)
+		if err != nil || backgroundFetch < 0 {
+			log.Warn(fmt.Sprintf("ignoring backgroundFetch %q, expected a duration like 10m", value))
+		} else {
+			log.Debug(fmt.Sprintf("setting backgroundFetch: %s from config", backgroundFetch))
+			cfg.BackgroundFetch = backgroundFetch
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "gitBackend") {
+		gitBackend := viper.GetString(viperRepoPrefix + "gitBackend")
+		switch gitBackend {
+		case GitBackendExec, GitBackendGoGit:
+			log.Debug(fmt.Sprintf("setting gitBackend: %s from config", gitBackend))
+			cfg.GitBackend = gitBackend
+		default:
+			log.Warn(fmt.Sprintf("ignoring gitBackend %q, expected %s or %s", gitBackend, GitBackendExec, GitBackendGoGit))
+		}
+	}
+
+	if viper.IsSet(viperRepoPrefix + "syncStrategy") {
+		syncStrategy := viper.GetString(viperRepoPrefix + "syncStrategy")
+		switch syncStrategy {
+		case SyncRebase, SyncMerge:
+			log.Debug(fmt.Sprintf("setting syncStrategy: %s from config", syncStrategy))
+			cfg.SyncStrategy = syncStrategy
+		default:
+			log.Warn(fmt.Sprintf("ignoring syncStrategy %q, expected %s or %s", syncStrategy, SyncRebase, SyncMerge))
+		}
+	}
+
 	return cfg, nil
 }
 
+// expandTargetDir resolves a worktreeTargetDir from the config, which is
+// relative to the home directory with or without ~/. Absolute paths are
+// kept when they exist or are in the home directory, like the ones move
+// saves.
+func expandTargetDir(dir string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return dir
+	}
+	if dir == "~" {
+		return homeDir
+	}
+	if filepath.IsAbs(dir) {
+		if _, err := os.Stat(dir); err == nil || strings.HasPrefix(dir, homeDir+string(filepath.Separator)) {
+			return dir
+		}
+	}
+	return filepath.Join(homeDir, strings.TrimPrefix(dir, "~/"))
+}
+
 func (cfg *AppConfig) Print() {
 	log.Info("=== AppConfig ===")
 	log.Info(fmt.Sprintf("BareRepoPath: %s", cfg.BareRepoPath))
@@ -210,7 +473,21 @@ func (cfg *AppConfig) Print() {
 	log.Info(fmt.Sprintf("PostScriptPath: %s", cfg.PostScriptPath))
 	log.Info(fmt.Sprintf("AutoRunPostScript: %t", cfg.RunPostScript))
 	log.Info(fmt.Sprintf("PullBeforeCuttingNewBranch: %t", cfg.PullBeforeCuttingNewBranch))
+	log.Info(fmt.Sprintf("GitBackend: %s", cfg.GitBackend))
+	log.Info(fmt.Sprintf("BackgroundFetch: %s", cfg.BackgroundFetch))
+	log.Info(fmt.Sprintf("SyncStrategy: %s", cfg.SyncStrategy))
+	log.Info(fmt.Sprintf("ForgeType: %s", cfg.ForgeType))
+	log.Info(fmt.Sprintf("ForgeBaseURL: %s", cfg.ForgeBaseURL))
+	log.Info(fmt.Sprintf("ForgeRepo: %s", cfg.ForgeRepo))
+	log.Info(fmt.Sprintf("IssueTracker: %s", cfg.IssueTracker))
+	log.Info(fmt.Sprintf("BranchTemplate: %s", cfg.BranchTemplate))
+	log.Info(fmt.Sprintf("BranchNameTemplate: %s", cfg.BranchNameTemplate))
+	log.Info(fmt.Sprintf("WorktreeNameTemplate: %s", cfg.WorktreeNameTemplate))
+	log.Info(fmt.Sprintf("SessionNameTemplate: %s", cfg.SessionNameTemplate))
+	log.Info(fmt.Sprintf("BranchNamePattern: regex=%s maxLength=%d forbiddenChars=%s requiredPrefixes=%v",
+		cfg.BranchNameRegex, cfg.BranchNameMaxLength, cfg.BranchNameForbiddenChars, cfg.BranchNameRequiredPrefixes))
 	log.Info(fmt.Sprintf("FilterOnlyStaleBranches: %t", cfg.FilterOnlyStaleBranches))
+	log.Info(fmt.Sprintf("FilterOnlyMerged: %t", cfg.FilterOnlyMerged))
 	log.Info(fmt.Sprintf("DeleteBranch: %t", cfg.DeleteBranch))
 	log.Info(fmt.Sprintf("ForceDelete: %t", cfg.ForceDelete))
 	log.Info("================")
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Planner records a change instead of making it, like the --dry-run
+// recorder does.
+type Planner interface {
+	PlanCommand(cmd string, args ...string)
+}
+
+// SaveWorktreeTargetDir sets the repo's worktreeTargetDir in the config
+// file. Only that line changes, so the rest of the file and its comments
+// stay as they are. dir is saved relative to ~ when it's in the home
+// directory. With a planner, in a dry run, the change is only planned.
+func SaveWorktreeTargetDir(cfg AppConfig, dir string, planner Planner) error {
+	path := viper.ConfigFileUsed()
+	if path == "" || cfg.RepoConfigKey == "" {
+		return fmt.Errorf("no config file to save worktreeTargetDir in")
+	}
+	key := cfg.RepoConfigKey + ".worktreeTargetDir"
+	value := homeRelative(dir)
+
+	if planner != nil {
+		planner.PlanCommand("config", path, key, value)
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	updated, err := setValue(data, strings.Split(key, "."), value)
+	if err != nil {
+		return fmt.Errorf("failed to set %s in %s: %w", key, path, err)
+	}
+	if err := os.WriteFile(path, updated, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	viper.Set(key, value)
+	return nil
+}
+
+// homeRelative returns dir as ~/<path> when it's in the home directory.
+func homeRelative(dir string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return dir
+	}
+	if rel, err := filepath.Rel(homeDir, dir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		return "~/" + filepath.ToSlash(rel)
+	}
+	return dir
+}
+
+// setValue sets the scalar at keys in the yaml document data by editing its
+// lines. A missing key is added as the first one of its mapping.
+func setValue(data []byte, keys []string, value string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, fmt.Errorf("the config is empty")
+	}
+
+	node := doc.Content[0]
+	for _, key := range keys[:len(keys)-1] {
+		node = mappingValue(node, key)
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s isn't a mapping", key)
+		}
+	}
+	if node.Style&yaml.FlowStyle != 0 || len(node.Content) == 0 {
+		return nil, fmt.Errorf("%s isn't a block mapping", keys[len(keys)-2])
+	}
+
+	encoded, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	scalar := strings.TrimSpace(string(encoded))
+
+	lines := strings.Split(string(data), "\n")
+	last := keys[len(keys)-1]
+	if v := mappingValue(node, last); v != nil {
+		if v.Kind != yaml.ScalarNode || v.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			return nil, fmt.Errorf("%s isn't a single line value", last)
+		}
+		line := lines[v.Line-1][:v.Column-1] + scalar
+		if v.LineComment != "" {
+			line += " " + v.LineComment
+		}
+		lines[v.Line-1] = line
+		return []byte(strings.Join(lines, "\n")), nil
+	}
+
+	// Above the first key and its comment, at its indentation
+	first := node.Content[0]
+	at := first.Line - 1
+	if first.HeadComment != "" {
+		at -= strings.Count(first.HeadComment, "\n") + 1
+	}
+	line := strings.Repeat(" ", first.Column-1) + last + ": " + scalar
+	lines = append(lines[:at], append([]string{line}, lines[at:]...)...)
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// mappingValue returns the value of key in a mapping node, matching the key
+// regardless of case like viper does.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
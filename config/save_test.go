@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetValue(t *testing.T) {
+	keys := []string{"repos", "widget", "worktreeTargetDir"}
+
+	t.Run("replaces the value", func(t *testing.T) {
+		data := "# my config\nrepos:\n  widget:\n    defaultBranch: main\n    WorktreeTargetDir: ~/code # where worktrees go\n"
+		updated, err := setValue([]byte(data), keys, "/disk/widget work")
+		require.NoError(t, err)
+		assert.Equal(t, "# my config\nrepos:\n  widget:\n    defaultBranch: main\n    WorktreeTargetDir: /disk/widget work # where worktrees go\n", string(updated))
+	})
+
+	t.Run("adds a missing key", func(t *testing.T) {
+		data := "repos:\n  other:\n    defaultBranch: main\n  widget:\n    # the main branch\n    defaultBranch: main\n"
+		updated, err := setValue([]byte(data), keys, "~/disk")
+		require.NoError(t, err)
+		assert.Equal(t, "repos:\n  other:\n    defaultBranch: main\n  widget:\n    worktreeTargetDir: ~/disk\n    # the main branch\n    defaultBranch: main\n", string(updated))
+	})
+
+	t.Run("quotes values yaml would misread", func(t *testing.T) {
+		updated, err := setValue([]byte("repos:\n  widget:\n    worktreeTargetDir: ~/code\n"), keys, "~/a: b")
+		require.NoError(t, err)
+		assert.Equal(t, "repos:\n  widget:\n    worktreeTargetDir: '~/a: b'\n", string(updated))
+	})
+
+	_, err := setValue([]byte("repos: {widget: {defaultBranch: main}}\n"), keys, "~/disk")
+	assert.EqualError(t, err, "widget isn't a block mapping")
+
+	_, err = setValue([]byte("repos:\n  other:\n    defaultBranch: main\n"), keys, "~/disk")
+	assert.EqualError(t, err, "widget isn't a mapping")
+}
@@ -1,6 +1,7 @@
 package connector
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,33 +9,60 @@ import (
 
 	"github.com/charmbracelet/log"
 	"github.com/garrettkrohn/treekanga/adapters"
+	"github.com/garrettkrohn/treekanga/editor"
+	"github.com/garrettkrohn/treekanga/form"
 	"github.com/garrettkrohn/treekanga/git"
 	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/naming"
 	"github.com/garrettkrohn/treekanga/shell"
 	"github.com/garrettkrohn/treekanga/transformer"
 	"github.com/garrettkrohn/treekanga/util"
-	"github.com/garrettkrohn/treekanga/utility"
 )
 
+// ErrPostScript is returned when connecting worked but the post script
+// couldn't be started in the new session.
+var ErrPostScript = errors.New("post script failed")
+
 type Connector interface {
 	Connect(name string, opts models.ConnectOpts) error
 	ConnectWithConfig(name string, opts models.ConnectOpts, postScriptPath string, runPostScript bool) error
-	VsCodeConnect(newRootPath string)
-	CursorConnect(newRootPath string)
+	Find(name string, opts models.ConnectOpts) (models.Connection, error)
+	OpenEditor(name string, path string) error
+	SetSessionNameTemplate(tmpl string)
+	SetEditors(editors map[string]string)
 }
 
 type RealConnector struct {
-	shell shell.Shell
-	tmux  adapters.Tmux
+	shell               shell.Shell
+	git                 git.Git
+	tmux                adapters.Tmux
+	form                form.Form // asks which worktree was meant when several match
+	sessionNameTemplate string
+	editors             map[string]string // editor name to command template, on top of the presets
 }
 
-func NewConnector(shell shell.Shell) Connector {
+func NewConnector(shell shell.Shell, git git.Git) Connector {
 	return &RealConnector{
 		shell: shell,
+		git:   git,
 		tmux:  adapters.NewTmux(shell),
+		form:  form.NewHuhForm(),
 	}
 }
 
+// SetSessionNameTemplate sets the sessionNameTemplate used to name sessions
+// for worktrees. The config is loaded after the connector is built, so the
+// root command sets it once the repo config is known.
+func (r *RealConnector) SetSessionNameTemplate(tmpl string) {
+	r.sessionNameTemplate = tmpl
+}
+
+// SetEditors sets the editors from the config that OpenEditor can use on
+// top of the presets.
+func (r *RealConnector) SetEditors(editors map[string]string) {
+	r.editors = editors
+}
+
 // Connect attempts to connect to a session using various strategies
 func (r *RealConnector) Connect(name string, opts models.ConnectOpts) error {
 	return r.ConnectWithConfig(name, opts, "", false)
@@ -42,7 +70,17 @@ func (r *RealConnector) Connect(name string, opts models.ConnectOpts) error {
 
 // ConnectWithConfig attempts to connect to a session and optionally runs a post-script
 func (r *RealConnector) ConnectWithConfig(name string, opts models.ConnectOpts, postScriptPath string, runPostScript bool) error {
-	strategies := []func(string) (models.Connection, error){
+	connection, err := r.Find(name, opts)
+	if err != nil {
+		return err
+	}
+	return r.connectToTmuxWithPostScript(connection, opts, postScriptPath, runPostScript)
+}
+
+// Find resolves name to a session, worktree or directory using the same
+// strategies as Connect, without connecting to it.
+func (r *RealConnector) Find(name string, opts models.ConnectOpts) (models.Connection, error) {
+	strategies := []func(string, models.ConnectOpts) (models.Connection, error){
 		r.tmuxStrategy,
 		r.worktreeStrategy,
 		r.dirStrategy,
@@ -50,9 +88,9 @@ func (r *RealConnector) ConnectWithConfig(name string, opts models.ConnectOpts,
 
 	var connection models.Connection
 	for _, strategy := range strategies {
-		conn, err := strategy(name)
+		conn, err := strategy(name, opts)
 		if err != nil {
-			return fmt.Errorf("connection strategy error: %w", err)
+			return models.Connection{}, fmt.Errorf("connection strategy error: %w", err)
 		}
 		if conn.Found {
 			connection = conn
@@ -61,14 +99,13 @@ func (r *RealConnector) ConnectWithConfig(name string, opts models.ConnectOpts,
 	}
 
 	if !connection.Found {
-		return fmt.Errorf("no connection found for '%s'", name)
+		return models.Connection{}, fmt.Errorf("no connection found for '%s'", name)
 	}
-
-	return r.connectToTmuxWithPostScript(connection, opts, postScriptPath, runPostScript)
+	return connection, nil
 }
 
 // tmuxStrategy checks if a tmux session with the given name exists
-func (r *RealConnector) tmuxStrategy(name string) (models.Connection, error) {
+func (r *RealConnector) tmuxStrategy(name string, _ models.ConnectOpts) (models.Connection, error) {
 	session, exists := r.tmux.FindSession(name)
 	if !exists {
 		return models.Connection{Found: false}, nil
@@ -80,43 +117,88 @@ func (r *RealConnector) tmuxStrategy(name string) (models.Connection, error) {
 	}, nil
 }
 
-// worktreeStrategy checks if the name matches a worktree path
-func (r *RealConnector) worktreeStrategy(name string) (models.Connection, error) {
-	// Try to get bare repo path
-	bareRepoPath, err := git.GetBareRepoPath("")
+// worktreeStrategy checks if the name matches a worktree, see matchWorktrees.
+// When several do the user picks one, or with opts.First the most recently
+// modified is taken.
+func (r *RealConnector) worktreeStrategy(name string, opts models.ConnectOpts) (models.Connection, error) {
+	// Try to get bare repo path, of the repo the path is in when given one so
+	// worktrees of other repos are found too
+	dir := ""
+	if info, err := os.Stat(name); err == nil && info.IsDir() && filepath.IsAbs(name) {
+		dir = name
+	}
+	bareRepoPath, err := r.git.GetBareRepoPath(dir)
 	if err != nil {
 		// Not in a git repo, skip this strategy
 		return models.Connection{Found: false}, nil
 	}
+	if dir != "" && !filepath.IsAbs(bareRepoPath) {
+		bareRepoPath = filepath.Join(dir, bareRepoPath)
+	}
 
-	worktrees, err := git.ListWorktrees(bareRepoPath)
+	worktrees, err := r.git.ListWorktrees(bareRepoPath)
 	if err != nil {
 		return models.Connection{Found: false}, nil
 	}
 
 	// Parse worktrees and check if name matches any worktree path or name
-	worktreeObjects := transformer.TransformWorktrees(worktrees)
-	for _, wt := range worktreeObjects {
-		// Check if name matches the full path or the directory name
-		if wt.FullPath == name || wt.Folder == name {
-			sessionName := r.generateWorktreeSessionName(wt.FullPath, wt.BranchName)
-			return models.Connection{
-				Found: true,
-				New:   true,
-				Session: models.Session{
-					Name: sessionName,
-					Path: wt.FullPath,
-					Src:  "worktree",
-				},
-			}, nil
+	matches := matchWorktrees(transformer.TransformWorktrees(worktrees), name)
+	if len(matches) == 0 {
+		return models.Connection{Found: false}, nil
+	}
+
+	util.SortWorktreesByModTime(matches)
+	wt := matches[0]
+	if len(matches) > 1 && !opts.First {
+		wt, err = r.pickWorktree(name, matches)
+		if err != nil {
+			return models.Connection{}, err
 		}
 	}
 
-	return models.Connection{Found: false}, nil
+	// Detached worktrees have no branch, name their session after the folder
+	branchName := wt.BranchName
+	if wt.Detached {
+		branchName = wt.Folder
+	}
+	sessionName := r.generateWorktreeSessionName(wt.FullPath, branchName)
+	return models.Connection{
+		Found: true,
+		New:   true,
+		Session: models.Session{
+			Name: sessionName,
+			Path: wt.FullPath,
+			Src:  "worktree",
+		},
+	}, nil
+}
+
+// pickWorktree asks which of the worktrees name was meant for.
+func (r *RealConnector) pickWorktree(name string, worktrees []models.Worktree) (models.Worktree, error) {
+	byLabel := make(map[string]models.Worktree, len(worktrees))
+	labels := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		labels[i] = fmt.Sprintf("%s (%s)", transformer.DisplayBranch(wt), wt.FullPath)
+		byLabel[labels[i]] = wt
+	}
+
+	var selected string
+	r.form.SetSingleSelection(&selected)
+	r.form.SetOptions(labels)
+	r.form.SetTitle(fmt.Sprintf("Several worktrees match '%s':", name))
+	if err := r.form.Run(); err != nil {
+		return models.Worktree{}, err
+	}
+
+	wt, ok := byLabel[selected]
+	if !ok {
+		return models.Worktree{}, fmt.Errorf("no worktree selected for '%s'", name)
+	}
+	return wt, nil
 }
 
 // dirStrategy checks if the name is a valid directory path
-func (r *RealConnector) dirStrategy(name string) (models.Connection, error) {
+func (r *RealConnector) dirStrategy(name string, _ models.ConnectOpts) (models.Connection, error) {
 	// Expand home directory if needed
 	path := name
 	if strings.HasPrefix(path, "~") {
@@ -162,24 +244,21 @@ func (r *RealConnector) generateSessionName(path string) string {
 	return util.SanitizeForSessionName(name)
 }
 
-// generateWorktreeSessionName creates a session name in the format "repo - branch"
+// generateWorktreeSessionName creates a session name from the sessionNameTemplate,
+// "repo-branch" by default (using dash instead of space-dash-space to avoid tmux parsing issues)
 func (r *RealConnector) generateWorktreeSessionName(worktreePath, branchName string) string {
-	// Get the parent directory name as the repo name
-	parentDir := filepath.Dir(worktreePath)
-	repoName := filepath.Base(parentDir)
-
-	// Clean up common suffixes from the repo name
-	repoName = strings.TrimSuffix(repoName, "_work")
-	repoName = strings.TrimSuffix(repoName, "_worktrees")
-	repoName = strings.TrimSuffix(repoName, "-bare")
-	repoName = strings.TrimSuffix(repoName, ".git")
-
-	// Sanitize both repo name and branch name for use in session name
-	safeRepoName := util.SanitizeForSessionName(repoName)
-	safeBranchName := util.SanitizeForSessionName(branchName)
-
-	// Format as "repo-branch" (using dash instead of space-dash-space to avoid tmux parsing issues)
-	return fmt.Sprintf("%s-%s", safeRepoName, safeBranchName)
+	data := naming.SessionNameData{
+		Repo:   naming.RepoName(filepath.Dir(worktreePath)),
+		Branch: branchName,
+		Folder: filepath.Base(worktreePath),
+	}
+
+	sessionName, err := naming.SessionName(r.sessionNameTemplate, data)
+	if err != nil {
+		log.Warn("Falling back to the default session name", "error", err)
+		sessionName, _ = naming.SessionName("", data)
+	}
+	return sessionName
 }
 
 // connectToTmux handles the actual connection to tmux
@@ -206,8 +285,7 @@ func (r *RealConnector) connectToTmuxWithPostScript(connection models.Connection
 		// Execute post-script in the tmux session if configured
 		if runPostScript && postScriptPath != "" {
 			if err := r.executePostScriptInTmux(connection.Session.Name, postScriptPath); err != nil {
-				// Log warning but don't fail the connection
-				log.Warn("Failed to execute post-script in tmux", "path", postScriptPath, "error", err)
+				return fmt.Errorf("%w: %w", ErrPostScript, err)
 			}
 		}
 	}
@@ -216,14 +294,18 @@ func (r *RealConnector) connectToTmuxWithPostScript(connection models.Connection
 	return r.tmux.SwitchOrAttach(connection.Session.Name, opts)
 }
 
-func (r *RealConnector) VsCodeConnect(newRootPath string) {
-	_, err := r.shell.Cmd("code", newRootPath)
-	utility.CheckError(err)
-}
-
-func (r *RealConnector) CursorConnect(newRootPath string) {
-	_, err := r.shell.Cmd("cursor", newRootPath)
-	utility.CheckError(err)
+// OpenEditor opens path in editor, one of the editors from the config or a
+// preset, see editor.Command.
+func (r *RealConnector) OpenEditor(name string, path string) error {
+	command, err := editor.Command(name, r.editors, editor.NewData(path))
+	if err != nil {
+		return err
+	}
+	log.Info("Opening in editor", "editor", name, "path", path)
+	if _, err := r.shell.Cmd(command[0], command[1:]...); err != nil {
+		return fmt.Errorf("failed to open %s in %s: %w", path, name, err)
+	}
+	return nil
 }
 
 // executePostScript runs the configured post-script in the given directory
@@ -6,9 +6,12 @@ import (
 	"testing"
 
 	"github.com/garrettkrohn/treekanga/execwrap"
+	"github.com/garrettkrohn/treekanga/form"
+	"github.com/garrettkrohn/treekanga/git"
 	"github.com/garrettkrohn/treekanga/models"
 	"github.com/garrettkrohn/treekanga/shell"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -214,6 +217,166 @@ func TestGenerateWorktreeSessionNameRealWorldExamples(t *testing.T) {
 	}
 }
 
+func TestWorktreeStrategy(t *testing.T) {
+	g := git.NewMockGit(t)
+	g.EXPECT().GetBareRepoPath("").Return("/code/repo_bare", nil)
+	g.EXPECT().ListWorktrees("/code/repo_bare").Return([]string{
+		"/code/repo_bare                 (bare)",
+		"/code/repo_work/feature-x       a1c4d34 [feature/x]",
+		"/code/repo_work/v2.3.1          b2d5e45 (detached HEAD)",
+	}, nil)
+	connector := &RealConnector{git: g}
+
+	conn, err := connector.worktreeStrategy("feature-x", models.ConnectOpts{})
+	require.NoError(t, err)
+	assert.True(t, conn.Found)
+	assert.Equal(t, "/code/repo_work/feature-x", conn.Session.Path)
+	assert.Equal(t, "repo-feature-x", conn.Session.Name)
+
+	conn, err = connector.worktreeStrategy("/code/repo_work/v2.3.1", models.ConnectOpts{})
+	require.NoError(t, err)
+	assert.True(t, conn.Found)
+	assert.Equal(t, "repo-v2_3_1", conn.Session.Name)
+
+	conn, err = connector.worktreeStrategy("missing", models.ConnectOpts{})
+	require.NoError(t, err)
+	assert.False(t, conn.Found)
+}
+
+func TestMatchWorktrees(t *testing.T) {
+	worktrees := []models.Worktree{
+		{FullPath: "/code/repo_work/main", Folder: "main", BranchName: "main"},
+		{FullPath: "/code/repo_work/login", Folder: "login", BranchName: "feature/PROJ-123-login"},
+		{FullPath: "/code/repo_work/PROJ-1234", Folder: "PROJ-1234", BranchName: "feature/PROJ-1234-signup"},
+		{FullPath: "/code/repo_work/maintenance", Folder: "maintenance", BranchName: "chore/maintenance"},
+		{FullPath: "/code/repo_work/v2.3.1", Folder: "v2.3.1", Detached: true},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"exact folder beats prefix", "main", []string{"main"}},
+		{"exact branch", "feature/PROJ-123-login", []string{"login"}},
+		{"exact path", "/code/repo_work/v2.3.1", []string{"v2.3.1"}},
+		{"issue key as a whole key", "proj-123", []string{"login"}},
+		{"issue key in the folder", "PROJ-1234", []string{"PROJ-1234"}},
+		{"branch prefix", "feature/", []string{"login", "PROJ-1234"}},
+		{"folder prefix", "maint", []string{"maintenance"}},
+		{"substring", "signup", []string{"PROJ-1234"}},
+		{"detached by folder", "v2", []string{"v2.3.1"}},
+		{"paths only match exactly", "./main", nil},
+		{"no match", "missing", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var folders []string
+			for _, wt := range matchWorktrees(worktrees, tt.query) {
+				folders = append(folders, wt.Folder)
+			}
+			assert.Equal(t, tt.expected, folders)
+		})
+	}
+
+	t.Run("existing folders only match exactly", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "sign"), 0755))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "main"), 0755))
+		t.Chdir(dir)
+
+		assert.Empty(t, matchWorktrees(worktrees, "sign"))
+		assert.Len(t, matchWorktrees(worktrees, "main"), 1)
+	})
+}
+
+func TestWorktreeStrategyAmbiguous(t *testing.T) {
+	listing := []string{
+		"/code/repo_bare  (bare)",
+		"/code/repo_work/feature-a  a1c4d34 [feature/a]",
+		"/code/repo_work/feature-b  b2d5e45 [feature/b]",
+	}
+
+	t.Run("asks which worktree was meant", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetBareRepoPath("").Return("/code/repo_bare", nil)
+		g.EXPECT().ListWorktrees("/code/repo_bare").Return(listing, nil)
+		f := form.NewMockForm(t)
+		var selection *string
+		f.EXPECT().SetSingleSelection(mock.Anything).Run(func(s *string) { selection = s })
+		f.EXPECT().SetOptions([]string{
+			"feature/a (/code/repo_work/feature-a)",
+			"feature/b (/code/repo_work/feature-b)",
+		})
+		f.EXPECT().SetTitle("Several worktrees match 'feat':")
+		f.EXPECT().Run().RunAndReturn(func() error {
+			*selection = "feature/b (/code/repo_work/feature-b)"
+			return nil
+		})
+		connector := &RealConnector{git: g, form: f}
+
+		conn, err := connector.worktreeStrategy("feat", models.ConnectOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, "/code/repo_work/feature-b", conn.Session.Path)
+	})
+
+	t.Run("takes the first one without asking", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetBareRepoPath("").Return("/code/repo_bare", nil)
+		g.EXPECT().ListWorktrees("/code/repo_bare").Return(listing, nil)
+		connector := &RealConnector{git: g, form: form.NewMockForm(t)}
+
+		conn, err := connector.worktreeStrategy("feat", models.ConnectOpts{First: true})
+		require.NoError(t, err)
+		assert.True(t, conn.Found)
+	})
+
+	t.Run("fails when the prompt does", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetBareRepoPath("").Return("/code/repo_bare", nil)
+		g.EXPECT().ListWorktrees("/code/repo_bare").Return(listing, nil)
+		f := form.NewMockForm(t)
+		f.EXPECT().SetSingleSelection(mock.Anything)
+		f.EXPECT().SetOptions(mock.Anything)
+		f.EXPECT().SetTitle(mock.Anything)
+		f.EXPECT().Run().Return(assert.AnError)
+		connector := &RealConnector{git: g, form: f}
+
+		_, err := connector.worktreeStrategy("feat", models.ConnectOpts{})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestWorktreeStrategyOtherRepo(t *testing.T) {
+	worktree := filepath.Join(t.TempDir(), "gadget_work", "feature-y")
+	require.NoError(t, os.MkdirAll(worktree, 0755))
+	bareRepo := filepath.Join(filepath.Dir(filepath.Dir(worktree)), "gadget.git")
+
+	g := git.NewMockGit(t)
+	g.EXPECT().GetBareRepoPath(worktree).Return("../../gadget.git", nil)
+	g.EXPECT().ListWorktrees(bareRepo).Return([]string{
+		bareRepo + "  (bare)",
+		worktree + "  a1c4d34 [feature/y]",
+	}, nil)
+	connector := &RealConnector{git: g}
+
+	conn, err := connector.worktreeStrategy(worktree, models.ConnectOpts{})
+	require.NoError(t, err)
+	assert.True(t, conn.Found)
+	assert.Equal(t, "gadget-feature-y", conn.Session.Name)
+}
+
+func TestWorktreeStrategyOutsideRepo(t *testing.T) {
+	g := git.NewMockGit(t)
+	g.EXPECT().GetBareRepoPath("").Return("", assert.AnError)
+	connector := &RealConnector{git: g}
+
+	conn, err := connector.worktreeStrategy("feature-x", models.ConnectOpts{})
+	require.NoError(t, err)
+	assert.False(t, conn.Found)
+}
+
 func TestExecutePostScript(t *testing.T) {
 	// Skip if running in CI without shell
 	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
@@ -236,7 +399,7 @@ func TestExecutePostScript(t *testing.T) {
 		// Create connector with real shell
 		execWrap := execwrap.NewExec()
 		sh := shell.NewShell(execWrap)
-		connector := NewConnector(sh).(*RealConnector)
+		connector := NewConnector(sh, git.NewGit()).(*RealConnector)
 
 		// Execute the post script
 		err = connector.executePostScript(tempDir, scriptPath)
@@ -262,7 +425,7 @@ func TestExecutePostScript(t *testing.T) {
 		// Create connector with real shell
 		execWrap := execwrap.NewExec()
 		sh := shell.NewShell(execWrap)
-		connector := NewConnector(sh).(*RealConnector)
+		connector := NewConnector(sh, git.NewGit()).(*RealConnector)
 
 		// Execute the failing post script
 		err = connector.executePostScript(tempDir, scriptPath)
@@ -292,7 +455,7 @@ func TestConnectWithConfig_PostScript(t *testing.T) {
 		// Create connector
 		execWrap := execwrap.NewExec()
 		sh := shell.NewShell(execWrap)
-		connector := NewConnector(sh).(*RealConnector)
+		connector := NewConnector(sh, git.NewGit()).(*RealConnector)
 
 		// Try to connect to a non-existent session (will fail, but we just want to verify script isn't run)
 		opts := models.ConnectOpts{Switch: false}
@@ -303,3 +466,20 @@ func TestConnectWithConfig_PostScript(t *testing.T) {
 		assert.True(t, os.IsNotExist(err), "Marker file should not exist when runPostScript is false")
 	})
 }
+
+func TestOpenEditor(t *testing.T) {
+	mockShell := shell.NewMockShell(t)
+	mockShell.EXPECT().Cmd("goland", "/code/widget_work/feature-login").Return("", nil)
+	mockShell.EXPECT().Cmd("emacsclient", "-c", "/code/widget_work/feature-login").Return("", assert.AnError)
+	connector := &RealConnector{shell: mockShell}
+	connector.SetEditors(map[string]string{"emacs": "emacsclient -c {{.Path}}"})
+
+	require.NoError(t, connector.OpenEditor("goland", "/code/widget_work/feature-login"))
+
+	err := connector.OpenEditor("emacs", "/code/widget_work/feature-login")
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorContains(t, err, "failed to open /code/widget_work/feature-login in emacs")
+
+	err = connector.OpenEditor("notepad", "/code/widget_work/feature-login")
+	assert.ErrorContains(t, err, "unknown editor 'notepad'")
+}
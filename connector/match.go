@@ -0,0 +1,98 @@
+package connector
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// issueKeyPattern matches issue keys like JIRA-123
+var issueKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*-[0-9]+$`)
+
+// matchWorktrees returns the worktrees name refers to, trying from the
+// strictest match to the loosest and stopping at the first that finds any:
+//  1. the full path, folder or branch name
+//  2. an issue key like JIRA-123 in the branch or folder name
+//  3. a prefix of the branch or folder name
+//  4. a part of the branch or folder name
+//
+// Matching is case-insensitive past the first step. Paths (starting with /,
+// ~ or .) and folders that exist, like src next to the current directory,
+// only match exactly, so they can still be connected to as folders.
+func matchWorktrees(worktrees []models.Worktree, name string) []models.Worktree {
+	matches := filterWorktrees(worktrees, func(wt models.Worktree) bool {
+		return wt.FullPath == name || wt.Folder == name || !wt.Detached && wt.BranchName == name
+	})
+	if len(matches) > 0 || name == "" || strings.ContainsAny(name[:1], "/~.") || isDir(name) {
+		return matches
+	}
+
+	lower := strings.ToLower(name)
+	names := func(wt models.Worktree) []string {
+		if wt.Detached {
+			return []string{strings.ToLower(wt.Folder)}
+		}
+		return []string{strings.ToLower(wt.BranchName), strings.ToLower(wt.Folder)}
+	}
+	anyName := func(match func(string) bool) func(models.Worktree) bool {
+		return func(wt models.Worktree) bool {
+			for _, n := range names(wt) {
+				if match(n) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	if issueKeyPattern.MatchString(name) {
+		if matches := filterWorktrees(worktrees, anyName(func(n string) bool { return containsIssueKey(n, lower) })); len(matches) > 0 {
+			return matches
+		}
+	}
+	if matches := filterWorktrees(worktrees, anyName(func(n string) bool { return strings.HasPrefix(n, lower) })); len(matches) > 0 {
+		return matches
+	}
+	return filterWorktrees(worktrees, anyName(func(n string) bool { return strings.Contains(n, lower) }))
+}
+
+// isDir reports whether name is a folder, relative to the current one.
+func isDir(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && info.IsDir()
+}
+
+// containsIssueKey reports whether s has key in it as a whole key, so
+// jira-12 is not found in feature/jira-123.
+func containsIssueKey(s, key string) bool {
+	for i := strings.Index(s, key); i >= 0; {
+		end := i + len(key)
+		before := i == 0 || !isAlphanumeric(s[i-1])
+		after := end == len(s) || s[end] < '0' || s[end] > '9'
+		if before && after {
+			return true
+		}
+		next := strings.Index(s[i+1:], key)
+		if next < 0 {
+			break
+		}
+		i += 1 + next
+	}
+	return false
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func filterWorktrees(worktrees []models.Worktree, keep func(models.Worktree) bool) []models.Worktree {
+	var kept []models.Worktree
+	for _, wt := range worktrees {
+		if keep(wt) {
+			kept = append(kept, wt)
+		}
+	}
+	return kept
+}
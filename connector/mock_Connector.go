@@ -3,7 +3,7 @@
 package connector
 
 import (
-	"github.com/garrettkrohn/treekanga/models"
+	models "github.com/garrettkrohn/treekanga/models"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -24,6 +24,10 @@ func (_m *MockConnector) EXPECT() *MockConnector_Expecter {
 func (_m *MockConnector) Connect(name string, opts models.ConnectOpts) error {
 	ret := _m.Called(name, opts)
 
+	if len(ret) == 0 {
+		panic("no return value specified for Connect")
+	}
+
 	var r0 error
 	if rf, ok := ret.Get(0).(func(string, models.ConnectOpts) error); ok {
 		r0 = rf(name, opts)
@@ -59,74 +63,225 @@ func (_c *MockConnector_Connect_Call) Return(_a0 error) *MockConnector_Connect_C
 }
 
 func (_c *MockConnector_Connect_Call) RunAndReturn(run func(string, models.ConnectOpts) error) *MockConnector_Connect_Call {
-	_c.Run(func(name string, opts models.ConnectOpts) {
-		run(name, opts)
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConnectWithConfig provides a mock function with given fields: name, opts, postScriptPath, runPostScript
+func (_m *MockConnector) ConnectWithConfig(name string, opts models.ConnectOpts, postScriptPath string, runPostScript bool) error {
+	ret := _m.Called(name, opts, postScriptPath, runPostScript)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConnectWithConfig")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, models.ConnectOpts, string, bool) error); ok {
+		r0 = rf(name, opts, postScriptPath, runPostScript)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockConnector_ConnectWithConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConnectWithConfig'
+type MockConnector_ConnectWithConfig_Call struct {
+	*mock.Call
+}
+
+// ConnectWithConfig is a helper method to define mock.On call
+//   - name string
+//   - opts models.ConnectOpts
+//   - postScriptPath string
+//   - runPostScript bool
+func (_e *MockConnector_Expecter) ConnectWithConfig(name interface{}, opts interface{}, postScriptPath interface{}, runPostScript interface{}) *MockConnector_ConnectWithConfig_Call {
+	return &MockConnector_ConnectWithConfig_Call{Call: _e.mock.On("ConnectWithConfig", name, opts, postScriptPath, runPostScript)}
+}
+
+func (_c *MockConnector_ConnectWithConfig_Call) Run(run func(name string, opts models.ConnectOpts, postScriptPath string, runPostScript bool)) *MockConnector_ConnectWithConfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(models.ConnectOpts), args[2].(string), args[3].(bool))
 	})
 	return _c
 }
 
-// CursorConnect provides a mock function with given fields: newRootPath
-func (_m *MockConnector) CursorConnect(newRootPath string) {
-	_m.Called(newRootPath)
+func (_c *MockConnector_ConnectWithConfig_Call) Return(_a0 error) *MockConnector_ConnectWithConfig_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConnector_ConnectWithConfig_Call) RunAndReturn(run func(string, models.ConnectOpts, string, bool) error) *MockConnector_ConnectWithConfig_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
-// MockConnector_CursorConnect_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CursorConnect'
-type MockConnector_CursorConnect_Call struct {
+// Find provides a mock function with given fields: name, opts
+func (_m *MockConnector) Find(name string, opts models.ConnectOpts) (models.Connection, error) {
+	ret := _m.Called(name, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 models.Connection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, models.ConnectOpts) (models.Connection, error)); ok {
+		return rf(name, opts)
+	}
+	if rf, ok := ret.Get(0).(func(string, models.ConnectOpts) models.Connection); ok {
+		r0 = rf(name, opts)
+	} else {
+		r0 = ret.Get(0).(models.Connection)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, models.ConnectOpts) error); ok {
+		r1 = rf(name, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockConnector_Find_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Find'
+type MockConnector_Find_Call struct {
 	*mock.Call
 }
 
-// CursorConnect is a helper method to define mock.On call
-//   - newRootPath string
-func (_e *MockConnector_Expecter) CursorConnect(newRootPath interface{}) *MockConnector_CursorConnect_Call {
-	return &MockConnector_CursorConnect_Call{Call: _e.mock.On("CursorConnect", newRootPath)}
+// Find is a helper method to define mock.On call
+//   - name string
+//   - opts models.ConnectOpts
+func (_e *MockConnector_Expecter) Find(name interface{}, opts interface{}) *MockConnector_Find_Call {
+	return &MockConnector_Find_Call{Call: _e.mock.On("Find", name, opts)}
 }
 
-func (_c *MockConnector_CursorConnect_Call) Run(run func(newRootPath string)) *MockConnector_CursorConnect_Call {
+func (_c *MockConnector_Find_Call) Run(run func(name string, opts models.ConnectOpts)) *MockConnector_Find_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(string), args[1].(models.ConnectOpts))
+	})
+	return _c
+}
+
+func (_c *MockConnector_Find_Call) Return(_a0 models.Connection, _a1 error) *MockConnector_Find_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockConnector_Find_Call) RunAndReturn(run func(string, models.ConnectOpts) (models.Connection, error)) *MockConnector_Find_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OpenEditor provides a mock function with given fields: name, path
+func (_m *MockConnector) OpenEditor(name string, path string) error {
+	ret := _m.Called(name, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenEditor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(name, path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockConnector_OpenEditor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OpenEditor'
+type MockConnector_OpenEditor_Call struct {
+	*mock.Call
+}
+
+// OpenEditor is a helper method to define mock.On call
+//   - name string
+//   - path string
+func (_e *MockConnector_Expecter) OpenEditor(name interface{}, path interface{}) *MockConnector_OpenEditor_Call {
+	return &MockConnector_OpenEditor_Call{Call: _e.mock.On("OpenEditor", name, path)}
+}
+
+func (_c *MockConnector_OpenEditor_Call) Run(run func(name string, path string)) *MockConnector_OpenEditor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockConnector_OpenEditor_Call) Return(_a0 error) *MockConnector_OpenEditor_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConnector_OpenEditor_Call) RunAndReturn(run func(string, string) error) *MockConnector_OpenEditor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetEditors provides a mock function with given fields: editors
+func (_m *MockConnector) SetEditors(editors map[string]string) {
+	_m.Called(editors)
+}
+
+// MockConnector_SetEditors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetEditors'
+type MockConnector_SetEditors_Call struct {
+	*mock.Call
+}
+
+// SetEditors is a helper method to define mock.On call
+//   - editors map[string]string
+func (_e *MockConnector_Expecter) SetEditors(editors interface{}) *MockConnector_SetEditors_Call {
+	return &MockConnector_SetEditors_Call{Call: _e.mock.On("SetEditors", editors)}
+}
+
+func (_c *MockConnector_SetEditors_Call) Run(run func(editors map[string]string)) *MockConnector_SetEditors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(map[string]string))
 	})
 	return _c
 }
 
-func (_c *MockConnector_CursorConnect_Call) Return() *MockConnector_CursorConnect_Call {
+func (_c *MockConnector_SetEditors_Call) Return() *MockConnector_SetEditors_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockConnector_CursorConnect_Call) RunAndReturn(run func(string)) *MockConnector_CursorConnect_Call {
+func (_c *MockConnector_SetEditors_Call) RunAndReturn(run func(map[string]string)) *MockConnector_SetEditors_Call {
 	_c.Run(run)
 	return _c
 }
 
-// VsCodeConnect provides a mock function with given fields: newRootPath
-func (_m *MockConnector) VsCodeConnect(newRootPath string) {
-	_m.Called(newRootPath)
+// SetSessionNameTemplate provides a mock function with given fields: tmpl
+func (_m *MockConnector) SetSessionNameTemplate(tmpl string) {
+	_m.Called(tmpl)
 }
 
-// MockConnector_VsCodeConnect_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VsCodeConnect'
-type MockConnector_VsCodeConnect_Call struct {
+// MockConnector_SetSessionNameTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetSessionNameTemplate'
+type MockConnector_SetSessionNameTemplate_Call struct {
 	*mock.Call
 }
 
-// VsCodeConnect is a helper method to define mock.On call
-//   - newRootPath string
-func (_e *MockConnector_Expecter) VsCodeConnect(newRootPath interface{}) *MockConnector_VsCodeConnect_Call {
-	return &MockConnector_VsCodeConnect_Call{Call: _e.mock.On("VsCodeConnect", newRootPath)}
+// SetSessionNameTemplate is a helper method to define mock.On call
+//   - tmpl string
+func (_e *MockConnector_Expecter) SetSessionNameTemplate(tmpl interface{}) *MockConnector_SetSessionNameTemplate_Call {
+	return &MockConnector_SetSessionNameTemplate_Call{Call: _e.mock.On("SetSessionNameTemplate", tmpl)}
 }
 
-func (_c *MockConnector_VsCodeConnect_Call) Run(run func(newRootPath string)) *MockConnector_VsCodeConnect_Call {
+func (_c *MockConnector_SetSessionNameTemplate_Call) Run(run func(tmpl string)) *MockConnector_SetSessionNameTemplate_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(string))
 	})
 	return _c
 }
 
-func (_c *MockConnector_VsCodeConnect_Call) Return() *MockConnector_VsCodeConnect_Call {
+func (_c *MockConnector_SetSessionNameTemplate_Call) Return() *MockConnector_SetSessionNameTemplate_Call {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockConnector_VsCodeConnect_Call) RunAndReturn(run func(string)) *MockConnector_VsCodeConnect_Call {
+func (_c *MockConnector_SetSessionNameTemplate_Call) RunAndReturn(run func(string)) *MockConnector_SetSessionNameTemplate_Call {
 	_c.Run(run)
 	return _c
 }
@@ -0,0 +1,166 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Data is the data available to editor command templates.
+type Data struct {
+	Path   string // the worktree or folder to open
+	Folder string // its folder name
+}
+
+// NewData returns the template data for opening path.
+func NewData(path string) Data {
+	return Data{Path: path, Folder: filepath.Base(path)}
+}
+
+type preset struct {
+	command string
+	needs   []string // programs that must be installed for the preset to show up in the TUI
+}
+
+// presets are the editors that work without configuring them. The terminal
+// editors open in a new window of the current tmux session.
+var presets = map[string]preset{
+	"code":      {command: "code {{.Path}}", needs: []string{"code"}},
+	"cursor":    {command: "cursor {{.Path}}", needs: []string{"cursor"}},
+	"zed":       {command: "zed {{.Path}}", needs: []string{"zed"}},
+	"idea":      {command: "idea {{.Path}}", needs: []string{"idea"}},
+	"goland":    {command: "goland {{.Path}}", needs: []string{"goland"}},
+	"webstorm":  {command: "webstorm {{.Path}}", needs: []string{"webstorm"}},
+	"pycharm":   {command: "pycharm {{.Path}}", needs: []string{"pycharm"}},
+	"phpstorm":  {command: "phpstorm {{.Path}}", needs: []string{"phpstorm"}},
+	"rubymine":  {command: "rubymine {{.Path}}", needs: []string{"rubymine"}},
+	"clion":     {command: "clion {{.Path}}", needs: []string{"clion"}},
+	"rider":     {command: "rider {{.Path}}", needs: []string{"rider"}},
+	"rustrover": {command: "rustrover {{.Path}}", needs: []string{"rustrover"}},
+	"nvim":      {command: "tmux new-window -n {{.Folder}} -c {{.Path}} nvim", needs: []string{"tmux", "nvim"}},
+	"helix":     {command: "tmux new-window -n {{.Folder}} -c {{.Path}} hx", needs: []string{"tmux", "hx"}},
+}
+
+// Names lists the editors from the config and the presets, sorted.
+func Names(editors map[string]string) []string {
+	names := make([]string, 0, len(presets)+len(editors))
+	for name := range presets {
+		names = append(names, name)
+	}
+	for name := range editors {
+		if _, ok := presets[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Installed lists the editors from the config and the presets whose
+// programs are on the PATH, sorted.
+func Installed(editors map[string]string) []string {
+	var names []string
+	for _, name := range Names(editors) {
+		if _, ok := editors[name]; ok || installed(presets[name].needs) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func installed(programs []string) bool {
+	for _, program := range programs {
+		if _, err := exec.LookPath(program); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Command renders the command that opens data.Path in the editor name.
+// Editors from the config take precedence over the presets. Every word of
+// the template is rendered on its own, so a path with spaces stays a single
+// argument; quote words to keep spaces in them.
+func Command(name string, editors map[string]string, data Data) ([]string, error) {
+	name = strings.ToLower(name)
+	tmpl, ok := editors[name]
+	if !ok {
+		p, found := presets[name]
+		if !found {
+			return nil, fmt.Errorf("unknown editor '%s', use one of %s or add it to editors in the config",
+				name, strings.Join(Names(editors), ", "))
+		}
+		tmpl = p.command
+	}
+
+	words, err := splitWords(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid command for editor '%s': %w", name, err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("editor '%s' has an empty command", name)
+	}
+
+	command := make([]string, len(words))
+	for i, word := range words {
+		t, err := template.New(name).Option("missingkey=error").Parse(word)
+		if err != nil {
+			return nil, fmt.Errorf("invalid command for editor '%s': %w", name, err)
+		}
+		var out bytes.Buffer
+		if err := t.Execute(&out, data); err != nil {
+			return nil, fmt.Errorf("failed to render the command for editor '%s': %w", name, err)
+		}
+		command[i] = out.String()
+	}
+	return command, nil
+}
+
+// splitWords splits a command template into words on whitespace, like a
+// shell would: quotes group words and are dropped. Template actions are
+// kept whole, so {{printf "%s/src" .Path}} isn't split.
+func splitWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for i := 0; i < len(s); i++ {
+		c := rune(s[i])
+		switch {
+		case quote == 0 && strings.HasPrefix(s[i:], "{{"):
+			end := strings.Index(s[i:], "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed action in %q", s)
+			}
+			word.WriteString(s[i : i+end+2])
+			inWord = true
+			i += end + 1
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+			inWord = true
+		case quote == 0 && unicode.IsSpace(c):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(s[i])
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unclosed quote in %q", s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,51 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommand(t *testing.T) {
+	data := NewData("/code/my widget_work/feature-login")
+	editors := map[string]string{
+		"code":  "code --new-window {{.Path}}",
+		"emacs": `emacsclient -c -a "" '{{.Path}}/src'`,
+		"wez":   `wezterm start --cwd {{printf "%s/src" .Path}} -- {{.Folder}}`,
+	}
+
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{"cursor", []string{"cursor", data.Path}},
+		{"CODE", []string{"code", "--new-window", data.Path}},
+		{"nvim", []string{"tmux", "new-window", "-n", "feature-login", "-c", data.Path, "nvim"}},
+		{"emacs", []string{"emacsclient", "-c", "-a", "", data.Path + "/src"}},
+		{"wez", []string{"wezterm", "start", "--cwd", data.Path + "/src", "--", "feature-login"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, err := Command(tt.name, editors, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, command)
+		})
+	}
+
+	_, err := Command("notepad", nil, data)
+	assert.ErrorContains(t, err, "unknown editor 'notepad', use one of clion, code, cursor")
+
+	_, err = Command("broken", map[string]string{"broken": "vim '{{.Path}}"}, data)
+	assert.ErrorContains(t, err, "unclosed quote")
+}
+
+func TestNames(t *testing.T) {
+	names := Names(map[string]string{"code": "code -n {{.Path}}", "emacs": "emacs {{.Path}}"})
+	assert.Contains(t, names, "emacs")
+	assert.Contains(t, names, "goland")
+	assert.IsIncreasing(t, names)
+
+	t.Setenv("PATH", t.TempDir())
+	assert.Equal(t, []string{"emacs"}, Installed(map[string]string{"emacs": "emacs {{.Path}}"}))
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/log"
 	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/transformer"
 )
 
 type Filter interface {
@@ -44,6 +45,14 @@ func (f *RealFilter) GetBranchNoMatchList(remoteBranches []string, worktreeBranc
 func (f *RealFilter) GetBranchMatchList(selectedBranchNames []string, allWorktrees []models.Worktree) []models.Worktree {
 	var selectedWorktreeObj []models.Worktree
 	for _, worktree := range allWorktrees {
+		if worktree.Detached {
+			// Detached worktrees have no branch, match their label or folder instead
+			if slices.Contains(selectedBranchNames, transformer.DisplayBranch(worktree)) ||
+				slices.Contains(selectedBranchNames, worktree.Folder) {
+				selectedWorktreeObj = append(selectedWorktreeObj, worktree)
+			}
+			continue
+		}
 		if slices.Contains(selectedBranchNames, worktree.BranchName) {
 			selectedWorktreeObj = append(selectedWorktreeObj, worktree)
 		}
@@ -113,4 +113,17 @@ func TestFilterWorktreesAndBranches(t *testing.T) {
 		assert.Equal(t, expected, result)
 	})
 
+	t.Run("detached worktrees match by label or folder", func(t *testing.T) {
+		worktrees := []models.Worktree{
+			{FullPath: "/path/to/v2.3.1", Folder: "v2.3.1", CommitHash: "abc1234", Detached: true, DetachedRef: "v2.3.1"},
+			{FullPath: "/path/to/sha", Folder: "sha", CommitHash: "def5678", Detached: true},
+			{FullPath: "/path/to/main", Folder: "main", BranchName: "main", CommitHash: "hash3"},
+		}
+
+		f := &RealFilter{}
+		result := f.GetBranchMatchList([]string{"(detached v2.3.1)", "sha"}, worktrees)
+
+		assert.Equal(t, worktrees[:2], result)
+		assert.Empty(t, f.GetBranchMatchList([]string{""}, worktrees), "an empty name must not match detached worktrees")
+	})
 }
@@ -0,0 +1,123 @@
+package forge
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// ErrNotFound is returned when the forge has no pull request for a branch.
+var ErrNotFound = errors.New("no pull request found")
+
+// Forge looks up pull request state on a code hosting service.
+type Forge interface {
+	// GetPullRequest returns the most recent pull request whose head is
+	// branch, or ErrNotFound if there is none.
+	GetPullRequest(branch string) (models.PullRequest, error)
+}
+
+// Options configures a Forge implementation.
+type Options struct {
+	Type     string        // "github"
+	BaseURL  string        // API base URL, e.g. https://api.github.com
+	Owner    string        // repository owner
+	Repo     string        // repository name
+	Token    string        // API token, optional for public repositories
+	CacheTTL time.Duration // how long a lookup is reused, 0 uses the default
+}
+
+const defaultCacheTTL = 5 * time.Minute
+
+// NewForge returns the Forge implementation selected by opts.Type, wrapped
+// in a per-branch cache.
+func NewForge(opts Options) (Forge, error) {
+	var f Forge
+	switch strings.ToLower(opts.Type) {
+	case "github":
+		f = NewGitHubForge(opts.BaseURL, opts.Owner, opts.Repo, opts.Token)
+	default:
+		return nil, fmt.Errorf("unsupported forge type: %q", opts.Type)
+	}
+
+	ttl := opts.CacheTTL
+	if ttl == 0 {
+		ttl = defaultCacheTTL
+	}
+	return NewCachedForge(f, ttl), nil
+}
+
+// ParseRepoSlug extracts owner and repository name from a remote URL such
+// as git@github.com:owner/repo.git or https://github.com/owner/repo.
+func ParseRepoSlug(remoteURL string) (owner, repo string, err error) {
+	path := strings.TrimSpace(remoteURL)
+	path = strings.TrimSuffix(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+
+	if i := strings.Index(path, "://"); i != -1 {
+		// scheme://host/owner/repo
+		path = path[i+3:]
+		if slash := strings.Index(path, "/"); slash != -1 {
+			path = path[slash+1:]
+		}
+	} else if colon := strings.Index(path, ":"); colon != -1 {
+		// user@host:owner/repo
+		path = path[colon+1:]
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return "", "", fmt.Errorf("could not determine owner/repo from remote url %q", remoteURL)
+	}
+	return parts[len(parts)-2], parts[len(parts)-1], nil
+}
+
+// CachedForge memoises lookups by branch name so repeated renders (the TUI
+// refreshing after every add/delete, delete --merged after list -v) don't
+// hit the API again.
+type CachedForge struct {
+	forge Forge
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	pr      models.PullRequest
+	err     error
+	fetched time.Time
+}
+
+// NewCachedForge wraps f so each branch is looked up at most once per ttl.
+func NewCachedForge(f Forge, ttl time.Duration) *CachedForge {
+	return &CachedForge{
+		forge:   f,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]cacheEntry{},
+	}
+}
+
+func (c *CachedForge) GetPullRequest(branch string) (models.PullRequest, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[branch]
+	c.mu.Unlock()
+	if ok && c.now().Sub(entry.fetched) < c.ttl {
+		return entry.pr, entry.err
+	}
+
+	pr, err := c.forge.GetPullRequest(branch)
+
+	// Only cache definitive answers; transient errors should be retried.
+	if err == nil || errors.Is(err, ErrNotFound) {
+		c.mu.Lock()
+		c.entries[branch] = cacheEntry{pr: pr, err: err, fetched: c.now()}
+		c.mu.Unlock()
+	}
+	return pr, err
+}
@@ -0,0 +1,248 @@
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// DefaultGitHubBaseURL is the public GitHub REST API endpoint.
+const DefaultGitHubBaseURL = "https://api.github.com"
+
+// GitHubForge implements Forge against the GitHub REST API.
+type GitHubForge struct {
+	baseURL string
+	owner   string
+	repo    string
+	token   string
+	client  *http.Client
+}
+
+// NewGitHubForge creates a GitHub client. baseURL defaults to the public
+// API and can point at GitHub Enterprise or a local mock server.
+func NewGitHubForge(baseURL, owner, repo, token string) *GitHubForge {
+	if baseURL == "" {
+		baseURL = DefaultGitHubBaseURL
+	}
+	return &GitHubForge{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		owner:   owner,
+		repo:    repo,
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type githubPull struct {
+	Number   int     `json:"number"`
+	HTMLURL  string  `json:"html_url"`
+	Title    string  `json:"title"`
+	State    string  `json:"state"`
+	Draft    bool    `json:"draft"`
+	MergedAt *string `json:"merged_at"`
+	Head     struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+type githubCombinedStatus struct {
+	State    string `json:"state"`
+	Statuses []struct {
+		State string `json:"state"`
+	} `json:"statuses"`
+}
+
+type githubCheckRuns struct {
+	CheckRuns []struct {
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+	} `json:"check_runs"`
+}
+
+type githubReview struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State string `json:"state"`
+}
+
+// GetPullRequest returns the most recently created pull request whose head
+// is branch, including its CI and review state.
+func (g *GitHubForge) GetPullRequest(branch string) (models.PullRequest, error) {
+	query := url.Values{}
+	query.Set("head", g.owner+":"+branch)
+	query.Set("state", "all")
+	query.Set("per_page", "1")
+
+	var pulls []githubPull
+	if err := g.get(fmt.Sprintf("/repos/%s/%s/pulls?%s", g.owner, g.repo, query.Encode()), &pulls); err != nil {
+		return models.PullRequest{}, err
+	}
+	if len(pulls) == 0 {
+		return models.PullRequest{}, ErrNotFound
+	}
+	pull := pulls[0]
+
+	pr := models.PullRequest{
+		Number:     pull.Number,
+		URL:        pull.HTMLURL,
+		Title:      pull.Title,
+		BaseBranch: pull.Base.Ref,
+		HeadSHA:    pull.Head.SHA,
+		State:      githubPullState(pull),
+	}
+
+	ci, err := g.getCIStatus(pull.Head.SHA)
+	if err != nil {
+		log.Debug("Failed to get CI status", "branch", branch, "error", err)
+	}
+	pr.CI = ci
+
+	review, err := g.getReviewState(pull.Number)
+	if err != nil {
+		log.Debug("Failed to get review state", "branch", branch, "error", err)
+	}
+	pr.Review = review
+
+	return pr, nil
+}
+
+func githubPullState(pull githubPull) models.PullRequestState {
+	switch {
+	case pull.MergedAt != nil && *pull.MergedAt != "":
+		return models.PullRequestStateMerged
+	case pull.State == "closed":
+		return models.PullRequestStateClosed
+	case pull.Draft:
+		return models.PullRequestStateDraft
+	default:
+		return models.PullRequestStateOpen
+	}
+}
+
+// getCIStatus folds the legacy commit statuses and the check runs API into
+// a single result: any failure wins, then anything still running.
+func (g *GitHubForge) getCIStatus(sha string) (models.CIStatus, error) {
+	if sha == "" {
+		return models.CIStatusNone, nil
+	}
+
+	var states []string
+
+	var combined githubCombinedStatus
+	if err := g.get(fmt.Sprintf("/repos/%s/%s/commits/%s/status", g.owner, g.repo, sha), &combined); err != nil {
+		return models.CIStatusNone, err
+	}
+	// An empty combined status reports "pending", so only count it when
+	// there is at least one status behind it.
+	if len(combined.Statuses) > 0 {
+		states = append(states, combined.State)
+	}
+
+	var checks githubCheckRuns
+	if err := g.get(fmt.Sprintf("/repos/%s/%s/commits/%s/check-runs", g.owner, g.repo, sha), &checks); err != nil {
+		return models.CIStatusNone, err
+	}
+	for _, run := range checks.CheckRuns {
+		if run.Status != "completed" {
+			states = append(states, "pending")
+			continue
+		}
+		switch run.Conclusion {
+		case "success", "neutral", "skipped":
+			states = append(states, "success")
+		default:
+			states = append(states, "failure")
+		}
+	}
+
+	return aggregateCIStatus(states), nil
+}
+
+func aggregateCIStatus(states []string) models.CIStatus {
+	if len(states) == 0 {
+		return models.CIStatusNone
+	}
+	result := models.CIStatusSuccess
+	for _, state := range states {
+		switch state {
+		case "failure", "error":
+			return models.CIStatusFailure
+		case "pending":
+			result = models.CIStatusPending
+		}
+	}
+	return result
+}
+
+// getReviewState reduces the review history to each reviewer's latest
+// decision: any outstanding change request wins over approvals.
+func (g *GitHubForge) getReviewState(number int) (models.ReviewState, error) {
+	var reviews []githubReview
+	if err := g.get(fmt.Sprintf("/repos/%s/%s/pulls/%d/reviews", g.owner, g.repo, number), &reviews); err != nil {
+		return models.ReviewStateNone, err
+	}
+
+	latest := map[string]string{}
+	for _, review := range reviews {
+		if review.State == "PENDING" {
+			continue
+		}
+		// Comments don't override an earlier approval or change request.
+		if review.State == "COMMENTED" && latest[review.User.Login] != "" {
+			continue
+		}
+		latest[review.User.Login] = review.State
+	}
+
+	result := models.ReviewStateNone
+	for _, state := range latest {
+		switch state {
+		case "CHANGES_REQUESTED":
+			return models.ReviewStateChangesRequested, nil
+		case "APPROVED":
+			result = models.ReviewStateApproved
+		case "COMMENTED":
+			if result == models.ReviewStateNone {
+				result = models.ReviewStateCommented
+			}
+		}
+	}
+	return result, nil
+}
+
+func (g *GitHubForge) get(path string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, g.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	log.Debug("GitHub API request", "url", req.URL.String())
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("github request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("github request %s returned %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode github response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockGitHub serves canned GitHub API responses keyed by request path.
+func newMockGitHub(t *testing.T, responses map[string]string) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestGitHubForgeGetPullRequest(t *testing.T) {
+	t.Run("open pull request with passing checks and approval", func(t *testing.T) {
+		server, _ := newMockGitHub(t, map[string]string{
+			"/repos/octo/app/pulls":                  `[{"number": 42, "html_url": "https://github.com/octo/app/pull/42", "title": "Add login", "state": "open", "draft": false, "merged_at": null, "head": {"sha": "abc"}, "base": {"ref": "main"}}]`,
+			"/repos/octo/app/commits/abc/status":     `{"state": "success", "statuses": [{"state": "success"}]}`,
+			"/repos/octo/app/commits/abc/check-runs": `{"check_runs": [{"status": "completed", "conclusion": "success"}]}`,
+			"/repos/octo/app/pulls/42/reviews":       `[{"user": {"login": "a"}, "state": "CHANGES_REQUESTED"}, {"user": {"login": "a"}, "state": "APPROVED"}]`,
+		})
+
+		f := NewGitHubForge(server.URL, "octo", "app", "secret")
+		pr, err := f.GetPullRequest("feature/login")
+		require.NoError(t, err)
+
+		assert.Equal(t, 42, pr.Number)
+		assert.Equal(t, "main", pr.BaseBranch)
+		assert.Equal(t, models.PullRequestStateOpen, pr.State)
+		assert.Equal(t, models.CIStatusSuccess, pr.CI)
+		assert.Equal(t, models.ReviewStateApproved, pr.Review)
+	})
+
+	t.Run("merged pull request with failing and running checks", func(t *testing.T) {
+		server, _ := newMockGitHub(t, map[string]string{
+			"/repos/octo/app/pulls":                  `[{"number": 7, "state": "closed", "merged_at": "2024-01-01T00:00:00Z", "head": {"sha": "def"}, "base": {"ref": "main"}}]`,
+			"/repos/octo/app/commits/def/status":     `{"state": "pending", "statuses": []}`,
+			"/repos/octo/app/commits/def/check-runs": `{"check_runs": [{"status": "in_progress"}, {"status": "completed", "conclusion": "failure"}]}`,
+			"/repos/octo/app/pulls/7/reviews":        `[]`,
+		})
+
+		f := NewGitHubForge(server.URL, "octo", "app", "secret")
+		pr, err := f.GetPullRequest("feature/rebased")
+		require.NoError(t, err)
+
+		assert.Equal(t, models.PullRequestStateMerged, pr.State)
+		assert.Equal(t, models.CIStatusFailure, pr.CI)
+		assert.Equal(t, models.ReviewStateNone, pr.Review)
+	})
+
+	t.Run("no pull request for branch", func(t *testing.T) {
+		server, _ := newMockGitHub(t, map[string]string{
+			"/repos/octo/app/pulls": `[]`,
+		})
+
+		f := NewGitHubForge(server.URL, "octo", "app", "secret")
+		_, err := f.GetPullRequest("feature/none")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestCachedForge(t *testing.T) {
+	server, requests := newMockGitHub(t, map[string]string{
+		"/repos/octo/app/pulls": `[]`,
+	})
+
+	cached := NewCachedForge(NewGitHubForge(server.URL, "octo", "app", "secret"), time.Minute)
+	now := time.Now()
+	cached.now = func() time.Time { return now }
+
+	_, err := cached.GetPullRequest("feature/a")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = cached.GetPullRequest("feature/a")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 1, *requests, "second lookup should be served from cache")
+
+	now = now.Add(2 * time.Minute)
+	_, _ = cached.GetPullRequest("feature/a")
+	assert.Equal(t, 2, *requests, "expired entry should be looked up again")
+}
+
+func TestParseRepoSlug(t *testing.T) {
+	tests := []struct {
+		url   string
+		owner string
+		repo  string
+	}{
+		{"git@github.com:garrettkrohn/treekanga.git", "garrettkrohn", "treekanga"},
+		{"https://github.com/garrettkrohn/treekanga.git", "garrettkrohn", "treekanga"},
+		{"https://github.com/garrettkrohn/treekanga", "garrettkrohn", "treekanga"},
+		{"ssh://git@github.example.com/org/service.git", "org", "service"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			owner, repo, err := ParseRepoSlug(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.owner, owner)
+			assert.Equal(t, tt.repo, repo)
+		})
+	}
+
+	_, _, err := ParseRepoSlug("not-a-remote")
+	assert.Error(t, err)
+}
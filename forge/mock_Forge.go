@@ -0,0 +1,91 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package forge
+
+import (
+	models "github.com/garrettkrohn/treekanga/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockForge is an autogenerated mock type for the Forge type
+type MockForge struct {
+	mock.Mock
+}
+
+type MockForge_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockForge) EXPECT() *MockForge_Expecter {
+	return &MockForge_Expecter{mock: &_m.Mock}
+}
+
+// GetPullRequest provides a mock function with given fields: branch
+func (_m *MockForge) GetPullRequest(branch string) (models.PullRequest, error) {
+	ret := _m.Called(branch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPullRequest")
+	}
+
+	var r0 models.PullRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (models.PullRequest, error)); ok {
+		return rf(branch)
+	}
+	if rf, ok := ret.Get(0).(func(string) models.PullRequest); ok {
+		r0 = rf(branch)
+	} else {
+		r0 = ret.Get(0).(models.PullRequest)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(branch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockForge_GetPullRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPullRequest'
+type MockForge_GetPullRequest_Call struct {
+	*mock.Call
+}
+
+// GetPullRequest is a helper method to define mock.On call
+//   - branch string
+func (_e *MockForge_Expecter) GetPullRequest(branch interface{}) *MockForge_GetPullRequest_Call {
+	return &MockForge_GetPullRequest_Call{Call: _e.mock.On("GetPullRequest", branch)}
+}
+
+func (_c *MockForge_GetPullRequest_Call) Run(run func(branch string)) *MockForge_GetPullRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockForge_GetPullRequest_Call) Return(_a0 models.PullRequest, _a1 error) *MockForge_GetPullRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockForge_GetPullRequest_Call) RunAndReturn(run func(string) (models.PullRequest, error)) *MockForge_GetPullRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockForge creates a new instance of MockForge. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockForge(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockForge {
+	mock := &MockForge{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
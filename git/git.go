@@ -1,26 +1,106 @@
 package git
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/charmbracelet/log"
 )
 
+// Git runs the git commands treekanga needs. Services and the TUI receive it
+// through cmd.Dependencies so they can be tested against a mock instead of a
+// real repository.
+type Git interface {
+	AddWorktree(bareRepoPath, worktreeTargetDir, worktreeName string, worktreeArgs []string) error
+	SetUpstream(worktreePath, branchName string) error
+	UnsetUpstream(worktreePath, branchName string) error
+	RemoveWorktree(bareRepoPath, worktreePath string, force bool) error
+	ListWorktrees(bareRepoPath string) ([]string, error)
+	Fetch(bareRepoPath, branch string) error
+	FetchBranches(bareRepoPath string, branches []string) error
+	FetchTag(bareRepoPath, tag string) error
+	FetchAll(bareRepoPath string) error
+	GetLastFetch(bareRepoPath string) (time.Time, error)
+	SetLastFetch(bareRepoPath string, at time.Time) error
+	GetBranchBase(repoPath, branch string) (string, error)
+	GetBranchBases(repoPath string) (map[string]string, error)
+	SetBranchBase(repoPath, branch, base string) error
+	GetBranchParent(repoPath, branch string) (string, error)
+	GetBranchParents(repoPath string) (map[string]string, error)
+	SetBranchParent(repoPath, branch, parent string) error
+	ResolveCommit(bareRepoPath, ref string) (string, error)
+	DescribeHead(worktreePath string) (string, error)
+	GetRemoteBranches(bareRepoPath string) ([]string, error)
+	GetLocalBranches(bareRepoPath string) ([]string, error)
+	GetRefSnapshot(bareRepoPath, base string) (*RefSnapshot, error)
+	DeleteBranch(bareRepoPath, branch string, force bool) error
+	RenameBranch(bareRepoPath, oldName, newName string) error
+	PushBranch(worktreePath, branch string) error
+	DeleteRemoteBranch(bareRepoPath, branch string) error
+	MoveWorktree(bareRepoPath, oldPath, newPath string, forceSubmodules bool) error
+	PruneWorktrees(bareRepoPath string) ([]string, error)
+	GetCurrentBranch(dir string) (string, error)
+	GetDefaultBranch(bareRepoPath string) (string, error)
+	CloneBare(url, folderName string) error
+	ConfigureBare(bareRepoPath string) error
+	GetBareRepoPath(dir string) (string, error)
+	GetProjectName() (string, error)
+	GetRemoteURL(bareRepoPath string) (string, error)
+	GetWorkingTreeStatus(worktreePath string) (staged, modified, untracked bool, err error)
+	GetAheadBehind(worktreePath, compareRef string) (ahead, behind int, err error)
+	GetUpstreamBranch(worktreePath string) (string, error)
+	IsMerged(worktreePath, branchName, targetRef string) (bool, error)
+	Rebase(worktreePath, onto string) error
+	RebaseOnto(worktreePath, onto, upstream string) error
+	RebaseInProgress(worktreePath string) (bool, error)
+	ForkPoint(repoPath, parent, branch string) (string, error)
+	ListStashes(repoPath string) ([]Stash, error)
+	StashPush(worktreePath, message string) (string, error)
+	PopStash(worktreePath, commit string) error
+	AbortRebase(worktreePath string) error
+	Merge(worktreePath, ref string) error
+	AbortMerge(worktreePath string) error
+	Planning() bool
+}
+
+// RealGit runs git through the package runner, see SetRunner.
+type RealGit struct{}
+
+func NewGit() Git {
+	return &RealGit{}
+}
+
+// Planning reports whether this is a dry run, where commands are recorded
+// instead of run and nothing on disk changes.
+func (g *RealGit) Planning() bool {
+	_, ok := GetRunner().(Planner)
+	return ok
+}
+
 // AddWorktree creates a new worktree with flexible arguments
-func AddWorktree(bareRepoPath, worktreeTargetDir, worktreeName string, worktreeArgs []string) error {
+func (g *RealGit) AddWorktree(bareRepoPath, worktreeTargetDir, worktreeName string, worktreeArgs []string) error {
 	args := []string{"-C", bareRepoPath, "worktree", "add", filepath.Join(worktreeTargetDir, worktreeName)}
 	args = append(args, worktreeArgs...)
 
 	fullCommand := strings.Join(append([]string{"git"}, args...), " ")
 	log.Debug("Executing git worktree command", "command", fullCommand)
 
-	err := runCommand("git", args...)
+	output, err := runCommandCombined("git", args...)
 	if err != nil {
+		// Keep git's reason (e.g. "fatal: a branch named 'x' already exists")
+		// so callers reporting several adds at once can show it
+		if reason := lastLine(output); reason != "" {
+			return fmt.Errorf("failed to add worktree: %v: %s\nCommand: %s", err, reason, fullCommand)
+		}
 		return fmt.Errorf("failed to add worktree: %v\nCommand: %s", err, fullCommand)
 	}
 
@@ -28,7 +108,7 @@ func AddWorktree(bareRepoPath, worktreeTargetDir, worktreeName string, worktreeA
 }
 
 // SetUpstream configures the upstream branch using git config for the current branch in a worktree
-func SetUpstream(worktreePath, branchName string) error {
+func (g *RealGit) SetUpstream(worktreePath, branchName string) error {
 	// Set remote for the branch
 	remoteArgs := []string{"-C", worktreePath, "config", "branch." + branchName + ".remote", "origin"}
 	err := runCommand("git", remoteArgs...)
@@ -50,7 +130,7 @@ func SetUpstream(worktreePath, branchName string) error {
 }
 
 // UnsetUpstream removes the upstream tracking config for a branch in a worktree
-func UnsetUpstream(worktreePath, branchName string) error {
+func (g *RealGit) UnsetUpstream(worktreePath, branchName string) error {
 	for _, key := range []string{"branch." + branchName + ".remote", "branch." + branchName + ".merge"} {
 		err := runCommand("git", "-C", worktreePath, "config", "--unset", key)
 		if err != nil {
@@ -66,7 +146,7 @@ func UnsetUpstream(worktreePath, branchName string) error {
 }
 
 // RemoveWorktree removes a worktree (worktreePath can be name or path)
-func RemoveWorktree(bareRepoPath, worktreePath string, force bool) error {
+func (g *RealGit) RemoveWorktree(bareRepoPath, worktreePath string, force bool) error {
 	args := []string{"-C", bareRepoPath, "worktree", "remove", worktreePath}
 	if force {
 		args = append(args, "--force")
@@ -81,7 +161,7 @@ func RemoveWorktree(bareRepoPath, worktreePath string, force bool) error {
 }
 
 // ListWorktrees returns raw worktree list output
-func ListWorktrees(bareRepoPath string) ([]string, error) {
+func (g *RealGit) ListWorktrees(bareRepoPath string) ([]string, error) {
 	args := []string{"-C", bareRepoPath, "worktree", "list"}
 	output, err := runCommandOutput("git", args...)
 	if err != nil {
@@ -91,7 +171,7 @@ func ListWorktrees(bareRepoPath string) ([]string, error) {
 }
 
 // Fetch fetches updates for a specific branch from remote
-func Fetch(bareRepoPath, branch string) error {
+func (g *RealGit) Fetch(bareRepoPath, branch string) error {
 	args := []string{"-C", bareRepoPath, "fetch", "origin", branch}
 	err := runCommand("git", args...)
 	if err != nil {
@@ -101,8 +181,177 @@ func Fetch(bareRepoPath, branch string) error {
 	return nil
 }
 
+// FetchBranches fetches several branches from remote in a single fetch
+func (g *RealGit) FetchBranches(bareRepoPath string, branches []string) error {
+	args := append([]string{"-C", bareRepoPath, "fetch", "origin"}, branches...)
+	err := runCommand("git", args...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch branches %s: %w", strings.Join(branches, ", "), err)
+	}
+	log.Debug("Fetched latest state from remote", "branches", branches)
+	return nil
+}
+
+// FetchTag fetches a single tag from remote into refs/tags
+func (g *RealGit) FetchTag(bareRepoPath, tag string) error {
+	args := []string{"-C", bareRepoPath, "fetch", "origin", "tag", tag, "--no-tags"}
+	err := runCommand("git", args...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tag %s: %w", tag, err)
+	}
+	log.Debug("Fetched tag from remote", "tag", tag)
+	return nil
+}
+
+// FetchAll fetches every branch from remote, pruning the ones deleted there
+func (g *RealGit) FetchAll(bareRepoPath string) error {
+	args := []string{"-C", bareRepoPath, "fetch", "--prune", "origin"}
+	err := runCommand("git", args...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", bareRepoPath, err)
+	}
+	log.Debug("Fetched all branches from remote", "repo", bareRepoPath)
+	return nil
+}
+
+// lastFetchKey is the repo config key FetchAll's callers record the time of
+// the last full fetch in, as unix seconds.
+const lastFetchKey = "treekanga.lastFetch"
+
+// GetLastFetch returns when the repo was last fetched with FetchAll, the
+// zero time if it never was
+func (g *RealGit) GetLastFetch(bareRepoPath string) (time.Time, error) {
+	output, err := runCommandOutput("git", "-C", bareRepoPath, "config", "--get", lastFetchKey)
+	if err != nil {
+		// git config exits 1 when the key isn't set
+		return time.Time{}, nil
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(output), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: %w", lastFetchKey, strings.TrimSpace(output), err)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// SetLastFetch records when the repo was last fetched
+func (g *RealGit) SetLastFetch(bareRepoPath string, at time.Time) error {
+	err := runCommand("git", "-C", bareRepoPath, "config", lastFetchKey, strconv.FormatInt(at.Unix(), 10))
+	if err != nil {
+		return fmt.Errorf("failed to record last fetch: %w", err)
+	}
+	return nil
+}
+
+// branchBaseKey is the branch config key the branch a branch was cut from is
+// recorded under, i.e. branch.<name>.treekangaBase. Renaming or deleting the
+// branch carries it along like the rest of branch.<name>.*
+const branchBaseKey = "treekangaBase"
+
+// GetBranchBase returns the base branch recorded for branch, or "" if none
+// is. repoPath is the bare repo or any of its worktrees.
+func (g *RealGit) GetBranchBase(repoPath, branch string) (string, error) {
+	output, err := runCommandOutput("git", "-C", repoPath, "config", "--get", "branch."+branch+"."+branchBaseKey)
+	if err != nil {
+		// git config exits 1 when the key isn't set
+		return "", nil
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// GetBranchBases returns the recorded base branch of every branch that has
+// one, keyed by branch name.
+func (g *RealGit) GetBranchBases(repoPath string) (map[string]string, error) {
+	return getBranchConfigs(repoPath, branchBaseKey)
+}
+
+// getBranchConfigs returns branch.<name>.<key> of every branch that has it
+// set, keyed by branch name.
+func getBranchConfigs(repoPath, key string) (map[string]string, error) {
+	// git matches and prints the key as branch.<name>.<lowercased key>, with
+	// the name as is
+	suffix := "." + strings.ToLower(key)
+	values := map[string]string{}
+	output, err := runCommandOutput("git", "-C", repoPath, "config", "--get-regexp", `^branch\..*\`+suffix+`$`)
+	if err != nil {
+		// git config exits 1 when no key matches
+		return values, nil
+	}
+	for _, line := range strings.Split(output, "\n") {
+		name, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		branch, ok := strings.CutSuffix(strings.TrimPrefix(name, "branch."), suffix)
+		if ok && branch != "" {
+			values[branch] = strings.TrimSpace(value)
+		}
+	}
+	return values, nil
+}
+
+// SetBranchBase records the branch branch was cut from.
+func (g *RealGit) SetBranchBase(repoPath, branch, base string) error {
+	err := runCommand("git", "-C", repoPath, "config", "branch."+branch+"."+branchBaseKey, base)
+	if err != nil {
+		return fmt.Errorf("failed to record base branch of %s: %w", branch, err)
+	}
+	log.Debug("Recorded base branch", "branch", branch, "base", base)
+	return nil
+}
+
+// branchParentKey is the branch config key the branch a stacked branch sits
+// on is recorded under, i.e. branch.<name>.treekangaParent
+const branchParentKey = "treekangaParent"
+
+// GetBranchParent returns the branch branch is stacked on, or "" if it
+// isn't stacked.
+func (g *RealGit) GetBranchParent(repoPath, branch string) (string, error) {
+	output, err := runCommandOutput("git", "-C", repoPath, "config", "--get", "branch."+branch+"."+branchParentKey)
+	if err != nil {
+		// git config exits 1 when the key isn't set
+		return "", nil
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// GetBranchParents returns the parent of every stacked branch, keyed by
+// branch name.
+func (g *RealGit) GetBranchParents(repoPath string) (map[string]string, error) {
+	return getBranchConfigs(repoPath, branchParentKey)
+}
+
+// SetBranchParent records that branch is stacked on parent.
+func (g *RealGit) SetBranchParent(repoPath, branch, parent string) error {
+	err := runCommand("git", "-C", repoPath, "config", "branch."+branch+"."+branchParentKey, parent)
+	if err != nil {
+		return fmt.Errorf("failed to record parent of %s: %w", branch, err)
+	}
+	log.Debug("Recorded parent branch", "branch", branch, "parent", parent)
+	return nil
+}
+
+// ResolveCommit resolves a tag, sha or other ref to the commit it points at
+func (g *RealGit) ResolveCommit(bareRepoPath, ref string) (string, error) {
+	args := []string{"-C", bareRepoPath, "rev-parse", "--verify", "--quiet", ref + "^{commit}"}
+	output, err := runCommandOutput("git", args...)
+	if err != nil {
+		return "", fmt.Errorf("ref '%s' not found: %w", ref, err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// DescribeHead returns the tag pointing exactly at HEAD in a worktree
+func (g *RealGit) DescribeHead(worktreePath string) (string, error) {
+	args := []string{"-C", worktreePath, "describe", "--tags", "--exact-match", "HEAD"}
+	output, err := runCommandOutput("git", args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
 // GetRemoteBranches lists remote branches (without fetching)
-func GetRemoteBranches(bareRepoPath string) ([]string, error) {
+func (g *RealGit) GetRemoteBranches(bareRepoPath string) ([]string, error) {
 	args := []string{"-C", bareRepoPath, "branch", "-r", "--format=%(refname:short)"}
 	output, err := runCommandOutput("git", args...)
 	if err != nil {
@@ -110,10 +359,11 @@ func GetRemoteBranches(bareRepoPath string) ([]string, error) {
 	}
 	branches := strings.Split(strings.TrimSpace(output), "\n")
 
-	// Remove "origin/" prefix
+	// Remove "origin/" prefix, and origin/HEAD which git lists as "origin"
+	// or, before 2.40, "origin/HEAD"
 	cleaned := make([]string, 0, len(branches))
 	for _, branch := range branches {
-		if branch != "" && branch != "origin" {
+		if branch != "" && branch != "origin" && !strings.HasSuffix(branch, "/HEAD") {
 			cleaned = append(cleaned, strings.TrimPrefix(branch, "origin/"))
 		}
 	}
@@ -121,7 +371,7 @@ func GetRemoteBranches(bareRepoPath string) ([]string, error) {
 }
 
 // GetLocalBranches lists local branches
-func GetLocalBranches(bareRepoPath string) ([]string, error) {
+func (g *RealGit) GetLocalBranches(bareRepoPath string) ([]string, error) {
 	args := []string{"-C", bareRepoPath, "branch", "--format=%(refname:short)"}
 	output, err := runCommandOutput("git", args...)
 	if err != nil {
@@ -141,7 +391,7 @@ func GetLocalBranches(bareRepoPath string) ([]string, error) {
 }
 
 // DeleteBranch deletes a local branch
-func DeleteBranch(bareRepoPath, branch string, force bool) error {
+func (g *RealGit) DeleteBranch(bareRepoPath, branch string, force bool) error {
 	args := []string{"-C", bareRepoPath, "branch"}
 	if force {
 		args = append(args, "-D", branch)
@@ -152,7 +402,7 @@ func DeleteBranch(bareRepoPath, branch string, force bool) error {
 }
 
 // RenameBranch renames a local branch
-func RenameBranch(bareRepoPath, oldName, newName string) error {
+func (g *RealGit) RenameBranch(bareRepoPath, oldName, newName string) error {
 	args := []string{"-C", bareRepoPath, "branch", "-m", oldName, newName}
 	err := runCommand("git", args...)
 	if err != nil {
@@ -161,29 +411,95 @@ func RenameBranch(bareRepoPath, oldName, newName string) error {
 	return nil
 }
 
-// MoveWorktree moves a worktree to a new location
-// If forceSubmodules is true, manually moves the directory and updates git's worktree config
-func MoveWorktree(bareRepoPath, oldPath, newPath string, forceSubmodules bool) error {
+// PushBranch pushes a branch to origin and makes origin/<branch> its upstream
+func (g *RealGit) PushBranch(worktreePath, branch string) error {
+	output, err := runCommandCombined("git", "-C", worktreePath, "push", "-u", "origin", branch)
+	if err != nil {
+		return fmt.Errorf("failed to push %s: %w: %s", branch, err, lastLine(output))
+	}
+	return nil
+}
+
+// DeleteRemoteBranch deletes a branch on origin
+func (g *RealGit) DeleteRemoteBranch(bareRepoPath, branch string) error {
+	output, err := runCommandCombined("git", "-C", bareRepoPath, "push", "origin", "--delete", branch)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s on origin: %w: %s", branch, err, lastLine(output))
+	}
+	return nil
+}
+
+// MoveWorktree moves a worktree to a new location, creating the folder it
+// goes in. If forceSubmodules is true, manually moves the directory and
+// updates git's worktree config. git can't move a worktree to another
+// filesystem, so those moves are done manually as well.
+func (g *RealGit) MoveWorktree(bareRepoPath, oldPath, newPath string, forceSubmodules bool) error {
+	planner, planning := GetRunner().(Planner)
+
+	parent := filepath.Dir(newPath)
+	if _, err := os.Stat(parent); os.IsNotExist(err) {
+		if planning {
+			planner.PlanCommand("mkdir", "-p", parent)
+		} else if err := os.MkdirAll(parent, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", parent, err)
+		}
+	}
+
 	// If forceSubmodules is enabled, skip the git command and go straight to manual move
 	if forceSubmodules {
 		log.Info("Force submodules enabled, using manual move workaround")
+		if planning {
+			planner.PlanCommand("mv", oldPath, newPath)
+			return nil
+		}
 		return moveWorktreeManually(bareRepoPath, oldPath, newPath)
 	}
 
 	args := []string{"-C", bareRepoPath, "worktree", "move", oldPath, newPath}
-	err := runCommand("git", args...)
+	output, err := runCommandCombined("git", args...)
+	if err != nil && strings.Contains(strings.ToLower(output), "cross-device link") {
+		log.Info("Moving the worktree to another filesystem manually", "from", oldPath, "to", newPath)
+		return moveWorktreeManually(bareRepoPath, oldPath, newPath)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to move worktree from %s to %s: %w", oldPath, newPath, err)
+		return fmt.Errorf("failed to move worktree from %s to %s: %w: %s", oldPath, newPath, err, lastLine(output))
 	}
 	return nil
 }
 
+// PruneWorktrees removes git's records of worktrees whose folders are gone,
+// e.g. deleted with rm -rf, and returns what it pruned as git reports it.
+func (g *RealGit) PruneWorktrees(bareRepoPath string) ([]string, error) {
+	// git lists what it would prune on stderr
+	output, err := runCommandCombined("git", "-C", bareRepoPath, "worktree", "prune", "--dry-run", "--verbose")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prunable worktrees: %w: %s", err, lastLine(output))
+	}
+	var pruned []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line = strings.TrimPrefix(strings.TrimSpace(line), "Removing "); line != "" {
+			pruned = append(pruned, line)
+		}
+	}
+	if len(pruned) == 0 {
+		return nil, nil
+	}
+
+	if err := runCommand("git", "-C", bareRepoPath, "worktree", "prune"); err != nil {
+		return nil, fmt.Errorf("failed to prune worktrees: %w", err)
+	}
+	return pruned, nil
+}
+
 // moveWorktreeManually manually moves a worktree directory and updates git's internal references
 // This is a workaround for git's limitation with submodules
 func moveWorktreeManually(bareRepoPath, oldPath, newPath string) error {
-	// Step 1: Move the directory
+	// Step 1: Move the directory, copying it when it goes to another filesystem
 	log.Debug("Manually moving directory", "from", oldPath, "to", newPath)
 	err := os.Rename(oldPath, newPath)
+	if errors.Is(err, syscall.EXDEV) {
+		err = moveAcrossFilesystems(oldPath, newPath)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to move directory: %w", err)
 	}
@@ -222,11 +538,74 @@ func moveWorktreeManually(bareRepoPath, oldPath, newPath string) error {
 	return nil
 }
 
+// moveAcrossFilesystems copies the directory from to to, then removes from.
+// A copy that fails part way is removed again, leaving from as it was.
+func moveAcrossFilesystems(from, to string) error {
+	if _, err := os.Lstat(to); err == nil {
+		return fmt.Errorf("%s already exists", to)
+	}
+	if err := copyTree(from, to); err != nil {
+		os.RemoveAll(to)
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+// copyTree copies the directory from to to, keeping file modes and
+// symlinks as they are.
+func copyTree(from, to string) error {
+	return filepath.WalkDir(from, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.IsDir():
+			return os.Mkdir(target, info.Mode().Perm())
+		case entry.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case entry.Type().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		log.Warn("Skipping a file that isn't a regular file, folder or symlink", "path", path)
+		return nil
+	})
+}
+
+func copyFile(from, to string, perm fs.FileMode) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
 // GetCurrentBranch returns the current branch name for a given directory
-func GetCurrentBranch(dir string) (string, error) {
-	command := exec.Command("git", "branch", "--show-current")
-	command.Dir = dir
-	output, err := command.Output()
+func (g *RealGit) GetCurrentBranch(dir string) (string, error) {
+	output, err := runQuery(dir, "", "branch", "--show-current")
 	if err != nil {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
@@ -234,13 +613,23 @@ func GetCurrentBranch(dir string) (string, error) {
 	return branchName, nil
 }
 
+// GetDefaultBranch returns the branch the bare repo's HEAD points at, the
+// remote's default branch when it was cloned.
+func (g *RealGit) GetDefaultBranch(bareRepoPath string) (string, error) {
+	output, err := runQuery(bareRepoPath, "", "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get default branch: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // CloneBare clones a repository as bare
-func CloneBare(url, folderName string) error {
+func (g *RealGit) CloneBare(url, folderName string) error {
 	return runCommand("git", "clone", "--progress", "--bare", url, folderName)
 }
 
 // ConfigureBare configures a bare repository for worktree usage
-func ConfigureBare(bareRepoPath string) error {
+func (g *RealGit) ConfigureBare(bareRepoPath string) error {
 	_, err := runCommandOutput("git", "-C", bareRepoPath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
 	if err != nil {
 		return err
@@ -256,11 +645,9 @@ func ConfigureBare(bareRepoPath string) error {
 }
 
 // GetBareRepoPath returns the path to the bare repository
-func GetBareRepoPath(dir string) (string, error) {
+func (g *RealGit) GetBareRepoPath(dir string) (string, error) {
 	if dir != "" {
-		command := exec.Command("git", "rev-parse", "--git-common-dir")
-		command.Dir = dir
-		output, err := command.Output()
+		output, err := runQuery(dir, "", "rev-parse", "--git-common-dir")
 		if err != nil {
 			return "", err
 		}
@@ -270,7 +657,7 @@ func GetBareRepoPath(dir string) (string, error) {
 }
 
 // GetProjectName returns the project name from git config
-func GetProjectName() (string, error) {
+func (g *RealGit) GetProjectName() (string, error) {
 	url, err := runCommandOutput("git", "config", "--get", "remote.origin.url")
 	if err != nil {
 		return "", err
@@ -283,9 +670,18 @@ func GetProjectName() (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
+// GetRemoteURL returns the url of the origin remote for a repository
+func (g *RealGit) GetRemoteURL(bareRepoPath string) (string, error) {
+	output, err := runCommandOutput("git", "-C", bareRepoPath, "config", "--get", "remote.origin.url")
+	if err != nil {
+		return "", fmt.Errorf("failed to get origin url: %w", err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
 // GetWorkingTreeStatus reports whether a worktree has staged, modified
 // (unstaged), or untracked changes.
-func GetWorkingTreeStatus(worktreePath string) (staged, modified, untracked bool, err error) {
+func (g *RealGit) GetWorkingTreeStatus(worktreePath string) (staged, modified, untracked bool, err error) {
 	output, err := runCommandOutput("git", "-C", worktreePath, "status", "--porcelain=v1", "--untracked-files=all")
 	if err != nil {
 		return false, false, false, fmt.Errorf("failed to get status for %s: %w", worktreePath, err)
@@ -317,7 +713,7 @@ func GetWorkingTreeStatus(worktreePath string) (staged, modified, untracked bool
 
 // GetAheadBehind returns how many commits HEAD is ahead/behind compareRef
 // in a given worktree.
-func GetAheadBehind(worktreePath, compareRef string) (ahead, behind int, err error) {
+func (g *RealGit) GetAheadBehind(worktreePath, compareRef string) (ahead, behind int, err error) {
 	output, err := runCommandOutput("git", "-C", worktreePath, "rev-list", "--left-right", "--count", "HEAD..."+compareRef)
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to compute ahead/behind for %s against %s: %w", worktreePath, compareRef, err)
@@ -342,7 +738,7 @@ func GetAheadBehind(worktreePath, compareRef string) (ahead, behind int, err err
 
 // GetUpstreamBranch returns the upstream (remote-tracking) branch for a
 // worktree's current branch, or "" if no upstream is configured.
-func GetUpstreamBranch(worktreePath string) (string, error) {
+func (g *RealGit) GetUpstreamBranch(worktreePath string) (string, error) {
 	output, err := runCommandOutput("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
 	if err != nil {
 		// No upstream configured - not an error condition for callers.
@@ -355,7 +751,7 @@ func GetUpstreamBranch(worktreePath string) (string, error) {
 // targetRef: either as a literal ancestor, or via a squash-merge content
 // match (the branch's aggregate diff since its merge-base matches the
 // patch-id of some commit in targetRef since that same merge-base).
-func IsMerged(worktreePath, branchName, targetRef string) (bool, error) {
+func (g *RealGit) IsMerged(worktreePath, branchName, targetRef string) (bool, error) {
 	isAncestor, err := isAncestor(worktreePath, branchName, targetRef)
 	if err != nil {
 		return false, err
@@ -398,9 +794,7 @@ func IsMerged(worktreePath, branchName, targetRef string) (bool, error) {
 }
 
 func isAncestor(worktreePath, ancestorRef, ref string) (bool, error) {
-	command := exec.Command("git", "-C", worktreePath, "merge-base", "--is-ancestor", ancestorRef, ref)
-	command.SysProcAttr = setSysProcAttr()
-	err := command.Run()
+	_, err := runQuery("", "", "-C", worktreePath, "merge-base", "--is-ancestor", ancestorRef, ref)
 	if err == nil {
 		return true, nil
 	}
@@ -432,9 +826,7 @@ func commitsSince(worktreePath, base, ref string) ([]string, error) {
 // patchID returns the stable patch-id for the diff between fromRef and
 // toRef, or "" if the diff is empty.
 func patchID(worktreePath, fromRef, toRef string) (string, error) {
-	diffCmd := exec.Command("git", "-C", worktreePath, "diff", fromRef, toRef)
-	diffCmd.SysProcAttr = setSysProcAttr()
-	diffOutput, err := diffCmd.Output()
+	diffOutput, err := runQuery("", "", "-C", worktreePath, "diff", fromRef, toRef)
 	if err != nil {
 		return "", fmt.Errorf("failed to diff %s..%s: %w", fromRef, toRef, err)
 	}
@@ -442,10 +834,7 @@ func patchID(worktreePath, fromRef, toRef string) (string, error) {
 		return "", nil
 	}
 
-	patchIDCmd := exec.Command("git", "-C", worktreePath, "patch-id", "--stable")
-	patchIDCmd.SysProcAttr = setSysProcAttr()
-	patchIDCmd.Stdin = strings.NewReader(string(diffOutput))
-	patchIDOutput, err := patchIDCmd.Output()
+	patchIDOutput, err := runQuery("", string(diffOutput), "-C", worktreePath, "patch-id", "--stable")
 	if err != nil {
 		return "", fmt.Errorf("failed to compute patch-id: %w", err)
 	}
@@ -460,54 +849,46 @@ func patchID(worktreePath, fromRef, toRef string) (string, error) {
 // Helper functions
 
 func runCommand(cmd string, args ...string) error {
-	log.Debug(cmd, "args", args)
-
-	command := exec.Command(cmd, args...)
-	command.Stdin = nil
-
-	// Set environment to prevent git from using pagers or editors
-	command.Env = append(os.Environ(),
-		"GIT_PAGER=cat",
-		"GIT_EDITOR=true",
-		"EDITOR=true",
-		"VISUAL=true",
-	)
-
-	// Create new process group
-	command.SysProcAttr = setSysProcAttr()
-
-	output, err := command.CombinedOutput()
+	ctx, cancel := operationContext(args)
+	defer cancel()
+	return contextError(ctx, args, GetRunner().Run(ctx, cmd, args...))
+}
 
-	// Log output
-	if len(output) > 0 {
-		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
-			if line != "" {
-				log.Info(line)
-			}
-		}
-	}
+// runCommandCombined runs a command like runCommand, but also returns its
+// combined output so failures can be reported with git's message.
+func runCommandCombined(cmd string, args ...string) (string, error) {
+	ctx, cancel := operationContext(args)
+	defer cancel()
+	output, err := GetRunner().Combined(ctx, cmd, args...)
+	return output, contextError(ctx, args, err)
+}
 
-	return err
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
 }
 
 func runCommandOutput(cmd string, args ...string) (string, error) {
-	log.Debug(cmd, "args", args)
-
-	command := exec.Command(cmd, args...)
-	command.Stdin = nil
+	ctx, cancel := operationContext(args)
+	defer cancel()
+	output, err := GetRunner().Output(ctx, cmd, args...)
+	return output, contextError(ctx, args, err)
+}
 
-	command.SysProcAttr = setSysProcAttr()
+// runQuery runs a read-only git command that needs more control than
+// runCommandOutput (a working directory, stdin or the exit code), with the
+// same context, timeout and process group handling.
+func runQuery(dir string, stdin string, args ...string) ([]byte, error) {
+	ctx, cancel := operationContext(args)
+	defer cancel()
+	running.Add(1)
+	defer running.Done()
 
-	output, err := command.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			errString := strings.TrimSpace(string(exitErr.Stderr))
-			if strings.HasPrefix(errString, "no server running on") {
-				return "", nil
-			}
-		}
-		return "", err
+	command := newCommand(ctx, "git", args...)
+	command.Dir = dir
+	if stdin != "" {
+		command.Stdin = strings.NewReader(stdin)
 	}
-
-	return strings.TrimSuffix(string(output), "\n"), nil
+	output, err := command.Output()
+	return output, contextError(ctx, args, err)
 }
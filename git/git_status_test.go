@@ -12,6 +12,7 @@ import (
 
 func setupStatusTestRepo(t *testing.T) (bareRepoPath, worktreePath string) {
 	t.Helper()
+	g := NewGit()
 
 	tempDir, err := os.MkdirTemp("", "treekanga-status-test-*")
 	require.NoError(t, err)
@@ -19,10 +20,10 @@ func setupStatusTestRepo(t *testing.T) (bareRepoPath, worktreePath string) {
 
 	bareRepoPath = filepath.Join(tempDir, "test.git")
 	require.NoError(t, runCommand("git", "init", "--bare", bareRepoPath))
-	require.NoError(t, ConfigureBare(bareRepoPath))
+	require.NoError(t, g.ConfigureBare(bareRepoPath))
 
 	worktreePath = filepath.Join(tempDir, "main")
-	require.NoError(t, AddWorktree(bareRepoPath, tempDir, "main", []string{"-b", "main"}))
+	require.NoError(t, g.AddWorktree(bareRepoPath, tempDir, "main", []string{"-b", "main"}))
 	require.NoError(t, runCommand("git", "-C", bareRepoPath, "symbolic-ref", "HEAD", "refs/heads/main"))
 
 	require.NoError(t, runCommand("git", "-C", worktreePath, "config", "user.email", "test@example.com"))
@@ -37,9 +38,11 @@ func TestGetWorkingTreeStatus(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := NewGit()
+
 	_, worktreePath := setupStatusTestRepo(t)
 
-	staged, modified, untracked, err := GetWorkingTreeStatus(worktreePath)
+	staged, modified, untracked, err := g.GetWorkingTreeStatus(worktreePath)
 	require.NoError(t, err)
 	assert.False(t, staged)
 	assert.False(t, modified)
@@ -47,7 +50,7 @@ func TestGetWorkingTreeStatus(t *testing.T) {
 
 	// Untracked file
 	require.NoError(t, runCommand("sh", "-c", fmt.Sprintf("cd %s && echo 'new' > untracked.txt", worktreePath)))
-	staged, modified, untracked, err = GetWorkingTreeStatus(worktreePath)
+	staged, modified, untracked, err = g.GetWorkingTreeStatus(worktreePath)
 	require.NoError(t, err)
 	assert.False(t, staged)
 	assert.False(t, modified)
@@ -55,7 +58,7 @@ func TestGetWorkingTreeStatus(t *testing.T) {
 
 	// Staged file
 	require.NoError(t, runCommand("git", "-C", worktreePath, "add", "untracked.txt"))
-	staged, modified, untracked, err = GetWorkingTreeStatus(worktreePath)
+	staged, modified, untracked, err = g.GetWorkingTreeStatus(worktreePath)
 	require.NoError(t, err)
 	assert.True(t, staged)
 	assert.False(t, modified)
@@ -64,7 +67,7 @@ func TestGetWorkingTreeStatus(t *testing.T) {
 	// Modified tracked file (unstaged)
 	require.NoError(t, runCommand("git", "-C", worktreePath, "commit", "-m", "add untracked file"))
 	require.NoError(t, runCommand("sh", "-c", fmt.Sprintf("cd %s && echo 'changed' >> file.txt", worktreePath)))
-	staged, modified, untracked, err = GetWorkingTreeStatus(worktreePath)
+	staged, modified, untracked, err = g.GetWorkingTreeStatus(worktreePath)
 	require.NoError(t, err)
 	assert.False(t, staged)
 	assert.True(t, modified)
@@ -76,27 +79,29 @@ func TestGetAheadBehind(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := NewGit()
+
 	bareRepoPath, worktreePath := setupStatusTestRepo(t)
 
-	ahead, behind, err := GetAheadBehind(worktreePath, "main")
+	ahead, behind, err := g.GetAheadBehind(worktreePath, "main")
 	require.NoError(t, err)
 	assert.Equal(t, 0, ahead)
 	assert.Equal(t, 0, behind)
 
 	// Create a feature branch ahead of main by one commit
 	featurePath := filepath.Join(filepath.Dir(worktreePath), "feature")
-	require.NoError(t, AddWorktree(bareRepoPath, filepath.Dir(worktreePath), "feature", []string{"-b", "feature"}))
+	require.NoError(t, g.AddWorktree(bareRepoPath, filepath.Dir(worktreePath), "feature", []string{"-b", "feature"}))
 	require.NoError(t, runCommand("git", "-C", featurePath, "config", "user.email", "test@example.com"))
 	require.NoError(t, runCommand("git", "-C", featurePath, "config", "user.name", "Test User"))
 	require.NoError(t, runCommand("sh", "-c", fmt.Sprintf("cd %s && echo 'feature' > feature.txt && git add feature.txt && git commit -m 'feature commit'", featurePath)))
 
-	ahead, behind, err = GetAheadBehind(featurePath, "main")
+	ahead, behind, err = g.GetAheadBehind(featurePath, "main")
 	require.NoError(t, err)
 	assert.Equal(t, 1, ahead)
 	assert.Equal(t, 0, behind)
 
 	// main is now behind feature by one commit
-	ahead, behind, err = GetAheadBehind(worktreePath, "feature")
+	ahead, behind, err = g.GetAheadBehind(worktreePath, "feature")
 	require.NoError(t, err)
 	assert.Equal(t, 0, ahead)
 	assert.Equal(t, 1, behind)
@@ -107,9 +112,11 @@ func TestGetUpstreamBranch(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := NewGit()
+
 	_, worktreePath := setupStatusTestRepo(t)
 
-	upstream, err := GetUpstreamBranch(worktreePath)
+	upstream, err := g.GetUpstreamBranch(worktreePath)
 	require.NoError(t, err)
 	assert.Empty(t, upstream, "no upstream should be configured")
 
@@ -117,8 +124,8 @@ func TestGetUpstreamBranch(t *testing.T) {
 	// test) and configure the branch to track it, mirroring what a real
 	// fetch from origin would leave behind.
 	require.NoError(t, runCommand("git", "-C", worktreePath, "update-ref", "refs/remotes/origin/main", "refs/heads/main"))
-	require.NoError(t, SetUpstream(worktreePath, "main"))
-	upstream, err = GetUpstreamBranch(worktreePath)
+	require.NoError(t, g.SetUpstream(worktreePath, "main"))
+	upstream, err = g.GetUpstreamBranch(worktreePath)
 	require.NoError(t, err)
 	assert.Equal(t, "origin/main", upstream)
 }
@@ -128,22 +135,24 @@ func TestIsMergedAncestor(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := NewGit()
+
 	bareRepoPath, worktreePath := setupStatusTestRepo(t)
 
 	featurePath := filepath.Join(filepath.Dir(worktreePath), "feature")
-	require.NoError(t, AddWorktree(bareRepoPath, filepath.Dir(worktreePath), "feature", []string{"-b", "feature"}))
+	require.NoError(t, g.AddWorktree(bareRepoPath, filepath.Dir(worktreePath), "feature", []string{"-b", "feature"}))
 	require.NoError(t, runCommand("git", "-C", featurePath, "config", "user.email", "test@example.com"))
 	require.NoError(t, runCommand("git", "-C", featurePath, "config", "user.name", "Test User"))
 	require.NoError(t, runCommand("sh", "-c", fmt.Sprintf("cd %s && echo 'feature' > feature.txt && git add feature.txt && git commit -m 'feature commit'", featurePath)))
 
 	// Not merged yet: main hasn't seen feature's commit
-	merged, err := IsMerged(worktreePath, "feature", "main")
+	merged, err := g.IsMerged(worktreePath, "feature", "main")
 	require.NoError(t, err)
 	assert.False(t, merged)
 
 	// Merge feature into main - now feature should be an ancestor of main
 	require.NoError(t, runCommand("git", "-C", worktreePath, "merge", "feature", "--no-edit"))
-	merged, err = IsMerged(worktreePath, "feature", "main")
+	merged, err = g.IsMerged(worktreePath, "feature", "main")
 	require.NoError(t, err)
 	assert.True(t, merged)
 }
@@ -153,15 +162,17 @@ func TestIsMergedSquash(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := NewGit()
+
 	bareRepoPath, worktreePath := setupStatusTestRepo(t)
 
 	featurePath := filepath.Join(filepath.Dir(worktreePath), "feature")
-	require.NoError(t, AddWorktree(bareRepoPath, filepath.Dir(worktreePath), "feature", []string{"-b", "feature"}))
+	require.NoError(t, g.AddWorktree(bareRepoPath, filepath.Dir(worktreePath), "feature", []string{"-b", "feature"}))
 	require.NoError(t, runCommand("git", "-C", featurePath, "config", "user.email", "test@example.com"))
 	require.NoError(t, runCommand("git", "-C", featurePath, "config", "user.name", "Test User"))
 	require.NoError(t, runCommand("sh", "-c", fmt.Sprintf("cd %s && echo 'feature' > feature.txt && git add feature.txt && git commit -m 'feature commit'", featurePath)))
 
-	merged, err := IsMerged(worktreePath, "feature", "main")
+	merged, err := g.IsMerged(worktreePath, "feature", "main")
 	require.NoError(t, err)
 	assert.False(t, merged)
 
@@ -169,7 +180,7 @@ func TestIsMergedSquash(t *testing.T) {
 	require.NoError(t, runCommand("git", "-C", worktreePath, "merge", "--squash", "feature"))
 	require.NoError(t, runCommand("git", "-C", worktreePath, "commit", "-m", "squash merge feature"))
 
-	merged, err = IsMerged(worktreePath, "feature", "main")
+	merged, err = g.IsMerged(worktreePath, "feature", "main")
 	require.NoError(t, err)
 	assert.True(t, merged, "squash-merged branch should be detected as merged via patch-id content match")
 }
@@ -5,7 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/garrettkrohn/treekanga/testfixture"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -16,6 +18,8 @@ func TestRenameBranch(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := NewGit()
+
 	// Create a temporary directory for test repo
 	tempDir, err := os.MkdirTemp("", "treekanga-rename-branch-test-*")
 	require.NoError(t, err)
@@ -27,12 +31,12 @@ func TestRenameBranch(t *testing.T) {
 	require.NoError(t, err)
 
 	// Configure the bare repo
-	err = ConfigureBare(bareRepoPath)
+	err = g.ConfigureBare(bareRepoPath)
 	require.NoError(t, err)
 
 	// Create a worktree with a branch
 	worktreePath := filepath.Join(tempDir, "test-branch")
-	err = AddWorktree(bareRepoPath, tempDir, "test-branch", []string{"-b", "old-branch"})
+	err = g.AddWorktree(bareRepoPath, tempDir, "test-branch", []string{"-b", "old-branch"})
 	require.NoError(t, err)
 
 	// Configure git user for the worktree
@@ -46,11 +50,11 @@ func TestRenameBranch(t *testing.T) {
 	require.NoError(t, err)
 
 	// Rename the branch
-	err = RenameBranch(bareRepoPath, "old-branch", "new-branch")
+	err = g.RenameBranch(bareRepoPath, "old-branch", "new-branch")
 	assert.NoError(t, err, "Should successfully rename branch")
 
 	// Verify the new branch exists
-	branches, err := GetLocalBranches(bareRepoPath)
+	branches, err := g.GetLocalBranches(bareRepoPath)
 	require.NoError(t, err)
 	assert.Contains(t, branches, "new-branch", "New branch should exist")
 	assert.NotContains(t, branches, "old-branch", "Old branch should not exist")
@@ -62,6 +66,8 @@ func TestMoveWorktree(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := NewGit()
+
 	// Create a temporary directory for test repo
 	tempDir, err := os.MkdirTemp("", "treekanga-move-worktree-test-*")
 	require.NoError(t, err)
@@ -73,12 +79,12 @@ func TestMoveWorktree(t *testing.T) {
 	require.NoError(t, err)
 
 	// Configure the bare repo
-	err = ConfigureBare(bareRepoPath)
+	err = g.ConfigureBare(bareRepoPath)
 	require.NoError(t, err)
 
 	// Create a worktree
 	oldPath := filepath.Join(tempDir, "old-folder")
-	err = AddWorktree(bareRepoPath, tempDir, "old-folder", []string{"-b", "test-branch"})
+	err = g.AddWorktree(bareRepoPath, tempDir, "old-folder", []string{"-b", "test-branch"})
 	require.NoError(t, err)
 
 	// Verify old path exists
@@ -87,7 +93,7 @@ func TestMoveWorktree(t *testing.T) {
 
 	// Move the worktree
 	newPath := filepath.Join(tempDir, "new-folder")
-	err = MoveWorktree(bareRepoPath, oldPath, newPath, false)
+	err = g.MoveWorktree(bareRepoPath, oldPath, newPath, false)
 	assert.NoError(t, err, "Should successfully move worktree")
 
 	// Verify new path exists and old path doesn't
@@ -98,12 +104,43 @@ func TestMoveWorktree(t *testing.T) {
 	assert.True(t, os.IsNotExist(err), "Old worktree path should not exist")
 }
 
+func TestMoveAcrossFilesystems(t *testing.T) {
+	from := filepath.Join(t.TempDir(), "feature-login")
+	require.NoError(t, os.MkdirAll(filepath.Join(from, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(from, ".git"), []byte("gitdir: /code/widget.git/worktrees/feature-login\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(from, "src", "run.sh"), []byte("#!/bin/sh\n"), 0755))
+	require.NoError(t, os.Symlink("src/run.sh", filepath.Join(from, "run")))
+
+	to := filepath.Join(t.TempDir(), "feature-login")
+	require.NoError(t, moveAcrossFilesystems(from, to))
+
+	assert.NoDirExists(t, from)
+	data, err := os.ReadFile(filepath.Join(to, ".git"))
+	require.NoError(t, err)
+	assert.Equal(t, "gitdir: /code/widget.git/worktrees/feature-login\n", string(data))
+	info, err := os.Stat(filepath.Join(to, "src", "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	link, err := os.Readlink(filepath.Join(to, "run"))
+	require.NoError(t, err)
+	assert.Equal(t, "src/run.sh", link)
+
+	// Nothing is copied over a folder that's already there
+	taken := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(taken, "notes.txt"), nil, 0644))
+	assert.EqualError(t, moveAcrossFilesystems(to, taken), taken+" already exists")
+	assert.FileExists(t, filepath.Join(to, ".git"))
+	assert.FileExists(t, filepath.Join(taken, "notes.txt"))
+}
+
 func TestGetCurrentBranch(t *testing.T) {
 	// Skip if running in CI without git
 	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
 		t.Skip("Skipping integration test")
 	}
 
+	g := NewGit()
+
 	// Create a temporary directory for test repo
 	tempDir, err := os.MkdirTemp("", "treekanga-current-branch-test-*")
 	require.NoError(t, err)
@@ -115,22 +152,22 @@ func TestGetCurrentBranch(t *testing.T) {
 	require.NoError(t, err)
 
 	// Configure the bare repo
-	err = ConfigureBare(bareRepoPath)
+	err = g.ConfigureBare(bareRepoPath)
 	require.NoError(t, err)
 
 	// Create a worktree with a specific branch
 	worktreePath := filepath.Join(tempDir, "test-branch")
-	err = AddWorktree(bareRepoPath, tempDir, "test-branch", []string{"-b", "feature/test-branch"})
+	err = g.AddWorktree(bareRepoPath, tempDir, "test-branch", []string{"-b", "feature/test-branch"})
 	require.NoError(t, err)
 
 	// Get current branch from the worktree
-	currentBranch, err := GetCurrentBranch(worktreePath)
+	currentBranch, err := g.GetCurrentBranch(worktreePath)
 	assert.NoError(t, err, "Should successfully get current branch")
 	assert.Equal(t, "feature/test-branch", currentBranch, "Should return correct branch name")
 
 	// Bare repos technically can report a branch (the default branch like master/main)
 	// but that's different from a worktree's current branch. We just verify it doesn't error.
-	_, err = GetCurrentBranch(bareRepoPath)
+	_, err = g.GetCurrentBranch(bareRepoPath)
 	// This may or may not error depending on git version, so we just ensure the function completes
 	t.Logf("GetCurrentBranch from bare repo returned: %v", err)
 }
@@ -141,6 +178,8 @@ func TestFetch(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := NewGit()
+
 	// Create a temporary directory for test repo
 	tempDir, err := os.MkdirTemp("", "treekanga-fetch-test-*")
 	require.NoError(t, err)
@@ -148,21 +187,21 @@ func TestFetch(t *testing.T) {
 
 	// Clone a real repo to have remote branches
 	bareRepoPath := filepath.Join(tempDir, "test.git")
-	err = CloneBare("https://github.com/octocat/Hello-World.git", bareRepoPath)
+	err = g.CloneBare(testfixture.NewRemote(t, "Hello-World").URL(), bareRepoPath)
 	require.NoError(t, err)
 
 	// Configure the bare repo
-	err = ConfigureBare(bareRepoPath)
+	err = g.ConfigureBare(bareRepoPath)
 	require.NoError(t, err)
 
 	// Fetch a specific branch
-	err = Fetch(bareRepoPath, "master")
+	err = g.Fetch(bareRepoPath, "main")
 	assert.NoError(t, err, "Should successfully fetch branch from remote")
 
 	// Verify the branch exists after fetch
-	remoteBranches, err := GetRemoteBranches(bareRepoPath)
+	remoteBranches, err := g.GetRemoteBranches(bareRepoPath)
 	require.NoError(t, err)
-	assert.Contains(t, remoteBranches, "master", "Master branch should exist after fetch")
+	assert.Contains(t, remoteBranches, "main", "Main branch should exist after fetch")
 }
 
 func TestFetchNonExistentBranch(t *testing.T) {
@@ -171,6 +210,8 @@ func TestFetchNonExistentBranch(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := NewGit()
+
 	// Create a temporary directory for test repo
 	tempDir, err := os.MkdirTemp("", "treekanga-fetch-error-test-*")
 	require.NoError(t, err)
@@ -178,13 +219,159 @@ func TestFetchNonExistentBranch(t *testing.T) {
 
 	// Clone a real repo
 	bareRepoPath := filepath.Join(tempDir, "test.git")
-	err = CloneBare("https://github.com/octocat/Hello-World.git", bareRepoPath)
+	err = g.CloneBare(testfixture.NewRemote(t, "Hello-World").URL(), bareRepoPath)
 	require.NoError(t, err)
 
-	err = ConfigureBare(bareRepoPath)
+	err = g.ConfigureBare(bareRepoPath)
 	require.NoError(t, err)
 
 	// Try to fetch a non-existent branch
-	err = Fetch(bareRepoPath, "this-branch-does-not-exist-12345")
+	err = g.Fetch(bareRepoPath, "this-branch-does-not-exist-12345")
 	assert.Error(t, err, "Should error when fetching non-existent branch")
 }
+
+func TestLastFetch(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := testfixture.NewEnv(t)
+	bareRepoPath := env.Clone(testfixture.NewRemote(t, "widget"), "widget")
+	g := NewGit()
+
+	lastFetch, err := g.GetLastFetch(bareRepoPath)
+	require.NoError(t, err)
+	assert.True(t, lastFetch.IsZero(), "never fetched")
+
+	require.NoError(t, g.FetchAll(bareRepoPath))
+	fetchedAt := time.Unix(1700000000, 0)
+	require.NoError(t, g.SetLastFetch(bareRepoPath, fetchedAt))
+
+	lastFetch, err = g.GetLastFetch(bareRepoPath)
+	require.NoError(t, err)
+	assert.Equal(t, fetchedAt.Unix(), lastFetch.Unix())
+}
+
+func TestBranchBase(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := testfixture.NewEnv(t)
+	remote := testfixture.NewRemote(t, "widget").Branch("release/2.0", testfixture.DefaultBranch)
+	bareRepoPath := env.Clone(remote, "widget")
+	testfixture.Git(t, bareRepoPath, "branch", "fix/v2.0.1", "release/2.0")
+	g := NewGit()
+
+	bases, err := g.GetBranchBases(bareRepoPath)
+	require.NoError(t, err)
+	assert.Empty(t, bases)
+
+	require.NoError(t, g.SetBranchBase(bareRepoPath, "fix/v2.0.1", "release/2.0"))
+	require.NoError(t, g.SetBranchBase(bareRepoPath, "Feature.X", "main"))
+
+	base, err := g.GetBranchBase(bareRepoPath, "fix/v2.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "release/2.0", base)
+
+	base, err = g.GetBranchBase(bareRepoPath, "main")
+	require.NoError(t, err)
+	assert.Empty(t, base)
+
+	bases, err = g.GetBranchBases(bareRepoPath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"fix/v2.0.1": "release/2.0", "Feature.X": "main"}, bases)
+
+	// renaming the branch keeps its base
+	testfixture.Git(t, bareRepoPath, "branch", "-m", "fix/v2.0.1", "fix/v2.0.2")
+	base, err = g.GetBranchBase(bareRepoPath, "fix/v2.0.2")
+	require.NoError(t, err)
+	assert.Equal(t, "release/2.0", base)
+}
+
+func TestGetDefaultBranch(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := testfixture.NewEnv(t)
+	bareRepoPath := env.Clone(testfixture.NewRemote(t, "widget"), "widget")
+
+	branch, err := NewGit().GetDefaultBranch(bareRepoPath)
+	require.NoError(t, err)
+	assert.Equal(t, testfixture.DefaultBranch, branch)
+}
+
+func TestBranchParent(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := testfixture.NewEnv(t)
+	bareRepoPath := env.Clone(testfixture.NewRemote(t, "widget"), "widget")
+	testfixture.Git(t, bareRepoPath, "branch", "feature/api", "main")
+	testfixture.Git(t, bareRepoPath, "branch", "feature/ui", "feature/api")
+	g := NewGit()
+
+	parents, err := g.GetBranchParents(bareRepoPath)
+	require.NoError(t, err)
+	assert.Empty(t, parents)
+
+	require.NoError(t, g.SetBranchParent(bareRepoPath, "feature/ui", "feature/api"))
+	require.NoError(t, g.SetBranchBase(bareRepoPath, "feature/ui", "feature/api"))
+
+	parents, err = g.GetBranchParents(bareRepoPath)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"feature/ui": "feature/api"}, parents)
+}
+
+func TestStashes(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := testfixture.NewEnv(t)
+	bareRepoPath := env.Clone(testfixture.NewRemote(t, "widget").Branch("feature", "main"), "widget")
+	g := NewGit()
+
+	stashes, err := g.ListStashes(bareRepoPath)
+	require.NoError(t, err)
+	assert.Empty(t, stashes)
+
+	feature := filepath.Join(env.Home, "feature")
+	detached := filepath.Join(env.Home, "detached")
+	testfixture.Git(t, bareRepoPath, "worktree", "add", "-q", feature, "feature")
+	testfixture.Git(t, bareRepoPath, "worktree", "add", "-q", "--detach", detached, "main")
+	require.NoError(t, os.WriteFile(filepath.Join(feature, "wip.txt"), []byte("wip\n"), 0644))
+	testfixture.Git(t, feature, "stash", "push", "-q", "-u", "-m", "half done")
+	require.NoError(t, os.WriteFile(filepath.Join(detached, "scratch.txt"), []byte("scratch\n"), 0644))
+	testfixture.Git(t, detached, "stash", "push", "-q", "-u")
+
+	stashes, err = g.ListStashes(bareRepoPath)
+	require.NoError(t, err)
+	require.Len(t, stashes, 2)
+	assert.Equal(t, "stash@{0}", stashes[0].Ref)
+	assert.Empty(t, stashes[0].Branch)
+	assert.Equal(t, "stash@{1}", stashes[1].Ref)
+	assert.Equal(t, "feature", stashes[1].Branch)
+	assert.Equal(t, "half done", stashes[1].Message)
+	assert.False(t, stashes[1].Created.IsZero())
+
+	// pop the feature stash into the detached worktree, after a push moved
+	// it to stash@{2}
+	require.NoError(t, os.WriteFile(filepath.Join(feature, "later.txt"), []byte("later\n"), 0644))
+	later, err := g.StashPush(feature, "later")
+	require.NoError(t, err)
+	require.NoError(t, g.PopStash(detached, stashes[1].Commit))
+	assert.FileExists(t, filepath.Join(detached, "wip.txt"))
+	stashes, err = g.ListStashes(bareRepoPath)
+	require.NoError(t, err)
+	require.Len(t, stashes, 2)
+	assert.Equal(t, later, stashes[0].Commit)
+	assert.Equal(t, "later", stashes[0].Message)
+	assert.Empty(t, stashes[1].Branch)
+
+	nothing, err := g.StashPush(feature, "nothing")
+	require.NoError(t, err)
+	assert.Empty(t, nothing)
+}
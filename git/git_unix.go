@@ -2,10 +2,19 @@
 
 package git
 
-import "syscall"
+import (
+	"os/exec"
+	"syscall"
+)
 
 func setSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{
 		Setpgid: true,
 	}
 }
+
+// killProcessGroup kills the command and everything it started, e.g. the
+// ssh or credential helper behind a hung git fetch
+func killProcessGroup(command *exec.Cmd) error {
+	return syscall.Kill(-command.Process.Pid, syscall.SIGKILL)
+}
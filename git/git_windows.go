@@ -2,10 +2,18 @@
 
 package git
 
-import "syscall"
+import (
+	"os/exec"
+	"syscall"
+)
 
 func setSysProcAttr() *syscall.SysProcAttr {
 	return &syscall.SysProcAttr{
 		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP,
 	}
 }
+
+// killProcessGroup kills the command; Windows has no process group signal
+func killProcessGroup(command *exec.Cmd) error {
+	return command.Process.Kill()
+}
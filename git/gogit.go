@@ -0,0 +1,420 @@
+package git
+
+import (
+	"container/heap"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GoGit answers the read-only queries that run for every worktree (listing
+// worktrees and branches, the current branch, ahead/behind counts) in
+// process with go-git and by reading the worktree admin dirs, instead of
+// spawning git. Everything else, and any query go-git can't answer (e.g. a
+// shallow history), goes to the wrapped Git.
+type GoGit struct {
+	Git
+}
+
+func NewGoGit(fallback Git) Git {
+	return &GoGit{Git: fallback}
+}
+
+// ListWorktrees returns the same lines as git worktree list.
+func (g *GoGit) ListWorktrees(bareRepoPath string) ([]string, error) {
+	lines, err := listWorktrees(bareRepoPath)
+	if err != nil {
+		log.Debug("go-git backend falling back to git", "query", "worktree list", "error", err)
+		return g.Git.ListWorktrees(bareRepoPath)
+	}
+	return lines, nil
+}
+
+// GetRemoteBranches lists remote branches without the origin/ prefix.
+func (g *GoGit) GetRemoteBranches(bareRepoPath string) ([]string, error) {
+	branches, err := listBranches(bareRepoPath, "refs/remotes/")
+	if err != nil {
+		log.Debug("go-git backend falling back to git", "query", "branch -r", "error", err)
+		return g.Git.GetRemoteBranches(bareRepoPath)
+	}
+
+	cleaned := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		// origin/HEAD, git lists it as just "origin"
+		if strings.HasSuffix(branch, "/HEAD") {
+			continue
+		}
+		cleaned = append(cleaned, strings.TrimPrefix(branch, "origin/"))
+	}
+	return cleaned, nil
+}
+
+// GetLocalBranches lists local branches
+func (g *GoGit) GetLocalBranches(bareRepoPath string) ([]string, error) {
+	branches, err := listBranches(bareRepoPath, "refs/heads/")
+	if err != nil {
+		log.Debug("go-git backend falling back to git", "query", "branch", "error", err)
+		return g.Git.GetLocalBranches(bareRepoPath)
+	}
+	return branches, nil
+}
+
+// GetCurrentBranch returns the branch checked out in dir, "" when detached.
+func (g *GoGit) GetCurrentBranch(dir string) (string, error) {
+	gitDir, _, err := findGitDirs(dir)
+	if err != nil {
+		log.Debug("go-git backend falling back to git", "query", "branch --show-current", "error", err)
+		return g.Git.GetCurrentBranch(dir)
+	}
+	head, err := readHead(gitDir)
+	if err != nil {
+		log.Debug("go-git backend falling back to git", "query", "branch --show-current", "error", err)
+		return g.Git.GetCurrentBranch(dir)
+	}
+	if head.Type() == plumbing.SymbolicReference && head.Target().IsBranch() {
+		return head.Target().Short(), nil
+	}
+	return "", nil
+}
+
+// GetAheadBehind returns how many commits HEAD is ahead/behind compareRef
+// in a given worktree.
+func (g *GoGit) GetAheadBehind(worktreePath, compareRef string) (ahead, behind int, err error) {
+	ahead, behind, err = aheadBehind(worktreePath, compareRef)
+	if err != nil {
+		log.Debug("go-git backend falling back to git", "query", "rev-list --left-right", "error", err)
+		return g.Git.GetAheadBehind(worktreePath, compareRef)
+	}
+	return ahead, behind, nil
+}
+
+// findGitDirs returns the git dir of the repository or worktree containing
+// dir and the common dir shared by all its worktrees, like git rev-parse
+// --git-dir and --git-common-dir.
+func findGitDirs(dir string) (gitDir, commonDir string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	for current := dir; ; current = filepath.Dir(current) {
+		gitDir = gitDirAt(current)
+		if gitDir != "" {
+			break
+		}
+		if filepath.Dir(current) == current {
+			return "", "", fmt.Errorf("not a git repository: %s", dir)
+		}
+	}
+
+	commonDir = gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = relativeTo(gitDir, strings.TrimSpace(string(data)))
+	}
+	return gitDir, commonDir, nil
+}
+
+// gitDirAt returns the git dir when dir is a bare repository, a worktree
+// admin dir or contains .git (a directory, or a file pointing at one).
+func gitDirAt(dir string) string {
+	dotGit := filepath.Join(dir, ".git")
+	if info, err := os.Stat(dotGit); err == nil {
+		if info.IsDir() {
+			return dotGit
+		}
+		data, err := os.ReadFile(dotGit)
+		if err == nil && strings.HasPrefix(string(data), "gitdir: ") {
+			return relativeTo(dir, strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir: ")))
+		}
+	}
+
+	_, headErr := os.Stat(filepath.Join(dir, "HEAD"))
+	_, objectsErr := os.Stat(filepath.Join(dir, "objects"))
+	_, commonDirErr := os.Stat(filepath.Join(dir, "commondir"))
+	if headErr == nil && (objectsErr == nil || commonDirErr == nil) {
+		return dir
+	}
+	return ""
+}
+
+func relativeTo(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(dir, path)
+}
+
+// readHead reads the HEAD of a git dir without resolving it. Worktrees keep
+// their own HEAD in their admin dir, which go-git doesn't know about.
+func readHead(gitDir string) (*plumbing.Reference, error) {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return nil, err
+	}
+	head := strings.TrimSpace(string(data))
+	if target, ok := strings.CutPrefix(head, "ref: "); ok {
+		return plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.ReferenceName(target)), nil
+	}
+	if !plumbing.IsHash(head) {
+		return nil, fmt.Errorf("unexpected HEAD in %s: %q", gitDir, head)
+	}
+	return plumbing.NewHashReference(plumbing.HEAD, plumbing.NewHash(head)), nil
+}
+
+// openRepo opens the common dir of the repository containing dir with go-git.
+func openRepo(dir string) (repo *gogit.Repository, gitDir, commonDir string, err error) {
+	gitDir, commonDir, err = findGitDirs(dir)
+	if err != nil {
+		return nil, "", "", err
+	}
+	repo, err = gogit.PlainOpenWithOptions(commonDir, &gogit.PlainOpenOptions{})
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to open %s: %w", commonDir, err)
+	}
+	return repo, gitDir, commonDir, nil
+}
+
+// resolveHead resolves the HEAD of gitDir to a commit, the zero hash for an
+// unborn branch.
+func resolveHead(repo *gogit.Repository, gitDir string) (*plumbing.Reference, plumbing.Hash, error) {
+	head, err := readHead(gitDir)
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+	if head.Type() == plumbing.HashReference {
+		return head, head.Hash(), nil
+	}
+	ref, err := repo.Reference(head.Target(), true)
+	if err == plumbing.ErrReferenceNotFound {
+		return head, plumbing.ZeroHash, nil
+	}
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+	return head, ref.Hash(), nil
+}
+
+// listBranches returns the short names of the refs under prefix, sorted like
+// git branch sorts them.
+func listBranches(bareRepoPath, prefix string) ([]string, error) {
+	repo, _, _, err := openRepo(bareRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if name, ok := strings.CutPrefix(ref.Name().String(), prefix); ok {
+			branches = append(branches, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(branches)
+	return branches, nil
+}
+
+type worktreeEntry struct {
+	path     string
+	bare     bool
+	head     *plumbing.Reference
+	hash     plumbing.Hash
+	locked   bool
+	prunable bool
+}
+
+// listWorktrees reads the main worktree and the admin dir of every linked
+// worktree and formats them like git worktree list. Hashes are abbreviated
+// to 7 characters, git lengthens them in repositories where that's ambiguous.
+func listWorktrees(bareRepoPath string) ([]string, error) {
+	repo, _, commonDir, err := openRepo(bareRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	commonDir, err = filepath.EvalSymlinks(commonDir)
+	if err != nil {
+		return nil, err
+	}
+	mainWorktree := worktreeEntry{path: commonDir, bare: cfg.Core.IsBare}
+	if !mainWorktree.bare {
+		mainWorktree.path = filepath.Dir(commonDir)
+		mainWorktree.head, mainWorktree.hash, err = resolveHead(repo, commonDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var linked []worktreeEntry
+	adminDirs, err := os.ReadDir(filepath.Join(commonDir, "worktrees"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, adminDir := range adminDirs {
+		adminPath := filepath.Join(commonDir, "worktrees", adminDir.Name())
+		gitdir, err := os.ReadFile(filepath.Join(adminPath, "gitdir"))
+		if err != nil {
+			continue
+		}
+		entry := worktreeEntry{path: filepath.Dir(relativeTo(adminPath, strings.TrimSpace(string(gitdir))))}
+		entry.head, entry.hash, err = resolveHead(repo, adminPath)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(filepath.Join(adminPath, "locked")); err == nil {
+			entry.locked = true
+		} else if _, err := os.Stat(entry.path); os.IsNotExist(err) {
+			entry.prunable = true
+		}
+		linked = append(linked, entry)
+	}
+	sort.Slice(linked, func(i, j int) bool { return linked[i].path < linked[j].path })
+
+	entries := append([]worktreeEntry{mainWorktree}, linked...)
+	width := 0
+	for _, entry := range entries {
+		width = max(width, len(entry.path))
+	}
+
+	lines := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		line := fmt.Sprintf("%-*s ", width+1, entry.path)
+		switch {
+		case entry.bare:
+			line += "(bare)"
+		case entry.head.Type() == plumbing.SymbolicReference:
+			line += fmt.Sprintf("%s [%s]", entry.hash.String()[:7], entry.head.Target().Short())
+		default:
+			line += fmt.Sprintf("%s (detached HEAD)", entry.hash.String()[:7])
+		}
+		if entry.locked {
+			line += " locked"
+		}
+		if entry.prunable {
+			line += " prunable"
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+// aheadBehind counts the commits only reachable from HEAD of worktreePath
+// and the ones only reachable from compareRef, like git rev-list
+// --left-right --count HEAD...compareRef.
+func aheadBehind(worktreePath, compareRef string) (ahead, behind int, err error) {
+	repo, gitDir, _, err := openRepo(worktreePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	_, head, err := resolveHead(repo, gitDir)
+	if err != nil {
+		return 0, 0, err
+	}
+	if head.IsZero() {
+		return 0, 0, fmt.Errorf("HEAD of %s has no commits", worktreePath)
+	}
+	compare, err := repo.ResolveRevision(plumbing.Revision(compareRef))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to resolve %s: %w", compareRef, err)
+	}
+
+	flags, err := paintSides(repo, head, *compare)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, flag := range flags {
+		switch flag {
+		case leftSide:
+			ahead++
+		case rightSide:
+			behind++
+		}
+	}
+	return ahead, behind, nil
+}
+
+const (
+	leftSide uint8 = 1 << iota
+	rightSide
+	bothSides = leftSide | rightSide
+)
+
+// paintSides walks the history of left and right newest first, marking each
+// commit with the sides it is reachable from, and stops once everything
+// left to walk is reachable from both.
+func paintSides(repo *gogit.Repository, left, right plumbing.Hash) (map[plumbing.Hash]uint8, error) {
+	flags := map[plumbing.Hash]uint8{}
+	queue := &commitQueue{}
+
+	mark := func(hash plumbing.Hash, side uint8) error {
+		if flags[hash]|side == flags[hash] {
+			return nil
+		}
+		flags[hash] |= side
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			return fmt.Errorf("failed to read commit %s: %w", hash, err)
+		}
+		heap.Push(queue, commit)
+		return nil
+	}
+
+	if err := mark(left, leftSide); err != nil {
+		return nil, err
+	}
+	if err := mark(right, rightSide); err != nil {
+		return nil, err
+	}
+
+	for queue.Len() > 0 && !queue.allMarked(flags, bothSides) {
+		commit := heap.Pop(queue).(*object.Commit)
+		for _, parent := range commit.ParentHashes {
+			if err := mark(parent, flags[commit.Hash]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return flags, nil
+}
+
+// commitQueue is a heap of commits, newest committer date first.
+type commitQueue []*object.Commit
+
+func (q commitQueue) Len() int { return len(q) }
+func (q commitQueue) Less(i, j int) bool {
+	return q[i].Committer.When.After(q[j].Committer.When)
+}
+func (q commitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x any)   { *q = append(*q, x.(*object.Commit)) }
+func (q *commitQueue) Pop() any {
+	old := *q
+	commit := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return commit
+}
+
+func (q commitQueue) allMarked(flags map[plumbing.Hash]uint8, flag uint8) bool {
+	for _, commit := range q {
+		if flags[commit.Hash] != flag {
+			return false
+		}
+	}
+	return true
+}
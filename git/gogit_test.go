@@ -0,0 +1,149 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/testfixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupBackendParityRepo clones a fixture remote and adds worktrees in every
+// state the read queries have to handle: on a branch ahead of and behind
+// main, detached, locked, on an unborn branch and with its folder removed.
+func setupBackendParityRepo(t *testing.T) (bareRepoPath, worktrees string) {
+	t.Helper()
+
+	env := testfixture.NewEnv(t)
+	remote := testfixture.NewRemote(t, "widget").
+		Branch("feature/shared", testfixture.DefaultBranch).
+		Commit("feature/shared", "second shared commit").
+		Commit(testfixture.DefaultBranch, "move main ahead")
+	bareRepoPath = env.Clone(remote, "widget")
+	worktrees = filepath.Join(env.Home, "widget_work")
+
+	testfixture.Git(t, bareRepoPath, "worktree", "add", "-q", filepath.Join(worktrees, "feature-shared"), "feature/shared")
+	testfixture.Git(t, filepath.Join(worktrees, "feature-shared"), "commit", "-q", "--allow-empty", "-m", "local only")
+	testfixture.Git(t, bareRepoPath, "worktree", "add", "-q", filepath.Join(worktrees, "detached"), "--detach", "origin/main~1")
+	testfixture.Git(t, bareRepoPath, "worktree", "add", "-q", filepath.Join(worktrees, "locked"), "-b", "locked", "origin/main")
+	testfixture.Git(t, bareRepoPath, "worktree", "lock", filepath.Join(worktrees, "locked"))
+	testfixture.Git(t, bareRepoPath, "worktree", "add", "-q", filepath.Join(worktrees, "gone"), "-b", "gone", "origin/main")
+	require.NoError(t, os.RemoveAll(filepath.Join(worktrees, "gone")))
+	testfixture.Git(t, bareRepoPath, "worktree", "add", "-q", filepath.Join(worktrees, "unborn"), "--detach")
+	testfixture.Git(t, filepath.Join(worktrees, "unborn"), "checkout", "-q", "--orphan", "unborn")
+	return bareRepoPath, worktrees
+}
+
+func TestGoGitParity(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	bareRepoPath, worktrees := setupBackendParityRepo(t)
+	execGit := NewGit()
+	goGit := NewGoGit(&MockGit{}) // a call reaching the mock means go-git fell back
+
+	t.Run("ListWorktrees", func(t *testing.T) {
+		expected, err := execGit.ListWorktrees(bareRepoPath)
+		require.NoError(t, err)
+		actual, err := goGit.ListWorktrees(bareRepoPath)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, expected, actual)
+	})
+
+	t.Run("GetLocalBranches", func(t *testing.T) {
+		expected, err := execGit.GetLocalBranches(bareRepoPath)
+		require.NoError(t, err)
+		actual, err := goGit.GetLocalBranches(bareRepoPath)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("GetRemoteBranches", func(t *testing.T) {
+		testfixture.Git(t, bareRepoPath, "remote", "set-head", "origin", testfixture.DefaultBranch)
+		expected, err := execGit.GetRemoteBranches(bareRepoPath)
+		require.NoError(t, err)
+		actual, err := goGit.GetRemoteBranches(bareRepoPath)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("GetCurrentBranch", func(t *testing.T) {
+		for _, dir := range []string{
+			bareRepoPath,
+			filepath.Join(worktrees, "feature-shared"),
+			filepath.Join(worktrees, "detached"),
+			filepath.Join(worktrees, "unborn"),
+		} {
+			expected, err := execGit.GetCurrentBranch(dir)
+			require.NoError(t, err)
+			actual, err := goGit.GetCurrentBranch(dir)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual, dir)
+		}
+	})
+
+	t.Run("GetAheadBehind", func(t *testing.T) {
+		for _, worktree := range []string{"feature-shared", "detached", "locked"} {
+			for _, compareRef := range []string{"main", "origin/main", "origin/feature/shared"} {
+				path := filepath.Join(worktrees, worktree)
+				expectedAhead, expectedBehind, err := execGit.GetAheadBehind(path, compareRef)
+				require.NoError(t, err)
+				ahead, behind, err := goGit.GetAheadBehind(path, compareRef)
+				require.NoError(t, err)
+				assert.Equal(t, []int{expectedAhead, expectedBehind}, []int{ahead, behind}, "%s against %s", worktree, compareRef)
+			}
+		}
+	})
+}
+
+func TestGoGitParityNonBare(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := testfixture.NewEnv(t)
+	remote := testfixture.NewRemote(t, "widget").Branch("feature/x", testfixture.DefaultBranch)
+	clone := filepath.Join(env.Home, "widget")
+	testfixture.Git(t, "", "clone", "-q", remote.URL(), clone)
+	testfixture.Git(t, clone, "worktree", "add", "-q", filepath.Join(env.Home, "feature-x"), "feature/x")
+
+	execGit := NewGit()
+	goGit := NewGoGit(&MockGit{})
+
+	expected, err := execGit.ListWorktrees(clone)
+	require.NoError(t, err)
+	actual, err := goGit.ListWorktrees(clone)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	// a subdirectory of the worktree
+	subdir := filepath.Join(env.Home, "feature-x", "nested")
+	require.NoError(t, os.Mkdir(subdir, 0755))
+	branch, err := goGit.GetCurrentBranch(subdir)
+	require.NoError(t, err)
+	assert.Equal(t, "feature/x", branch)
+}
+
+func TestGoGitFallsBack(t *testing.T) {
+	fallback := NewMockGit(t)
+	fallback.EXPECT().ListWorktrees("/not/a/repo").Return([]string{"/not/a/repo  (bare)"}, nil)
+	fallback.EXPECT().GetAheadBehind("/not/a/repo", "main").Return(1, 2, nil)
+	fallback.EXPECT().RemoveWorktree("/not/a/repo", "/not/a/repo/wt", false).Return(nil)
+
+	goGit := NewGoGit(fallback)
+
+	lines, err := goGit.ListWorktrees("/not/a/repo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/not/a/repo  (bare)"}, lines)
+
+	ahead, behind, err := goGit.GetAheadBehind("/not/a/repo", "main")
+	require.NoError(t, err)
+	assert.Equal(t, 1, ahead)
+	assert.Equal(t, 2, behind)
+
+	// writes always go to the wrapped client
+	assert.NoError(t, goGit.RemoveWorktree("/not/a/repo", "/not/a/repo/wt", false))
+}
@@ -0,0 +1,2661 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package git
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockGit is an autogenerated mock type for the Git type
+type MockGit struct {
+	mock.Mock
+}
+
+type MockGit_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockGit) EXPECT() *MockGit_Expecter {
+	return &MockGit_Expecter{mock: &_m.Mock}
+}
+
+// AbortMerge provides a mock function with given fields: worktreePath
+func (_m *MockGit) AbortMerge(worktreePath string) error {
+	ret := _m.Called(worktreePath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AbortMerge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(worktreePath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_AbortMerge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AbortMerge'
+type MockGit_AbortMerge_Call struct {
+	*mock.Call
+}
+
+// AbortMerge is a helper method to define mock.On call
+//   - worktreePath string
+func (_e *MockGit_Expecter) AbortMerge(worktreePath interface{}) *MockGit_AbortMerge_Call {
+	return &MockGit_AbortMerge_Call{Call: _e.mock.On("AbortMerge", worktreePath)}
+}
+
+func (_c *MockGit_AbortMerge_Call) Run(run func(worktreePath string)) *MockGit_AbortMerge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_AbortMerge_Call) Return(_a0 error) *MockGit_AbortMerge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_AbortMerge_Call) RunAndReturn(run func(string) error) *MockGit_AbortMerge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AbortRebase provides a mock function with given fields: worktreePath
+func (_m *MockGit) AbortRebase(worktreePath string) error {
+	ret := _m.Called(worktreePath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AbortRebase")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(worktreePath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_AbortRebase_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AbortRebase'
+type MockGit_AbortRebase_Call struct {
+	*mock.Call
+}
+
+// AbortRebase is a helper method to define mock.On call
+//   - worktreePath string
+func (_e *MockGit_Expecter) AbortRebase(worktreePath interface{}) *MockGit_AbortRebase_Call {
+	return &MockGit_AbortRebase_Call{Call: _e.mock.On("AbortRebase", worktreePath)}
+}
+
+func (_c *MockGit_AbortRebase_Call) Run(run func(worktreePath string)) *MockGit_AbortRebase_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_AbortRebase_Call) Return(_a0 error) *MockGit_AbortRebase_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_AbortRebase_Call) RunAndReturn(run func(string) error) *MockGit_AbortRebase_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddWorktree provides a mock function with given fields: bareRepoPath, worktreeTargetDir, worktreeName, worktreeArgs
+func (_m *MockGit) AddWorktree(bareRepoPath string, worktreeTargetDir string, worktreeName string, worktreeArgs []string) error {
+	ret := _m.Called(bareRepoPath, worktreeTargetDir, worktreeName, worktreeArgs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddWorktree")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, []string) error); ok {
+		r0 = rf(bareRepoPath, worktreeTargetDir, worktreeName, worktreeArgs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_AddWorktree_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddWorktree'
+type MockGit_AddWorktree_Call struct {
+	*mock.Call
+}
+
+// AddWorktree is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - worktreeTargetDir string
+//   - worktreeName string
+//   - worktreeArgs []string
+func (_e *MockGit_Expecter) AddWorktree(bareRepoPath interface{}, worktreeTargetDir interface{}, worktreeName interface{}, worktreeArgs interface{}) *MockGit_AddWorktree_Call {
+	return &MockGit_AddWorktree_Call{Call: _e.mock.On("AddWorktree", bareRepoPath, worktreeTargetDir, worktreeName, worktreeArgs)}
+}
+
+func (_c *MockGit_AddWorktree_Call) Run(run func(bareRepoPath string, worktreeTargetDir string, worktreeName string, worktreeArgs []string)) *MockGit_AddWorktree_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *MockGit_AddWorktree_Call) Return(_a0 error) *MockGit_AddWorktree_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_AddWorktree_Call) RunAndReturn(run func(string, string, string, []string) error) *MockGit_AddWorktree_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CloneBare provides a mock function with given fields: url, folderName
+func (_m *MockGit) CloneBare(url string, folderName string) error {
+	ret := _m.Called(url, folderName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CloneBare")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(url, folderName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_CloneBare_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CloneBare'
+type MockGit_CloneBare_Call struct {
+	*mock.Call
+}
+
+// CloneBare is a helper method to define mock.On call
+//   - url string
+//   - folderName string
+func (_e *MockGit_Expecter) CloneBare(url interface{}, folderName interface{}) *MockGit_CloneBare_Call {
+	return &MockGit_CloneBare_Call{Call: _e.mock.On("CloneBare", url, folderName)}
+}
+
+func (_c *MockGit_CloneBare_Call) Run(run func(url string, folderName string)) *MockGit_CloneBare_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_CloneBare_Call) Return(_a0 error) *MockGit_CloneBare_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_CloneBare_Call) RunAndReturn(run func(string, string) error) *MockGit_CloneBare_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ConfigureBare provides a mock function with given fields: bareRepoPath
+func (_m *MockGit) ConfigureBare(bareRepoPath string) error {
+	ret := _m.Called(bareRepoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfigureBare")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(bareRepoPath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_ConfigureBare_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfigureBare'
+type MockGit_ConfigureBare_Call struct {
+	*mock.Call
+}
+
+// ConfigureBare is a helper method to define mock.On call
+//   - bareRepoPath string
+func (_e *MockGit_Expecter) ConfigureBare(bareRepoPath interface{}) *MockGit_ConfigureBare_Call {
+	return &MockGit_ConfigureBare_Call{Call: _e.mock.On("ConfigureBare", bareRepoPath)}
+}
+
+func (_c *MockGit_ConfigureBare_Call) Run(run func(bareRepoPath string)) *MockGit_ConfigureBare_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_ConfigureBare_Call) Return(_a0 error) *MockGit_ConfigureBare_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_ConfigureBare_Call) RunAndReturn(run func(string) error) *MockGit_ConfigureBare_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteBranch provides a mock function with given fields: bareRepoPath, branch, force
+func (_m *MockGit) DeleteBranch(bareRepoPath string, branch string, force bool) error {
+	ret := _m.Called(bareRepoPath, branch, force)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBranch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, bool) error); ok {
+		r0 = rf(bareRepoPath, branch, force)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_DeleteBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteBranch'
+type MockGit_DeleteBranch_Call struct {
+	*mock.Call
+}
+
+// DeleteBranch is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - branch string
+//   - force bool
+func (_e *MockGit_Expecter) DeleteBranch(bareRepoPath interface{}, branch interface{}, force interface{}) *MockGit_DeleteBranch_Call {
+	return &MockGit_DeleteBranch_Call{Call: _e.mock.On("DeleteBranch", bareRepoPath, branch, force)}
+}
+
+func (_c *MockGit_DeleteBranch_Call) Run(run func(bareRepoPath string, branch string, force bool)) *MockGit_DeleteBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *MockGit_DeleteBranch_Call) Return(_a0 error) *MockGit_DeleteBranch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_DeleteBranch_Call) RunAndReturn(run func(string, string, bool) error) *MockGit_DeleteBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRemoteBranch provides a mock function with given fields: bareRepoPath, branch
+func (_m *MockGit) DeleteRemoteBranch(bareRepoPath string, branch string) error {
+	ret := _m.Called(bareRepoPath, branch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRemoteBranch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(bareRepoPath, branch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_DeleteRemoteBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRemoteBranch'
+type MockGit_DeleteRemoteBranch_Call struct {
+	*mock.Call
+}
+
+// DeleteRemoteBranch is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - branch string
+func (_e *MockGit_Expecter) DeleteRemoteBranch(bareRepoPath interface{}, branch interface{}) *MockGit_DeleteRemoteBranch_Call {
+	return &MockGit_DeleteRemoteBranch_Call{Call: _e.mock.On("DeleteRemoteBranch", bareRepoPath, branch)}
+}
+
+func (_c *MockGit_DeleteRemoteBranch_Call) Run(run func(bareRepoPath string, branch string)) *MockGit_DeleteRemoteBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_DeleteRemoteBranch_Call) Return(_a0 error) *MockGit_DeleteRemoteBranch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_DeleteRemoteBranch_Call) RunAndReturn(run func(string, string) error) *MockGit_DeleteRemoteBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DescribeHead provides a mock function with given fields: worktreePath
+func (_m *MockGit) DescribeHead(worktreePath string) (string, error) {
+	ret := _m.Called(worktreePath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DescribeHead")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(worktreePath)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(worktreePath)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(worktreePath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_DescribeHead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DescribeHead'
+type MockGit_DescribeHead_Call struct {
+	*mock.Call
+}
+
+// DescribeHead is a helper method to define mock.On call
+//   - worktreePath string
+func (_e *MockGit_Expecter) DescribeHead(worktreePath interface{}) *MockGit_DescribeHead_Call {
+	return &MockGit_DescribeHead_Call{Call: _e.mock.On("DescribeHead", worktreePath)}
+}
+
+func (_c *MockGit_DescribeHead_Call) Run(run func(worktreePath string)) *MockGit_DescribeHead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_DescribeHead_Call) Return(_a0 string, _a1 error) *MockGit_DescribeHead_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_DescribeHead_Call) RunAndReturn(run func(string) (string, error)) *MockGit_DescribeHead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Fetch provides a mock function with given fields: bareRepoPath, branch
+func (_m *MockGit) Fetch(bareRepoPath string, branch string) error {
+	ret := _m.Called(bareRepoPath, branch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fetch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(bareRepoPath, branch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_Fetch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fetch'
+type MockGit_Fetch_Call struct {
+	*mock.Call
+}
+
+// Fetch is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - branch string
+func (_e *MockGit_Expecter) Fetch(bareRepoPath interface{}, branch interface{}) *MockGit_Fetch_Call {
+	return &MockGit_Fetch_Call{Call: _e.mock.On("Fetch", bareRepoPath, branch)}
+}
+
+func (_c *MockGit_Fetch_Call) Run(run func(bareRepoPath string, branch string)) *MockGit_Fetch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_Fetch_Call) Return(_a0 error) *MockGit_Fetch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_Fetch_Call) RunAndReturn(run func(string, string) error) *MockGit_Fetch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FetchAll provides a mock function with given fields: bareRepoPath
+func (_m *MockGit) FetchAll(bareRepoPath string) error {
+	ret := _m.Called(bareRepoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(bareRepoPath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_FetchAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchAll'
+type MockGit_FetchAll_Call struct {
+	*mock.Call
+}
+
+// FetchAll is a helper method to define mock.On call
+//   - bareRepoPath string
+func (_e *MockGit_Expecter) FetchAll(bareRepoPath interface{}) *MockGit_FetchAll_Call {
+	return &MockGit_FetchAll_Call{Call: _e.mock.On("FetchAll", bareRepoPath)}
+}
+
+func (_c *MockGit_FetchAll_Call) Run(run func(bareRepoPath string)) *MockGit_FetchAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_FetchAll_Call) Return(_a0 error) *MockGit_FetchAll_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_FetchAll_Call) RunAndReturn(run func(string) error) *MockGit_FetchAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FetchBranches provides a mock function with given fields: bareRepoPath, branches
+func (_m *MockGit) FetchBranches(bareRepoPath string, branches []string) error {
+	ret := _m.Called(bareRepoPath, branches)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchBranches")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string) error); ok {
+		r0 = rf(bareRepoPath, branches)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_FetchBranches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchBranches'
+type MockGit_FetchBranches_Call struct {
+	*mock.Call
+}
+
+// FetchBranches is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - branches []string
+func (_e *MockGit_Expecter) FetchBranches(bareRepoPath interface{}, branches interface{}) *MockGit_FetchBranches_Call {
+	return &MockGit_FetchBranches_Call{Call: _e.mock.On("FetchBranches", bareRepoPath, branches)}
+}
+
+func (_c *MockGit_FetchBranches_Call) Run(run func(bareRepoPath string, branches []string)) *MockGit_FetchBranches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *MockGit_FetchBranches_Call) Return(_a0 error) *MockGit_FetchBranches_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_FetchBranches_Call) RunAndReturn(run func(string, []string) error) *MockGit_FetchBranches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FetchTag provides a mock function with given fields: bareRepoPath, tag
+func (_m *MockGit) FetchTag(bareRepoPath string, tag string) error {
+	ret := _m.Called(bareRepoPath, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(bareRepoPath, tag)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_FetchTag_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchTag'
+type MockGit_FetchTag_Call struct {
+	*mock.Call
+}
+
+// FetchTag is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - tag string
+func (_e *MockGit_Expecter) FetchTag(bareRepoPath interface{}, tag interface{}) *MockGit_FetchTag_Call {
+	return &MockGit_FetchTag_Call{Call: _e.mock.On("FetchTag", bareRepoPath, tag)}
+}
+
+func (_c *MockGit_FetchTag_Call) Run(run func(bareRepoPath string, tag string)) *MockGit_FetchTag_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_FetchTag_Call) Return(_a0 error) *MockGit_FetchTag_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_FetchTag_Call) RunAndReturn(run func(string, string) error) *MockGit_FetchTag_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ForkPoint provides a mock function with given fields: repoPath, parent, branch
+func (_m *MockGit) ForkPoint(repoPath string, parent string, branch string) (string, error) {
+	ret := _m.Called(repoPath, parent, branch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForkPoint")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (string, error)); ok {
+		return rf(repoPath, parent, branch)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
+		r0 = rf(repoPath, parent, branch)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(repoPath, parent, branch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_ForkPoint_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForkPoint'
+type MockGit_ForkPoint_Call struct {
+	*mock.Call
+}
+
+// ForkPoint is a helper method to define mock.On call
+//   - repoPath string
+//   - parent string
+//   - branch string
+func (_e *MockGit_Expecter) ForkPoint(repoPath interface{}, parent interface{}, branch interface{}) *MockGit_ForkPoint_Call {
+	return &MockGit_ForkPoint_Call{Call: _e.mock.On("ForkPoint", repoPath, parent, branch)}
+}
+
+func (_c *MockGit_ForkPoint_Call) Run(run func(repoPath string, parent string, branch string)) *MockGit_ForkPoint_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_ForkPoint_Call) Return(_a0 string, _a1 error) *MockGit_ForkPoint_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_ForkPoint_Call) RunAndReturn(run func(string, string, string) (string, error)) *MockGit_ForkPoint_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAheadBehind provides a mock function with given fields: worktreePath, compareRef
+func (_m *MockGit) GetAheadBehind(worktreePath string, compareRef string) (int, int, error) {
+	ret := _m.Called(worktreePath, compareRef)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAheadBehind")
+	}
+
+	var r0 int
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, string) (int, int, error)); ok {
+		return rf(worktreePath, compareRef)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) int); ok {
+		r0 = rf(worktreePath, compareRef)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) int); ok {
+		r1 = rf(worktreePath, compareRef)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, string) error); ok {
+		r2 = rf(worktreePath, compareRef)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockGit_GetAheadBehind_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAheadBehind'
+type MockGit_GetAheadBehind_Call struct {
+	*mock.Call
+}
+
+// GetAheadBehind is a helper method to define mock.On call
+//   - worktreePath string
+//   - compareRef string
+func (_e *MockGit_Expecter) GetAheadBehind(worktreePath interface{}, compareRef interface{}) *MockGit_GetAheadBehind_Call {
+	return &MockGit_GetAheadBehind_Call{Call: _e.mock.On("GetAheadBehind", worktreePath, compareRef)}
+}
+
+func (_c *MockGit_GetAheadBehind_Call) Run(run func(worktreePath string, compareRef string)) *MockGit_GetAheadBehind_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetAheadBehind_Call) Return(ahead int, behind int, err error) *MockGit_GetAheadBehind_Call {
+	_c.Call.Return(ahead, behind, err)
+	return _c
+}
+
+func (_c *MockGit_GetAheadBehind_Call) RunAndReturn(run func(string, string) (int, int, error)) *MockGit_GetAheadBehind_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBareRepoPath provides a mock function with given fields: dir
+func (_m *MockGit) GetBareRepoPath(dir string) (string, error) {
+	ret := _m.Called(dir)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBareRepoPath")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(dir)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(dir)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(dir)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetBareRepoPath_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBareRepoPath'
+type MockGit_GetBareRepoPath_Call struct {
+	*mock.Call
+}
+
+// GetBareRepoPath is a helper method to define mock.On call
+//   - dir string
+func (_e *MockGit_Expecter) GetBareRepoPath(dir interface{}) *MockGit_GetBareRepoPath_Call {
+	return &MockGit_GetBareRepoPath_Call{Call: _e.mock.On("GetBareRepoPath", dir)}
+}
+
+func (_c *MockGit_GetBareRepoPath_Call) Run(run func(dir string)) *MockGit_GetBareRepoPath_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetBareRepoPath_Call) Return(_a0 string, _a1 error) *MockGit_GetBareRepoPath_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetBareRepoPath_Call) RunAndReturn(run func(string) (string, error)) *MockGit_GetBareRepoPath_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBranchBase provides a mock function with given fields: repoPath, branch
+func (_m *MockGit) GetBranchBase(repoPath string, branch string) (string, error) {
+	ret := _m.Called(repoPath, branch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBranchBase")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
+		return rf(repoPath, branch)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(repoPath, branch)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(repoPath, branch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetBranchBase_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBranchBase'
+type MockGit_GetBranchBase_Call struct {
+	*mock.Call
+}
+
+// GetBranchBase is a helper method to define mock.On call
+//   - repoPath string
+//   - branch string
+func (_e *MockGit_Expecter) GetBranchBase(repoPath interface{}, branch interface{}) *MockGit_GetBranchBase_Call {
+	return &MockGit_GetBranchBase_Call{Call: _e.mock.On("GetBranchBase", repoPath, branch)}
+}
+
+func (_c *MockGit_GetBranchBase_Call) Run(run func(repoPath string, branch string)) *MockGit_GetBranchBase_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetBranchBase_Call) Return(_a0 string, _a1 error) *MockGit_GetBranchBase_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetBranchBase_Call) RunAndReturn(run func(string, string) (string, error)) *MockGit_GetBranchBase_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBranchBases provides a mock function with given fields: repoPath
+func (_m *MockGit) GetBranchBases(repoPath string) (map[string]string, error) {
+	ret := _m.Called(repoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBranchBases")
+	}
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (map[string]string, error)); ok {
+		return rf(repoPath)
+	}
+	if rf, ok := ret.Get(0).(func(string) map[string]string); ok {
+		r0 = rf(repoPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(repoPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetBranchBases_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBranchBases'
+type MockGit_GetBranchBases_Call struct {
+	*mock.Call
+}
+
+// GetBranchBases is a helper method to define mock.On call
+//   - repoPath string
+func (_e *MockGit_Expecter) GetBranchBases(repoPath interface{}) *MockGit_GetBranchBases_Call {
+	return &MockGit_GetBranchBases_Call{Call: _e.mock.On("GetBranchBases", repoPath)}
+}
+
+func (_c *MockGit_GetBranchBases_Call) Run(run func(repoPath string)) *MockGit_GetBranchBases_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetBranchBases_Call) Return(_a0 map[string]string, _a1 error) *MockGit_GetBranchBases_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetBranchBases_Call) RunAndReturn(run func(string) (map[string]string, error)) *MockGit_GetBranchBases_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBranchParent provides a mock function with given fields: repoPath, branch
+func (_m *MockGit) GetBranchParent(repoPath string, branch string) (string, error) {
+	ret := _m.Called(repoPath, branch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBranchParent")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
+		return rf(repoPath, branch)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(repoPath, branch)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(repoPath, branch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetBranchParent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBranchParent'
+type MockGit_GetBranchParent_Call struct {
+	*mock.Call
+}
+
+// GetBranchParent is a helper method to define mock.On call
+//   - repoPath string
+//   - branch string
+func (_e *MockGit_Expecter) GetBranchParent(repoPath interface{}, branch interface{}) *MockGit_GetBranchParent_Call {
+	return &MockGit_GetBranchParent_Call{Call: _e.mock.On("GetBranchParent", repoPath, branch)}
+}
+
+func (_c *MockGit_GetBranchParent_Call) Run(run func(repoPath string, branch string)) *MockGit_GetBranchParent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetBranchParent_Call) Return(_a0 string, _a1 error) *MockGit_GetBranchParent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetBranchParent_Call) RunAndReturn(run func(string, string) (string, error)) *MockGit_GetBranchParent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetBranchParents provides a mock function with given fields: repoPath
+func (_m *MockGit) GetBranchParents(repoPath string) (map[string]string, error) {
+	ret := _m.Called(repoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBranchParents")
+	}
+
+	var r0 map[string]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (map[string]string, error)); ok {
+		return rf(repoPath)
+	}
+	if rf, ok := ret.Get(0).(func(string) map[string]string); ok {
+		r0 = rf(repoPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(repoPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetBranchParents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBranchParents'
+type MockGit_GetBranchParents_Call struct {
+	*mock.Call
+}
+
+// GetBranchParents is a helper method to define mock.On call
+//   - repoPath string
+func (_e *MockGit_Expecter) GetBranchParents(repoPath interface{}) *MockGit_GetBranchParents_Call {
+	return &MockGit_GetBranchParents_Call{Call: _e.mock.On("GetBranchParents", repoPath)}
+}
+
+func (_c *MockGit_GetBranchParents_Call) Run(run func(repoPath string)) *MockGit_GetBranchParents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetBranchParents_Call) Return(_a0 map[string]string, _a1 error) *MockGit_GetBranchParents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetBranchParents_Call) RunAndReturn(run func(string) (map[string]string, error)) *MockGit_GetBranchParents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCurrentBranch provides a mock function with given fields: dir
+func (_m *MockGit) GetCurrentBranch(dir string) (string, error) {
+	ret := _m.Called(dir)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCurrentBranch")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(dir)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(dir)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(dir)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetCurrentBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCurrentBranch'
+type MockGit_GetCurrentBranch_Call struct {
+	*mock.Call
+}
+
+// GetCurrentBranch is a helper method to define mock.On call
+//   - dir string
+func (_e *MockGit_Expecter) GetCurrentBranch(dir interface{}) *MockGit_GetCurrentBranch_Call {
+	return &MockGit_GetCurrentBranch_Call{Call: _e.mock.On("GetCurrentBranch", dir)}
+}
+
+func (_c *MockGit_GetCurrentBranch_Call) Run(run func(dir string)) *MockGit_GetCurrentBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetCurrentBranch_Call) Return(_a0 string, _a1 error) *MockGit_GetCurrentBranch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetCurrentBranch_Call) RunAndReturn(run func(string) (string, error)) *MockGit_GetCurrentBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetDefaultBranch provides a mock function with given fields: bareRepoPath
+func (_m *MockGit) GetDefaultBranch(bareRepoPath string) (string, error) {
+	ret := _m.Called(bareRepoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDefaultBranch")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(bareRepoPath)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(bareRepoPath)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bareRepoPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetDefaultBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetDefaultBranch'
+type MockGit_GetDefaultBranch_Call struct {
+	*mock.Call
+}
+
+// GetDefaultBranch is a helper method to define mock.On call
+//   - bareRepoPath string
+func (_e *MockGit_Expecter) GetDefaultBranch(bareRepoPath interface{}) *MockGit_GetDefaultBranch_Call {
+	return &MockGit_GetDefaultBranch_Call{Call: _e.mock.On("GetDefaultBranch", bareRepoPath)}
+}
+
+func (_c *MockGit_GetDefaultBranch_Call) Run(run func(bareRepoPath string)) *MockGit_GetDefaultBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetDefaultBranch_Call) Return(_a0 string, _a1 error) *MockGit_GetDefaultBranch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetDefaultBranch_Call) RunAndReturn(run func(string) (string, error)) *MockGit_GetDefaultBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLastFetch provides a mock function with given fields: bareRepoPath
+func (_m *MockGit) GetLastFetch(bareRepoPath string) (time.Time, error) {
+	ret := _m.Called(bareRepoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastFetch")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (time.Time, error)); ok {
+		return rf(bareRepoPath)
+	}
+	if rf, ok := ret.Get(0).(func(string) time.Time); ok {
+		r0 = rf(bareRepoPath)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bareRepoPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetLastFetch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastFetch'
+type MockGit_GetLastFetch_Call struct {
+	*mock.Call
+}
+
+// GetLastFetch is a helper method to define mock.On call
+//   - bareRepoPath string
+func (_e *MockGit_Expecter) GetLastFetch(bareRepoPath interface{}) *MockGit_GetLastFetch_Call {
+	return &MockGit_GetLastFetch_Call{Call: _e.mock.On("GetLastFetch", bareRepoPath)}
+}
+
+func (_c *MockGit_GetLastFetch_Call) Run(run func(bareRepoPath string)) *MockGit_GetLastFetch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetLastFetch_Call) Return(_a0 time.Time, _a1 error) *MockGit_GetLastFetch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetLastFetch_Call) RunAndReturn(run func(string) (time.Time, error)) *MockGit_GetLastFetch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLocalBranches provides a mock function with given fields: bareRepoPath
+func (_m *MockGit) GetLocalBranches(bareRepoPath string) ([]string, error) {
+	ret := _m.Called(bareRepoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLocalBranches")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(bareRepoPath)
+	}
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(bareRepoPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bareRepoPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetLocalBranches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLocalBranches'
+type MockGit_GetLocalBranches_Call struct {
+	*mock.Call
+}
+
+// GetLocalBranches is a helper method to define mock.On call
+//   - bareRepoPath string
+func (_e *MockGit_Expecter) GetLocalBranches(bareRepoPath interface{}) *MockGit_GetLocalBranches_Call {
+	return &MockGit_GetLocalBranches_Call{Call: _e.mock.On("GetLocalBranches", bareRepoPath)}
+}
+
+func (_c *MockGit_GetLocalBranches_Call) Run(run func(bareRepoPath string)) *MockGit_GetLocalBranches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetLocalBranches_Call) Return(_a0 []string, _a1 error) *MockGit_GetLocalBranches_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetLocalBranches_Call) RunAndReturn(run func(string) ([]string, error)) *MockGit_GetLocalBranches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetProjectName provides a mock function with no fields
+func (_m *MockGit) GetProjectName() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProjectName")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetProjectName_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProjectName'
+type MockGit_GetProjectName_Call struct {
+	*mock.Call
+}
+
+// GetProjectName is a helper method to define mock.On call
+func (_e *MockGit_Expecter) GetProjectName() *MockGit_GetProjectName_Call {
+	return &MockGit_GetProjectName_Call{Call: _e.mock.On("GetProjectName")}
+}
+
+func (_c *MockGit_GetProjectName_Call) Run(run func()) *MockGit_GetProjectName_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockGit_GetProjectName_Call) Return(_a0 string, _a1 error) *MockGit_GetProjectName_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetProjectName_Call) RunAndReturn(run func() (string, error)) *MockGit_GetProjectName_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRefSnapshot provides a mock function with given fields: bareRepoPath, base
+func (_m *MockGit) GetRefSnapshot(bareRepoPath string, base string) (*RefSnapshot, error) {
+	ret := _m.Called(bareRepoPath, base)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefSnapshot")
+	}
+
+	var r0 *RefSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*RefSnapshot, error)); ok {
+		return rf(bareRepoPath, base)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *RefSnapshot); ok {
+		r0 = rf(bareRepoPath, base)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*RefSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(bareRepoPath, base)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetRefSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRefSnapshot'
+type MockGit_GetRefSnapshot_Call struct {
+	*mock.Call
+}
+
+// GetRefSnapshot is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - base string
+func (_e *MockGit_Expecter) GetRefSnapshot(bareRepoPath interface{}, base interface{}) *MockGit_GetRefSnapshot_Call {
+	return &MockGit_GetRefSnapshot_Call{Call: _e.mock.On("GetRefSnapshot", bareRepoPath, base)}
+}
+
+func (_c *MockGit_GetRefSnapshot_Call) Run(run func(bareRepoPath string, base string)) *MockGit_GetRefSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetRefSnapshot_Call) Return(_a0 *RefSnapshot, _a1 error) *MockGit_GetRefSnapshot_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetRefSnapshot_Call) RunAndReturn(run func(string, string) (*RefSnapshot, error)) *MockGit_GetRefSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRemoteBranches provides a mock function with given fields: bareRepoPath
+func (_m *MockGit) GetRemoteBranches(bareRepoPath string) ([]string, error) {
+	ret := _m.Called(bareRepoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRemoteBranches")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(bareRepoPath)
+	}
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(bareRepoPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bareRepoPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetRemoteBranches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRemoteBranches'
+type MockGit_GetRemoteBranches_Call struct {
+	*mock.Call
+}
+
+// GetRemoteBranches is a helper method to define mock.On call
+//   - bareRepoPath string
+func (_e *MockGit_Expecter) GetRemoteBranches(bareRepoPath interface{}) *MockGit_GetRemoteBranches_Call {
+	return &MockGit_GetRemoteBranches_Call{Call: _e.mock.On("GetRemoteBranches", bareRepoPath)}
+}
+
+func (_c *MockGit_GetRemoteBranches_Call) Run(run func(bareRepoPath string)) *MockGit_GetRemoteBranches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetRemoteBranches_Call) Return(_a0 []string, _a1 error) *MockGit_GetRemoteBranches_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetRemoteBranches_Call) RunAndReturn(run func(string) ([]string, error)) *MockGit_GetRemoteBranches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRemoteURL provides a mock function with given fields: bareRepoPath
+func (_m *MockGit) GetRemoteURL(bareRepoPath string) (string, error) {
+	ret := _m.Called(bareRepoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRemoteURL")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(bareRepoPath)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(bareRepoPath)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bareRepoPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetRemoteURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRemoteURL'
+type MockGit_GetRemoteURL_Call struct {
+	*mock.Call
+}
+
+// GetRemoteURL is a helper method to define mock.On call
+//   - bareRepoPath string
+func (_e *MockGit_Expecter) GetRemoteURL(bareRepoPath interface{}) *MockGit_GetRemoteURL_Call {
+	return &MockGit_GetRemoteURL_Call{Call: _e.mock.On("GetRemoteURL", bareRepoPath)}
+}
+
+func (_c *MockGit_GetRemoteURL_Call) Run(run func(bareRepoPath string)) *MockGit_GetRemoteURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetRemoteURL_Call) Return(_a0 string, _a1 error) *MockGit_GetRemoteURL_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetRemoteURL_Call) RunAndReturn(run func(string) (string, error)) *MockGit_GetRemoteURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUpstreamBranch provides a mock function with given fields: worktreePath
+func (_m *MockGit) GetUpstreamBranch(worktreePath string) (string, error) {
+	ret := _m.Called(worktreePath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUpstreamBranch")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(worktreePath)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(worktreePath)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(worktreePath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_GetUpstreamBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUpstreamBranch'
+type MockGit_GetUpstreamBranch_Call struct {
+	*mock.Call
+}
+
+// GetUpstreamBranch is a helper method to define mock.On call
+//   - worktreePath string
+func (_e *MockGit_Expecter) GetUpstreamBranch(worktreePath interface{}) *MockGit_GetUpstreamBranch_Call {
+	return &MockGit_GetUpstreamBranch_Call{Call: _e.mock.On("GetUpstreamBranch", worktreePath)}
+}
+
+func (_c *MockGit_GetUpstreamBranch_Call) Run(run func(worktreePath string)) *MockGit_GetUpstreamBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetUpstreamBranch_Call) Return(_a0 string, _a1 error) *MockGit_GetUpstreamBranch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_GetUpstreamBranch_Call) RunAndReturn(run func(string) (string, error)) *MockGit_GetUpstreamBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWorkingTreeStatus provides a mock function with given fields: worktreePath
+func (_m *MockGit) GetWorkingTreeStatus(worktreePath string) (bool, bool, bool, error) {
+	ret := _m.Called(worktreePath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkingTreeStatus")
+	}
+
+	var r0 bool
+	var r1 bool
+	var r2 bool
+	var r3 error
+	if rf, ok := ret.Get(0).(func(string) (bool, bool, bool, error)); ok {
+		return rf(worktreePath)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(worktreePath)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(worktreePath)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(string) bool); ok {
+		r2 = rf(worktreePath)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	if rf, ok := ret.Get(3).(func(string) error); ok {
+		r3 = rf(worktreePath)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// MockGit_GetWorkingTreeStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWorkingTreeStatus'
+type MockGit_GetWorkingTreeStatus_Call struct {
+	*mock.Call
+}
+
+// GetWorkingTreeStatus is a helper method to define mock.On call
+//   - worktreePath string
+func (_e *MockGit_Expecter) GetWorkingTreeStatus(worktreePath interface{}) *MockGit_GetWorkingTreeStatus_Call {
+	return &MockGit_GetWorkingTreeStatus_Call{Call: _e.mock.On("GetWorkingTreeStatus", worktreePath)}
+}
+
+func (_c *MockGit_GetWorkingTreeStatus_Call) Run(run func(worktreePath string)) *MockGit_GetWorkingTreeStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_GetWorkingTreeStatus_Call) Return(staged bool, modified bool, untracked bool, err error) *MockGit_GetWorkingTreeStatus_Call {
+	_c.Call.Return(staged, modified, untracked, err)
+	return _c
+}
+
+func (_c *MockGit_GetWorkingTreeStatus_Call) RunAndReturn(run func(string) (bool, bool, bool, error)) *MockGit_GetWorkingTreeStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsMerged provides a mock function with given fields: worktreePath, branchName, targetRef
+func (_m *MockGit) IsMerged(worktreePath string, branchName string, targetRef string) (bool, error) {
+	ret := _m.Called(worktreePath, branchName, targetRef)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsMerged")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (bool, error)); ok {
+		return rf(worktreePath, branchName, targetRef)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
+		r0 = rf(worktreePath, branchName, targetRef)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(worktreePath, branchName, targetRef)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_IsMerged_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsMerged'
+type MockGit_IsMerged_Call struct {
+	*mock.Call
+}
+
+// IsMerged is a helper method to define mock.On call
+//   - worktreePath string
+//   - branchName string
+//   - targetRef string
+func (_e *MockGit_Expecter) IsMerged(worktreePath interface{}, branchName interface{}, targetRef interface{}) *MockGit_IsMerged_Call {
+	return &MockGit_IsMerged_Call{Call: _e.mock.On("IsMerged", worktreePath, branchName, targetRef)}
+}
+
+func (_c *MockGit_IsMerged_Call) Run(run func(worktreePath string, branchName string, targetRef string)) *MockGit_IsMerged_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_IsMerged_Call) Return(_a0 bool, _a1 error) *MockGit_IsMerged_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_IsMerged_Call) RunAndReturn(run func(string, string, string) (bool, error)) *MockGit_IsMerged_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListStashes provides a mock function with given fields: repoPath
+func (_m *MockGit) ListStashes(repoPath string) ([]Stash, error) {
+	ret := _m.Called(repoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListStashes")
+	}
+
+	var r0 []Stash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]Stash, error)); ok {
+		return rf(repoPath)
+	}
+	if rf, ok := ret.Get(0).(func(string) []Stash); ok {
+		r0 = rf(repoPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Stash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(repoPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_ListStashes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListStashes'
+type MockGit_ListStashes_Call struct {
+	*mock.Call
+}
+
+// ListStashes is a helper method to define mock.On call
+//   - repoPath string
+func (_e *MockGit_Expecter) ListStashes(repoPath interface{}) *MockGit_ListStashes_Call {
+	return &MockGit_ListStashes_Call{Call: _e.mock.On("ListStashes", repoPath)}
+}
+
+func (_c *MockGit_ListStashes_Call) Run(run func(repoPath string)) *MockGit_ListStashes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_ListStashes_Call) Return(_a0 []Stash, _a1 error) *MockGit_ListStashes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_ListStashes_Call) RunAndReturn(run func(string) ([]Stash, error)) *MockGit_ListStashes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListWorktrees provides a mock function with given fields: bareRepoPath
+func (_m *MockGit) ListWorktrees(bareRepoPath string) ([]string, error) {
+	ret := _m.Called(bareRepoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWorktrees")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(bareRepoPath)
+	}
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(bareRepoPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bareRepoPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_ListWorktrees_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListWorktrees'
+type MockGit_ListWorktrees_Call struct {
+	*mock.Call
+}
+
+// ListWorktrees is a helper method to define mock.On call
+//   - bareRepoPath string
+func (_e *MockGit_Expecter) ListWorktrees(bareRepoPath interface{}) *MockGit_ListWorktrees_Call {
+	return &MockGit_ListWorktrees_Call{Call: _e.mock.On("ListWorktrees", bareRepoPath)}
+}
+
+func (_c *MockGit_ListWorktrees_Call) Run(run func(bareRepoPath string)) *MockGit_ListWorktrees_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_ListWorktrees_Call) Return(_a0 []string, _a1 error) *MockGit_ListWorktrees_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_ListWorktrees_Call) RunAndReturn(run func(string) ([]string, error)) *MockGit_ListWorktrees_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Merge provides a mock function with given fields: worktreePath, ref
+func (_m *MockGit) Merge(worktreePath string, ref string) error {
+	ret := _m.Called(worktreePath, ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Merge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(worktreePath, ref)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_Merge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Merge'
+type MockGit_Merge_Call struct {
+	*mock.Call
+}
+
+// Merge is a helper method to define mock.On call
+//   - worktreePath string
+//   - ref string
+func (_e *MockGit_Expecter) Merge(worktreePath interface{}, ref interface{}) *MockGit_Merge_Call {
+	return &MockGit_Merge_Call{Call: _e.mock.On("Merge", worktreePath, ref)}
+}
+
+func (_c *MockGit_Merge_Call) Run(run func(worktreePath string, ref string)) *MockGit_Merge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_Merge_Call) Return(_a0 error) *MockGit_Merge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_Merge_Call) RunAndReturn(run func(string, string) error) *MockGit_Merge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MoveWorktree provides a mock function with given fields: bareRepoPath, oldPath, newPath, forceSubmodules
+func (_m *MockGit) MoveWorktree(bareRepoPath string, oldPath string, newPath string, forceSubmodules bool) error {
+	ret := _m.Called(bareRepoPath, oldPath, newPath, forceSubmodules)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveWorktree")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, bool) error); ok {
+		r0 = rf(bareRepoPath, oldPath, newPath, forceSubmodules)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_MoveWorktree_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MoveWorktree'
+type MockGit_MoveWorktree_Call struct {
+	*mock.Call
+}
+
+// MoveWorktree is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - oldPath string
+//   - newPath string
+//   - forceSubmodules bool
+func (_e *MockGit_Expecter) MoveWorktree(bareRepoPath interface{}, oldPath interface{}, newPath interface{}, forceSubmodules interface{}) *MockGit_MoveWorktree_Call {
+	return &MockGit_MoveWorktree_Call{Call: _e.mock.On("MoveWorktree", bareRepoPath, oldPath, newPath, forceSubmodules)}
+}
+
+func (_c *MockGit_MoveWorktree_Call) Run(run func(bareRepoPath string, oldPath string, newPath string, forceSubmodules bool)) *MockGit_MoveWorktree_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *MockGit_MoveWorktree_Call) Return(_a0 error) *MockGit_MoveWorktree_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_MoveWorktree_Call) RunAndReturn(run func(string, string, string, bool) error) *MockGit_MoveWorktree_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Planning provides a mock function with no fields
+func (_m *MockGit) Planning() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Planning")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockGit_Planning_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Planning'
+type MockGit_Planning_Call struct {
+	*mock.Call
+}
+
+// Planning is a helper method to define mock.On call
+func (_e *MockGit_Expecter) Planning() *MockGit_Planning_Call {
+	return &MockGit_Planning_Call{Call: _e.mock.On("Planning")}
+}
+
+func (_c *MockGit_Planning_Call) Run(run func()) *MockGit_Planning_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockGit_Planning_Call) Return(_a0 bool) *MockGit_Planning_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_Planning_Call) RunAndReturn(run func() bool) *MockGit_Planning_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PopStash provides a mock function with given fields: worktreePath, commit
+func (_m *MockGit) PopStash(worktreePath string, commit string) error {
+	ret := _m.Called(worktreePath, commit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PopStash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(worktreePath, commit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_PopStash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PopStash'
+type MockGit_PopStash_Call struct {
+	*mock.Call
+}
+
+// PopStash is a helper method to define mock.On call
+//   - worktreePath string
+//   - commit string
+func (_e *MockGit_Expecter) PopStash(worktreePath interface{}, commit interface{}) *MockGit_PopStash_Call {
+	return &MockGit_PopStash_Call{Call: _e.mock.On("PopStash", worktreePath, commit)}
+}
+
+func (_c *MockGit_PopStash_Call) Run(run func(worktreePath string, commit string)) *MockGit_PopStash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_PopStash_Call) Return(_a0 error) *MockGit_PopStash_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_PopStash_Call) RunAndReturn(run func(string, string) error) *MockGit_PopStash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PruneWorktrees provides a mock function with given fields: bareRepoPath
+func (_m *MockGit) PruneWorktrees(bareRepoPath string) ([]string, error) {
+	ret := _m.Called(bareRepoPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneWorktrees")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]string, error)); ok {
+		return rf(bareRepoPath)
+	}
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(bareRepoPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bareRepoPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_PruneWorktrees_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PruneWorktrees'
+type MockGit_PruneWorktrees_Call struct {
+	*mock.Call
+}
+
+// PruneWorktrees is a helper method to define mock.On call
+//   - bareRepoPath string
+func (_e *MockGit_Expecter) PruneWorktrees(bareRepoPath interface{}) *MockGit_PruneWorktrees_Call {
+	return &MockGit_PruneWorktrees_Call{Call: _e.mock.On("PruneWorktrees", bareRepoPath)}
+}
+
+func (_c *MockGit_PruneWorktrees_Call) Run(run func(bareRepoPath string)) *MockGit_PruneWorktrees_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_PruneWorktrees_Call) Return(_a0 []string, _a1 error) *MockGit_PruneWorktrees_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_PruneWorktrees_Call) RunAndReturn(run func(string) ([]string, error)) *MockGit_PruneWorktrees_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PushBranch provides a mock function with given fields: worktreePath, branch
+func (_m *MockGit) PushBranch(worktreePath string, branch string) error {
+	ret := _m.Called(worktreePath, branch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PushBranch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(worktreePath, branch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_PushBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PushBranch'
+type MockGit_PushBranch_Call struct {
+	*mock.Call
+}
+
+// PushBranch is a helper method to define mock.On call
+//   - worktreePath string
+//   - branch string
+func (_e *MockGit_Expecter) PushBranch(worktreePath interface{}, branch interface{}) *MockGit_PushBranch_Call {
+	return &MockGit_PushBranch_Call{Call: _e.mock.On("PushBranch", worktreePath, branch)}
+}
+
+func (_c *MockGit_PushBranch_Call) Run(run func(worktreePath string, branch string)) *MockGit_PushBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_PushBranch_Call) Return(_a0 error) *MockGit_PushBranch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_PushBranch_Call) RunAndReturn(run func(string, string) error) *MockGit_PushBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Rebase provides a mock function with given fields: worktreePath, onto
+func (_m *MockGit) Rebase(worktreePath string, onto string) error {
+	ret := _m.Called(worktreePath, onto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rebase")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(worktreePath, onto)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_Rebase_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rebase'
+type MockGit_Rebase_Call struct {
+	*mock.Call
+}
+
+// Rebase is a helper method to define mock.On call
+//   - worktreePath string
+//   - onto string
+func (_e *MockGit_Expecter) Rebase(worktreePath interface{}, onto interface{}) *MockGit_Rebase_Call {
+	return &MockGit_Rebase_Call{Call: _e.mock.On("Rebase", worktreePath, onto)}
+}
+
+func (_c *MockGit_Rebase_Call) Run(run func(worktreePath string, onto string)) *MockGit_Rebase_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_Rebase_Call) Return(_a0 error) *MockGit_Rebase_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_Rebase_Call) RunAndReturn(run func(string, string) error) *MockGit_Rebase_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RebaseInProgress provides a mock function with given fields: worktreePath
+func (_m *MockGit) RebaseInProgress(worktreePath string) (bool, error) {
+	ret := _m.Called(worktreePath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebaseInProgress")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(worktreePath)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(worktreePath)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(worktreePath)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_RebaseInProgress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RebaseInProgress'
+type MockGit_RebaseInProgress_Call struct {
+	*mock.Call
+}
+
+// RebaseInProgress is a helper method to define mock.On call
+//   - worktreePath string
+func (_e *MockGit_Expecter) RebaseInProgress(worktreePath interface{}) *MockGit_RebaseInProgress_Call {
+	return &MockGit_RebaseInProgress_Call{Call: _e.mock.On("RebaseInProgress", worktreePath)}
+}
+
+func (_c *MockGit_RebaseInProgress_Call) Run(run func(worktreePath string)) *MockGit_RebaseInProgress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_RebaseInProgress_Call) Return(_a0 bool, _a1 error) *MockGit_RebaseInProgress_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_RebaseInProgress_Call) RunAndReturn(run func(string) (bool, error)) *MockGit_RebaseInProgress_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RebaseOnto provides a mock function with given fields: worktreePath, onto, upstream
+func (_m *MockGit) RebaseOnto(worktreePath string, onto string, upstream string) error {
+	ret := _m.Called(worktreePath, onto, upstream)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebaseOnto")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(worktreePath, onto, upstream)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_RebaseOnto_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RebaseOnto'
+type MockGit_RebaseOnto_Call struct {
+	*mock.Call
+}
+
+// RebaseOnto is a helper method to define mock.On call
+//   - worktreePath string
+//   - onto string
+//   - upstream string
+func (_e *MockGit_Expecter) RebaseOnto(worktreePath interface{}, onto interface{}, upstream interface{}) *MockGit_RebaseOnto_Call {
+	return &MockGit_RebaseOnto_Call{Call: _e.mock.On("RebaseOnto", worktreePath, onto, upstream)}
+}
+
+func (_c *MockGit_RebaseOnto_Call) Run(run func(worktreePath string, onto string, upstream string)) *MockGit_RebaseOnto_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_RebaseOnto_Call) Return(_a0 error) *MockGit_RebaseOnto_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_RebaseOnto_Call) RunAndReturn(run func(string, string, string) error) *MockGit_RebaseOnto_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RemoveWorktree provides a mock function with given fields: bareRepoPath, worktreePath, force
+func (_m *MockGit) RemoveWorktree(bareRepoPath string, worktreePath string, force bool) error {
+	ret := _m.Called(bareRepoPath, worktreePath, force)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveWorktree")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, bool) error); ok {
+		r0 = rf(bareRepoPath, worktreePath, force)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_RemoveWorktree_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RemoveWorktree'
+type MockGit_RemoveWorktree_Call struct {
+	*mock.Call
+}
+
+// RemoveWorktree is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - worktreePath string
+//   - force bool
+func (_e *MockGit_Expecter) RemoveWorktree(bareRepoPath interface{}, worktreePath interface{}, force interface{}) *MockGit_RemoveWorktree_Call {
+	return &MockGit_RemoveWorktree_Call{Call: _e.mock.On("RemoveWorktree", bareRepoPath, worktreePath, force)}
+}
+
+func (_c *MockGit_RemoveWorktree_Call) Run(run func(bareRepoPath string, worktreePath string, force bool)) *MockGit_RemoveWorktree_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(bool))
+	})
+	return _c
+}
+
+func (_c *MockGit_RemoveWorktree_Call) Return(_a0 error) *MockGit_RemoveWorktree_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_RemoveWorktree_Call) RunAndReturn(run func(string, string, bool) error) *MockGit_RemoveWorktree_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RenameBranch provides a mock function with given fields: bareRepoPath, oldName, newName
+func (_m *MockGit) RenameBranch(bareRepoPath string, oldName string, newName string) error {
+	ret := _m.Called(bareRepoPath, oldName, newName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameBranch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(bareRepoPath, oldName, newName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_RenameBranch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenameBranch'
+type MockGit_RenameBranch_Call struct {
+	*mock.Call
+}
+
+// RenameBranch is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - oldName string
+//   - newName string
+func (_e *MockGit_Expecter) RenameBranch(bareRepoPath interface{}, oldName interface{}, newName interface{}) *MockGit_RenameBranch_Call {
+	return &MockGit_RenameBranch_Call{Call: _e.mock.On("RenameBranch", bareRepoPath, oldName, newName)}
+}
+
+func (_c *MockGit_RenameBranch_Call) Run(run func(bareRepoPath string, oldName string, newName string)) *MockGit_RenameBranch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_RenameBranch_Call) Return(_a0 error) *MockGit_RenameBranch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_RenameBranch_Call) RunAndReturn(run func(string, string, string) error) *MockGit_RenameBranch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResolveCommit provides a mock function with given fields: bareRepoPath, ref
+func (_m *MockGit) ResolveCommit(bareRepoPath string, ref string) (string, error) {
+	ret := _m.Called(bareRepoPath, ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveCommit")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
+		return rf(bareRepoPath, ref)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(bareRepoPath, ref)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(bareRepoPath, ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_ResolveCommit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveCommit'
+type MockGit_ResolveCommit_Call struct {
+	*mock.Call
+}
+
+// ResolveCommit is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - ref string
+func (_e *MockGit_Expecter) ResolveCommit(bareRepoPath interface{}, ref interface{}) *MockGit_ResolveCommit_Call {
+	return &MockGit_ResolveCommit_Call{Call: _e.mock.On("ResolveCommit", bareRepoPath, ref)}
+}
+
+func (_c *MockGit_ResolveCommit_Call) Run(run func(bareRepoPath string, ref string)) *MockGit_ResolveCommit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_ResolveCommit_Call) Return(_a0 string, _a1 error) *MockGit_ResolveCommit_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_ResolveCommit_Call) RunAndReturn(run func(string, string) (string, error)) *MockGit_ResolveCommit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetBranchBase provides a mock function with given fields: repoPath, branch, base
+func (_m *MockGit) SetBranchBase(repoPath string, branch string, base string) error {
+	ret := _m.Called(repoPath, branch, base)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBranchBase")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(repoPath, branch, base)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_SetBranchBase_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetBranchBase'
+type MockGit_SetBranchBase_Call struct {
+	*mock.Call
+}
+
+// SetBranchBase is a helper method to define mock.On call
+//   - repoPath string
+//   - branch string
+//   - base string
+func (_e *MockGit_Expecter) SetBranchBase(repoPath interface{}, branch interface{}, base interface{}) *MockGit_SetBranchBase_Call {
+	return &MockGit_SetBranchBase_Call{Call: _e.mock.On("SetBranchBase", repoPath, branch, base)}
+}
+
+func (_c *MockGit_SetBranchBase_Call) Run(run func(repoPath string, branch string, base string)) *MockGit_SetBranchBase_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_SetBranchBase_Call) Return(_a0 error) *MockGit_SetBranchBase_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_SetBranchBase_Call) RunAndReturn(run func(string, string, string) error) *MockGit_SetBranchBase_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetBranchParent provides a mock function with given fields: repoPath, branch, parent
+func (_m *MockGit) SetBranchParent(repoPath string, branch string, parent string) error {
+	ret := _m.Called(repoPath, branch, parent)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBranchParent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(repoPath, branch, parent)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_SetBranchParent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetBranchParent'
+type MockGit_SetBranchParent_Call struct {
+	*mock.Call
+}
+
+// SetBranchParent is a helper method to define mock.On call
+//   - repoPath string
+//   - branch string
+//   - parent string
+func (_e *MockGit_Expecter) SetBranchParent(repoPath interface{}, branch interface{}, parent interface{}) *MockGit_SetBranchParent_Call {
+	return &MockGit_SetBranchParent_Call{Call: _e.mock.On("SetBranchParent", repoPath, branch, parent)}
+}
+
+func (_c *MockGit_SetBranchParent_Call) Run(run func(repoPath string, branch string, parent string)) *MockGit_SetBranchParent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_SetBranchParent_Call) Return(_a0 error) *MockGit_SetBranchParent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_SetBranchParent_Call) RunAndReturn(run func(string, string, string) error) *MockGit_SetBranchParent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetLastFetch provides a mock function with given fields: bareRepoPath, at
+func (_m *MockGit) SetLastFetch(bareRepoPath string, at time.Time) error {
+	ret := _m.Called(bareRepoPath, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLastFetch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(bareRepoPath, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_SetLastFetch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetLastFetch'
+type MockGit_SetLastFetch_Call struct {
+	*mock.Call
+}
+
+// SetLastFetch is a helper method to define mock.On call
+//   - bareRepoPath string
+//   - at time.Time
+func (_e *MockGit_Expecter) SetLastFetch(bareRepoPath interface{}, at interface{}) *MockGit_SetLastFetch_Call {
+	return &MockGit_SetLastFetch_Call{Call: _e.mock.On("SetLastFetch", bareRepoPath, at)}
+}
+
+func (_c *MockGit_SetLastFetch_Call) Run(run func(bareRepoPath string, at time.Time)) *MockGit_SetLastFetch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockGit_SetLastFetch_Call) Return(_a0 error) *MockGit_SetLastFetch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_SetLastFetch_Call) RunAndReturn(run func(string, time.Time) error) *MockGit_SetLastFetch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUpstream provides a mock function with given fields: worktreePath, branchName
+func (_m *MockGit) SetUpstream(worktreePath string, branchName string) error {
+	ret := _m.Called(worktreePath, branchName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUpstream")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(worktreePath, branchName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_SetUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUpstream'
+type MockGit_SetUpstream_Call struct {
+	*mock.Call
+}
+
+// SetUpstream is a helper method to define mock.On call
+//   - worktreePath string
+//   - branchName string
+func (_e *MockGit_Expecter) SetUpstream(worktreePath interface{}, branchName interface{}) *MockGit_SetUpstream_Call {
+	return &MockGit_SetUpstream_Call{Call: _e.mock.On("SetUpstream", worktreePath, branchName)}
+}
+
+func (_c *MockGit_SetUpstream_Call) Run(run func(worktreePath string, branchName string)) *MockGit_SetUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_SetUpstream_Call) Return(_a0 error) *MockGit_SetUpstream_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_SetUpstream_Call) RunAndReturn(run func(string, string) error) *MockGit_SetUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// StashPush provides a mock function with given fields: worktreePath, message
+func (_m *MockGit) StashPush(worktreePath string, message string) (string, error) {
+	ret := _m.Called(worktreePath, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StashPush")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
+		return rf(worktreePath, message)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) string); ok {
+		r0 = rf(worktreePath, message)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(worktreePath, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGit_StashPush_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StashPush'
+type MockGit_StashPush_Call struct {
+	*mock.Call
+}
+
+// StashPush is a helper method to define mock.On call
+//   - worktreePath string
+//   - message string
+func (_e *MockGit_Expecter) StashPush(worktreePath interface{}, message interface{}) *MockGit_StashPush_Call {
+	return &MockGit_StashPush_Call{Call: _e.mock.On("StashPush", worktreePath, message)}
+}
+
+func (_c *MockGit_StashPush_Call) Run(run func(worktreePath string, message string)) *MockGit_StashPush_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_StashPush_Call) Return(_a0 string, _a1 error) *MockGit_StashPush_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGit_StashPush_Call) RunAndReturn(run func(string, string) (string, error)) *MockGit_StashPush_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnsetUpstream provides a mock function with given fields: worktreePath, branchName
+func (_m *MockGit) UnsetUpstream(worktreePath string, branchName string) error {
+	ret := _m.Called(worktreePath, branchName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnsetUpstream")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(worktreePath, branchName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockGit_UnsetUpstream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnsetUpstream'
+type MockGit_UnsetUpstream_Call struct {
+	*mock.Call
+}
+
+// UnsetUpstream is a helper method to define mock.On call
+//   - worktreePath string
+//   - branchName string
+func (_e *MockGit_Expecter) UnsetUpstream(worktreePath interface{}, branchName interface{}) *MockGit_UnsetUpstream_Call {
+	return &MockGit_UnsetUpstream_Call{Call: _e.mock.On("UnsetUpstream", worktreePath, branchName)}
+}
+
+func (_c *MockGit_UnsetUpstream_Call) Run(run func(worktreePath string, branchName string)) *MockGit_UnsetUpstream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockGit_UnsetUpstream_Call) Return(_a0 error) *MockGit_UnsetUpstream_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockGit_UnsetUpstream_Call) RunAndReturn(run func(string, string) error) *MockGit_UnsetUpstream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockGit creates a new instance of MockGit. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGit(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGit {
+	mock := &MockGit{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,183 @@
+package git
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Ref is one branch in a RefSnapshot.
+type Ref struct {
+	Name       string // short name, e.g. feature/x; remote branches drop the origin/ prefix
+	Commit     string
+	CommitDate time.Time
+
+	// Local branches only
+	Upstream       string // short name of the upstream, e.g. origin/feature/x, "" when none is set
+	UpstreamGone   bool   // an upstream is set but the remote branch no longer exists
+	AheadUpstream  int
+	BehindUpstream int
+
+	// Against RefSnapshot.Base, when RefSnapshot.BaseCounted
+	AheadBase  int
+	BehindBase int
+}
+
+// RefSnapshot is every local and remote branch, read with a single
+// for-each-ref, so a command can answer all its branch questions without
+// running git again.
+type RefSnapshot struct {
+	Base        string // ref AheadBase/BehindBase count against, "" for none
+	BaseCounted bool   // false when there is no Base or git is older than 2.41
+	Local       map[string]Ref
+	Remote      map[string]Ref
+}
+
+// HasLocal reports whether a local branch exists.
+func (s *RefSnapshot) HasLocal(name string) bool {
+	_, ok := s.Local[name]
+	return ok
+}
+
+// HasRemote reports whether a remote-tracking branch exists.
+func (s *RefSnapshot) HasRemote(name string) bool {
+	_, ok := s.Remote[name]
+	return ok
+}
+
+// LocalRef returns a local branch. A nil snapshot has no branches.
+func (s *RefSnapshot) LocalRef(name string) (Ref, bool) {
+	if s == nil {
+		return Ref{}, false
+	}
+	ref, ok := s.Local[name]
+	return ref, ok
+}
+
+// OnRemote reports whether a local branch still has a counterpart on the
+// remote: its upstream when one is set and not gone, otherwise a remote
+// branch with the same name.
+func (s *RefSnapshot) OnRemote(name string) bool {
+	if ref, ok := s.Local[name]; ok && ref.Upstream != "" {
+		return !ref.UpstreamGone
+	}
+	return s.HasRemote(name)
+}
+
+// LocalBranches returns the local branch names, sorted.
+func (s *RefSnapshot) LocalBranches() []string {
+	return sortedNames(s.Local)
+}
+
+// RemoteBranches returns the remote branch names, sorted.
+func (s *RefSnapshot) RemoteBranches() []string {
+	return sortedNames(s.Remote)
+}
+
+func sortedNames(refs map[string]Ref) []string {
+	names := make([]string, 0, len(refs))
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// refFields are read for every ref, NUL separated.
+var refFields = []string{
+	"%(refname)",
+	"%(objectname)",
+	"%(committerdate:unix)",
+	"%(upstream:short)",
+	"%(upstream:track,nobracket)",
+}
+
+// GetRefSnapshot reads every local and remote branch with one for-each-ref.
+// With a base ref, ahead/behind counts against it are included through
+// %(ahead-behind:), on git older than 2.41 the snapshot is taken without
+// them and BaseCounted stays false.
+func (g *RealGit) GetRefSnapshot(bareRepoPath, base string) (*RefSnapshot, error) {
+	if base != "" {
+		output, err := forEachRef(bareRepoPath, slices.Concat(refFields, []string{"%(ahead-behind:" + base + ")"}))
+		if err == nil {
+			return parseRefSnapshot(output, base, true)
+		}
+		log.Debug("for-each-ref with ahead-behind failed, taking the snapshot without it", "base", base, "error", err)
+	}
+
+	output, err := forEachRef(bareRepoPath, refFields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refs: %w", err)
+	}
+	return parseRefSnapshot(output, base, false)
+}
+
+func forEachRef(bareRepoPath string, fields []string) (string, error) {
+	return runCommandOutput("git", "-C", bareRepoPath, "for-each-ref",
+		"--format="+strings.Join(fields, "%00"), "refs/heads", "refs/remotes")
+}
+
+func parseRefSnapshot(output, base string, baseCounted bool) (*RefSnapshot, error) {
+	snapshot := &RefSnapshot{
+		Base:        base,
+		BaseCounted: baseCounted,
+		Local:       map[string]Ref{},
+		Remote:      map[string]Ref{},
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\x00")
+		if len(fields) < len(refFields) {
+			return nil, fmt.Errorf("unexpected for-each-ref output: %q", line)
+		}
+
+		ref := Ref{Commit: fields[1], Upstream: fields[3]}
+		if seconds, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			ref.CommitDate = time.Unix(seconds, 0)
+		}
+		ref.UpstreamGone, ref.AheadUpstream, ref.BehindUpstream = parseTrack(fields[4])
+		if baseCounted && len(fields) > len(refFields) {
+			counts := strings.Fields(fields[len(refFields)])
+			if len(counts) == 2 {
+				ref.AheadBase, _ = strconv.Atoi(counts[0])
+				ref.BehindBase, _ = strconv.Atoi(counts[1])
+			}
+		}
+
+		if name, ok := strings.CutPrefix(fields[0], "refs/heads/"); ok {
+			ref.Name = name
+			snapshot.Local[name] = ref
+		} else if name, ok := strings.CutPrefix(fields[0], "refs/remotes/"); ok {
+			if strings.HasSuffix(name, "/HEAD") {
+				continue
+			}
+			ref.Name = strings.TrimPrefix(name, "origin/")
+			snapshot.Remote[ref.Name] = ref
+		}
+	}
+	return snapshot, nil
+}
+
+// parseTrack parses %(upstream:track,nobracket): "gone", "ahead 1",
+// "behind 2", "ahead 1, behind 2" or "" when in sync or without upstream.
+func parseTrack(track string) (gone bool, ahead, behind int) {
+	if track == "gone" {
+		return true, 0, 0
+	}
+	for _, part := range strings.Split(track, ", ") {
+		if count, ok := strings.CutPrefix(part, "ahead "); ok {
+			ahead, _ = strconv.Atoi(count)
+		} else if count, ok := strings.CutPrefix(part, "behind "); ok {
+			behind, _ = strconv.Atoi(count)
+		}
+	}
+	return false, ahead, behind
+}
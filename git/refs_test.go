@@ -0,0 +1,110 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/testfixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrack(t *testing.T) {
+	tests := []struct {
+		track  string
+		gone   bool
+		ahead  int
+		behind int
+	}{
+		{track: ""},
+		{track: "gone", gone: true},
+		{track: "ahead 2", ahead: 2},
+		{track: "behind 3", behind: 3},
+		{track: "ahead 2, behind 3", ahead: 2, behind: 3},
+	}
+	for _, tt := range tests {
+		gone, ahead, behind := parseTrack(tt.track)
+		assert.Equal(t, tt.gone, gone, tt.track)
+		assert.Equal(t, tt.ahead, ahead, tt.track)
+		assert.Equal(t, tt.behind, behind, tt.track)
+	}
+}
+
+func TestParseRefSnapshot(t *testing.T) {
+	output := "refs/heads/feature/x\x00a1c4d34\x001700000000\x00origin/feature/x\x00ahead 1\x002 3\n" +
+		"refs/heads/main\x00b2d5e45\x001700000100\x00origin/main\x00\x000 0\n" +
+		"refs/remotes/origin/HEAD\x00b2d5e45\x001700000100\x00\x00\x000 0\n" +
+		"refs/remotes/origin/main\x00b2d5e45\x001700000100\x00\x00\x000 0\n"
+
+	refs, err := parseRefSnapshot(output, "main", true)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"feature/x", "main"}, refs.LocalBranches())
+	assert.Equal(t, []string{"main"}, refs.RemoteBranches())
+
+	feature, ok := refs.LocalRef("feature/x")
+	require.True(t, ok)
+	assert.Equal(t, "origin/feature/x", feature.Upstream)
+	assert.Equal(t, 1, feature.AheadUpstream)
+	assert.Equal(t, 2, feature.AheadBase)
+	assert.Equal(t, 3, feature.BehindBase)
+	assert.Equal(t, int64(1700000000), feature.CommitDate.Unix())
+
+	// upstream set and not gone, even though fetch hasn't brought it in yet
+	assert.True(t, refs.OnRemote("feature/x"))
+	assert.False(t, refs.HasRemote("feature/x"))
+}
+
+func TestGetRefSnapshot(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := testfixture.NewEnv(t)
+	remote := testfixture.NewRemote(t, "widget").
+		Branch("feature", testfixture.DefaultBranch).
+		Branch("shipped", testfixture.DefaultBranch).
+		Commit(testfixture.DefaultBranch, "move main ahead")
+	bareRepoPath := env.Clone(remote, "widget")
+	worktrees := filepath.Join(env.Home, "widget_work")
+
+	g := NewGit()
+	for _, branch := range []string{"feature", "shipped"} {
+		path := filepath.Join(worktrees, branch)
+		testfixture.Git(t, bareRepoPath, "worktree", "add", "-q", path, branch)
+		require.NoError(t, g.SetUpstream(path, branch))
+	}
+	testfixture.Git(t, filepath.Join(worktrees, "feature"), "commit", "-q", "--allow-empty", "-m", "local only")
+	testfixture.Git(t, bareRepoPath, "branch", "local-only", "main")
+
+	remote.DeleteBranch("shipped")
+	testfixture.Git(t, bareRepoPath, "fetch", "-q", "--prune", "origin")
+
+	refs, err := g.GetRefSnapshot(bareRepoPath, "main")
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"feature", "shipped", "local-only", "main"}, refs.LocalBranches())
+	assert.ElementsMatch(t, []string{"feature", "main"}, refs.RemoteBranches())
+
+	feature, _ := refs.LocalRef("feature")
+	assert.Equal(t, "origin/feature", feature.Upstream)
+	assert.Equal(t, 1, feature.AheadUpstream)
+	assert.False(t, feature.CommitDate.IsZero())
+	assert.True(t, refs.OnRemote("feature"))
+
+	shipped, _ := refs.LocalRef("shipped")
+	assert.True(t, shipped.UpstreamGone)
+	assert.False(t, refs.OnRemote("shipped"))
+	assert.False(t, refs.OnRemote("local-only"))
+
+	// %(ahead-behind:) needs git 2.41, older versions leave the counts out
+	if refs.BaseCounted {
+		assert.Equal(t, 2, feature.AheadBase)
+		assert.Equal(t, 1, feature.BehindBase)
+	}
+	ahead, behind, err := g.GetAheadBehind(filepath.Join(worktrees, "feature"), "main")
+	require.NoError(t, err)
+	assert.Equal(t, 2, ahead)
+	assert.Equal(t, 1, behind)
+}
@@ -0,0 +1,235 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Runner executes the commands behind this package. Run is used for
+// commands that change something (add, remove, fetch, config...), Combined
+// for changes whose output is needed to report failures and Output for
+// read-only queries. Commands are stopped when ctx is done.
+type Runner interface {
+	Run(ctx context.Context, cmd string, args ...string) error
+	Combined(ctx context.Context, cmd string, args ...string) (string, error)
+	Output(ctx context.Context, cmd string, args ...string) (string, error)
+}
+
+// Planner is implemented by runners that only record what would happen.
+// Changes this package makes without a git command, like moving a worktree
+// by hand, are reported to it instead of being applied.
+type Planner interface {
+	PlanCommand(cmd string, args ...string)
+}
+
+// DefaultTimeouts limit the git commands that talk to the remote, so a
+// dead network fails instead of hanging. Keys are git subcommands, "default"
+// applies to every other command; zero means no limit.
+var DefaultTimeouts = map[string]time.Duration{
+	"fetch":     2 * time.Minute,
+	"ls-remote": time.Minute,
+}
+
+var (
+	runner Runner = NewExecRunner()
+
+	// mu guards runner, baseCtx and timeouts
+	mu       sync.RWMutex
+	baseCtx  = context.Background()
+	timeouts = DefaultTimeouts
+
+	// running tracks commands started by the exec runner, see Wait
+	running sync.WaitGroup
+)
+
+// SetRunner replaces the runner used for every git command, e.g. with a
+// recorder for --dry-run.
+func SetRunner(r Runner) {
+	mu.Lock()
+	defer mu.Unlock()
+	runner = r
+}
+
+// GetRunner returns the runner currently used for git commands.
+func GetRunner() Runner {
+	mu.RLock()
+	defer mu.RUnlock()
+	return runner
+}
+
+// SetContext sets the context every git command runs under. Cancelling it,
+// on Ctrl-C or when the TUI quits, kills the running commands.
+func SetContext(ctx context.Context) {
+	mu.Lock()
+	defer mu.Unlock()
+	baseCtx = ctx
+}
+
+// IgnoreCancel keeps git commands running after the context was cancelled,
+// so cleanup like rolling back an interrupted add can still run. Timeouts
+// still apply.
+func IgnoreCancel() {
+	mu.Lock()
+	defer mu.Unlock()
+	baseCtx = context.WithoutCancel(baseCtx)
+}
+
+// SetTimeouts sets the per-subcommand timeouts, on top of DefaultTimeouts.
+func SetTimeouts(t map[string]time.Duration) {
+	merged := map[string]time.Duration{}
+	for op, d := range DefaultTimeouts {
+		merged[op] = d
+	}
+	for op, d := range t {
+		merged[op] = d
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	timeouts = merged
+}
+
+// Wait blocks until every running git command has exited. After cancelling
+// the context this makes sure their process groups are gone before
+// treekanga exits.
+func Wait() {
+	running.Wait()
+}
+
+// subcommand returns the git subcommand in args, skipping -C/-c options
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-C" || args[i] == "-c":
+			i++
+		case strings.HasPrefix(args[i], "-"):
+		default:
+			return args[i]
+		}
+	}
+	return ""
+}
+
+// timeoutFor returns the timeouts key that applies to a subcommand and its
+// timeout
+func timeoutFor(op string) (string, time.Duration) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if timeout, ok := timeouts[op]; ok {
+		return op, timeout
+	}
+	return "default", timeouts["default"]
+}
+
+// operationContext returns the context for one git command: the base
+// context, limited by the timeout configured for the subcommand.
+func operationContext(args []string) (context.Context, context.CancelFunc) {
+	mu.RLock()
+	ctx := baseCtx
+	mu.RUnlock()
+
+	_, timeout := timeoutFor(subcommand(args))
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// contextError explains why a command failed if it was because ctx ended
+func contextError(ctx context.Context, args []string, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	op := subcommand(args)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		key, timeout := timeoutFor(op)
+		return fmt.Errorf("git %s timed out after %s, raise gitTimeouts.%s if it needs longer: %w", op, timeout, key, ctx.Err())
+	}
+	return fmt.Errorf("git %s was interrupted: %w", op, ctx.Err())
+}
+
+// newCommand builds a command that runs in its own process group, without
+// prompting for credentials, and kills the whole group when ctx is done.
+func newCommand(ctx context.Context, cmd string, args ...string) *exec.Cmd {
+	command := exec.CommandContext(ctx, cmd, args...)
+	command.Stdin = nil
+
+	// Set environment to prevent git from using pagers, editors or
+	// credential prompts, which would hang without a terminal
+	command.Env = append(os.Environ(),
+		"GIT_PAGER=cat",
+		"GIT_EDITOR=true",
+		"EDITOR=true",
+		"VISUAL=true",
+		"GIT_TERMINAL_PROMPT=0",
+	)
+
+	// Create new process group, so Ctrl-C in the terminal doesn't reach
+	// git directly and cancelling can take down everything git started
+	command.SysProcAttr = setSysProcAttr()
+	command.Cancel = func() error {
+		return killProcessGroup(command)
+	}
+	// Don't wait forever on pipes held open by grandchildren
+	command.WaitDelay = 5 * time.Second
+
+	return command
+}
+
+type execRunner struct{}
+
+// NewExecRunner returns the Runner that actually executes commands.
+func NewExecRunner() Runner {
+	return execRunner{}
+}
+
+func (execRunner) Run(ctx context.Context, cmd string, args ...string) error {
+	_, err := execRunner{}.Combined(ctx, cmd, args...)
+	return err
+}
+
+func (execRunner) Combined(ctx context.Context, cmd string, args ...string) (string, error) {
+	log.Debug(cmd, "args", args)
+	running.Add(1)
+	defer running.Done()
+
+	command := newCommand(ctx, cmd, args...)
+	output, err := command.CombinedOutput()
+
+	// Log output
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			log.Info(line)
+		}
+	}
+
+	return string(output), err
+}
+
+func (execRunner) Output(ctx context.Context, cmd string, args ...string) (string, error) {
+	log.Debug(cmd, "args", args)
+	running.Add(1)
+	defer running.Done()
+
+	command := newCommand(ctx, cmd, args...)
+	output, err := command.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			errString := strings.TrimSpace(string(exitErr.Stderr))
+			if strings.HasPrefix(errString, "no server running on") {
+				return "", nil
+			}
+		}
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(output), "\n"), nil
+}
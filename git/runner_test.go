@@ -0,0 +1,89 @@
+//go:build unix
+
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubcommand(t *testing.T) {
+	assert.Equal(t, "fetch", subcommand([]string{"-C", "/bare", "fetch", "origin", "main"}))
+	assert.Equal(t, "worktree", subcommand([]string{"-c", "core.x=y", "--no-pager", "worktree", "list"}))
+	assert.Equal(t, "", subcommand([]string{"-C", "/bare"}))
+}
+
+func TestRunnerTimeoutKillsProcessGroup(t *testing.T) {
+	t.Cleanup(func() { SetTimeouts(nil) })
+	SetTimeouts(map[string]time.Duration{"default": 200 * time.Millisecond})
+
+	// the grandchild writes a file if it survives the timeout
+	marker := filepath.Join(t.TempDir(), "survived")
+	start := time.Now()
+	err := runCommand("sh", "-c", "(sleep 1; touch "+marker+") & wait")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.ErrorContains(t, err, "timed out after 200ms, raise gitTimeouts.default")
+	assert.Less(t, time.Since(start), time.Second)
+
+	time.Sleep(1500 * time.Millisecond)
+	_, statErr := os.Stat(marker)
+	assert.True(t, os.IsNotExist(statErr), "child process outlived the timeout")
+}
+
+func TestRunnerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() { SetContext(context.Background()) })
+	SetContext(ctx)
+
+	time.AfterFunc(100*time.Millisecond, cancel)
+	_, err := runCommandOutput("sh", "-c", "sleep 5")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	Wait()
+
+	// cleanup after Ctrl-C still runs
+	IgnoreCancel()
+	_, err = runCommandOutput("sh", "-c", "true")
+	assert.NoError(t, err)
+}
+
+func TestSetTimeoutsKeepsDefaults(t *testing.T) {
+	t.Cleanup(func() { SetTimeouts(nil) })
+	SetTimeouts(map[string]time.Duration{"default": time.Minute})
+
+	key, timeout := timeoutFor("fetch")
+	assert.Equal(t, "fetch", key)
+	assert.Equal(t, DefaultTimeouts["fetch"], timeout)
+
+	key, timeout = timeoutFor("worktree")
+	assert.Equal(t, "default", key)
+	assert.Equal(t, time.Minute, timeout)
+}
+
+func TestSetRunnerWhileRunning(t *testing.T) {
+	previous := GetRunner()
+	t.Cleanup(func() { SetRunner(previous) })
+
+	// commands run on other goroutines while the runner is replaced, go test
+	// -race catches unguarded access
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			SetRunner(NewExecRunner())
+		}
+	}()
+	for range 100 {
+		_, err := runCommandOutput("true")
+		require.NoError(t, err)
+	}
+	<-done
+}
@@ -0,0 +1,115 @@
+package git
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stash is one entry of the stash list, which every worktree of a repo
+// shares.
+type Stash struct {
+	Ref     string // stash@{n}, which shifts as stashes are pushed and dropped
+	Commit  string // the stash commit, what PopStash takes
+	Branch  string // branch checked out where it was stashed, "" when detached
+	Message string
+	Created time.Time
+}
+
+// ListStashes returns the repo's stashes, newest first. It reads the
+// refs/stash reflog directly, as git stash list needs a worktree to run in.
+func (g *RealGit) ListStashes(repoPath string) ([]Stash, error) {
+	if _, err := runCommandOutput("git", "-C", repoPath, "rev-parse", "--verify", "--quiet", "refs/stash"); err != nil {
+		// no stashes
+		return nil, nil
+	}
+
+	output, err := runCommandOutput("git", "-C", repoPath, "log", "--walk-reflogs", "--first-parent",
+		"--format=%gd%x00%H%x00%gs%x00%ct", "refs/stash", "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %w", err)
+	}
+
+	var stashes []Stash
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.SplitN(line, "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		stash := parseStashSubject(fields[2])
+		stash.Ref = fields[0]
+		stash.Commit = fields[1]
+		if created, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+			stash.Created = time.Unix(created, 0)
+		}
+		stashes = append(stashes, stash)
+	}
+	return stashes, nil
+}
+
+// parseStashSubject reads the branch out of the subject git gives a stash,
+// "WIP on <branch>: <commit> <subject>" or "On <branch>: <message>", with
+// "(no branch)" for a detached HEAD.
+func parseStashSubject(subject string) Stash {
+	rest, ok := strings.CutPrefix(subject, "WIP on ")
+	if !ok {
+		rest, ok = strings.CutPrefix(subject, "On ")
+	}
+	branch, message, found := strings.Cut(rest, ": ")
+	if !ok || !found {
+		return Stash{Message: subject}
+	}
+	if branch == "(no branch)" {
+		branch = ""
+	}
+	return Stash{Branch: branch, Message: message}
+}
+
+// StashPush stashes a worktree's changes, untracked files included, and
+// returns the commit of the stash, "" when there was nothing to stash.
+func (g *RealGit) StashPush(worktreePath, message string) (string, error) {
+	output, err := runCommandCombined("git", "-C", worktreePath, "stash", "push", "--include-untracked", "-m", message)
+	if err != nil {
+		return "", fmt.Errorf("failed to stash changes in %s: %w", worktreePath, err)
+	}
+	if strings.Contains(output, "No local changes to save") {
+		return "", nil
+	}
+	if g.Planning() {
+		// nothing was stashed, the plan names it by where it would be
+		return "stash@{0}", nil
+	}
+
+	// Taken right away, before another push moves it to stash@{1}
+	commit, err := runCommandOutput("git", "-C", worktreePath, "rev-parse", "--verify", "--quiet", "refs/stash")
+	if err != nil {
+		return "", fmt.Errorf("failed to find the stash made in %s: %w", worktreePath, err)
+	}
+	return strings.TrimSpace(commit), nil
+}
+
+// PopStash applies the stash with commit to a worktree and drops it. The
+// stash is found by its commit rather than stash@{n}, which points at
+// another stash once one is pushed or dropped. When applying conflicts git
+// keeps the stash, so nothing is lost.
+func (g *RealGit) PopStash(worktreePath, commit string) error {
+	if err := runSync(worktreePath, "stash", "apply", commit); err != nil {
+		return err
+	}
+
+	ref := commit
+	if !g.Planning() {
+		stashes, err := g.ListStashes(worktreePath)
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(stashes, func(s Stash) bool { return s.Commit == commit })
+		if i < 0 {
+			return fmt.Errorf("stash %s was applied but is no longer in the stash list", commit)
+		}
+		ref = stashes[i].Ref
+	}
+	return runCommand("git", "-C", worktreePath, "stash", "drop", "--quiet", ref)
+}
@@ -0,0 +1,103 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// Rebase rebases the branch checked out in a worktree onto ref. On a
+// conflict the rebase is left in progress, call AbortRebase to undo it.
+func (g *RealGit) Rebase(worktreePath, onto string) error {
+	return runSync(worktreePath, "rebase", onto)
+}
+
+// RebaseOnto replays the commits of the branch checked out in a worktree
+// that aren't in upstream onto onto, i.e. git rebase --onto onto upstream.
+// Used to move a stacked branch along when its parent was rewritten, with
+// upstream being the ForkPoint it was cut from the parent at.
+func (g *RealGit) RebaseOnto(worktreePath, onto, upstream string) error {
+	return runSync(worktreePath, "rebase", "--onto", onto, upstream)
+}
+
+// ForkPoint returns the commit branch was cut from parent at. It uses the
+// parent's reflog, so it is found even after the parent was rewritten, and
+// falls back to the merge-base when the reflog doesn't reach back far
+// enough.
+func (g *RealGit) ForkPoint(repoPath, parent, branch string) (string, error) {
+	output, err := runCommandOutput("git", "-C", repoPath, "merge-base", "--fork-point", parent, branch)
+	if err == nil {
+		return strings.TrimSpace(output), nil
+	}
+	base, err := mergeBase(repoPath, parent, branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to find where %s was cut from %s: %w", branch, parent, err)
+	}
+	return base, nil
+}
+
+// RebaseInProgress reports whether a worktree is in the middle of a rebase,
+// e.g. one stopped by a conflict.
+func (g *RealGit) RebaseInProgress(worktreePath string) (bool, error) {
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		output, err := runCommandOutput("git", "-C", worktreePath, "rev-parse", "--git-path", dir)
+		if err != nil {
+			return false, fmt.Errorf("failed to check for a rebase in %s: %w", worktreePath, err)
+		}
+		path := strings.TrimSpace(output)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(worktreePath, path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AbortRebase undoes a rebase left in progress by a conflict.
+func (g *RealGit) AbortRebase(worktreePath string) error {
+	return runSync(worktreePath, "rebase", "--abort")
+}
+
+// Merge merges ref into the branch checked out in a worktree, without
+// opening an editor for the message. On a conflict the merge is left in
+// progress, call AbortMerge to undo it.
+func (g *RealGit) Merge(worktreePath, ref string) error {
+	return runSync(worktreePath, "merge", "--no-edit", ref)
+}
+
+// AbortMerge undoes a merge left in progress by a conflict.
+func (g *RealGit) AbortMerge(worktreePath string) error {
+	return runSync(worktreePath, "merge", "--abort")
+}
+
+func runSync(worktreePath string, args ...string) error {
+	args = append([]string{"-C", worktreePath}, args...)
+	fullCommand := strings.Join(append([]string{"git"}, args...), " ")
+	log.Debug("Executing git command", "command", fullCommand)
+
+	output, err := runCommandCombined("git", args...)
+	if err != nil {
+		// Keep git's reason, e.g. "CONFLICT (content): Merge conflict in x.go"
+		if reason := conflictLine(output); reason != "" {
+			return fmt.Errorf("%s failed: %s", args[2], reason)
+		}
+		return fmt.Errorf("%s failed: %w", args[2], err)
+	}
+	return nil
+}
+
+// conflictLine picks the most useful line of a failed rebase or merge: the
+// first CONFLICT line, otherwise git's last line.
+func conflictLine(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "CONFLICT") {
+			return strings.TrimSpace(line)
+		}
+	}
+	return lastLine(output)
+}
@@ -14,7 +14,16 @@ type Session struct {
 	Src  string // The source of the session (tmux, worktree, dir)
 }
 
+// Pane represents a tmux pane
+type Pane struct {
+	ID      string // The pane id, e.g. %3
+	Session string // The name of the session it is in
+	Command string // The program running in it
+	Path    string // Its current directory
+}
+
 // ConnectOpts represents options for connecting to a session
 type ConnectOpts struct {
 	Switch bool // Whether to switch to the session (rather than attach)
+	First  bool // Whether to take the most recent worktree instead of asking when several match
 }
@@ -0,0 +1,9 @@
+package models
+
+// Issue is the subset of an issue tracker ticket used to name branches.
+type Issue struct {
+	Key   string // tracker identifier, e.g. JIRA-123, ENG-42 or 123
+	Title string
+	Type  string // tracker issue type or label, e.g. Bug, Story
+	URL   string
+}
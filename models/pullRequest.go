@@ -0,0 +1,47 @@
+package models
+
+// PullRequestState is the lifecycle state of a worktree's pull request on
+// the forge.
+type PullRequestState string
+
+const (
+	PullRequestStateNone   PullRequestState = ""
+	PullRequestStateOpen   PullRequestState = "open"
+	PullRequestStateDraft  PullRequestState = "draft"
+	PullRequestStateMerged PullRequestState = "merged"
+	PullRequestStateClosed PullRequestState = "closed"
+)
+
+// CIStatus is the aggregate result of the checks run against a pull
+// request's head commit.
+type CIStatus string
+
+const (
+	CIStatusNone    CIStatus = ""
+	CIStatusPending CIStatus = "pending"
+	CIStatusSuccess CIStatus = "success"
+	CIStatusFailure CIStatus = "failure"
+)
+
+// ReviewState is the aggregate review decision on a pull request.
+type ReviewState string
+
+const (
+	ReviewStateNone             ReviewState = ""
+	ReviewStateApproved         ReviewState = "approved"
+	ReviewStateChangesRequested ReviewState = "changes_requested"
+	ReviewStateCommented        ReviewState = "commented"
+)
+
+// PullRequest is the forge-side view of the pull request opened for a
+// worktree's branch.
+type PullRequest struct {
+	Number     int
+	URL        string
+	Title      string
+	BaseBranch string
+	HeadSHA    string
+	State      PullRequestState
+	CI         CIStatus
+	Review     ReviewState
+}
@@ -0,0 +1,13 @@
+package models
+
+// StackNode is a branch in a stack of branches, each cut from its parent and
+// restacked onto it when the parent moves. The top nodes of a stack are the
+// branches the stacks sit on, e.g. the default branch.
+type StackNode struct {
+	Branch   string
+	Worktree *Worktree // nil when the branch isn't checked out in a worktree
+	Ahead    int       // commits on Branch that aren't on its parent
+	Behind   int       // commits on the parent that aren't on Branch yet
+	Counted  bool      // whether Ahead/Behind were computed, never for top nodes
+	Children []*StackNode
+}
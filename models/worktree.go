@@ -18,12 +18,30 @@ type Worktree struct {
 	BranchName string
 	CommitHash string
 
+	// Detached is true for worktrees checked out at a tag or commit rather
+	// than a branch; BranchName is empty for them.
+	Detached bool
+	// DetachedRef is the tag at HEAD of a detached worktree, if any. It is
+	// filled in alongside the status fields.
+	DetachedRef string
+
 	// Working tree state (R1)
 	HasStaged    bool
 	HasModified  bool
 	HasUntracked bool
 
-	// Ahead/behind the default branch (R2)
+	// BaseBranch is the branch the worktree's branch was cut from, as
+	// recorded by add or set-base, or the configured default branch. It is
+	// filled in alongside the status fields.
+	BaseBranch string
+
+	// StackParent is the local branch the worktree's branch is stacked on
+	// with add --stack-on, "" when it isn't stacked. Merge status compares
+	// against it instead of origin/<BaseBranch>, which may not exist yet or
+	// be behind a restack.
+	StackParent string
+
+	// Ahead/behind BaseBranch (R2)
 	AheadDefault int
 	BehindDefault int
 
@@ -31,6 +49,7 @@ type Worktree struct {
 	HasUpstream  bool
 	AheadRemote  int
 	BehindRemote int
+	UpstreamGone bool // the upstream was deleted on the remote
 
 	// Merge status against origin/<default-branch> (R4)
 	Merged MergeStatus
@@ -38,6 +57,19 @@ type Worktree struct {
 	// StatusLoaded is true once the R1-R4 fields above have been computed.
 	// Used by the TUI to distinguish "not yet loaded" from "loaded, all clear".
 	StatusLoaded bool
+
+	// PullRequest is the forge's pull request for BranchName, or nil when
+	// none exists (or no forge is configured).
+	PullRequest *PullRequest
+
+	// PullRequestLoaded is true once the forge has been queried for this
+	// worktree, mirroring StatusLoaded for the PR column.
+	PullRequestLoaded bool
+
+	// Stashes is how many stashes were made on BranchName. The stash list
+	// is shared by all worktrees, so it's matched by the stashed branch.
+	Stashes       int
+	StashesLoaded bool
 }
 
 type CustomThemeData struct {
@@ -0,0 +1,86 @@
+package naming
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// DefaultBranchTemplate is used when a repo doesn't configure branchTemplate.
+const DefaultBranchTemplate = "{{.Type}}/{{.Key}}-{{.Slug}}"
+
+// DefaultSlugLength caps the slug so branch and folder names stay readable.
+const DefaultSlugLength = 40
+
+// BranchNameData is the data available to branch name templates.
+type BranchNameData struct {
+	Type  string // branch type derived from the issue type, e.g. feature, bugfix
+	Key   string // issue key, e.g. JIRA-123
+	Slug  string // slugified issue title
+	Title string // raw issue title
+}
+
+// Slugify lowercases s and collapses every run of characters that aren't
+// letters or digits into a single dash, trimming to at most maxLen
+// characters on a word boundary when possible.
+func Slugify(s string, maxLen int) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range strings.ToLower(s) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			b.WriteRune(r)
+		} else {
+			pendingDash = true
+		}
+	}
+
+	slug := b.String()
+	if maxLen > 0 && len(slug) > maxLen {
+		slug = slug[:maxLen]
+		if cut := strings.LastIndex(slug, "-"); cut > maxLen/2 {
+			slug = slug[:cut]
+		}
+		slug = strings.TrimSuffix(slug, "-")
+	}
+	return slug
+}
+
+// BranchType maps a tracker issue type (Bug, Story, enhancement, ...) to
+// the branch type used in templates. typeMap entries from the config take
+// precedence, matched case-insensitively; otherwise bugs become "bugfix"
+// and everything else "feature".
+func BranchType(issueType string, typeMap map[string]string) string {
+	for from, to := range typeMap {
+		if strings.EqualFold(from, issueType) {
+			return to
+		}
+	}
+
+	switch strings.ToLower(issueType) {
+	case "bug", "defect", "incident":
+		return "bugfix"
+	default:
+		return "feature"
+	}
+}
+
+// BranchNameFromIssue renders tmpl (DefaultBranchTemplate when empty) for
+// an issue.
+func BranchNameFromIssue(tmpl string, issue models.Issue, typeMap map[string]string) (string, error) {
+	return RenderBranchName(tmpl, BranchNameData{
+		Type:  BranchType(issue.Type, typeMap),
+		Key:   issue.Key,
+		Slug:  Slugify(issue.Title, DefaultSlugLength),
+		Title: issue.Title,
+	})
+}
+
+// RenderBranchName executes a branch name template.
+func RenderBranchName(tmpl string, data BranchNameData) (string, error) {
+	return render("branch", tmpl, DefaultBranchTemplate, data)
+}
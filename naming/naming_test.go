@@ -0,0 +1,135 @@
+package naming
+
+import (
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxLen   int
+		expected string
+	}{
+		{"simple title", "Add login page", 0, "add-login-page"},
+		{"punctuation collapses", "Fix: crash (on save)!", 0, "fix-crash-on-save"},
+		{"non-ascii dropped", "Café menu über alles", 0, "caf-menu-ber-alles"},
+		{"trims on word boundary", "Support exporting reports as spreadsheets", 20, "support-exporting"},
+		{"long single word is cut", "supercalifragilistic", 10, "supercalif"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Slugify(tt.input, tt.maxLen))
+		})
+	}
+}
+
+func TestBranchType(t *testing.T) {
+	assert.Equal(t, "bugfix", BranchType("Bug", nil))
+	assert.Equal(t, "feature", BranchType("Story", nil))
+	assert.Equal(t, "chore", BranchType("task", map[string]string{"Task": "chore"}))
+}
+
+func TestBranchNameFromIssue(t *testing.T) {
+	issue := models.Issue{Key: "JIRA-123", Title: "Short slug", Type: "Story"}
+
+	t.Run("default template", func(t *testing.T) {
+		name, err := BranchNameFromIssue("", issue, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "feature/JIRA-123-short-slug", name)
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		name, err := BranchNameFromIssue("{{.Key}}/{{.Slug}}", issue, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "JIRA-123/short-slug", name)
+	})
+
+	t.Run("unknown field is an error", func(t *testing.T) {
+		_, err := BranchNameFromIssue("{{.Nope}}", issue, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestWorktreeName(t *testing.T) {
+	name, err := WorktreeName("", "feature/login", "treekanga")
+	require.NoError(t, err)
+	assert.Equal(t, "feature-login", name)
+
+	name, err = WorktreeName("{{.Repo}}-{{.Branch | trimPrefix \"feature/\"}}", "feature/login", "treekanga")
+	require.NoError(t, err)
+	assert.Equal(t, "treekanga-login", name)
+}
+
+func TestSessionName(t *testing.T) {
+	data := SessionNameData{Repo: "platform", Branch: "release/2.1.0", Folder: "release-2.1.0"}
+
+	name, err := SessionName("", data)
+	require.NoError(t, err)
+	assert.Equal(t, "platform-release-2_1_0", name)
+
+	name, err = SessionName("{{.Folder | upper}}", data)
+	require.NoError(t, err)
+	assert.Equal(t, "RELEASE-2_1_0", name)
+
+	_, err = SessionName("{{.Nope}}", data)
+	assert.Error(t, err)
+}
+
+func TestExpandBranchName(t *testing.T) {
+	t.Setenv("USER", "gk")
+
+	name, err := ExpandBranchName("", "login")
+	require.NoError(t, err)
+	assert.Equal(t, "login", name)
+
+	name, err = ExpandBranchName("{{.User}}/{{.Name}}", "login")
+	require.NoError(t, err)
+	assert.Equal(t, "gk/login", name)
+}
+
+func TestRepoName(t *testing.T) {
+	assert.Equal(t, "treekanga", RepoName("/home/user/treekanga_work"))
+	assert.Equal(t, "myrepo", RepoName("/home/user/myrepo.git"))
+}
+
+func TestBranchRulesValidate(t *testing.T) {
+	rules := BranchRules{
+		Regex:            `^[a-z]+/[a-z0-9-]+$`,
+		MaxLength:        20,
+		ForbiddenChars:   "#@",
+		RequiredPrefixes: []string{"feature/", "bugfix/"},
+	}
+
+	tests := []struct {
+		name    string
+		branch  string
+		wantErr string
+	}{
+		{"valid", "feature/login", ""},
+		{"empty", "", "cannot be empty"},
+		{"too long", "feature/a-very-long-branch-name", "maxLength"},
+		{"forbidden char", "feature/#12", "forbiddenChars"},
+		{"missing prefix", "chore/deps", "requiredPrefixes"},
+		{"regex mismatch", "feature/Login", "branchNamePattern.regex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rules.Validate(tt.branch)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	assert.NoError(t, BranchRules{}.Validate("anything/goes"))
+	assert.ErrorContains(t, BranchRules{Regex: "("}.Validate("x"), "invalid branchNamePattern.regex")
+}
@@ -0,0 +1,115 @@
+package naming
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/garrettkrohn/treekanga/util"
+)
+
+// The defaults reproduce the names treekanga used before these were
+// configurable: the branch as typed, the branch with slashes replaced for
+// the folder, and "repo-branch" for the tmux session.
+const (
+	DefaultBranchNameTemplate   = "{{.Name}}"
+	DefaultWorktreeNameTemplate = "{{.Branch}}"
+	DefaultSessionNameTemplate  = "{{.Repo}}-{{.Branch}}"
+)
+
+// NewBranchData is the data available to branchNameTemplate.
+type NewBranchData struct {
+	Name string // the branch name as given to add
+	User string // $USER
+	Date string // today, formatted 2006-01-02
+}
+
+// WorktreeNameData is the data available to worktreeNameTemplate.
+type WorktreeNameData struct {
+	Branch string // full branch name, e.g. feature/login
+	Repo   string // repo name derived from the worktree parent directory
+}
+
+// SessionNameData is the data available to sessionNameTemplate.
+type SessionNameData struct {
+	Repo   string // repo name derived from the worktree parent directory
+	Branch string // branch checked out in the worktree
+	Folder string // worktree folder name
+}
+
+var templateFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"slug":       func(s string) string { return Slugify(s, 0) },
+}
+
+// ExpandBranchName renders branchNameTemplate for a branch name given to add
+// or rename.
+func ExpandBranchName(tmpl, name string) (string, error) {
+	return render("branchName", tmpl, DefaultBranchNameTemplate, NewBranchData{
+		Name: name,
+		User: os.Getenv("USER"),
+		Date: time.Now().Format("2006-01-02"),
+	})
+}
+
+// WorktreeName renders worktreeNameTemplate for a branch. Slashes in the
+// result are replaced with dashes so the worktree never ends up nested.
+func WorktreeName(tmpl, branch, repo string) (string, error) {
+	name, err := render("worktreeName", tmpl, DefaultWorktreeNameTemplate, WorktreeNameData{
+		Branch: branch,
+		Repo:   repo,
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(name, "/", "-"), nil
+}
+
+// SessionName renders sessionNameTemplate and sanitizes the result for tmux.
+func SessionName(tmpl string, data SessionNameData) (string, error) {
+	name, err := render("sessionName", tmpl, DefaultSessionNameTemplate, data)
+	if err != nil {
+		return "", err
+	}
+	return util.SanitizeForSessionName(name), nil
+}
+
+// RepoName derives the repo name from the directory holding the worktrees,
+// dropping the suffixes commonly used for bare repo layouts.
+func RepoName(worktreeParentDir string) string {
+	repoName := filepath.Base(worktreeParentDir)
+	repoName = strings.TrimSuffix(repoName, "_work")
+	repoName = strings.TrimSuffix(repoName, "_worktrees")
+	repoName = strings.TrimSuffix(repoName, "-bare")
+	repoName = strings.TrimSuffix(repoName, ".git")
+	return repoName
+}
+
+func render(kind, tmpl, defaultTmpl string, data any) (string, error) {
+	if tmpl == "" {
+		tmpl = defaultTmpl
+	}
+
+	t, err := template.New(kind).Funcs(templateFuncs).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template %q: %w", kind, tmpl, err)
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template %q: %w", kind, tmpl, err)
+	}
+
+	name := strings.TrimSpace(out.String())
+	if name == "" {
+		return "", fmt.Errorf("%s template %q rendered an empty name", kind, tmpl)
+	}
+	return name, nil
+}
@@ -0,0 +1,59 @@
+package naming
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BranchRules are the branchNamePattern checks applied to branches
+// created by add and rename. Zero values disable a check.
+type BranchRules struct {
+	Regex            string
+	MaxLength        int
+	ForbiddenChars   string
+	RequiredPrefixes []string
+}
+
+// Validate returns an error naming the first rule the branch breaks.
+func (r BranchRules) Validate(branch string) error {
+	if branch == "" {
+		return fmt.Errorf("branch name cannot be empty")
+	}
+
+	if r.MaxLength > 0 && len(branch) > r.MaxLength {
+		return fmt.Errorf("branch name '%s' is %d characters, the maximum is %d (branchNamePattern.maxLength)",
+			branch, len(branch), r.MaxLength)
+	}
+
+	if i := strings.IndexAny(branch, r.ForbiddenChars); r.ForbiddenChars != "" && i >= 0 {
+		return fmt.Errorf("branch name '%s' contains forbidden character %q (branchNamePattern.forbiddenChars)",
+			branch, branch[i:i+1])
+	}
+
+	if len(r.RequiredPrefixes) > 0 && !hasAnyPrefix(branch, r.RequiredPrefixes) {
+		return fmt.Errorf("branch name '%s' must start with one of: %s (branchNamePattern.requiredPrefixes)",
+			branch, strings.Join(r.RequiredPrefixes, ", "))
+	}
+
+	if r.Regex != "" {
+		re, err := regexp.Compile(r.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid branchNamePattern.regex '%s': %w", r.Regex, err)
+		}
+		if !re.MatchString(branch) {
+			return fmt.Errorf("branch name '%s' does not match %s (branchNamePattern.regex)", branch, r.Regex)
+		}
+	}
+
+	return nil
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package picker
+
+import (
+	"sort"
+
+	"github.com/sahilm/fuzzy"
+)
+
+// Item is one entry of the picker.
+type Item struct {
+	Icon   string // source of the item, e.g. tmux session or worktree
+	Label  string // what the query is matched against
+	Detail string // shown dimmed after the label, e.g. the path
+}
+
+// Match is an item that matches the query, with the label positions that
+// matched for highlighting.
+type Match struct {
+	Index   int // of the item in the picker's items
+	Matched []int
+}
+
+type labels []Item
+
+func (l labels) String(i int) string { return l[i].Label }
+func (l labels) Len() int            { return len(l) }
+
+// Filter returns the items matching query, best match first. Items that
+// match equally well keep their order, so the items' order is the ranking
+// for an empty query and the tie-breaker otherwise.
+func Filter(items []Item, query string) []Match {
+	if query == "" {
+		matches := make([]Match, len(items))
+		for i := range items {
+			matches[i] = Match{Index: i}
+		}
+		return matches
+	}
+
+	found := fuzzy.FindFromNoSort(query, labels(items))
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Score > found[j].Score })
+
+	matches := make([]Match, len(found))
+	for i, m := range found {
+		matches[i] = Match{Index: m.Index, Matched: m.MatchedIndexes}
+	}
+	return matches
+}
@@ -0,0 +1,39 @@
+package picker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	items := []Item{
+		{Label: "widget/feature/login"},
+		{Label: "gadget/main"},
+		{Label: "widget/main"},
+		{Label: "notes"},
+		{Label: "manual"},
+	}
+
+	indexes := func(matches []Match) []int {
+		var result []int
+		for _, m := range matches {
+			result = append(result, m.Index)
+		}
+		return result
+	}
+
+	t.Run("keeps the ranking without a query", func(t *testing.T) {
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, indexes(Filter(items, "")))
+	})
+
+	t.Run("drops items that don't match", func(t *testing.T) {
+		assert.Equal(t, []int{1, 2}, indexes(Filter(items, "main")))
+	})
+
+	t.Run("puts better matches first", func(t *testing.T) {
+		matches := Filter(items, "ma")
+		assert.Equal(t, []int{4, 1, 2}, indexes(matches))
+		assert.Equal(t, []int{0, 1}, matches[0].Matched)
+	})
+}
@@ -0,0 +1,217 @@
+package picker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// Model is a fuzzy finder over items with a preview of the selected one,
+// rendered next to the list.
+type Model struct {
+	items   []Item
+	preview func(i int) string
+	theme   *models.Theme
+
+	input    textinput.Model
+	matches  []Match
+	cursor   int
+	previews map[int]string // by item index, computed in the background
+	width    int
+	height   int
+
+	chosen    int
+	cancelled bool
+}
+
+// previewMsg carries the preview of an item once it is computed.
+type previewMsg struct {
+	index   int
+	preview string
+}
+
+// NewModel builds a picker over items, best ranked first. preview describes
+// an item, it runs in the background the first time the item is selected.
+func NewModel(items []Item, preview func(i int) string, theme *models.Theme) Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "type to filter"
+	input.Focus()
+
+	return Model{
+		items:    items,
+		preview:  preview,
+		theme:    theme,
+		input:    input,
+		matches:  Filter(items, ""),
+		previews: map[int]string{},
+		chosen:   -1,
+	}
+}
+
+// Run shows the picker and returns the index of the chosen item, or -1 when
+// the user cancelled.
+func Run(items []Item, preview func(i int) string, theme *models.Theme) (int, error) {
+	final, err := tea.NewProgram(NewModel(items, preview, theme), tea.WithAltScreen()).Run()
+	if err != nil {
+		return -1, err
+	}
+	return final.(Model).Chosen(), nil
+}
+
+// Chosen is the index of the item picked with enter, -1 until then or when
+// the picker was cancelled.
+func (m Model) Chosen() int {
+	if m.cancelled {
+		return -1
+	}
+	return m.chosen
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.loadPreview())
+}
+
+// loadPreview computes the preview of the selected item unless it is known.
+func (m Model) loadPreview() tea.Cmd {
+	if m.preview == nil || m.cursor >= len(m.matches) {
+		return nil
+	}
+	index := m.matches[m.cursor].Index
+	if _, ok := m.previews[index]; ok {
+		return nil
+	}
+	return func() tea.Msg {
+		return previewMsg{index: index, preview: m.preview(index)}
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+	case previewMsg:
+		m.previews[msg.index] = msg.preview
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.cancelled = true
+			return m, tea.Quit
+		case "enter":
+			if len(m.matches) > 0 {
+				m.chosen = m.matches[m.cursor].Index
+				return m, tea.Quit
+			}
+			return m, nil
+		case "up", "ctrl+p", "ctrl+k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, m.loadPreview()
+		case "down", "ctrl+n", "ctrl+j":
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+			return m, m.loadPreview()
+		}
+	}
+
+	var cmd tea.Cmd
+	query := m.input.Value()
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != query {
+		m.matches = Filter(m.items, m.input.Value())
+		m.cursor = 0
+		return m, tea.Batch(cmd, m.loadPreview())
+	}
+	return m, cmd
+}
+
+func (m Model) View() string {
+	if m.width == 0 {
+		return ""
+	}
+
+	listWidth := m.width / 2
+	previewWidth := m.width - listWidth - 4
+	listHeight := max(m.height-4, 1)
+
+	header := m.input.View()
+	count := lipgloss.NewStyle().Foreground(m.theme.MutedFg).
+		Render(fmt.Sprintf("  %d/%d", len(m.matches), len(m.items)))
+
+	list := lipgloss.NewStyle().Width(listWidth).Height(listHeight).
+		Render(strings.Join(m.listLines(listWidth, listHeight), "\n"))
+
+	preview := ""
+	if len(m.matches) > 0 {
+		var ok bool
+		preview, ok = m.previews[m.matches[m.cursor].Index]
+		if !ok {
+			preview = "loading..."
+		}
+	}
+	previewPane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.BorderDim).
+		Width(previewWidth).
+		Height(listHeight).
+		Render(preview)
+
+	help := lipgloss.NewStyle().Foreground(m.theme.MutedFg).
+		Render("↑/↓ select • enter connect • esc cancel")
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header+count,
+		lipgloss.JoinHorizontal(lipgloss.Top, list, previewPane),
+		help,
+	)
+}
+
+// listLines renders the visible window of matches around the cursor.
+func (m Model) listLines(width, height int) []string {
+	start := 0
+	if m.cursor >= height {
+		start = m.cursor - height + 1
+	}
+	end := min(start+height, len(m.matches))
+
+	highlight := lipgloss.NewStyle().Foreground(m.theme.Cyan).Bold(true)
+	detail := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	selected := lipgloss.NewStyle().Foreground(m.theme.AccentFg).Background(m.theme.Accent)
+
+	var lines []string
+	for i := start; i < end; i++ {
+		match := m.matches[i]
+		item := m.items[match.Index]
+
+		var label strings.Builder
+		for j, r := range item.Label {
+			if slices.Contains(match.Matched, j) {
+				label.WriteString(highlight.Render(string(r)))
+			} else {
+				label.WriteRune(r)
+			}
+		}
+
+		line := item.Icon + " " + label.String()
+		if item.Detail != "" {
+			line += " " + detail.Render(item.Detail)
+		}
+		line = lipgloss.NewStyle().MaxWidth(width).Render(line)
+		if i == m.cursor {
+			line = selected.Render("▌") + line
+		} else {
+			line = " " + line
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
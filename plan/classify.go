@@ -0,0 +1,369 @@
+package plan
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// gitQueries are git subcommands that never change anything
+var gitQueries = []string{
+	"rev-parse", "rev-list", "log", "status", "diff", "patch-id", "merge-base",
+	"describe", "show", "ls-remote", "for-each-ref", "show-ref", "cat-file",
+}
+
+// tmuxQueries are tmux commands that only read state
+var tmuxQueries = []string{
+	"list-sessions", "ls", "list-windows", "list-panes", "display-message", "has-session", "show-options",
+}
+
+// Classify describes a command and its effects, and reports whether it
+// changes anything. Commands that only read state are safe to run in a dry
+// run; everything else is recorded instead.
+func Classify(cmd string, args []string) (Step, bool) {
+	step := Step{Command: append([]string{cmd}, args...)}
+
+	switch cmd {
+	case "git":
+		return classifyGit(step, gitArgs(args))
+	case "tmux":
+		return classifyTmux(step, args)
+	case "code", "cursor", "zed", "idea", "goland", "webstorm", "pycharm", "phpstorm", "rubymine", "clion", "rider", "rustrover":
+		for _, arg := range args {
+			if strings.HasPrefix(arg, "-") {
+				continue
+			}
+			step.Effects = append(step.Effects, Effect{Kind: KindEditor, Change: ChangeOpen, Target: arg})
+		}
+		step.Description = fmt.Sprintf("open in %s", cmd)
+		return step, true
+	case "mv":
+		if len(args) == 2 {
+			step.Description = "move the worktree by hand and repoint its gitdir"
+			step.Effects = []Effect{
+				{Kind: KindDirectory, Change: ChangeRemove, Target: args[0]},
+				{Kind: KindDirectory, Change: ChangeCreate, Target: args[1]},
+			}
+		}
+		return step, true
+	case "mkdir":
+		for _, dir := range positional(args) {
+			step.Effects = append(step.Effects, Effect{Kind: KindDirectory, Change: ChangeCreate, Target: dir})
+		}
+		step.Description = "create the folder the worktree moves into"
+		return step, true
+	case "config":
+		// treekanga's own config file, set without a command: file key value
+		if len(args) == 3 {
+			step.Description = fmt.Sprintf("set %s to %s in %s", args[1], args[2], args[0])
+			step.Effects = []Effect{{Kind: KindConfig, Change: ChangeSet, Target: args[1]}}
+		}
+		return step, true
+	case "zoxide":
+		if len(args) == 0 || args[0] == "query" {
+			return step, false
+		}
+		change := ChangeCreate
+		step.Description = "add to zoxide"
+		if args[0] == "remove" {
+			change = ChangeRemove
+			step.Description = "remove from zoxide"
+		}
+		for _, path := range positional(args[1:]) {
+			step.Effects = append(step.Effects, Effect{Kind: KindZoxide, Change: change, Target: path})
+		}
+		return step, true
+	case "basename":
+		return step, false
+	}
+	return step, true
+}
+
+// gitArgs drops the global options in front of the subcommand
+func gitArgs(args []string) []string {
+	for len(args) > 0 {
+		switch {
+		case args[0] == "-C" || args[0] == "-c":
+			if len(args) < 2 {
+				return nil
+			}
+			args = args[2:]
+		case strings.HasPrefix(args[0], "-"):
+			args = args[1:]
+		default:
+			return args
+		}
+	}
+	return args
+}
+
+func classifyGit(step Step, args []string) (Step, bool) {
+	if len(args) == 0 {
+		return step, false
+	}
+	sub, rest := args[0], args[1:]
+	if slices.Contains(gitQueries, sub) {
+		return step, false
+	}
+
+	switch sub {
+	case "worktree":
+		return classifyWorktree(step, rest)
+	case "branch":
+		return classifyBranch(step, rest)
+	case "config":
+		return classifyConfig(step, rest)
+	case "fetch":
+		step.Description = "fetch " + strings.Join(positional(rest), " ")
+		return step, true
+	case "rebase", "merge":
+		targets := positional(rest)
+		switch {
+		case hasAny(rest, "--abort"):
+			step.Description = "abort the " + sub
+		case len(targets) == 0:
+		case sub == "rebase" && hasAny(rest, "--onto") && len(targets) > 1:
+			step.Description = fmt.Sprintf("rebase the checked out branch's commits after %s onto %s", targets[1], targets[0])
+		case sub == "rebase":
+			step.Description = "rebase the checked out branch onto " + targets[0]
+		default:
+			step.Description = fmt.Sprintf("merge %s into the checked out branch", targets[0])
+		}
+		return step, true
+	case "stash":
+		targets := positional(rest)
+		switch {
+		case len(targets) > 0 && targets[0] == "push":
+			step.Description = "stash the worktree's changes, untracked files included"
+		case len(targets) == 2 && targets[0] == "apply":
+			step.Description = fmt.Sprintf("apply stash %s to the checked out branch", targets[1])
+		case len(targets) == 2 && targets[0] == "drop":
+			step.Description = "drop stash " + targets[1]
+		}
+		return step, true
+	case "push":
+		targets := positional(rest)
+		if len(targets) != 2 {
+			return step, true
+		}
+		remote, branch := targets[0], targets[1]
+		if hasAny(rest, "--delete", "-d") {
+			step.Description = fmt.Sprintf("delete branch %s on %s", branch, remote)
+			step.Effects = []Effect{{Kind: KindBranch, Change: ChangeRemove, Target: remote + "/" + branch}}
+			return step, true
+		}
+		step.Description = fmt.Sprintf("push branch %s to %s", branch, remote)
+		if hasAny(rest, "-u", "--set-upstream") {
+			step.Description += " and track it"
+		}
+		step.Effects = []Effect{{Kind: KindBranch, Change: ChangeCreate, Target: remote + "/" + branch}}
+		return step, true
+	case "clone":
+		if targets := positional(rest); len(targets) == 2 {
+			step.Description = "clone " + targets[0]
+			step.Effects = []Effect{{Kind: KindDirectory, Change: ChangeCreate, Target: targets[1]}}
+		}
+		return step, true
+	}
+	return step, true
+}
+
+func classifyWorktree(step Step, args []string) (Step, bool) {
+	if len(args) == 0 || args[0] == "list" {
+		return step, false
+	}
+
+	targets := positional(args[1:])
+	switch args[0] {
+	case "add":
+		if len(targets) == 0 {
+			return step, true
+		}
+		path := targets[0]
+		step.Effects = []Effect{{Kind: KindDirectory, Change: ChangeCreate, Target: path}}
+		if branch := flagValue(args, "-b"); branch != "" {
+			step.Effects = append(step.Effects, Effect{Kind: KindBranch, Change: ChangeCreate, Target: branch})
+		}
+		step.Description = describeWorktreeAdd(args[1:])
+	case "remove":
+		if len(targets) > 0 {
+			step.Description = "remove the worktree and its directory"
+			step.Effects = []Effect{{Kind: KindDirectory, Change: ChangeRemove, Target: targets[0]}}
+		}
+	case "prune":
+		if hasAny(args, "-n", "--dry-run") {
+			return step, false
+		}
+		step.Description = "prune git's records of worktrees whose folders are gone"
+	case "move":
+		if len(targets) == 2 {
+			step.Description = "move the worktree"
+			step.Effects = []Effect{
+				{Kind: KindDirectory, Change: ChangeRemove, Target: targets[0]},
+				{Kind: KindDirectory, Change: ChangeCreate, Target: targets[1]},
+			}
+		}
+	}
+	return step, true
+}
+
+// describeWorktreeAdd explains which variant of "git worktree add" the
+// arguments are, i.e. which case of GetAddWorktreeArguements built them.
+func describeWorktreeAdd(args []string) string {
+	if ref := flagValue(args, "--detach"); ref != "" {
+		return fmt.Sprintf("check out %s detached, without a branch", ref)
+	}
+
+	if branch := flagValue(args, "-b"); branch != "" {
+		start := ""
+		if targets := positional(args); len(targets) > 1 {
+			start = targets[len(targets)-1]
+		}
+		switch {
+		case start == "":
+			return fmt.Sprintf("create branch %s from HEAD", branch)
+		case strings.HasPrefix(start, "origin/"):
+			return fmt.Sprintf("create branch %s from the remote base %s", branch, start)
+		default:
+			return fmt.Sprintf("create branch %s from %s", branch, start)
+		}
+	}
+
+	if targets := positional(args); len(targets) > 1 {
+		return fmt.Sprintf("check out existing branch %s (tracks origin/%s if it only exists on the remote)", targets[1], targets[1])
+	}
+	return ""
+}
+
+func classifyBranch(step Step, args []string) (Step, bool) {
+	targets := positional(args)
+	switch {
+	case hasAny(args, "-d", "-D", "--delete"):
+		step.Description = "delete local branch"
+		if hasAny(args, "-D", "--force") {
+			step.Description = "force delete local branch"
+		}
+		for _, branch := range targets {
+			step.Effects = append(step.Effects, Effect{Kind: KindBranch, Change: ChangeRemove, Target: branch})
+		}
+		return step, true
+	case hasAny(args, "-m", "-M", "--move"):
+		if len(targets) == 2 {
+			step.Description = fmt.Sprintf("rename branch %s to %s", targets[0], targets[1])
+			step.Effects = []Effect{
+				{Kind: KindBranch, Change: ChangeRemove, Target: targets[0]},
+				{Kind: KindBranch, Change: ChangeCreate, Target: targets[1]},
+			}
+		}
+		return step, true
+	case len(targets) > 0 && !hasAny(args, "--list", "-l", "-a", "-r", "--show-current", "--contains", "--merged"):
+		step.Effects = []Effect{{Kind: KindBranch, Change: ChangeCreate, Target: targets[0]}}
+		return step, true
+	}
+	return step, false
+}
+
+func classifyConfig(step Step, args []string) (Step, bool) {
+	if hasAny(args, "--get", "--get-all", "--get-regexp", "--list", "-l") {
+		return step, false
+	}
+
+	targets := positional(args)
+	if len(targets) == 0 {
+		return step, false
+	}
+	if hasAny(args, "--unset", "--unset-all") {
+		step.Description = "unset " + targets[0]
+		step.Effects = []Effect{{Kind: KindConfig, Change: ChangeUnset, Target: targets[0]}}
+		return step, true
+	}
+	if len(targets) == 1 {
+		// "git config key" reads the key
+		return step, false
+	}
+	step.Description = fmt.Sprintf("set %s = %s", targets[0], targets[1])
+	step.Effects = []Effect{{Kind: KindConfig, Change: ChangeSet, Target: targets[0]}}
+	return step, true
+}
+
+func classifyTmux(step Step, args []string) (Step, bool) {
+	if len(args) == 0 || slices.Contains(tmuxQueries, args[0]) {
+		return step, false
+	}
+
+	switch args[0] {
+	case "new-session":
+		if name := flagValue(args, "-s"); name != "" {
+			step.Description = "create tmux session"
+			if dir := flagValue(args, "-c"); dir != "" {
+				step.Description += " in " + dir
+			}
+			step.Effects = []Effect{{Kind: KindSession, Change: ChangeCreate, Target: name}}
+		}
+	case "kill-session":
+		step.Description = "kill tmux session"
+		step.Effects = []Effect{{Kind: KindSession, Change: ChangeRemove, Target: flagValue(args, "-t")}}
+	case "switch-client", "attach-session":
+		step.Description = "switch to tmux session"
+		if args[0] == "attach-session" {
+			step.Description = "attach to tmux session"
+		}
+		step.Effects = []Effect{{Kind: KindSession, Change: ChangeSwitch, Target: flagValue(args, "-t")}}
+	case "new-window":
+		dir := flagValue(args, "-c")
+		step.Description = "open a tmux window"
+		if dir != "" {
+			step.Description += " in " + dir
+		}
+		if command := positional(args[1:]); len(command) > 0 {
+			step.Description += " running " + strings.Join(command, " ")
+			step.Effects = []Effect{{Kind: KindEditor, Change: ChangeOpen, Target: dir}}
+		}
+	case "send-keys":
+		target := flagValue(args, "-t")
+		if keys := positional(args[1:]); len(keys) > 0 {
+			step.Description = fmt.Sprintf("run %q in the session", keys[0])
+		}
+		step.Effects = []Effect{{Kind: KindSession, Change: ChangeSend, Target: target}}
+	}
+	return step, true
+}
+
+// flagValue returns the argument following flag, if any
+func flagValue(args []string, flag string) string {
+	for i, arg := range args {
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// flagsWithValues are the flags the commands above take a value for
+var flagsWithValues = []string{"-b", "-B", "-c", "-n", "-s", "-t", "-F", "--detach", "--score"}
+
+// positional returns the arguments that aren't flags or flag values
+func positional(args []string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if slices.Contains(flagsWithValues, arg) {
+			i++
+			continue
+		}
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+func hasAny(args []string, flags ...string) bool {
+	for _, flag := range flags {
+		if slices.Contains(args, flag) {
+			return true
+		}
+	}
+	return false
+}
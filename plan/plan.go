@@ -0,0 +1,194 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Output formats accepted by Write
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Kinds of things a step changes
+const (
+	KindDirectory = "directory"
+	KindBranch    = "branch"
+	KindSession   = "session"
+	KindConfig    = "config"
+	KindEditor    = "editor"
+	KindZoxide    = "zoxide"
+)
+
+// Changes a step makes to a thing
+const (
+	ChangeCreate = "create"
+	ChangeRemove = "remove"
+	ChangeSwitch = "switch"
+	ChangeSend   = "send-keys"
+	ChangeSet    = "set"
+	ChangeUnset  = "unset"
+	ChangeOpen   = "open"
+)
+
+// Effect is one thing a step changes, e.g. the directory a worktree add
+// creates or the session a tmux command switches to.
+type Effect struct {
+	Kind   string `json:"kind"`
+	Change string `json:"change"`
+	Target string `json:"target"`
+}
+
+// Step is a command that would have run, with what it means and what it
+// would change.
+type Step struct {
+	Command     []string `json:"command"`
+	Description string   `json:"description,omitempty"`
+	Effects     []Effect `json:"effects,omitempty"`
+}
+
+// Plan is everything a dry run would have done, in order, along with the
+// directories, branches and sessions it touches.
+type Plan struct {
+	Steps              []Step   `json:"steps"`
+	DirectoriesCreated []string `json:"directoriesCreated"`
+	DirectoriesRemoved []string `json:"directoriesRemoved"`
+	BranchesCreated    []string `json:"branchesCreated"`
+	BranchesDeleted    []string `json:"branchesDeleted"`
+	SessionsTouched    []string `json:"sessionsTouched"`
+}
+
+// New builds a plan from the recorded steps.
+func New(steps []Step) Plan {
+	p := Plan{
+		Steps:              steps,
+		DirectoriesCreated: []string{},
+		DirectoriesRemoved: []string{},
+		BranchesCreated:    []string{},
+		BranchesDeleted:    []string{},
+		SessionsTouched:    []string{},
+	}
+	if p.Steps == nil {
+		p.Steps = []Step{}
+	}
+
+	add := func(list *[]string, target string) {
+		if !slices.Contains(*list, target) {
+			*list = append(*list, target)
+		}
+	}
+	for _, step := range steps {
+		for _, effect := range step.Effects {
+			switch {
+			case effect.Kind == KindDirectory && effect.Change == ChangeCreate:
+				add(&p.DirectoriesCreated, effect.Target)
+			case effect.Kind == KindDirectory && effect.Change == ChangeRemove:
+				add(&p.DirectoriesRemoved, effect.Target)
+			case effect.Kind == KindBranch && effect.Change == ChangeCreate:
+				add(&p.BranchesCreated, effect.Target)
+			case effect.Kind == KindBranch && effect.Change == ChangeRemove:
+				add(&p.BranchesDeleted, effect.Target)
+			case effect.Kind == KindSession:
+				add(&p.SessionsTouched, effect.Target)
+			}
+		}
+	}
+	return p
+}
+
+// ValidateFormat returns an error for anything but "text" or "json".
+func ValidateFormat(format string) error {
+	if format != FormatText && format != FormatJSON {
+		return fmt.Errorf("unknown plan format '%s', use '%s' or '%s'", format, FormatText, FormatJSON)
+	}
+	return nil
+}
+
+// Write prints the plan as text or JSON.
+func Write(w io.Writer, p Plan, format string) error {
+	if err := ValidateFormat(format); err != nil {
+		return err
+	}
+
+	if format == FormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(p)
+	}
+
+	_, err := io.WriteString(w, Text(p))
+	return err
+}
+
+// Text renders the plan for people: the numbered steps with what each one
+// means, then a summary of what would change.
+func Text(p Plan) string {
+	var b strings.Builder
+
+	if len(p.Steps) == 0 {
+		b.WriteString("Dry run: nothing would be changed.\n")
+		return b.String()
+	}
+
+	b.WriteString("Dry run, nothing was changed. Planned steps:\n\n")
+	width := len(strconv.Itoa(len(p.Steps)))
+	indent := strings.Repeat(" ", width+2)
+	for i, step := range p.Steps {
+		fmt.Fprintf(&b, "%*d. %s\n", width, i+1, CommandString(step.Command))
+		if step.Description != "" {
+			fmt.Fprintf(&b, "%s%s\n", indent, step.Description)
+		}
+		for _, effect := range step.Effects {
+			fmt.Fprintf(&b, "%s%s %s %s\n", indent, changeSymbol(effect.Change), effect.Kind, effect.Target)
+		}
+	}
+
+	summary := []struct {
+		title   string
+		targets []string
+	}{
+		{"Directories created", p.DirectoriesCreated},
+		{"Directories removed", p.DirectoriesRemoved},
+		{"Branches created", p.BranchesCreated},
+		{"Branches deleted", p.BranchesDeleted},
+		{"Sessions touched", p.SessionsTouched},
+	}
+	b.WriteString("\n")
+	for _, section := range summary {
+		if len(section.targets) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s\n", section.title, strings.Join(section.targets, ", "))
+	}
+
+	return b.String()
+}
+
+// CommandString joins a command for display, quoting arguments that contain
+// spaces so it can be copied back into a shell.
+func CommandString(command []string) string {
+	parts := make([]string, len(command))
+	for i, arg := range command {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+		parts[i] = arg
+	}
+	return strings.Join(parts, " ")
+}
+
+func changeSymbol(change string) string {
+	switch change {
+	case ChangeCreate:
+		return "+"
+	case ChangeRemove, ChangeUnset:
+		return "-"
+	default:
+		return "~"
+	}
+}
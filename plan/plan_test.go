@@ -0,0 +1,265 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRunner answers queries with canned output and remembers every command
+// that reached it
+type fakeRunner struct {
+	outputs map[string]string
+	ran     []string
+}
+
+func (f *fakeRunner) Run(_ context.Context, cmd string, args ...string) error {
+	f.ran = append(f.ran, CommandString(append([]string{cmd}, args...)))
+	return nil
+}
+
+func (f *fakeRunner) Combined(_ context.Context, cmd string, args ...string) (string, error) {
+	f.ran = append(f.ran, CommandString(append([]string{cmd}, args...)))
+	return "", nil
+}
+
+func (f *fakeRunner) Output(_ context.Context, cmd string, args ...string) (string, error) {
+	command := CommandString(append([]string{cmd}, args...))
+	f.ran = append(f.ran, command)
+	return f.outputs[command], nil
+}
+
+func TestClassifyWorktreeAdd(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		description string
+		effects     []Effect
+	}{
+		{
+			name:        "new branch from local base",
+			args:        []string{"-C", "/bare", "worktree", "add", "/wt/feat", "-b", "feat", "--no-track", "main"},
+			description: "create branch feat from main",
+			effects: []Effect{
+				{Kind: KindDirectory, Change: ChangeCreate, Target: "/wt/feat"},
+				{Kind: KindBranch, Change: ChangeCreate, Target: "feat"},
+			},
+		},
+		{
+			name:        "new branch from remote base",
+			args:        []string{"-C", "/bare", "worktree", "add", "/wt/feat", "-b", "feat", "--no-track", "origin/main"},
+			description: "create branch feat from the remote base origin/main",
+			effects: []Effect{
+				{Kind: KindDirectory, Change: ChangeCreate, Target: "/wt/feat"},
+				{Kind: KindBranch, Change: ChangeCreate, Target: "feat"},
+			},
+		},
+		{
+			name:        "existing branch",
+			args:        []string{"-C", "/bare", "worktree", "add", "/wt/feat", "feat"},
+			description: "check out existing branch feat (tracks origin/feat if it only exists on the remote)",
+			effects:     []Effect{{Kind: KindDirectory, Change: ChangeCreate, Target: "/wt/feat"}},
+		},
+		{
+			name:        "detached at a tag",
+			args:        []string{"-C", "/bare", "worktree", "add", "/wt/v1.0", "--detach", "v1.0"},
+			description: "check out v1.0 detached, without a branch",
+			effects:     []Effect{{Kind: KindDirectory, Change: ChangeCreate, Target: "/wt/v1.0"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, mutates := Classify("git", tt.args)
+			assert.True(t, mutates)
+			assert.Equal(t, tt.description, step.Description)
+			assert.Equal(t, tt.effects, step.Effects)
+		})
+	}
+}
+
+func TestClassifyQueries(t *testing.T) {
+	queries := [][]string{
+		{"git", "-C", "/bare", "worktree", "list"},
+		{"git", "-C", "/bare", "worktree", "prune", "--dry-run", "--verbose"},
+		{"git", "-C", "/bare", "branch", "--format=%(refname:short)"},
+		{"git", "config", "--get", "remote.origin.url"},
+		{"git", "-C", "/wt", "rev-list", "--left-right", "--count", "HEAD...origin/main"},
+		{"tmux", "list-sessions", "-F", "#{session_name}:#{session_path}"},
+		{"tmux", "display-message", "-p", "#{session_name}"},
+		{"tmux", "list-panes", "-a", "-F", "#{pane_id}"},
+		{"zoxide", "query", "--list", "--score", "--all"},
+	}
+
+	for _, command := range queries {
+		_, mutates := Classify(command[0], command[1:])
+		assert.False(t, mutates, CommandString(command))
+	}
+}
+
+func TestClassifyChanges(t *testing.T) {
+	step, mutates := Classify("git", []string{"-C", "/bare", "branch", "-D", "old"})
+	assert.True(t, mutates)
+	assert.Equal(t, []Effect{{Kind: KindBranch, Change: ChangeRemove, Target: "old"}}, step.Effects)
+
+	step, mutates = Classify("git", []string{"-C", "/bare", "branch", "-m", "old", "new"})
+	assert.True(t, mutates)
+	assert.Equal(t, "rename branch old to new", step.Description)
+
+	step, mutates = Classify("git", []string{"-C", "/wt", "config", "--unset", "branch.new.merge"})
+	assert.True(t, mutates)
+	assert.Equal(t, []Effect{{Kind: KindConfig, Change: ChangeUnset, Target: "branch.new.merge"}}, step.Effects)
+
+	step, mutates = Classify("git", []string{"-C", "/wt", "rebase", "origin/main"})
+	assert.True(t, mutates)
+	assert.Equal(t, "rebase the checked out branch onto origin/main", step.Description)
+
+	step, mutates = Classify("git", []string{"-C", "/wt", "rebase", "--onto", "feature/api", "a1c4d34"})
+	assert.True(t, mutates)
+	assert.Equal(t, "rebase the checked out branch's commits after a1c4d34 onto feature/api", step.Description)
+
+	step, mutates = Classify("git", []string{"-C", "/wt", "stash", "push", "--include-untracked", "-m", "treekanga carry"})
+	assert.True(t, mutates)
+	assert.Equal(t, "stash the worktree's changes, untracked files included", step.Description)
+
+	step, mutates = Classify("git", []string{"-C", "/wt", "stash", "apply", "a1c4d34"})
+	assert.True(t, mutates)
+	assert.Equal(t, "apply stash a1c4d34 to the checked out branch", step.Description)
+
+	step, mutates = Classify("git", []string{"-C", "/wt", "stash", "drop", "--quiet", "stash@{1}"})
+	assert.True(t, mutates)
+	assert.Equal(t, "drop stash stash@{1}", step.Description)
+
+	step, mutates = Classify("git", []string{"-C", "/wt", "merge", "--no-edit", "origin/main"})
+	assert.True(t, mutates)
+	assert.Equal(t, "merge origin/main into the checked out branch", step.Description)
+
+	step, mutates = Classify("tmux", []string{"new-session", "-d", "-s", "repo-feat", "-c", "/wt/feat"})
+	assert.True(t, mutates)
+	assert.Equal(t, []Effect{{Kind: KindSession, Change: ChangeCreate, Target: "repo-feat"}}, step.Effects)
+
+	step, mutates = Classify("zoxide", []string{"remove", "/wt/feat", "/wt/feat/api"})
+	assert.True(t, mutates)
+	assert.Equal(t, []Effect{
+		{Kind: KindZoxide, Change: ChangeRemove, Target: "/wt/feat"},
+		{Kind: KindZoxide, Change: ChangeRemove, Target: "/wt/feat/api"},
+	}, step.Effects)
+
+	step, mutates = Classify("zoxide", []string{"add", "--score", "12.5", "/wt/login"})
+	assert.True(t, mutates)
+	assert.Equal(t, []Effect{{Kind: KindZoxide, Change: ChangeCreate, Target: "/wt/login"}}, step.Effects)
+
+	step, mutates = Classify("code", []string{"/wt/feat"})
+	assert.True(t, mutates)
+	assert.Equal(t, []Effect{{Kind: KindEditor, Change: ChangeOpen, Target: "/wt/feat"}}, step.Effects)
+
+	step, mutates = Classify("git", []string{"-C", "/wt/new", "push", "-u", "origin", "new"})
+	assert.True(t, mutates)
+	assert.Equal(t, "push branch new to origin and track it", step.Description)
+	assert.Equal(t, []Effect{{Kind: KindBranch, Change: ChangeCreate, Target: "origin/new"}}, step.Effects)
+
+	step, mutates = Classify("git", []string{"-C", "/bare", "push", "origin", "--delete", "old"})
+	assert.True(t, mutates)
+	assert.Equal(t, "delete branch old on origin", step.Description)
+	assert.Equal(t, []Effect{{Kind: KindBranch, Change: ChangeRemove, Target: "origin/old"}}, step.Effects)
+
+	step, mutates = Classify("zed", []string{"--new", "/wt/feat"})
+	assert.True(t, mutates)
+	assert.Equal(t, []Effect{{Kind: KindEditor, Change: ChangeOpen, Target: "/wt/feat"}}, step.Effects)
+
+	step, mutates = Classify("tmux", []string{"new-window", "-n", "feat", "-c", "/wt/feat", "nvim"})
+	assert.True(t, mutates)
+	assert.Equal(t, "open a tmux window in /wt/feat running nvim", step.Description)
+	assert.Equal(t, []Effect{{Kind: KindEditor, Change: ChangeOpen, Target: "/wt/feat"}}, step.Effects)
+
+	step, mutates = Classify("git", []string{"-C", "/bare", "worktree", "prune"})
+	assert.True(t, mutates)
+	assert.Equal(t, "prune git's records of worktrees whose folders are gone", step.Description)
+
+	step, mutates = Classify("mkdir", []string{"-p", "/disk/widget_work"})
+	assert.True(t, mutates)
+	assert.Equal(t, []Effect{{Kind: KindDirectory, Change: ChangeCreate, Target: "/disk/widget_work"}}, step.Effects)
+
+	step, mutates = Classify("config", []string{"/home/me/.config/treekanga/treekanga.yml", "repos.widget.worktreeTargetDir", "/disk/widget_work"})
+	assert.True(t, mutates)
+	assert.Equal(t, "set repos.widget.worktreeTargetDir to /disk/widget_work in /home/me/.config/treekanga/treekanga.yml", step.Description)
+	assert.Equal(t, []Effect{{Kind: KindConfig, Change: ChangeSet, Target: "repos.widget.worktreeTargetDir"}}, step.Effects)
+}
+
+func TestRecorder(t *testing.T) {
+	runner := &fakeRunner{outputs: map[string]string{
+		"git -C /bare worktree list": "/bare (bare)\n/wt/old abc1234 [old]",
+	}}
+	mockShell := shell.NewMockShell(t)
+	mockShell.EXPECT().Cmd("tmux", "list-sessions", "-F", "#{session_name}:#{session_path}").Return("", nil)
+
+	recorder := NewRecorder(runner, mockShell)
+	ctx := context.Background()
+
+	_, err := recorder.Combined(ctx, "git", "-C", "/bare", "worktree", "add", "/wt/feat", "-b", "feat", "--no-track", "main")
+	require.NoError(t, err)
+	require.NoError(t, recorder.Run(ctx, "git", "-C", "/bare", "worktree", "remove", "/wt/old"))
+
+	// planned worktrees show up in later listings
+	list, err := recorder.Output(ctx, "git", "-C", "/bare", "worktree", "list")
+	require.NoError(t, err)
+	assert.Equal(t, "/bare (bare)\n/wt/feat 0000000 [feat]", list)
+
+	_, err = recorder.Cmd("tmux", "list-sessions", "-F", "#{session_name}:#{session_path}")
+	require.NoError(t, err)
+	_, err = recorder.Cmd("tmux", "new-session", "-d", "-s", "repo-feat", "-c", "/wt/feat")
+	require.NoError(t, err)
+
+	// only the query reached the real runner
+	assert.Equal(t, []string{"git -C /bare worktree list"}, runner.ran)
+
+	p := recorder.Plan()
+	assert.Len(t, p.Steps, 3)
+	assert.Equal(t, []string{"/wt/feat"}, p.DirectoriesCreated)
+	assert.Equal(t, []string{"/wt/old"}, p.DirectoriesRemoved)
+	assert.Equal(t, []string{"feat"}, p.BranchesCreated)
+	assert.Equal(t, []string{"repo-feat"}, p.SessionsTouched)
+}
+
+func TestWrite(t *testing.T) {
+	p := New([]Step{{
+		Command:     []string{"git", "-C", "/bare", "worktree", "add", "/wt/feat", "-b", "feat", "--no-track", "main"},
+		Description: "create branch feat from main",
+		Effects: []Effect{
+			{Kind: KindDirectory, Change: ChangeCreate, Target: "/wt/feat"},
+			{Kind: KindBranch, Change: ChangeCreate, Target: "feat"},
+		},
+	}})
+
+	var text bytes.Buffer
+	require.NoError(t, Write(&text, p, FormatText))
+	assert.Equal(t, `Dry run, nothing was changed. Planned steps:
+
+1. git -C /bare worktree add /wt/feat -b feat --no-track main
+   create branch feat from main
+   + directory /wt/feat
+   + branch feat
+
+Directories created: /wt/feat
+Branches created: feat
+`, text.String())
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, p, FormatJSON))
+	var decoded Plan
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, p, decoded)
+
+	assert.Error(t, Write(&out, p, "yaml"))
+}
+
+func TestWriteEmptyPlan(t *testing.T) {
+	var text bytes.Buffer
+	require.NoError(t, Write(&text, New(nil), FormatText))
+	assert.Equal(t, "Dry run: nothing would be changed.\n", text.String())
+}
@@ -0,0 +1,178 @@
+package plan
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/shell"
+)
+
+// Recorder stands in for the git runner and the shell during a dry run.
+// Commands that only read state are passed through so services still see
+// the real repo; commands that would change something are recorded as plan
+// steps and reported as successful without running.
+//
+// Worktrees the plan adds or removes are reflected in "git worktree list"
+// so later steps, like connecting to the new worktree's tmux session, are
+// planned as if the earlier ones had happened.
+type Recorder struct {
+	runner git.Runner
+	shell  shell.Shell
+
+	mu      sync.Mutex
+	steps   []Step
+	added   []string // "path commit [branch]" lines for planned worktrees
+	removed []string // paths of worktrees the plan removes
+}
+
+// NewRecorder creates a recorder that passes queries to runner and shell.
+func NewRecorder(runner git.Runner, shell shell.Shell) *Recorder {
+	return &Recorder{runner: runner, shell: shell}
+}
+
+// Plan returns everything recorded so far.
+func (r *Recorder) Plan() Plan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return New(slices.Clone(r.steps))
+}
+
+// record adds the command to the plan when it changes something and reports
+// whether it did. The description is used when the command alone doesn't
+// say enough.
+func (r *Recorder) record(cmd string, args []string, description ...string) bool {
+	step, mutates := Classify(cmd, args)
+	if !mutates {
+		return false
+	}
+	if step.Description == "" && len(description) > 0 {
+		step.Description = description[0]
+	}
+
+	log.Debug("Dry run, not running", "command", CommandString(step.Command))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+	r.trackWorktrees(step)
+	return true
+}
+
+// trackWorktrees remembers the worktrees a step adds or removes
+func (r *Recorder) trackWorktrees(step Step) {
+	args := gitArgs(step.Command[1:])
+	if step.Command[0] != "git" || len(args) < 2 || args[0] != "worktree" {
+		return
+	}
+
+	targets := positional(args[2:])
+	if len(targets) == 0 {
+		return
+	}
+	switch args[1] {
+	case "add":
+		label := "(detached HEAD)"
+		if branch := flagValue(args, "-b"); branch != "" {
+			label = "[" + branch + "]"
+		} else if flagValue(args, "--detach") == "" && len(targets) > 1 {
+			label = "[" + targets[1] + "]"
+		}
+		r.added = append(r.added, targets[0]+" 0000000 "+label)
+	case "remove":
+		r.removed = append(r.removed, targets[0])
+	}
+}
+
+// worktreeList applies the planned adds and removes to real "git worktree
+// list" output
+func (r *Recorder) worktreeList(output string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.added) == 0 && len(r.removed) == 0 {
+		return output
+	}
+
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && slices.Contains(r.removed, fields[0]) {
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	lines = append(lines, r.added...)
+	return strings.Join(lines, "\n")
+}
+
+// Run implements git.Runner
+func (r *Recorder) Run(ctx context.Context, cmd string, args ...string) error {
+	if r.record(cmd, args) {
+		return nil
+	}
+	return r.runner.Run(ctx, cmd, args...)
+}
+
+// Combined implements git.Runner
+func (r *Recorder) Combined(ctx context.Context, cmd string, args ...string) (string, error) {
+	if r.record(cmd, args) {
+		return "", nil
+	}
+	return r.runner.Combined(ctx, cmd, args...)
+}
+
+// Output implements git.Runner
+func (r *Recorder) Output(ctx context.Context, cmd string, args ...string) (string, error) {
+	if r.record(cmd, args) {
+		return "", nil
+	}
+	output, err := r.runner.Output(ctx, cmd, args...)
+	if err == nil && cmd == "git" {
+		if sub := gitArgs(args); len(sub) > 1 && sub[0] == "worktree" && sub[1] == "list" {
+			output = r.worktreeList(output)
+		}
+	}
+	return output, err
+}
+
+// PlanCommand implements git.Planner for changes made without a command
+func (r *Recorder) PlanCommand(cmd string, args ...string) {
+	r.record(cmd, args)
+}
+
+// Cmd implements shell.Shell
+func (r *Recorder) Cmd(cmd string, args ...string) (string, error) {
+	if r.record(cmd, args) {
+		return "", nil
+	}
+	return r.shell.Cmd(cmd, args...)
+}
+
+// ListCmd implements shell.Shell
+func (r *Recorder) ListCmd(cmd string, args ...string) ([]string, error) {
+	if r.record(cmd, args) {
+		return nil, nil
+	}
+	return r.shell.ListCmd(cmd, args...)
+}
+
+// CmdWithDir implements shell.Shell
+func (r *Recorder) CmdWithDir(dir string, cmd string, args ...string) (string, error) {
+	if r.record(cmd, args, "run in "+dir) {
+		return "", nil
+	}
+	return r.shell.CmdWithDir(dir, cmd, args...)
+}
+
+// CmdWithStreaming implements shell.Shell
+func (r *Recorder) CmdWithStreaming(cmd string, args ...string) error {
+	if r.record(cmd, args) {
+		return nil
+	}
+	return r.shell.CmdWithStreaming(cmd, args...)
+}
@@ -1,14 +1,18 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
-	"slices"
 	"strings"
+	"sync"
 
 	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/adapters"
 	"github.com/garrettkrohn/treekanga/config"
 	"github.com/garrettkrohn/treekanga/connector"
+	"github.com/garrettkrohn/treekanga/directoryReader"
 	"github.com/garrettkrohn/treekanga/form"
 	"github.com/garrettkrohn/treekanga/git"
 	"github.com/garrettkrohn/treekanga/models"
@@ -18,23 +22,38 @@ import (
 	"github.com/garrettkrohn/treekanga/utility"
 )
 
-func SetConfigForAddService(cfg config.AppConfig, args []string) config.AppConfig {
+func SetConfigForAddService(git git.Git, cfg config.AppConfig, args []string) config.AppConfig {
 	log.Debug("Running configuration for add command")
 
-	if len(args) == 1 {
-		cfg.NewBranchName = strings.TrimSpace(args[0])
-		log.Debug(fmt.Sprintf("Setting newBranchName = %s in addService", args[0]))
-	} else {
-		log.Fatal("please include new branch name as an argument")
+	if cfg.AddRef != "" {
+		commit := resolveAddRef(git, cfg.BareRepoPath, cfg.AddRef)
+		log.Debug(fmt.Sprintf("Resolved ref %s to %s", cfg.AddRef, commit))
 	}
 
-	if cfg.NewWorktreeName == "" {
-		cfg.NewWorktreeName = cfg.NewBranchName
-		log.Debug(fmt.Sprintf("No worktree name specified in flags, so defaults to new branch name: %s", cfg.NewWorktreeName))
+	// A detached worktree has no branch, so there's nothing else to check
+	if cfg.Detach {
+		if len(args) > 0 {
+			log.Fatal("--detach checks out --ref without a branch, please don't also pass a branch name")
+		}
+		if cfg.NewWorktreeName == "" {
+			worktreeName, err := WorktreeFolderName(cfg, cfg.AddRef)
+			utility.CheckError(err)
+			cfg.NewWorktreeName = worktreeName
+		}
+		cfg.NewWorktreeName = strings.ReplaceAll(cfg.NewWorktreeName, "/", "-")
+		log.Debug(fmt.Sprintf("Setting NewWorktreeName = %s for detached ref %s", cfg.NewWorktreeName, cfg.AddRef))
+		return cfg
 	}
 
-	// Sanitize worktree name by replacing slashes with dashes to prevent nested directory issues
-	cfg.NewWorktreeName = strings.ReplaceAll(cfg.NewWorktreeName, "/", "-")
+	if len(args) == 1 {
+		var err error
+		cfg, err = ResolveNewBranchName(cfg, args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		log.Fatal("please include new branch name as an argument")
+	}
 
 	// When checking out an existing remote branch, fetch it first so a branch
 	// pushed after the last fetch is still found. A fetch failure here (e.g.
@@ -46,29 +65,86 @@ func SetConfigForAddService(cfg config.AppConfig, args []string) config.AppConfi
 		}
 	}
 
-	remoteBranches, err := git.GetRemoteBranches(cfg.BareRepoPath)
+	refs, err := git.GetRefSnapshot(cfg.BareRepoPath, "")
 	utility.CheckError(err)
-	log.Debug("Remote branches", "branches", remoteBranches)
 
-	localBranches, err := git.GetLocalBranches(cfg.BareRepoPath)
-	utility.CheckError(err)
-	log.Debug("Local branches", "branches", localBranches)
+	cfg = applyBranchExistence(cfg, refs)
+
+	return cfg
+}
 
-	cfg.NewBranchExistsLocally = slices.Contains(localBranches, cfg.NewBranchName)
+// applyBranchExistence records where the new and base branches already exist.
+func applyBranchExistence(cfg config.AppConfig, refs *git.RefSnapshot) config.AppConfig {
+	cfg.NewBranchExistsLocally = refs.HasLocal(cfg.NewBranchName)
 	log.Debug(fmt.Sprintf("Setting NewBranchExistsLocally = %t from addService", cfg.NewBranchExistsLocally))
 
-	cfg.NewBranchExistsRemotely = slices.Contains(remoteBranches, cfg.NewBranchName)
+	cfg.NewBranchExistsRemotely = refs.HasRemote(cfg.NewBranchName)
 	log.Debug(fmt.Sprintf("Setting NewBranchExistsRemotely = %t from addService", cfg.NewBranchExistsRemotely))
 
-	cfg.BaseBranchExistsLocally = slices.Contains(localBranches, cfg.BaseBranch)
+	cfg.BaseBranchExistsLocally = refs.HasLocal(cfg.BaseBranch)
 	log.Debug(fmt.Sprintf("Setting BaseBranchExistsLocally = %t from addService", cfg.BaseBranchExistsLocally))
 
-	cfg.BaseBranchExistsRemotely = slices.Contains(remoteBranches, cfg.BaseBranch)
+	cfg.BaseBranchExistsRemotely = refs.HasRemote(cfg.BaseBranch)
 	log.Debug(fmt.Sprintf("Setting BaseBranchExistsRemotely = %t from addService", cfg.BaseBranchExistsRemotely))
 
 	return cfg
 }
 
+// resolveAddRef makes sure a tag or commit given with --ref exists, fetching
+// it as a tag from the remote when it isn't known locally yet.
+func resolveAddRef(git git.Git, bareRepoPath, ref string) string {
+	commit, err := git.ResolveCommit(bareRepoPath, ref)
+	if err == nil {
+		return commit
+	}
+
+	log.Debug("Ref not found locally, trying to fetch it as a tag", "ref", ref)
+	if fetchErr := git.FetchTag(bareRepoPath, ref); fetchErr != nil {
+		log.Debug("Failed to fetch tag", "ref", ref, "error", fetchErr)
+	}
+
+	commit, err = git.ResolveCommit(bareRepoPath, ref)
+	if err != nil {
+		log.Fatal(fmt.Sprintf("Ref '%s' not found locally or on remote", ref))
+	}
+	return commit
+}
+
+// ResolveNewBranchName sets the branch to add and derives the worktree folder
+// name from it unless one was given with --name. Branches that will be
+// created go through branchNameTemplate and branchNamePattern; existing
+// branches checked out with --remote or --local are used as-is, as are
+// branches already named from an issue.
+func ResolveNewBranchName(cfg config.AppConfig, name string) (config.AppConfig, error) {
+	branchName := strings.TrimSpace(name)
+	if !cfg.CheckoutRemote && !cfg.CheckoutLocal {
+		var err error
+		if cfg.IssueKey != "" {
+			err = branchRules(cfg).Validate(branchName)
+		} else {
+			branchName, err = NewBranchName(cfg, branchName)
+		}
+		if err != nil {
+			return cfg, err
+		}
+	}
+	cfg.NewBranchName = branchName
+	log.Debug(fmt.Sprintf("Setting newBranchName = %s in addService", cfg.NewBranchName))
+
+	if cfg.NewWorktreeName == "" {
+		worktreeName, err := WorktreeFolderName(cfg, cfg.NewBranchName)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.NewWorktreeName = worktreeName
+		log.Debug(fmt.Sprintf("No worktree name specified in flags, so derived from new branch name: %s", cfg.NewWorktreeName))
+	}
+
+	// Sanitize worktree name by replacing slashes with dashes to prevent nested directory issues
+	cfg.NewWorktreeName = strings.ReplaceAll(cfg.NewWorktreeName, "/", "-")
+	return cfg, nil
+}
+
 type AddWorktreeConfig struct {
 	BareRepoPath               string
 	WorktreeTargetDirectory    string
@@ -82,9 +158,16 @@ type AddWorktreeConfig struct {
 	PullBeforeCuttingNewBranch bool
 	BaseBranch                 string
 	NewWorktreeName            string
+	Ref                        string
+	Detach                     bool
 }
 
 func GetAddWorktreeArguements(params AddWorktreeConfig) []string {
+	// Case 0: Detached worktree at a tag or commit, no branch involved
+	if params.Detach {
+		return []string{"--detach", params.Ref}
+	}
+
 	// Case 1: Checkout existing remote branch
 	if params.CheckoutRemote {
 		return []string{params.NewBranchName}
@@ -95,7 +178,12 @@ func GetAddWorktreeArguements(params AddWorktreeConfig) []string {
 		return []string{params.NewBranchName}
 	}
 
-	// Case 3: Default mode - create new branch from base branch
+	// Case 3: New branch cut from a tag or commit given with --ref
+	if params.Ref != "" {
+		return []string{"-b", params.NewBranchName, "--no-track", params.Ref}
+	}
+
+	// Case 4: Default mode - create new branch from base branch
 	// Base branch exists locally
 	if params.BaseBranchExistsLocally {
 		if params.PullBeforeCuttingNewBranch && params.BaseBranchExistsRemotely {
@@ -128,66 +216,47 @@ func handleFromForm(form form.HuhForm, worktrees []string) string {
 	return selectedBranch
 }
 
-func AddWorktree(connector connector.Connector, shell shell.Shell, cfg config.AppConfig) {
-
-	// Validation: Check mode and branch existence constraints
-	if cfg.CheckoutRemote {
+// validateAddMode checks the new branch against the checkout mode: --remote
+// and --local need an existing branch, the default mode needs a new one.
+func validateAddMode(cfg config.AppConfig) error {
+	if cfg.Detach {
+		log.Debug("Checkout mode: detached - ignoring -b and -p flags if set")
+	} else if cfg.CheckoutRemote {
 		// --remote mode: branch must exist remotely
 		if !cfg.NewBranchExistsRemotely {
-			log.Fatal(fmt.Sprintf("Branch '%s' not found on remote", cfg.NewBranchName))
+			return fmt.Errorf("branch '%s' not found on remote", cfg.NewBranchName)
 		}
 		log.Debug("Checkout mode: remote - ignoring -b and -p flags if set")
 	} else if cfg.CheckoutLocal {
 		// --local mode: branch must exist locally
 		if !cfg.NewBranchExistsLocally {
-			log.Fatal(fmt.Sprintf("Branch '%s' not found locally", cfg.NewBranchName))
+			return fmt.Errorf("branch '%s' not found locally", cfg.NewBranchName)
 		}
 		log.Debug("Checkout mode: local - ignoring -b and -p flags if set")
 	} else {
 		// Default mode: branch must NOT exist
 		if cfg.NewBranchExistsLocally || cfg.NewBranchExistsRemotely {
-			log.Fatal(fmt.Sprintf("Branch '%s' already exists. Use --remote or --local to checkout existing branch.", cfg.NewBranchName))
+			return fmt.Errorf("branch '%s' already exists. Use --remote or --local to checkout existing branch", cfg.NewBranchName)
 		}
 		log.Debug("Default mode: creating new branch")
-	}
-
-	if cfg.UseFormToSetBaseBranch {
-		worktrees, err := git.ListWorktrees(cfg.BareRepoPath)
-		utility.CheckError(err)
-
-		worktreeObjects := transformer.TransformWorktrees(worktrees)
-
-		util.SortWorktreesByModTime(worktreeObjects)
-
-		var branchStrings []string
-
-		for _, wt := range worktreeObjects {
-			branchStrings = append(branchStrings, wt.BranchName)
+		if cfg.StackOn != "" && !cfg.BaseBranchExistsLocally {
+			return fmt.Errorf("can't stack on '%s', it isn't a local branch", cfg.StackOn)
 		}
-
-		form := form.NewHuhForm()
-
-		selectedBranch := handleFromForm(*form, branchStrings)
-		cfg.BaseBranch = selectedBranch
-		log.Debug(fmt.Sprintf("Set BaseBranch = %s from form selection", selectedBranch))
-
-		// Update the BaseBranchExists flags after selection
-		localBranches, err := git.GetLocalBranches(cfg.BareRepoPath)
-		utility.CheckError(err)
-		cfg.BaseBranchExistsLocally = slices.Contains(localBranches, cfg.BaseBranch)
-		log.Debug(fmt.Sprintf("Updated BaseBranchExistsLocally = %t after form selection", cfg.BaseBranchExistsLocally))
-
-		remoteBranches, err := git.GetRemoteBranches(cfg.BareRepoPath)
-		utility.CheckError(err)
-		cfg.BaseBranchExistsRemotely = slices.Contains(remoteBranches, cfg.BaseBranch)
-		log.Debug(fmt.Sprintf("Updated BaseBranchExistsRemotely = %t after form selection", cfg.BaseBranchExistsRemotely))
 	}
+	return nil
+}
 
-	// Fetch the latest state of base branch if pull flag is set
-	if cfg.PullBeforeCuttingNewBranch && cfg.BaseBranchExistsRemotely {
-		err := git.Fetch(cfg.BareRepoPath, cfg.BaseBranch)
-		utility.CheckError(err)
-		log.Debug(fmt.Sprintf("Fetched latest state of %s from remote", cfg.BaseBranch))
+// gitConfigMu serializes adds that write to the shared git config, which
+// git locks, so concurrent batch adds don't fail on config.lock.
+var gitConfigMu sync.Mutex
+
+// createWorktree runs git worktree add for a prepared config and sets the
+// upstream of new branches. It returns the new worktree's path. The
+// worktree, and the branch if one was created, are removed again if tx
+// rolls back.
+func createWorktree(git git.Git, cfg config.AppConfig, tx *Transaction) (string, error) {
+	if err := validateAddMode(cfg); err != nil {
+		return "", err
 	}
 
 	worktreeAddArgs := GetAddWorktreeArguements(AddWorktreeConfig{
@@ -203,23 +272,78 @@ func AddWorktree(connector connector.Connector, shell shell.Shell, cfg config.Ap
 		PullBeforeCuttingNewBranch: cfg.PullBeforeCuttingNewBranch,
 		BaseBranch:                 cfg.BaseBranch,
 		NewWorktreeName:            cfg.NewWorktreeName,
+		Ref:                        cfg.AddRef,
+		Detach:                     cfg.Detach,
 	})
 
+	// Checking out a remote branch creates a tracking branch, which writes
+	// branch.<name>.* to the config
+	if cfg.CheckoutRemote {
+		gitConfigMu.Lock()
+	}
 	err := git.AddWorktree(cfg.BareRepoPath, cfg.WorktreeTargetDir, cfg.NewWorktreeName, worktreeAddArgs)
-	utility.CheckError(err)
+	if cfg.CheckoutRemote {
+		gitConfigMu.Unlock()
+	}
+	if err != nil {
+		return "", err
+	}
 
 	//TODO: different place for this?
 	newRootDirectory := cfg.WorktreeTargetDir + "/" + cfg.NewWorktreeName
 
-	// Set upstream for new branches (not existing ones)
-	if !cfg.CheckoutRemote && !cfg.CheckoutLocal {
+	// git worktree add created the branch unless it already existed locally
+	// or no branch is checked out at all
+	if !cfg.Detach && !cfg.CheckoutLocal && !cfg.NewBranchExistsLocally {
+		tx.OnRollback("delete branch "+cfg.NewBranchName, func() error {
+			// deleting a branch removes its branch.<name>.* config
+			gitConfigMu.Lock()
+			defer gitConfigMu.Unlock()
+			return git.DeleteBranch(cfg.BareRepoPath, cfg.NewBranchName, true)
+		})
+	}
+	tx.OnRollback("remove worktree "+newRootDirectory, func() error {
+		return git.RemoveWorktree(cfg.BareRepoPath, newRootDirectory, true)
+	})
+
+	// Set upstream for new branches (not existing ones or detached worktrees)
+	if !cfg.CheckoutRemote && !cfg.CheckoutLocal && !cfg.Detach {
+		gitConfigMu.Lock()
 		err = git.SetUpstream(newRootDirectory, cfg.NewBranchName)
+		gitConfigMu.Unlock()
 		if err != nil {
-			log.Warn("Failed to set upstream branch", "branch", cfg.NewBranchName, "error", err)
+			return "", fmt.Errorf("failed to set upstream of %s: %w", cfg.NewBranchName, err)
+		}
+
+		// Remember the base so status, merged detection and sync compare
+		// against it instead of the default branch. Branches cut from a tag
+		// or commit have no base branch.
+		if cfg.AddRef == "" {
+			gitConfigMu.Lock()
+			err = git.SetBranchBase(newRootDirectory, cfg.NewBranchName, cfg.BaseBranch)
+			gitConfigMu.Unlock()
+			if err != nil {
+				log.Warn("Comparing against the default branch instead", "error", err)
+			}
+		}
+
+		if cfg.StackOn != "" {
+			gitConfigMu.Lock()
+			err = git.SetBranchParent(newRootDirectory, cfg.NewBranchName, cfg.StackOn)
+			gitConfigMu.Unlock()
+			if err != nil {
+				return "", err
+			}
 		}
 	}
 
-	if cfg.CheckoutRemote {
+	if cfg.Detach {
+		log.Info("worktree created detached at ref", "ref", cfg.AddRef)
+	} else if cfg.AddRef != "" {
+		log.Info("worktree created with new branch cut from ref",
+			"newBranch", cfg.NewBranchName,
+			"ref", cfg.AddRef)
+	} else if cfg.CheckoutRemote {
 		log.Info("worktree created with remote branch", "branch", cfg.NewBranchName)
 	} else if cfg.CheckoutLocal {
 		log.Info("worktree created with local branch", "branch", cfg.NewBranchName)
@@ -229,31 +353,130 @@ func AddWorktree(connector connector.Connector, shell shell.Shell, cfg config.Ap
 			"baseBranch", cfg.BaseBranch)
 	}
 
+	return newRootDirectory, nil
+}
+
+// AddWorktree creates the worktree cfg describes and connects to it as
+// configured, returning its path. When adding fails what was done is rolled
+// back and the path is empty. With a path, the error is opening it in the
+// editor failing, the worktree is there regardless.
+func AddWorktree(git git.Git, conn connector.Connector, shell shell.Shell, cfg config.AppConfig) (string, error) {
+
+	// Validation: Check mode and branch existence constraints
+	if err := validateAddMode(cfg); err != nil {
+		return "", err
+	}
+
+	if cfg.UseFormToSetBaseBranch && cfg.AddRef == "" {
+		worktrees, err := git.ListWorktrees(cfg.BareRepoPath)
+		if err != nil {
+			return "", err
+		}
+
+		worktreeObjects := transformer.TransformWorktrees(worktrees)
+
+		util.SortWorktreesByModTime(worktreeObjects)
+
+		var branchStrings []string
+
+		for _, wt := range worktreeObjects {
+			if wt.Detached {
+				continue
+			}
+			branchStrings = append(branchStrings, wt.BranchName)
+		}
+
+		form := form.NewHuhForm()
+
+		selectedBranch := handleFromForm(*form, branchStrings)
+		cfg.BaseBranch = selectedBranch
+		log.Debug(fmt.Sprintf("Set BaseBranch = %s from form selection", selectedBranch))
+
+		// Update the BaseBranchExists flags after selection
+		refs, err := git.GetRefSnapshot(cfg.BareRepoPath, "")
+		if err != nil {
+			return "", err
+		}
+		cfg.BaseBranchExistsLocally = refs.HasLocal(cfg.BaseBranch)
+		log.Debug(fmt.Sprintf("Updated BaseBranchExistsLocally = %t after form selection", cfg.BaseBranchExistsLocally))
+
+		cfg.BaseBranchExistsRemotely = refs.HasRemote(cfg.BaseBranch)
+		log.Debug(fmt.Sprintf("Updated BaseBranchExistsRemotely = %t after form selection", cfg.BaseBranchExistsRemotely))
+	}
+
+	// Fetch the latest state of base branch if pull flag is set
+	if cfg.PullBeforeCuttingNewBranch && cfg.BaseBranchExistsRemotely && cfg.AddRef == "" {
+		if err := git.Fetch(cfg.BareRepoPath, cfg.BaseBranch); err != nil {
+			return "", err
+		}
+		log.Debug(fmt.Sprintf("Fetched latest state of %s from remote", cfg.BaseBranch))
+	}
+
+	var carried *carry
+	if cfg.Carry {
+		from, err := GetCurrentWorktreePath(git)
+		if err != nil {
+			return "", fmt.Errorf("--carry moves the changes of the worktree you are in: %w", err)
+		}
+		carried = newCarry(git, from)
+	}
+
+	// Everything from here on is undone if a step fails or the user hits
+	// Ctrl-C, unless --keep-on-failure is set
+	ctx, stop := interruptContext(context.Background())
+	defer stop()
+	tx := NewTransaction(cfg.KeepOnFailure)
+
+	if carried != nil {
+		if err := carried.stash(tx, cfg.NewWorktreeName); err != nil {
+			return "", tx.Rollback(err)
+		}
+	}
+
+	newRootDirectory, err := createWorktree(git, cfg, tx)
+	if err == nil && carried != nil {
+		err = carried.pop(tx, newRootDirectory)
+	}
+	if err == nil {
+		err = checkInterrupted(ctx)
+	}
+	if err != nil {
+		return "", tx.Rollback(err)
+	}
+
+	zoxide := adapters.NewZoxide(shell)
+	RegisterWithZoxide(zoxide, newRootDirectory, cfg.ZoxideFolders, directoryReader.NewDirectoryReader())
+	tx.OnRollback("remove "+newRootDirectory+" from zoxide", func() error {
+		ForgetInZoxide(zoxide, newRootDirectory)
+		return nil
+	})
+
 	if cfg.TmuxConnect != "" {
+		tmux := adapters.NewTmux(shell)
+		tx.OnRollback("kill tmux sessions in "+newRootDirectory, func() error {
+			return killSessionsIn(tmux, newRootDirectory)
+		})
+
 		connectPath := newRootDirectory
 		if cfg.TmuxConnect != "." {
 			connectPath = newRootDirectory + "/" + cfg.TmuxConnect
 		}
 		opts := models.ConnectOpts{Switch: false}
-		if err := connector.ConnectWithConfig(connectPath, opts, cfg.PostScriptPath, cfg.RunPostScript); err != nil {
+		err := conn.ConnectWithConfig(connectPath, opts, cfg.PostScriptPath, cfg.RunPostScript)
+		if err != nil && !errors.Is(err, connector.ErrPostScript) {
 			log.Warn("Subdirectory not found, connecting to root instead", "subdirectory", cfg.TmuxConnect)
-			if err := connector.ConnectWithConfig(newRootDirectory, opts, cfg.PostScriptPath, cfg.RunPostScript); err != nil {
-				log.Error("Failed to connect to tmux session", "error", err)
-			}
+			err = conn.ConnectWithConfig(newRootDirectory, opts, cfg.PostScriptPath, cfg.RunPostScript)
+		}
+		if errors.Is(err, connector.ErrPostScript) {
+			return "", tx.Rollback(err)
+		} else if err != nil {
+			log.Error("Failed to connect to tmux session", "error", err)
 		}
-	}
-
-	if cfg.CursorConnect {
-		connector.CursorConnect(newRootDirectory)
-	}
-
-	if cfg.VsCodeConnect {
-		connector.VsCodeConnect(newRootDirectory)
 	}
 
 	// Post-script execution is handled by ConnectWithConfig when using tmux connect flag
 	// If not connecting to a new session, run the script in the current context
-	if cfg.RunPostScript && cfg.TmuxConnect == "" && !cfg.CursorConnect && !cfg.VsCodeConnect {
+	if cfg.RunPostScript && cfg.TmuxConnect == "" && cfg.OpenEditor == "" {
 		log.Info("Running post script in current session")
 		script := cfg.PostScriptPath
 		// Expand tilde in script path
@@ -266,7 +489,41 @@ func AddWorktree(connector connector.Connector, shell shell.Shell, cfg config.Ap
 		}
 		// Run the script in a subshell so the user stays in their current directory
 		command := fmt.Sprintf("(cd %s && sh %s)", newRootDirectory, expandedPath)
-		shell.Cmd("tmux", "send-keys", "-t", ".", command, "Enter")
+		if _, err := shell.Cmd("tmux", "send-keys", "-t", ".", command, "Enter"); err != nil {
+			return "", tx.Rollback(fmt.Errorf("failed to send post script to the current session: %w", err))
+		}
 		log.Info("Post script command sent to current session")
 	}
+
+	if err := checkInterrupted(ctx); err != nil {
+		return "", tx.Rollback(err)
+	}
+	tx.Commit()
+
+	if cfg.OpenEditor != "" {
+		if err := conn.OpenEditor(cfg.OpenEditor, newRootDirectory); err != nil {
+			return newRootDirectory, fmt.Errorf("failed to open the new worktree in %s: %w", cfg.OpenEditor, err)
+		}
+	}
+
+	return newRootDirectory, nil
+}
+
+// killSessionsIn kills every tmux session started in dir or below it. Used
+// to undo connecting to a worktree that is being rolled back: the worktree
+// is new, so any session there was started by this add.
+func killSessionsIn(tmux adapters.Tmux, dir string) error {
+	sessions, err := tmux.ListSessions()
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.Path != dir && !strings.HasPrefix(session.Path, dir+"/") {
+			continue
+		}
+		if err := tmux.KillSession(session.Name); err != nil {
+			return err
+		}
+	}
+	return nil
 }
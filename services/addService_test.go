@@ -8,8 +8,12 @@ import (
 	"testing"
 
 	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/connector"
 	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/shell"
+	"github.com/garrettkrohn/treekanga/testfixture"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,6 +23,8 @@ func TestAddWorktreeWithPullFlag(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := git.NewGit()
+
 	t.Run("adds worktree from remote base branch with pull flag", func(t *testing.T) {
 		// Create a temporary directory for test repo
 		tempDir, err := os.MkdirTemp("", "treekanga-add-pull-test-*")
@@ -27,14 +33,14 @@ func TestAddWorktreeWithPullFlag(t *testing.T) {
 
 		// Clone a real repo to have remote branches
 		bareRepoPath := filepath.Join(tempDir, "test.git")
-		err = git.CloneBare("https://github.com/octocat/Hello-World.git", bareRepoPath)
+		err = g.CloneBare(testfixture.NewRemote(t, "Hello-World").URL(), bareRepoPath)
 		require.NoError(t, err)
 
-		err = git.ConfigureBare(bareRepoPath)
+		err = g.ConfigureBare(bareRepoPath)
 		require.NoError(t, err)
 
-		// Get the commit hash of origin/master before fetch
-		beforeFetchCommit, err := getCommitHash(bareRepoPath, "origin/master")
+		// Get the commit hash of origin/main before fetch
+		beforeFetchCommit, err := getCommitHash(bareRepoPath, "origin/main")
 		require.NoError(t, err)
 
 		// Simulate the config with pull flag enabled
@@ -43,7 +49,7 @@ func TestAddWorktreeWithPullFlag(t *testing.T) {
 			WorktreeTargetDir:          tempDir,
 			NewBranchName:              "feature/new-branch",
 			NewWorktreeName:            "feature-new-branch",
-			BaseBranch:                 "master",
+			BaseBranch:                 "main",
 			BaseBranchExistsLocally:    false,
 			BaseBranchExistsRemotely:   true,
 			NewBranchExistsLocally:     false,
@@ -52,7 +58,7 @@ func TestAddWorktreeWithPullFlag(t *testing.T) {
 		}
 
 		// Call AddWorktree with connector and shell as nil (not needed for this test)
-		AddWorktree(nil, nil, cfg)
+		AddWorktree(g, nil, nil, cfg)
 
 		// Verify the worktree was created
 		worktreePath := filepath.Join(tempDir, "feature-new-branch")
@@ -60,19 +66,19 @@ func TestAddWorktreeWithPullFlag(t *testing.T) {
 		assert.NoError(t, err, "Worktree directory should exist")
 
 		// Verify the new branch was created
-		branches, err := git.GetLocalBranches(bareRepoPath)
+		branches, err := g.GetLocalBranches(bareRepoPath)
 		require.NoError(t, err)
 		assert.Contains(t, branches, "feature/new-branch", "New branch should exist")
 
-		// Verify the branch was created from origin/master
-		currentBranch, err := git.GetCurrentBranch(worktreePath)
+		// Verify the branch was created from origin/main
+		currentBranch, err := g.GetCurrentBranch(worktreePath)
 		require.NoError(t, err)
 		assert.Equal(t, "feature/new-branch", currentBranch, "Should be on the new branch")
 
-		// Verify commit hash matches what we fetched from origin/master
+		// Verify commit hash matches what we fetched from origin/main
 		newBranchCommit, err := getCommitHash(bareRepoPath, "feature/new-branch")
 		require.NoError(t, err)
-		assert.Equal(t, beforeFetchCommit, newBranchCommit, "New branch should be cut from origin/master")
+		assert.Equal(t, beforeFetchCommit, newBranchCommit, "New branch should be cut from origin/main")
 	})
 
 	t.Run("does not fetch when pull flag is false", func(t *testing.T) {
@@ -83,14 +89,14 @@ func TestAddWorktreeWithPullFlag(t *testing.T) {
 
 		// Clone a real repo
 		bareRepoPath := filepath.Join(tempDir, "test.git")
-		err = git.CloneBare("https://github.com/octocat/Hello-World.git", bareRepoPath)
+		err = g.CloneBare(testfixture.NewRemote(t, "Hello-World").URL(), bareRepoPath)
 		require.NoError(t, err)
 
-		err = git.ConfigureBare(bareRepoPath)
+		err = g.ConfigureBare(bareRepoPath)
 		require.NoError(t, err)
 
 		// Create a local base branch to work from
-		err = git.AddWorktree(bareRepoPath, tempDir, "base-worktree", []string{"-b", "local-base", "origin/master", "--no-track"})
+		err = g.AddWorktree(bareRepoPath, tempDir, "base-worktree", []string{"-b", "local-base", "origin/main", "--no-track"})
 		require.NoError(t, err)
 
 		// Simulate the config WITHOUT pull flag
@@ -108,7 +114,7 @@ func TestAddWorktreeWithPullFlag(t *testing.T) {
 		}
 
 		// Call AddWorktree
-		AddWorktree(nil, nil, cfg)
+		AddWorktree(g, nil, nil, cfg)
 
 		// Verify the worktree was created
 		worktreePath := filepath.Join(tempDir, "feature-no-pull")
@@ -116,18 +122,82 @@ func TestAddWorktreeWithPullFlag(t *testing.T) {
 		assert.NoError(t, err, "Worktree directory should exist")
 
 		// Verify the new branch exists
-		branches, err := git.GetLocalBranches(bareRepoPath)
+		branches, err := g.GetLocalBranches(bareRepoPath)
 		require.NoError(t, err)
 		assert.Contains(t, branches, "feature/no-pull", "New branch should exist")
 	})
 }
 
+func TestAddWorktreeOpenFails(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := testfixture.NewEnv(t)
+	bareRepoPath := env.Clone(testfixture.NewRemote(t, "widget"), "widget")
+	cfg := config.AppConfig{
+		BareRepoPath:             bareRepoPath,
+		WorktreeTargetDir:        env.Home,
+		NewBranchName:            "feature/editor",
+		NewWorktreeName:          "feature-editor",
+		BaseBranch:               testfixture.DefaultBranch,
+		BaseBranchExistsRemotely: true,
+		OpenEditor:               "zed",
+	}
+	worktreePath := filepath.Join(env.Home, "feature-editor")
+
+	conn := connector.NewMockConnector(t)
+	conn.EXPECT().OpenEditor("zed", worktreePath).Return(assert.AnError)
+
+	path, err := AddWorktree(git.NewGit(), conn, nil, cfg)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, worktreePath, path)
+	assert.DirExists(t, worktreePath, "the worktree stays when the editor fails")
+}
+
+func TestAddWorktreeRollsBack(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := testfixture.NewEnv(t)
+	bareRepoPath := env.Clone(testfixture.NewRemote(t, "widget"), "widget")
+	cfg := config.AppConfig{
+		BareRepoPath:             bareRepoPath,
+		WorktreeTargetDir:        env.Home,
+		NewBranchName:            "feature/script",
+		NewWorktreeName:          "feature-script",
+		BaseBranch:               testfixture.DefaultBranch,
+		BaseBranchExistsRemotely: true,
+		RunPostScript:            true,
+		PostScriptPath:           "setup.sh",
+	}
+	worktreePath := filepath.Join(env.Home, "feature-script")
+
+	// Only git on the PATH, so zoxide doesn't go through the shell
+	gitPath, err := exec.LookPath("git")
+	require.NoError(t, err)
+	bin := t.TempDir()
+	require.NoError(t, os.Symlink(gitPath, filepath.Join(bin, "git")))
+	t.Setenv("PATH", bin)
+
+	sh := shell.NewMockShell(t)
+	sh.EXPECT().Cmd("tmux", "send-keys", "-t", ".", mock.Anything, "Enter").Return("", assert.AnError)
+
+	path, err := AddWorktree(git.NewGit(), nil, sh, cfg)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, path)
+	assert.NoDirExists(t, worktreePath, "the failed add is rolled back")
+}
+
 func TestSetConfigForAddServiceFetchesRemoteBranchForCheckoutRemote(t *testing.T) {
 	// Skip if running in CI without git
 	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
 		t.Skip("Skipping integration test")
 	}
 
+	g := git.NewGit()
+
 	// setupOriginAndBareClone creates a local "origin" repo with an initial
 	// commit on main, then bare-clones it the way treekanga does. Returns
 	// paths to both so a test can push further commits/branches to origin
@@ -145,9 +215,9 @@ func TestSetConfigForAddServiceFetchesRemoteBranchForCheckoutRemote(t *testing.T
 		require.NoError(t, err)
 
 		bareRepoPath = filepath.Join(tempDir, "test.git")
-		err = git.CloneBare(originPath, bareRepoPath)
+		err = g.CloneBare(originPath, bareRepoPath)
 		require.NoError(t, err)
-		err = git.ConfigureBare(bareRepoPath)
+		err = g.ConfigureBare(bareRepoPath)
 		require.NoError(t, err)
 
 		return originPath, bareRepoPath
@@ -171,7 +241,7 @@ func TestSetConfigForAddServiceFetchesRemoteBranchForCheckoutRemote(t *testing.T
 		require.NoError(t, err)
 
 		// Sanity check: the bare repo's cached remote-tracking refs don't know about it yet.
-		cachedBranches, err := git.GetRemoteBranches(bareRepoPath)
+		cachedBranches, err := g.GetRemoteBranches(bareRepoPath)
 		require.NoError(t, err)
 		assert.NotContains(t, cachedBranches, "feature/late-push", "branch should not be visible without a fetch")
 
@@ -180,7 +250,7 @@ func TestSetConfigForAddServiceFetchesRemoteBranchForCheckoutRemote(t *testing.T
 			CheckoutRemote: true,
 		}
 
-		cfg = SetConfigForAddService(cfg, []string{"feature/late-push"})
+		cfg = SetConfigForAddService(g, cfg, []string{"feature/late-push"})
 
 		assert.True(t, cfg.NewBranchExistsRemotely, "branch pushed after last fetch should be found once --remote triggers a fetch")
 	})
@@ -197,7 +267,7 @@ func TestSetConfigForAddServiceFetchesRemoteBranchForCheckoutRemote(t *testing.T
 			CheckoutRemote: true,
 		}
 
-		cfg = SetConfigForAddService(cfg, []string{"does-not-exist-anywhere"})
+		cfg = SetConfigForAddService(g, cfg, []string{"does-not-exist-anywhere"})
 
 		assert.False(t, cfg.NewBranchExistsRemotely, "nonexistent branch should not be found, and the failed targeted fetch should not crash config setup")
 	})
@@ -225,13 +295,39 @@ func TestSetConfigForAddServiceFetchesRemoteBranchForCheckoutRemote(t *testing.T
 			BaseBranch:     "main",
 		}
 
-		cfg = SetConfigForAddService(cfg, []string{"feature/late-push"})
+		cfg = SetConfigForAddService(g, cfg, []string{"feature/late-push"})
 
 		assert.False(t, cfg.NewBranchExistsRemotely, "branch pushed after last fetch should stay hidden when --remote isn't used, since no fetch should be triggered")
 	})
 }
 
 func TestGetAddWorktreeArguments(t *testing.T) {
+	t.Run("detach checks out the ref without a branch", func(t *testing.T) {
+		params := AddWorktreeConfig{
+			BaseBranch:              "main",
+			BaseBranchExistsLocally: true,
+			Ref:                     "v2.3.1",
+			Detach:                  true,
+		}
+
+		args := GetAddWorktreeArguements(params)
+		assert.Equal(t, []string{"--detach", "v2.3.1"}, args)
+	})
+
+	t.Run("ref without detach cuts the new branch at the ref", func(t *testing.T) {
+		params := AddWorktreeConfig{
+			NewBranchName:              "hotfix-2.3",
+			BaseBranch:                 "main",
+			BaseBranchExistsLocally:    true,
+			BaseBranchExistsRemotely:   true,
+			PullBeforeCuttingNewBranch: true,
+			Ref:                        "a1c4d34",
+		}
+
+		args := GetAddWorktreeArguements(params)
+		assert.Equal(t, []string{"-b", "hotfix-2.3", "--no-track", "a1c4d34"}, args)
+	})
+
 	t.Run("default mode creates from local branch when pull flag is false", func(t *testing.T) {
 		params := AddWorktreeConfig{
 			BareRepoPath:               "/test/path",
@@ -351,6 +447,168 @@ func TestGetAddWorktreeArguments(t *testing.T) {
 	})
 }
 
+func TestResolveNewBranchName(t *testing.T) {
+	t.Run("defaults keep the branch and dash the folder", func(t *testing.T) {
+		cfg, err := ResolveNewBranchName(config.AppConfig{WorktreeTargetDir: "/code/treekanga_work"}, "feature/login")
+		require.NoError(t, err)
+		assert.Equal(t, "feature/login", cfg.NewBranchName)
+		assert.Equal(t, "feature-login", cfg.NewWorktreeName)
+	})
+
+	t.Run("templates apply to new branches", func(t *testing.T) {
+		t.Setenv("USER", "gk")
+		cfg := config.AppConfig{
+			WorktreeTargetDir:    "/code/treekanga_work",
+			BranchNameTemplate:   "{{.User}}/{{.Name}}",
+			WorktreeNameTemplate: "{{.Repo}}-{{.Branch}}",
+		}
+
+		cfg, err := ResolveNewBranchName(cfg, "login")
+		require.NoError(t, err)
+		assert.Equal(t, "gk/login", cfg.NewBranchName)
+		assert.Equal(t, "treekanga-gk-login", cfg.NewWorktreeName)
+	})
+
+	t.Run("existing branches skip the template and rules", func(t *testing.T) {
+		cfg := config.AppConfig{
+			BranchNameTemplate:         "{{.User}}/{{.Name}}",
+			BranchNameRequiredPrefixes: []string{"feature/"},
+			CheckoutRemote:             true,
+		}
+
+		cfg, err := ResolveNewBranchName(cfg, "main")
+		require.NoError(t, err)
+		assert.Equal(t, "main", cfg.NewBranchName)
+	})
+
+	t.Run("branchNamePattern is enforced", func(t *testing.T) {
+		cfg := config.AppConfig{BranchNameRequiredPrefixes: []string{"feature/", "bugfix/"}}
+
+		_, err := ResolveNewBranchName(cfg, "login")
+		assert.ErrorContains(t, err, "must start with one of: feature/, bugfix/")
+	})
+}
+
+func TestSetConfigForAddServiceBranchResolution(t *testing.T) {
+	t.Run("new branch records where the base branch exists", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetRefSnapshot("/bare", "").Return(snapshot([]string{"main"}, []string{"main", "develop"}), nil)
+
+		cfg := SetConfigForAddService(g, config.AppConfig{BareRepoPath: "/bare", BaseBranch: "develop"}, []string{"feature/x"})
+		assert.Equal(t, "feature/x", cfg.NewBranchName)
+		assert.Equal(t, "feature-x", cfg.NewWorktreeName)
+		assert.False(t, cfg.NewBranchExistsLocally)
+		assert.False(t, cfg.NewBranchExistsRemotely)
+		assert.False(t, cfg.BaseBranchExistsLocally)
+		assert.True(t, cfg.BaseBranchExistsRemotely)
+	})
+
+	t.Run("--remote fetches the branch and tolerates a failed fetch", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().Fetch("/bare", "feature/x").Return(assert.AnError)
+		g.EXPECT().GetRefSnapshot("/bare", "").Return(snapshot([]string{"main"}, []string{"main", "feature/x"}), nil)
+
+		cfg := SetConfigForAddService(g, config.AppConfig{BareRepoPath: "/bare", BaseBranch: "main", CheckoutRemote: true}, []string{"feature/x"})
+		assert.True(t, cfg.NewBranchExistsRemotely)
+		assert.False(t, cfg.NewBranchExistsLocally)
+	})
+
+	t.Run("--ref fetches an unknown tag before resolving it", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().ResolveCommit("/bare", "v2.3.1").Return("", assert.AnError).Once()
+		g.EXPECT().FetchTag("/bare", "v2.3.1").Return(nil)
+		g.EXPECT().ResolveCommit("/bare", "v2.3.1").Return("a1c4d34", nil).Once()
+
+		cfg := SetConfigForAddService(g, config.AppConfig{BareRepoPath: "/bare", AddRef: "v2.3.1", Detach: true}, nil)
+		assert.Equal(t, "v2.3.1", cfg.NewWorktreeName)
+	})
+}
+
+func TestCreateWorktree(t *testing.T) {
+	cfg := config.AppConfig{
+		BareRepoPath:            "/bare",
+		WorktreeTargetDir:       "/work",
+		BaseBranch:              "main",
+		BaseBranchExistsLocally: true,
+		NewBranchName:           "feature",
+		NewWorktreeName:         "feature",
+	}
+
+	t.Run("new branch sets upstream and base and is deleted on rollback", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().AddWorktree("/bare", "/work", "feature", []string{"-b", "feature", "--no-track", "main"}).Return(nil)
+		g.EXPECT().SetUpstream("/work/feature", "feature").Return(nil)
+		g.EXPECT().SetBranchBase("/work/feature", "feature", "main").Return(nil)
+
+		tx := NewTransaction(false)
+		path, err := createWorktree(g, cfg, tx)
+		require.NoError(t, err)
+		assert.Equal(t, "/work/feature", path)
+
+		g.EXPECT().RemoveWorktree("/bare", "/work/feature", true).Return(nil)
+		g.EXPECT().DeleteBranch("/bare", "feature", true).Return(nil)
+		tx.Rollback(assert.AnError)
+	})
+
+	t.Run("existing local branch keeps the branch on rollback", func(t *testing.T) {
+		local := cfg
+		local.CheckoutLocal = true
+		local.NewBranchExistsLocally = true
+
+		g := git.NewMockGit(t)
+		g.EXPECT().AddWorktree("/bare", "/work", "feature", []string{"feature"}).Return(nil)
+
+		tx := NewTransaction(false)
+		_, err := createWorktree(g, local, tx)
+		require.NoError(t, err)
+
+		g.EXPECT().RemoveWorktree("/bare", "/work/feature", true).Return(nil)
+		tx.Rollback(assert.AnError)
+	})
+
+	t.Run("failing upstream is an error", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().AddWorktree("/bare", "/work", "feature", []string{"-b", "feature", "--no-track", "main"}).Return(nil)
+		g.EXPECT().SetUpstream("/work/feature", "feature").Return(assert.AnError)
+
+		_, err := createWorktree(g, cfg, NewTransaction(false))
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("stacked branch records its parent", func(t *testing.T) {
+		stacked := cfg
+		stacked.BaseBranch = "feature/api"
+		stacked.StackOn = "feature/api"
+
+		g := git.NewMockGit(t)
+		g.EXPECT().AddWorktree("/bare", "/work", "feature", []string{"-b", "feature", "--no-track", "feature/api"}).Return(nil)
+		g.EXPECT().SetUpstream("/work/feature", "feature").Return(nil)
+		g.EXPECT().SetBranchBase("/work/feature", "feature", "feature/api").Return(nil)
+		g.EXPECT().SetBranchParent("/work/feature", "feature", "feature/api").Return(nil)
+
+		_, err := createWorktree(g, stacked, NewTransaction(false))
+		require.NoError(t, err)
+	})
+
+	t.Run("stacking on a branch that isn't local is rejected", func(t *testing.T) {
+		stacked := cfg
+		stacked.BaseBranch = "feature/api"
+		stacked.StackOn = "feature/api"
+		stacked.BaseBranchExistsLocally = false
+
+		_, err := createWorktree(git.NewMockGit(t), stacked, NewTransaction(false))
+		assert.ErrorContains(t, err, "isn't a local branch")
+	})
+
+	t.Run("existing branch is rejected before git runs", func(t *testing.T) {
+		existing := cfg
+		existing.NewBranchExistsRemotely = true
+
+		_, err := createWorktree(git.NewMockGit(t), existing, NewTransaction(false))
+		assert.ErrorContains(t, err, "already exists")
+	})
+}
+
 // Helper function to get commit hash for a branch
 func getCommitHash(bareRepoPath, ref string) (string, error) {
 	output, err := runCommandOutput("git", "-C", bareRepoPath, "rev-parse", ref)
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/adapters"
+	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/directoryReader"
+	"github.com/garrettkrohn/treekanga/git"
+)
+
+// DefaultBatchAddConcurrency is how many worktrees a batch add creates at once.
+const DefaultBatchAddConcurrency = 4
+
+// BatchAddEntry is one worktree to create in a batch add. An empty Base uses
+// the configured base branch.
+type BatchAddEntry struct {
+	Branch string
+	Base   string
+}
+
+// BatchAddState is the progress of a single entry in a batch add.
+type BatchAddState int
+
+const (
+	BatchAddPending BatchAddState = iota
+	BatchAddRunning
+	BatchAddCreated
+	BatchAddFailed
+)
+
+// BatchAddResult reports what happened to one entry of a batch add.
+type BatchAddResult struct {
+	Entry BatchAddEntry
+	State BatchAddState
+	Path  string // path of the new worktree, set once created
+	Err   error  // why the entry failed, set when State is BatchAddFailed
+}
+
+// ParseBatchAddFile reads a --from-file list: one "branch [base]" per line.
+// Blank lines and lines starting with # are ignored.
+func ParseBatchAddFile(r io.Reader) ([]BatchAddEntry, error) {
+	var entries []BatchAddEntry
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: expected 'branch [base]', got %q", lineNumber, line)
+		}
+
+		entry := BatchAddEntry{Branch: fields[0]}
+		if len(fields) == 2 {
+			entry.Base = fields[1]
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// BatchAddEntriesFromArgs turns branch arguments into entries on the
+// configured base branch.
+func BatchAddEntriesFromArgs(args []string) []BatchAddEntry {
+	entries := make([]BatchAddEntry, len(args))
+	for i, arg := range args {
+		entries[i] = BatchAddEntry{Branch: arg}
+	}
+	return entries
+}
+
+// AddWorktrees creates a worktree for every entry. Branches are listed and
+// fetched once up front, then up to concurrency worktrees are created at a
+// time. A failing entry is rolled back and doesn't stop the others;
+// onUpdate is called, one call at a time, whenever an entry changes state
+// and the final results are returned in entry order. The created worktrees
+// are added to zoxide when it is installed. Cancelling ctx, like Ctrl-C does,
+// rolls back the entries being created and skips the ones not started.
+func AddWorktrees(ctx context.Context, git git.Git, zoxide adapters.Zoxide, cfg config.AppConfig, entries []BatchAddEntry, concurrency int, onUpdate func(index int, result BatchAddResult)) []BatchAddResult {
+	if concurrency < 1 {
+		concurrency = DefaultBatchAddConcurrency
+	}
+	if onUpdate == nil {
+		onUpdate = func(int, BatchAddResult) {}
+	}
+
+	results := make([]BatchAddResult, len(entries))
+	for i, entry := range entries {
+		results[i] = BatchAddResult{Entry: entry, State: BatchAddPending}
+	}
+
+	var mu sync.Mutex
+	update := func(i int, state BatchAddState, path string, err error) {
+		mu.Lock()
+		results[i].State = state
+		results[i].Path = path
+		results[i].Err = err
+		onUpdate(i, results[i])
+		mu.Unlock()
+	}
+
+	fetchBatchBranches(git, cfg, entries)
+
+	refs, err := git.GetRefSnapshot(cfg.BareRepoPath, "")
+	if err != nil {
+		for i := range entries {
+			update(i, BatchAddFailed, "", err)
+		}
+		return results
+	}
+
+	configs := make([]config.AppConfig, len(entries))
+	seen := map[string]bool{}
+	for i, entry := range entries {
+		entryCfg, err := prepareBatchEntry(cfg, entry, refs)
+		if err == nil && seen[entryCfg.NewBranchName] {
+			err = fmt.Errorf("branch '%s' is listed more than once", entryCfg.NewBranchName)
+		}
+		if err != nil {
+			update(i, BatchAddFailed, "", err)
+			continue
+		}
+		seen[entryCfg.NewBranchName] = true
+		configs[i] = entryCfg
+	}
+
+	// An entry that fails or is interrupted is rolled back on its own, the
+	// worktrees that were already created are kept
+	ctx, stop := interruptContext(ctx)
+	defer stop()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := range entries {
+		if results[i].State == BatchAddFailed {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			// entries that haven't started when Ctrl-C is hit aren't created
+			if err := checkInterrupted(ctx); err != nil {
+				update(i, BatchAddFailed, "", err)
+				return
+			}
+
+			update(i, BatchAddRunning, "", nil)
+			tx := NewTransaction(cfg.KeepOnFailure)
+			path, err := createWorktree(git, configs[i], tx)
+			if err == nil {
+				err = checkInterrupted(ctx)
+			}
+			if err != nil {
+				log.Debug("Failed to add worktree", "branch", entries[i].Branch, "error", err)
+				update(i, BatchAddFailed, "", tx.Rollback(err))
+				return
+			}
+			tx.Commit()
+			update(i, BatchAddCreated, path, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	// one at a time, zoxide rewrites its whole database on every add
+	for _, result := range results {
+		if result.State == BatchAddCreated {
+			RegisterWithZoxide(zoxide, result.Path, cfg.ZoxideFolders, directoryReader.NewDirectoryReader())
+		}
+	}
+
+	return results
+}
+
+// prepareBatchEntry builds the add config for a single entry, the batch
+// equivalent of SetConfigForAddService.
+func prepareBatchEntry(cfg config.AppConfig, entry BatchAddEntry, refs *git.RefSnapshot) (config.AppConfig, error) {
+	if entry.Base != "" {
+		cfg.BaseBranch = entry.Base
+	}
+
+	cfg, err := ResolveNewBranchName(cfg, entry.Branch)
+	if err != nil {
+		return cfg, err
+	}
+	cfg = applyBranchExistence(cfg, refs)
+
+	newFromBase := !cfg.CheckoutRemote && !cfg.CheckoutLocal && cfg.AddRef == ""
+	if newFromBase && !cfg.BaseBranchExistsLocally && !cfg.BaseBranchExistsRemotely {
+		return cfg, fmt.Errorf("base branch '%s' not found locally or on remote", cfg.BaseBranch)
+	}
+	return cfg, nil
+}
+
+// fetchBatchBranches fetches everything the batch needs from the remote in
+// one go: the branches themselves for --remote, the base branches for
+// --pull. If the combined fetch fails, e.g. because one branch doesn't
+// exist, each branch is fetched on its own so the others are still fresh.
+func fetchBatchBranches(git git.Git, cfg config.AppConfig, entries []BatchAddEntry) {
+	var branches []string
+	for _, entry := range entries {
+		branch := ""
+		if cfg.CheckoutRemote {
+			branch = entry.Branch
+		} else if cfg.PullBeforeCuttingNewBranch {
+			branch = entry.Base
+			if branch == "" {
+				branch = cfg.BaseBranch
+			}
+		}
+		if branch != "" && !slices.Contains(branches, branch) {
+			branches = append(branches, branch)
+		}
+	}
+	if len(branches) == 0 {
+		return
+	}
+
+	err := git.FetchBranches(cfg.BareRepoPath, branches)
+	if err == nil {
+		return
+	}
+	// a timeout means the remote isn't answering, fetching each branch
+	// would only time out again
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Warn("Fetch timed out, using cached remote-tracking refs", "error", err)
+		return
+	}
+	log.Debug("Combined fetch failed, fetching branches one at a time", "error", err)
+
+	for _, branch := range branches {
+		if err := git.Fetch(cfg.BareRepoPath, branch); err != nil {
+			log.Debug("Failed to fetch branch, falling back to cached remote-tracking refs", "branch", branch, "error", err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBatchAddFile(t *testing.T) {
+	t.Run("branches with optional base", func(t *testing.T) {
+		input := `feature/login
+# sprint 12
+feature/signup release/2.3
+
+bugfix/crash
+`
+		entries, err := ParseBatchAddFile(strings.NewReader(input))
+		require.NoError(t, err)
+		assert.Equal(t, []BatchAddEntry{
+			{Branch: "feature/login"},
+			{Branch: "feature/signup", Base: "release/2.3"},
+			{Branch: "bugfix/crash"},
+		}, entries)
+	})
+
+	t.Run("too many fields", func(t *testing.T) {
+		_, err := ParseBatchAddFile(strings.NewReader("ok\none two three\n"))
+		assert.ErrorContains(t, err, "line 2")
+	})
+}
+
+func TestAddWorktrees(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	g := git.NewGit()
+
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "source")
+	bareRepoPath := filepath.Join(tempDir, "source.git")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", sourcePath},
+		{"-C", sourcePath, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "initial"},
+		{"clone", "-q", "--bare", sourcePath, bareRepoPath},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	require.NoError(t, g.ConfigureBare(bareRepoPath))
+
+	cfg := config.AppConfig{
+		BareRepoPath:      bareRepoPath,
+		WorktreeTargetDir: filepath.Join(tempDir, "worktrees"),
+		BaseBranch:        "main",
+	}
+	entries := []BatchAddEntry{
+		{Branch: "feature/one"},
+		{Branch: "feature/two", Base: "main"},
+		{Branch: "feature/three", Base: "missing"},
+		{Branch: "feature/one"},
+	}
+
+	var updates int
+	results := AddWorktrees(context.Background(), g, nil, cfg, entries, 2, func(int, BatchAddResult) { updates++ })
+
+	require.Len(t, results, 4)
+	assert.Equal(t, BatchAddCreated, results[0].State)
+	assert.DirExists(t, filepath.Join(tempDir, "worktrees", "feature-one"))
+	assert.Equal(t, BatchAddCreated, results[1].State)
+	assert.DirExists(t, filepath.Join(tempDir, "worktrees", "feature-two"))
+	assert.Equal(t, BatchAddFailed, results[2].State)
+	assert.ErrorContains(t, results[2].Err, "base branch 'missing' not found")
+	assert.Equal(t, BatchAddFailed, results[3].State)
+	assert.ErrorContains(t, results[3].Err, "listed more than once")
+	assert.Equal(t, 6, updates, "created entries report running and created, failed ones just failed")
+
+	// stopped, e.g. by ctrl+c in the progress display
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results = AddWorktrees(ctx, g, nil, cfg, []BatchAddEntry{{Branch: "feature/four"}}, 2, nil)
+	require.Len(t, results, 1)
+	assert.Equal(t, BatchAddFailed, results[0].State)
+	assert.ErrorIs(t, results[0].Err, ErrInterrupted)
+	assert.NoDirExists(t, filepath.Join(tempDir, "worktrees", "feature-four"))
+}
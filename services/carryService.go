@@ -0,0 +1,63 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/garrettkrohn/treekanga/git"
+)
+
+// carry moves the uncommitted changes of the worktree add --carry runs in
+// into the new worktree, through a stash. Both steps register how to put the
+// changes back, so a failed add leaves them where they started.
+type carry struct {
+	git    git.Git
+	from   string
+	commit string // of the stash carrying the changes, "" when there were none
+	popped bool
+}
+
+func newCarry(git git.Git, from string) *carry {
+	return &carry{git: git, from: from}
+}
+
+// stash stashes the changes, untracked files included. Nothing to carry is
+// not an error.
+func (c *carry) stash(tx *Transaction, branch string) error {
+	commit, err := c.git.StashPush(c.from, "treekanga carry to "+branch)
+	if err != nil {
+		return err
+	}
+	c.commit = commit
+	if commit == "" {
+		return nil
+	}
+
+	tx.OnRollback("restore carried changes to "+c.from, func() error {
+		// once popped they are moved back before the worktree is removed
+		if c.popped {
+			return nil
+		}
+		return c.git.PopStash(c.from, c.commit)
+	})
+	return nil
+}
+
+// pop applies the stashed changes in the new worktree and drops the stash.
+func (c *carry) pop(tx *Transaction, to string) error {
+	if c.commit == "" {
+		return nil
+	}
+	if err := c.git.PopStash(to, c.commit); err != nil {
+		return fmt.Errorf("failed to carry changes into %s: %w", to, err)
+	}
+	c.popped = true
+
+	tx.OnRollback("carry changes back to "+c.from, func() error {
+		commit, err := c.git.StashPush(to, "treekanga carry back")
+		if err != nil || commit == "" {
+			return err
+		}
+		return c.git.PopStash(c.from, commit)
+	})
+	return nil
+}
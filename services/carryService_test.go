@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCarry(t *testing.T) {
+	t.Run("moves the changes into the new worktree", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().StashPush("/work/wrong", "treekanga carry to right").Return("a1c4d34", nil)
+		mockGit.EXPECT().PopStash("/work/right", "a1c4d34").Return(nil)
+
+		c := newCarry(mockGit, "/work/wrong")
+		tx := NewTransaction(false)
+		require.NoError(t, c.stash(tx, "right"))
+		require.NoError(t, c.pop(tx, "/work/right"))
+		tx.Commit()
+	})
+
+	t.Run("nothing to carry", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().StashPush("/work/wrong", "treekanga carry to right").Return("", nil)
+
+		c := newCarry(mockGit, "/work/wrong")
+		tx := NewTransaction(false)
+		require.NoError(t, c.stash(tx, "right"))
+		require.NoError(t, c.pop(tx, "/work/right"))
+		tx.Rollback(assert.AnError)
+	})
+
+	t.Run("a failed pop restores the stash where it came from", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().StashPush("/work/wrong", "treekanga carry to right").Return("a1c4d34", nil)
+		mockGit.EXPECT().PopStash("/work/right", "a1c4d34").Return(assert.AnError)
+
+		c := newCarry(mockGit, "/work/wrong")
+		tx := NewTransaction(false)
+		require.NoError(t, c.stash(tx, "right"))
+		assert.ErrorIs(t, c.pop(tx, "/work/right"), assert.AnError)
+
+		mockGit.EXPECT().PopStash("/work/wrong", "a1c4d34").Return(nil)
+		tx.Rollback(assert.AnError)
+	})
+
+	t.Run("a later failure carries the changes back", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().StashPush("/work/wrong", "treekanga carry to right").Return("a1c4d34", nil)
+		mockGit.EXPECT().PopStash("/work/right", "a1c4d34").Return(nil)
+
+		c := newCarry(mockGit, "/work/wrong")
+		tx := NewTransaction(false)
+		require.NoError(t, c.stash(tx, "right"))
+		require.NoError(t, c.pop(tx, "/work/right"))
+
+		mockGit.EXPECT().StashPush("/work/right", "treekanga carry back").Return("b2d5e45", nil)
+		mockGit.EXPECT().PopStash("/work/wrong", "b2d5e45").Return(nil).Once()
+		tx.Rollback(assert.AnError)
+	})
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/shellinit"
+)
+
+// CdWorktree finds the worktree named by branch or folder and asks the shell
+// function to cd into it. It reports false when treekanga wasn't run through
+// the function, so the caller can print the path instead.
+func CdWorktree(git git.Git, bareRepoPath, name string) (models.Worktree, bool, error) {
+	wt, ok := findWorktree(getWorktrees(git, bareRepoPath), name, "")
+	if !ok {
+		return wt, false, fmt.Errorf("no worktree named %s", name)
+	}
+	requested, err := shellinit.RequestCd(wt.FullPath)
+	return wt, requested, err
+}
+
+// workingDir is the current directory with symlinks resolved, as git
+// reports worktree paths. Empty when it can't be found.
+func workingDir() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(cwd); err == nil {
+		return resolved
+	}
+	return cwd
+}
+
+// resolveSymlinks resolves the symlinks in path, like workingDir does, so
+// /var and /private/var on macOS or a symlinked home compare equal. path
+// may be gone already, e.g. a removed worktree, then only its folder is
+// resolved.
+func resolveSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		return filepath.Join(dir, filepath.Base(path))
+	}
+	return path
+}
+
+// followMove asks the shell function to follow a worktree moved from from to
+// to, into the same folder inside it, when cwd (taken before the move) was
+// in it. It reports whether the shell will follow; on a dry run it stays put
+// as nothing was moved.
+func followMove(git git.Git, cwd, from, to string) bool {
+	rel, ok := relativeTo(resolveSymlinks(cwd), resolveSymlinks(from))
+	if !ok || git.Planning() {
+		return false
+	}
+	requested, err := shellinit.RequestCd(filepath.Join(to, rel))
+	if err != nil {
+		log.Warn("Failed to change into the moved worktree", "error", err)
+	}
+	return requested
+}
+
+// leaveRemoved asks the shell function to cd into dir when cwd (taken before
+// the removal) was in one of the removed worktrees, unless it's a dry run.
+func leaveRemoved(git git.Git, cwd string, removed []string, dir string) {
+	cwd = resolveSymlinks(cwd)
+	for _, path := range removed {
+		if _, ok := relativeTo(cwd, resolveSymlinks(path)); !ok {
+			continue
+		}
+		if git.Planning() {
+			return
+		}
+		if _, err := shellinit.RequestCd(dir); err != nil {
+			log.Warn("Failed to change out of the deleted worktree", "error", err)
+		}
+		return
+	}
+}
+
+// relativeTo returns path relative to dir when it is dir or inside it.
+func relativeTo(path, dir string) (string, bool) {
+	if path == "" || dir == "" {
+		return "", false
+	}
+	if path == dir {
+		return ".", true
+	}
+	if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+		return "", false
+	}
+	return strings.TrimPrefix(path, dir+string(filepath.Separator)), true
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/shellinit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCdWorktree(t *testing.T) {
+	root := t.TempDir()
+	login := filepath.Join(root, "feature-login")
+	require.NoError(t, os.Mkdir(login, 0755))
+	cdFile := filepath.Join(t.TempDir(), "cd")
+	t.Setenv(shellinit.CdFileEnv, cdFile)
+
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().ListWorktrees("/code/widget.git").Return([]string{
+		login + "  a1c4d34 [feature/login]",
+	}, nil)
+
+	wt, requested, err := CdWorktree(mockGit, "/code/widget.git", "feature/login")
+	require.NoError(t, err)
+	assert.True(t, requested)
+	assert.Equal(t, login, wt.FullPath)
+	data, err := os.ReadFile(cdFile)
+	require.NoError(t, err)
+	assert.Equal(t, login+"\n", string(data))
+
+	_, _, err = CdWorktree(mockGit, "/code/widget.git", "missing")
+	assert.EqualError(t, err, "no worktree named missing")
+}
+
+func TestFollowMove(t *testing.T) {
+	root := t.TempDir()
+	to := filepath.Join(root, "feature-signin")
+	require.NoError(t, os.MkdirAll(filepath.Join(to, "src"), 0755))
+	from := filepath.Join(root, "feature-login")
+	cdFile := filepath.Join(t.TempDir(), "cd")
+	t.Setenv(shellinit.CdFileEnv, cdFile)
+
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().Planning().Return(false)
+
+	assert.False(t, followMove(mockGit, filepath.Join(root, "feature-login2"), from, to))
+	assert.NoFileExists(t, cdFile)
+
+	assert.True(t, followMove(mockGit, filepath.Join(from, "src"), from, to))
+	data, err := os.ReadFile(cdFile)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(to, "src")+"\n", string(data))
+
+	leaveRemoved(mockGit, filepath.Join(root, "other"), []string{from}, root)
+	data, err = os.ReadFile(cdFile)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(to, "src")+"\n", string(data))
+
+	leaveRemoved(mockGit, from, []string{filepath.Join(root, "x"), from}, root)
+	data, err = os.ReadFile(cdFile)
+	require.NoError(t, err)
+	assert.Equal(t, root+"\n", string(data))
+
+	// The shell's path goes through a symlink, git's doesn't
+	link := filepath.Join(t.TempDir(), "code")
+	require.NoError(t, os.Symlink(root, link))
+	require.NoError(t, os.Remove(cdFile))
+	leaveRemoved(mockGit, filepath.Join(link, "feature-signin", "src"), []string{to}, root)
+	data, err = os.ReadFile(cdFile)
+	require.NoError(t, err)
+	assert.Equal(t, root+"\n", string(data))
+}
+
+func TestFollowMoveDryRun(t *testing.T) {
+	root := t.TempDir()
+	wt := filepath.Join(root, "feature-login")
+	require.NoError(t, os.Mkdir(wt, 0755))
+	cdFile := filepath.Join(t.TempDir(), "cd")
+	t.Setenv(shellinit.CdFileEnv, cdFile)
+
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().Planning().Return(true)
+
+	// Nothing was removed or moved, the worktree is still there
+	leaveRemoved(mockGit, wt, []string{wt}, root)
+	assert.False(t, followMove(mockGit, wt, wt, root))
+	assert.NoFileExists(t, cdFile)
+}
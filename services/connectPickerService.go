@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/adapters"
+	"github.com/garrettkrohn/treekanga/directoryReader"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/naming"
+	"github.com/garrettkrohn/treekanga/transformer"
+	"github.com/garrettkrohn/treekanga/util"
+)
+
+// ConnectCandidate is something connect can be pointed at: a tmux session,
+// a worktree or a folder inside one.
+type ConnectCandidate struct {
+	Session  models.Session   // Name is what's shown and matched, Src where it came from
+	Worktree *models.Worktree // the worktree the candidate is in, nil for sessions elsewhere
+	Repo     string           // the bare repo of Worktree
+}
+
+// Target is what to pass to connect for the candidate: the session name for
+// tmux sessions, the path otherwise.
+func (c ConnectCandidate) Target() string {
+	if c.Session.Src == "tmux" {
+		return c.Session.Name
+	}
+	return c.Session.Path
+}
+
+// ConnectCandidates lists what connect can be pointed at, most likely first:
+// the open tmux sessions in the order given, the worktrees of every repo in
+// bareRepoPaths, most recently modified first, then the zoxideFolders inside
+// the worktrees of the first repo, the current one. When zoxide is installed
+// the worktrees and folders are ranked by their zoxide score instead, the
+// order above breaking ties. A path shows up once, as the first candidate
+// that has it.
+func ConnectCandidates(git git.Git, dirReader directoryReader.DirectoryReader, zoxide adapters.Zoxide, sessions []models.Session, bareRepoPaths, zoxideFolders []string) []ConnectCandidate {
+	var worktrees []models.Worktree
+	repoWorktrees := map[string][]models.Worktree{}
+	repoOf := map[string]string{}
+	for _, bareRepoPath := range bareRepoPaths {
+		worktreeStrings, err := git.ListWorktrees(bareRepoPath)
+		if err != nil {
+			log.Debug("Skipping repo", "repo", bareRepoPath, "error", err)
+			continue
+		}
+		repoWorktrees[bareRepoPath] = transformer.TransformWorktrees(worktreeStrings)
+		worktrees = append(worktrees, repoWorktrees[bareRepoPath]...)
+		for _, wt := range repoWorktrees[bareRepoPath] {
+			repoOf[wt.FullPath] = bareRepoPath
+		}
+	}
+	util.SortWorktreesByModTime(worktrees)
+
+	var candidates []ConnectCandidate
+	seen := map[string]bool{}
+	add := func(candidate ConnectCandidate) {
+		if seen[candidate.Session.Path] {
+			return
+		}
+		seen[candidate.Session.Path] = true
+		if candidate.Worktree != nil {
+			candidate.Repo = repoOf[candidate.Worktree.FullPath]
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	for _, session := range sessions {
+		add(ConnectCandidate{Session: session, Worktree: containingWorktree(worktrees, session.Path)})
+	}
+	opened := len(candidates)
+
+	for _, wt := range worktrees {
+		add(ConnectCandidate{
+			Session:  models.Session{Name: worktreeLabel(wt), Path: wt.FullPath, Src: "worktree"},
+			Worktree: &wt,
+		})
+	}
+
+	if len(bareRepoPaths) > 0 && len(zoxideFolders) > 0 {
+		current := repoWorktrees[bareRepoPaths[0]]
+		util.SortWorktreesByModTime(current)
+		for _, wt := range current {
+			for _, path := range ExpandWorktreesWithZoxideFolders([]models.Worktree{wt}, zoxideFolders, dirReader) {
+				rel, err := filepath.Rel(wt.FullPath, path)
+				if err != nil || rel == "." {
+					continue
+				}
+				add(ConnectCandidate{
+					Session:  models.Session{Name: worktreeLabel(wt) + "/" + filepath.ToSlash(rel), Path: path, Src: "dir"},
+					Worktree: &wt,
+				})
+			}
+		}
+	}
+
+	if scores := zoxideScores(zoxide); len(scores) > 0 {
+		rest := candidates[opened:]
+		sort.SliceStable(rest, func(i, j int) bool {
+			return scores[rest[i].Session.Path] > scores[rest[j].Session.Path]
+		})
+	}
+
+	return candidates
+}
+
+// worktreeLabel names a worktree as repo/branch, so worktrees of different
+// repos on the same branch can be told apart.
+func worktreeLabel(wt models.Worktree) string {
+	return naming.RepoName(filepath.Dir(wt.FullPath)) + "/" + transformer.DisplayBranch(wt)
+}
+
+// containingWorktree returns the worktree path is in, or nil.
+func containingWorktree(worktrees []models.Worktree, path string) *models.Worktree {
+	if wt, ok := findWorktree(worktrees, "", path); ok {
+		return &wt
+	}
+	return nil
+}
+
+// ConnectPreview describes a candidate for the connect picker: where it is
+// and, when it's in a worktree, the status of its branch against its base
+// branch. When none is recorded that's defaultBranch for worktrees of the
+// current repo, bareRepoPath, and the default branch of their own repo for
+// the others.
+func ConnectPreview(git git.Git, candidate ConnectCandidate, bareRepoPath, defaultBranch string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s\n", candidate.Session.Src, candidate.Session.Name)
+	fmt.Fprintf(&b, "path    %s\n", candidate.Session.Path)
+	if candidate.Worktree == nil {
+		return b.String()
+	}
+
+	if candidate.Repo != "" && candidate.Repo != bareRepoPath {
+		branch, err := git.GetDefaultBranch(candidate.Repo)
+		if err != nil {
+			log.Debug("Failed to get default branch", "repo", candidate.Repo, "error", err)
+		}
+		defaultBranch = branch
+	}
+
+	wt := ComputeWorktreeStatus(git, *candidate.Worktree, defaultBranch)
+	status := transformer.WorktreeStatusSymbols(wt)
+	if status == "" {
+		status = "clean"
+	}
+	fmt.Fprintf(&b, "branch  %s\n", transformer.DisplayBranch(wt))
+	if !wt.Detached {
+		fmt.Fprintf(&b, "base    %s\n", wt.BaseBranch)
+	}
+	fmt.Fprintf(&b, "status  %s\n", status)
+	fmt.Fprintf(&b, "commit  %s\n", wt.CommitHash)
+	return b.String()
+}
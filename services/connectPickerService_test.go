@@ -0,0 +1,124 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/directoryReader"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectCandidates(t *testing.T) {
+	root := t.TempDir()
+	widget := filepath.Join(root, "widget_work")
+	for _, dir := range []string{"main/api", "feature-a/api", "feature-a/web"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(widget, dir), 0755))
+	}
+
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().ListWorktrees("/code/widget.git").Return([]string{
+		"/code/widget.git  (bare)",
+		widget + "/main       a1c4d34 [main]",
+		widget + "/feature-a  b2d5e45 [feature/a]",
+	}, nil)
+	mockGit.EXPECT().ListWorktrees("/code/gadget.git").Return([]string{
+		"/code/gadget_work/main  c3e6f56 [main]",
+	}, nil)
+	mockGit.EXPECT().ListWorktrees("/code/broken.git").Return(nil, assert.AnError)
+
+	dirReader := directoryReader.NewMockDirectoryReader(t)
+	dirReader.EXPECT().GetFoldersInDirectory(filepath.Join(widget, "feature-a")).Return([]string{"api", "web"}, nil)
+	dirReader.EXPECT().GetFoldersInDirectory(filepath.Join(widget, "main")).Return([]string{"api"}, nil)
+
+	sessions := []models.Session{
+		{Name: "notes", Path: "/home/me/notes", Src: "tmux"},
+		{Name: "widget-main", Path: widget + "/main", Src: "tmux"},
+	}
+
+	candidates := ConnectCandidates(mockGit, dirReader, nil, sessions,
+		[]string{"/code/widget.git", "/code/gadget.git", "/code/broken.git"}, []string{"*"})
+
+	var names []string
+	for _, c := range candidates {
+		names = append(names, c.Session.Src+" "+c.Session.Name)
+	}
+	// The widget worktrees exist so they're more recent than the gadget one,
+	// the main worktree is listed once, as its session
+	assert.ElementsMatch(t, []string{
+		"tmux notes",
+		"tmux widget-main",
+		"worktree widget/feature/a",
+		"worktree gadget/main",
+		"dir widget/feature/a/api",
+		"dir widget/feature/a/web",
+		"dir widget/main/api",
+	}, names)
+	assert.Equal(t, "tmux notes", names[0])
+	assert.Equal(t, "tmux widget-main", names[1])
+	assert.Equal(t, "worktree gadget/main", names[3])
+
+	assert.Nil(t, candidates[0].Worktree)
+	require.NotNil(t, candidates[1].Worktree)
+	assert.Equal(t, "main", candidates[1].Worktree.BranchName)
+	assert.Equal(t, "widget-main", candidates[1].Target())
+	assert.Equal(t, widget+"/feature-a", candidates[2].Target())
+	assert.Equal(t, "/code/widget.git", candidates[1].Repo)
+	assert.Equal(t, "/code/gadget.git", candidates[3].Repo)
+}
+
+func TestConnectPreview(t *testing.T) {
+	t.Run("shows the path of sessions outside worktrees", func(t *testing.T) {
+		preview := ConnectPreview(git.NewMockGit(t), ConnectCandidate{
+			Session: models.Session{Name: "notes", Path: "/home/me/notes", Src: "tmux"},
+		}, "/code/widget.git", "main")
+
+		assert.Equal(t, "tmux  notes\npath    /home/me/notes\n", preview)
+	})
+
+	t.Run("shows the branch status of worktrees", func(t *testing.T) {
+		wt := models.Worktree{FullPath: "/code/widget_work/feature-a", Folder: "feature-a", BranchName: "feature/a", CommitHash: "b2d5e45"}
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().GetBranchBase(wt.FullPath, "feature/a").Return("develop", nil)
+		mockGit.EXPECT().GetBranchParent(wt.FullPath, "feature/a").Return("", nil)
+		mockGit.EXPECT().GetWorkingTreeStatus(wt.FullPath).Return(false, true, false, nil)
+		mockGit.EXPECT().GetAheadBehind(wt.FullPath, "develop").Return(2, 0, nil)
+		mockGit.EXPECT().GetUpstreamBranch(wt.FullPath).Return("", nil)
+		mockGit.EXPECT().IsMerged(wt.FullPath, "feature/a", "origin/develop").Return(false, nil)
+
+		preview := ConnectPreview(mockGit, ConnectCandidate{
+			Session:  models.Session{Name: "widget/feature/a", Path: wt.FullPath, Src: "worktree"},
+			Worktree: &wt,
+			Repo:     "/code/widget.git",
+		}, "/code/widget.git", "main")
+
+		assert.Contains(t, preview, "branch  feature/a\n")
+		assert.Contains(t, preview, "base    develop\n")
+		assert.Contains(t, preview, "status  * ↑2\n")
+		assert.Contains(t, preview, "commit  b2d5e45\n")
+	})
+
+	t.Run("compares worktrees of other repos against their own default branch", func(t *testing.T) {
+		wt := models.Worktree{FullPath: "/code/gadget_work/feature-b", Folder: "feature-b", BranchName: "feature/b", CommitHash: "c3e6f56"}
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().GetDefaultBranch("/code/gadget.git").Return("trunk", nil)
+		mockGit.EXPECT().GetBranchBase(wt.FullPath, "feature/b").Return("", nil)
+		mockGit.EXPECT().GetBranchParent(wt.FullPath, "feature/b").Return("", nil)
+		mockGit.EXPECT().GetWorkingTreeStatus(wt.FullPath).Return(false, false, false, nil)
+		mockGit.EXPECT().GetAheadBehind(wt.FullPath, "trunk").Return(1, 0, nil)
+		mockGit.EXPECT().GetUpstreamBranch(wt.FullPath).Return("", nil)
+		mockGit.EXPECT().IsMerged(wt.FullPath, "feature/b", "origin/trunk").Return(false, nil)
+
+		preview := ConnectPreview(mockGit, ConnectCandidate{
+			Session:  models.Session{Name: "gadget/feature/b", Path: wt.FullPath, Src: "worktree"},
+			Worktree: &wt,
+			Repo:     "/code/gadget.git",
+		}, "/code/widget.git", "main")
+
+		assert.Contains(t, preview, "base    trunk\n")
+		assert.Contains(t, preview, "status  ↑1\n")
+	})
+}
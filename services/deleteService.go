@@ -3,22 +3,29 @@ package services
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"slices"
 	"sort"
 
 	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/adapters"
 	"github.com/garrettkrohn/treekanga/config"
 	"github.com/garrettkrohn/treekanga/confirmer"
 	"github.com/garrettkrohn/treekanga/filter"
+	"github.com/garrettkrohn/treekanga/forge"
 	"github.com/garrettkrohn/treekanga/form"
 	"github.com/garrettkrohn/treekanga/git"
 	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/transformer"
 	"github.com/garrettkrohn/treekanga/utility"
 )
 
 func DeleteWorktrees(
+	git git.Git,
 	filter filter.Filter,
 	form form.Form,
+	forge forge.Forge,
+	zoxide adapters.Zoxide,
 	listOfBranchesToDeleteFromArgs []string,
 	cfg config.AppConfig) (int, error) {
 
@@ -26,26 +33,34 @@ func DeleteWorktrees(
 	treesToDeleteAreValid := false
 
 	//1. get all worktrees
-	worktrees := getWorktrees(cfg.BareRepoPath)
+	worktrees := getWorktrees(git, cfg.BareRepoPath)
 
 	//2. filter for only worktrees that don't exist on remote
 	if cfg.FilterOnlyStaleBranches {
-		worktrees = filterLocalBranchesOnly(worktrees, filter, cfg.BareRepoPath)
+		worktrees = filterLocalBranchesOnly(git, worktrees, filter, cfg.BareRepoPath)
 		if len(worktrees) == 0 {
 			log.Fatal("All local branches exist on remote")
 		}
 	}
 
-	// get names to display
+	//3. filter for only worktrees whose branch has been merged
+	if cfg.FilterOnlyMerged {
+		worktrees = filterMergedOnly(git, worktrees, forge, cfg)
+		if len(worktrees) == 0 {
+			log.Fatal("No merged branches found")
+		}
+	}
+
+	// get names to display, detached worktrees show as "(detached <ref>)"
 	stringWorktrees := make([]string, len(worktrees))
 	for i, wt := range worktrees {
-		stringWorktrees[i] = wt.BranchName
+		stringWorktrees[i] = transformer.DisplayBranch(wt)
 	}
 
 	// branches can be provided via args or the form
 	if len(listOfBranchesToDeleteFromArgs) > 0 {
 		log.Debug("branch(es) submitted as argument(s)", "branches", listOfBranchesToDeleteFromArgs)
-		treesToDeleteAreValid = validateAllBranchesToDelete(stringWorktrees, listOfBranchesToDeleteFromArgs)
+		treesToDeleteAreValid = validateAllBranchesToDelete(deletableNames(worktrees), listOfBranchesToDeleteFromArgs)
 		if !treesToDeleteAreValid {
 			log.Error("At least one of the branches provided were not valid, please select a branch")
 		} else {
@@ -69,13 +84,20 @@ func DeleteWorktrees(
 	// get list of full paths
 	worktreeFullPaths := getWorktreeFullPaths(selectedWorktreeObj)
 
-	// remove worktrees
-	removeWorktrees(worktreeFullPaths, cfg.ForceDelete, cfg.BareRepoPath)
+	// remove worktrees, leaving the one the shell is in for the bare repo
+	cwd := workingDir()
+	bareRepoPath, err := filepath.Abs(cfg.BareRepoPath)
+	utility.CheckError(err)
+	removeWorktrees(git, worktreeFullPaths, cfg.ForceDelete, cfg.BareRepoPath)
+	leaveRemoved(git, cwd, worktreeFullPaths, bareRepoPath)
+	for _, path := range worktreeFullPaths {
+		ForgetInZoxide(zoxide, path)
+	}
 
 	// delete branches
 	if cfg.DeleteBranch {
 		log.Debug("delete branches flag true")
-		deleteLocalBranches(selectedWorktreeObj, cfg.ForceDelete, cfg.BareRepoPath, confirmer.NewConfirmer())
+		deleteLocalBranches(git, selectedWorktreeObj, cfg.ForceDelete, cfg.BareRepoPath, confirmer.NewConfirmer())
 	}
 
 	return len(selectedWorktreeObj), nil
@@ -91,9 +113,23 @@ func getWorktreeFullPaths(worktrees []models.Worktree) []string {
 
 }
 
-func deleteLocalBranches(selectedWorktreeObj []models.Worktree, forceDelete bool, bareRepoPath string, confirmer confirmer.Confirmer) {
+func deleteLocalBranches(git git.Git, selectedWorktreeObj []models.Worktree, forceDelete bool, bareRepoPath string, confirmer confirmer.Confirmer) {
 	confirm := false
 
+	// detached worktrees have no branch to delete
+	var withBranches []models.Worktree
+	for _, worktreeObj := range selectedWorktreeObj {
+		if worktreeObj.Detached {
+			log.Debug("Skipping branch deletion for detached worktree", "folder", worktreeObj.Folder)
+			continue
+		}
+		withBranches = append(withBranches, worktreeObj)
+	}
+	if len(withBranches) == 0 {
+		return
+	}
+	selectedWorktreeObj = withBranches
+
 	confirmationMessage := "Are you sure you want to delete these branches: "
 
 	for _, worktreeObj := range selectedWorktreeObj {
@@ -126,6 +162,20 @@ func deleteLocalBranches(selectedWorktreeObj []models.Worktree, forceDelete bool
 
 }
 
+// deletableNames lists the names delete accepts as arguments: branch names,
+// plus the label and folder of detached worktrees.
+func deletableNames(worktrees []models.Worktree) []string {
+	var names []string
+	for _, wt := range worktrees {
+		if wt.Detached {
+			names = append(names, transformer.DisplayBranch(wt), wt.Folder)
+			continue
+		}
+		names = append(names, wt.BranchName)
+	}
+	return names
+}
+
 func validateAllBranchesToDelete(stringWorktrees []string, listOfBranchesToDelete []string) bool {
 	for _, branch := range listOfBranchesToDelete {
 		if !slices.Contains(stringWorktrees, branch) {
@@ -135,7 +185,7 @@ func validateAllBranchesToDelete(stringWorktrees []string, listOfBranchesToDelet
 	return true
 }
 
-func removeWorktrees(worktreePaths []string, forceDelete bool, bareRepoPath string) {
+func removeWorktrees(git git.Git, worktreePaths []string, forceDelete bool, bareRepoPath string) {
 	log.Debug("removeWorktrees called", "count", len(worktreePaths))
 
 	for _, worktreePath := range worktreePaths {
@@ -146,18 +196,55 @@ func removeWorktrees(worktreePaths []string, forceDelete bool, bareRepoPath stri
 	}
 }
 
-func filterLocalBranchesOnly(worktrees []models.Worktree,
+func filterLocalBranchesOnly(git git.Git, worktrees []models.Worktree,
 	filter filter.Filter,
 	bareRepoPath string) []models.Worktree {
 
 	log.Info("filtering local branches only")
 
-	branches, err := git.GetRemoteBranches(bareRepoPath)
+	refs, err := git.GetRefSnapshot(bareRepoPath, "")
 	utility.CheckError(err)
-	worktrees = filter.GetBranchNoMatchList(branches, worktrees)
+
+	// A branch whose upstream is gone counts as local only, even if a remote
+	// branch with its name is still around. GetBranchNoMatchList matches
+	// the worktree folder.
+	var onRemote []string
+	for _, wt := range worktrees {
+		if !wt.Detached && refs.OnRemote(wt.BranchName) {
+			onRemote = append(onRemote, wt.Folder)
+		}
+	}
+	worktrees = filter.GetBranchNoMatchList(onRemote, worktrees)
 	return worktrees
 }
 
+// filterMergedOnly keeps worktrees whose branch content is in the base
+// branch (ancestor or squash merge), or whose pull request was merged on
+// the forge, which also covers rebase merges.
+func filterMergedOnly(git git.Git, worktrees []models.Worktree,
+	forge forge.Forge,
+	cfg config.AppConfig) []models.Worktree {
+
+	log.Info("filtering merged branches only")
+
+	worktrees = ComputeAllWorktreeStatuses(git, cfg.BareRepoPath, cfg.BaseBranch, worktrees)
+	if forge != nil {
+		worktrees = ComputeAllWorktreePullRequests(forge, worktrees)
+	}
+
+	var merged []models.Worktree
+	for _, wt := range worktrees {
+		if wt.Merged == models.MergeStatusMerged {
+			log.Debug("branch merged into base branch", "branch", wt.BranchName)
+			merged = append(merged, wt)
+		} else if IsMergedOnForge(wt) {
+			log.Debug("branch merged on forge", "branch", wt.BranchName, "pullRequest", describePullRequest(*wt.PullRequest))
+			merged = append(merged, wt)
+		}
+	}
+	return merged
+}
+
 // TODO: remove dupilcate code here
 // SortWorktreesByModTime sorts worktrees by modification time (most recent first)
 func SortWorktreesByModTime(worktrees []models.Worktree) {
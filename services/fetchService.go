@@ -0,0 +1,59 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/git"
+)
+
+// DefaultFetchConcurrency is how many repos fetch --all-repos fetches at once.
+const DefaultFetchConcurrency = 4
+
+// FetchResult is the outcome of fetching one repo.
+type FetchResult struct {
+	BareRepoPath string
+	FetchedAt    time.Time // zero when the fetch failed
+	Err          error
+}
+
+// FetchRepo fetches every branch of a repo and records when, so status can
+// show how fresh its comparisons are.
+func FetchRepo(git git.Git, bareRepoPath string) (time.Time, error) {
+	if err := git.FetchAll(bareRepoPath); err != nil {
+		return time.Time{}, err
+	}
+
+	fetchedAt := time.Now()
+	if err := git.SetLastFetch(bareRepoPath, fetchedAt); err != nil {
+		log.Debug("Failed to record last fetch", "repo", bareRepoPath, "error", err)
+	}
+	return fetchedAt, nil
+}
+
+// FetchRepos fetches several repos, up to concurrency at a time. Results are
+// in the order of bareRepoPaths; a failing repo doesn't stop the others.
+func FetchRepos(git git.Git, bareRepoPaths []string, concurrency int) []FetchResult {
+	if concurrency < 1 {
+		concurrency = DefaultFetchConcurrency
+	}
+
+	results := make([]FetchResult, len(bareRepoPaths))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, bareRepoPath := range bareRepoPaths {
+		wg.Add(1)
+		go func(i int, bareRepoPath string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			fetchedAt, err := FetchRepo(git, bareRepoPath)
+			results[i] = FetchResult{BareRepoPath: bareRepoPath, FetchedAt: fetchedAt, Err: err}
+		}(i, bareRepoPath)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFetchRepos(t *testing.T) {
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().FetchAll("/code/a.git").Return(nil)
+	mockGit.EXPECT().SetLastFetch("/code/a.git", mock.AnythingOfType("time.Time")).Return(nil)
+	mockGit.EXPECT().FetchAll("/code/b.git").Return(errors.New("could not read from remote"))
+	mockGit.EXPECT().FetchAll("/code/c.git").Return(nil)
+	// failing to record the time doesn't fail the fetch
+	mockGit.EXPECT().SetLastFetch("/code/c.git", mock.AnythingOfType("time.Time")).Return(errors.New("config locked"))
+
+	before := time.Now()
+	results := FetchRepos(mockGit, []string{"/code/a.git", "/code/b.git", "/code/c.git"}, 2)
+
+	assert.Len(t, results, 3)
+	assert.Equal(t, "/code/a.git", results[0].BareRepoPath)
+	assert.NoError(t, results[0].Err)
+	assert.False(t, results[0].FetchedAt.Before(before))
+
+	assert.Equal(t, "/code/b.git", results[1].BareRepoPath)
+	assert.Error(t, results[1].Err)
+	assert.True(t, results[1].FetchedAt.IsZero())
+
+	assert.NoError(t, results[2].Err)
+	assert.False(t, results[2].FetchedAt.IsZero())
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/forge"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/naming"
+	"github.com/garrettkrohn/treekanga/tracker"
+)
+
+// trackerTokenEnv lists the environment variables consulted, in order, when
+// no issueTrackerToken is configured.
+var trackerTokenEnv = map[string][]string{
+	"jira":   {"JIRA_API_TOKEN"},
+	"github": {"GITHUB_TOKEN", "GH_TOKEN"},
+	"linear": {"LINEAR_API_KEY"},
+}
+
+// NewTrackerFromConfig builds the issue tracker configured for the repo.
+func NewTrackerFromConfig(git git.Git, cfg config.AppConfig) (tracker.Tracker, error) {
+	if cfg.IssueTracker == "" {
+		return nil, fmt.Errorf("no issueTracker configured for this repo")
+	}
+
+	token := cfg.IssueTrackerToken
+	for _, env := range trackerTokenEnv[cfg.IssueTracker] {
+		if token != "" {
+			break
+		}
+		token = os.Getenv(env)
+	}
+
+	opts := tracker.Options{
+		Type:    cfg.IssueTracker,
+		BaseURL: cfg.IssueTrackerBaseURL,
+		User:    cfg.IssueTrackerUser,
+		Token:   token,
+	}
+
+	// GitHub issues live in the same repository as the code.
+	if cfg.IssueTracker == "github" {
+		repoSlug := cfg.ForgeRepo
+		if repoSlug == "" {
+			remoteURL, err := git.GetRemoteURL(cfg.BareRepoPath)
+			if err != nil {
+				return nil, err
+			}
+			repoSlug = remoteURL
+		}
+		owner, repo, err := forge.ParseRepoSlug(repoSlug)
+		if err != nil {
+			return nil, err
+		}
+		opts.Owner = owner
+		opts.Repo = repo
+		if opts.BaseURL == "" {
+			opts.BaseURL = cfg.ForgeBaseURL
+		}
+	}
+
+	return tracker.NewTracker(opts)
+}
+
+// BranchNameForIssue resolves an issue key to its title through the tracker
+// and renders the repo's branchTemplate for it.
+func BranchNameForIssue(t tracker.Tracker, cfg config.AppConfig, issueKey string) (string, error) {
+	issue, err := t.GetIssue(issueKey)
+	if errors.Is(err, tracker.ErrNotFound) {
+		return "", fmt.Errorf("issue '%s' not found in %s", issueKey, cfg.IssueTracker)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up issue '%s': %w", issueKey, err)
+	}
+	log.Debug("Resolved issue", "key", issue.Key, "title", issue.Title, "type", issue.Type)
+
+	branchName, err := naming.BranchNameFromIssue(cfg.BranchTemplate, issue, cfg.IssueTypes)
+	if err != nil {
+		return "", err
+	}
+	log.Info("Branch name from issue", "issue", issue.Key, "branch", branchName)
+	return branchName, nil
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/tracker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranchNameForIssue(t *testing.T) {
+	t.Run("renders the branch template", func(t *testing.T) {
+		mockTracker := tracker.NewMockTracker(t)
+		mockTracker.EXPECT().GetIssue("JIRA-123").Return(models.Issue{
+			Key:   "JIRA-123",
+			Title: "Short slug",
+			Type:  "Bug",
+		}, nil)
+
+		cfg := config.AppConfig{IssueTracker: "jira", BranchTemplate: "{{.Type}}/{{.Key}}-{{.Slug}}"}
+		branchName, err := BranchNameForIssue(mockTracker, cfg, "JIRA-123")
+		require.NoError(t, err)
+		assert.Equal(t, "bugfix/JIRA-123-short-slug", branchName)
+
+		cfg.IssueKey = "JIRA-123"
+		cfg, err = ResolveNewBranchName(cfg, branchName)
+		require.NoError(t, err)
+		assert.Equal(t, "bugfix-JIRA-123-short-slug", cfg.NewWorktreeName)
+	})
+
+	t.Run("unknown issue", func(t *testing.T) {
+		mockTracker := tracker.NewMockTracker(t)
+		mockTracker.EXPECT().GetIssue("JIRA-404").Return(models.Issue{}, tracker.ErrNotFound)
+
+		_, err := BranchNameForIssue(mockTracker, config.AppConfig{IssueTracker: "jira"}, "JIRA-404")
+		assert.ErrorContains(t, err, "not found")
+	})
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/adapters"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// MoveResult describes a worktree move.
+type MoveResult struct {
+	Worktree models.Worktree // the worktree as it was before the move
+	NewPath  string
+	Skipped  bool  // it was already where it should go
+	Err      error // why the move failed
+}
+
+// shells are the programs a tmux pane can be running to be sent a cd
+var shells = []string{"bash", "zsh", "fish", "sh", "dash", "ksh"}
+
+// MoveWorktree moves the worktree named by branch or folder to dest. Like
+// mv, when dest is an existing folder the worktree moves into it, keeping
+// its folder name.
+func MoveWorktree(git git.Git, tmux adapters.Tmux, zoxide adapters.Zoxide, bareRepoPath, name, dest string, forceSubmodules bool) (MoveResult, error) {
+	wt, ok := findWorktree(getWorktrees(git, bareRepoPath), name, "")
+	if !ok {
+		return MoveResult{}, fmt.Errorf("no worktree named %s", name)
+	}
+
+	to, err := filepath.Abs(dest)
+	if err != nil {
+		return MoveResult{}, err
+	}
+	if info, err := os.Stat(to); err == nil && info.IsDir() {
+		to = filepath.Join(to, wt.Folder)
+	}
+	if to == wt.FullPath {
+		return MoveResult{}, fmt.Errorf("%s is already in %s", wt.Folder, filepath.Dir(to))
+	}
+
+	result := moveWorktrees(git, tmux, zoxide, bareRepoPath, []models.Worktree{wt}, []string{to}, forceSubmodules)[0]
+	return result, result.Err
+}
+
+// MoveAllWorktrees moves every worktree into dir, keeping their folder
+// names. Worktrees already there are skipped, and a worktree that can't be
+// moved doesn't stop the others.
+func MoveAllWorktrees(git git.Git, tmux adapters.Tmux, zoxide adapters.Zoxide, bareRepoPath, dir string, forceSubmodules bool) ([]MoveResult, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees := getWorktrees(git, bareRepoPath)
+	destinations := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		destinations[i] = filepath.Join(dir, wt.Folder)
+	}
+	return moveWorktrees(git, tmux, zoxide, bareRepoPath, worktrees, destinations, forceSubmodules), nil
+}
+
+// moveWorktrees moves each worktree to its destination, then points zoxide,
+// the tmux panes in it and the shell, when it was in it, at the new place.
+func moveWorktrees(git git.Git, tmux adapters.Tmux, zoxide adapters.Zoxide, bareRepoPath string, worktrees []models.Worktree, destinations []string, forceSubmodules bool) []MoveResult {
+	// Where the shell and the panes are, before the folders move from
+	// under them
+	cwd := workingDir()
+	panes := listPanes(tmux)
+
+	results := make([]MoveResult, len(worktrees))
+	for i, wt := range worktrees {
+		to := destinations[i]
+		results[i] = MoveResult{Worktree: wt, NewPath: to}
+
+		if to == wt.FullPath {
+			results[i].Skipped = true
+			continue
+		}
+		if _, ok := relativeTo(to, wt.FullPath); ok {
+			results[i].Err = fmt.Errorf("can't move %s into itself", wt.Folder)
+			continue
+		}
+		if _, err := os.Lstat(to); err == nil {
+			results[i].Err = fmt.Errorf("%s already exists", to)
+			continue
+		}
+
+		// zoxide only lists folders that exist, so take them before they move
+		zoxideEntries := ZoxideEntries(zoxide, wt.FullPath)
+
+		log.Debug("Moving worktree", "from", wt.FullPath, "to", to)
+		if err := git.MoveWorktree(bareRepoPath, wt.FullPath, to, forceSubmodules); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		MoveInZoxide(zoxide, zoxideEntries, wt.FullPath, to)
+		followInTmux(tmux, panes, wt.FullPath, to)
+		followMove(git, cwd, wt.FullPath, to)
+	}
+	return results
+}
+
+// listPanes lists the tmux panes, or none without tmux.
+func listPanes(tmux adapters.Tmux) []models.Pane {
+	if tmux == nil {
+		return nil
+	}
+	panes, err := tmux.ListPanes()
+	if err != nil {
+		log.Debug("Failed to list tmux panes", "error", err)
+	}
+	return panes
+}
+
+// followInTmux changes the panes that were in a worktree moved from from to
+// to into the same folder in its new place. Only panes at a shell prompt
+// are sent a cd, the others are listed to be changed by hand. The pane
+// treekanga runs in is left to the shell integration.
+func followInTmux(tmux adapters.Tmux, panes []models.Pane, from, to string) {
+	current := os.Getenv("TMUX_PANE")
+	for _, pane := range panes {
+		rel, ok := relativeTo(pane.Path, from)
+		if !ok || pane.ID == current {
+			continue
+		}
+		dir := filepath.Join(to, rel)
+
+		if !slices.Contains(shells, strings.TrimPrefix(pane.Command, "-")) {
+			log.Warn("A tmux pane in the moved worktree is busy, change its directory yourself",
+				"session", pane.Session, "pane", pane.ID, "running", pane.Command, "dir", dir)
+			continue
+		}
+		if err := tmux.ChangeDirectory(pane.ID, dir); err != nil {
+			log.Warn("Failed to change the directory of a tmux pane", "session", pane.Session, "pane", pane.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/adapters"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveWorktree(t *testing.T) {
+	root := t.TempDir()
+	login := filepath.Join(root, "widget_work", "feature-login")
+	api := filepath.Join(root, "widget_work", "feature-api")
+	disk := filepath.Join(root, "disk")
+	require.NoError(t, os.MkdirAll(disk, 0755))
+	t.Setenv("TMUX_PANE", "%9")
+
+	listed := []string{
+		login + "  a1c4d34 [feature/login]",
+		api + "  b2d5e45 [feature/api]",
+	}
+
+	t.Run("moves into an existing folder and follows tmux", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().ListWorktrees("/code/widget.git").Return(listed, nil)
+		mockGit.EXPECT().MoveWorktree("/code/widget.git", login, filepath.Join(disk, "feature-login"), false).Return(nil)
+
+		tmux := adapters.NewMockTmux(t)
+		tmux.EXPECT().ListPanes().Return([]models.Pane{
+			{ID: "%1", Session: "widget-login", Command: "zsh", Path: filepath.Join(login, "src")},
+			{ID: "%2", Session: "widget-login", Command: "nvim", Path: login},
+			{ID: "%3", Session: "widget-api", Command: "bash", Path: api},
+			{ID: "%9", Session: "widget-login", Command: "treekanga", Path: login},
+		}, nil)
+		tmux.EXPECT().ChangeDirectory("%1", filepath.Join(disk, "feature-login", "src")).Return(nil)
+
+		zoxide := adapters.NewMockZoxide(t)
+		zoxide.EXPECT().Available().Return(false)
+
+		result, err := MoveWorktree(mockGit, tmux, zoxide, "/code/widget.git", "feature/login", disk, false)
+		require.NoError(t, err)
+		assert.Equal(t, login, result.Worktree.FullPath)
+		assert.Equal(t, filepath.Join(disk, "feature-login"), result.NewPath)
+	})
+
+	t.Run("refuses to move onto an existing path", func(t *testing.T) {
+		taken := filepath.Join(disk, "taken")
+		require.NoError(t, os.WriteFile(taken, nil, 0644))
+
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().ListWorktrees("/code/widget.git").Return(listed, nil)
+
+		_, err := MoveWorktree(mockGit, nil, nil, "/code/widget.git", "feature-api", taken, false)
+		assert.EqualError(t, err, taken+" already exists")
+
+		_, err = MoveWorktree(mockGit, nil, nil, "/code/widget.git", "feature-api", filepath.Join(api, "nested"), false)
+		assert.EqualError(t, err, "can't move feature-api into itself")
+
+		_, err = MoveWorktree(mockGit, nil, nil, "/code/widget.git", "missing", disk, false)
+		assert.EqualError(t, err, "no worktree named missing")
+	})
+}
+
+func TestMoveAllWorktrees(t *testing.T) {
+	root := t.TempDir()
+	disk := filepath.Join(root, "disk")
+	login := filepath.Join(root, "widget_work", "feature-login")
+
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().ListWorktrees("/code/widget.git").Return([]string{
+		login + "  a1c4d34 [feature/login]",
+		filepath.Join(disk, "feature-api") + "  b2d5e45 [feature/api]",
+		filepath.Join(root, "old", "feature-docs") + "  c3e6f56 (detached HEAD)",
+	}, nil)
+	mockGit.EXPECT().MoveWorktree("/code/widget.git", login, filepath.Join(disk, "feature-login"), true).Return(nil)
+	mockGit.EXPECT().MoveWorktree("/code/widget.git", filepath.Join(root, "old", "feature-docs"), filepath.Join(disk, "feature-docs"), true).
+		Return(assert.AnError)
+
+	results, err := MoveAllWorktrees(mockGit, nil, nil, "/code/widget.git", disk, true)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	byFolder := map[string]MoveResult{}
+	for _, r := range results {
+		byFolder[r.Worktree.Folder] = r
+	}
+	assert.NoError(t, byFolder["feature-login"].Err)
+	assert.True(t, byFolder["feature-api"].Skipped)
+	assert.ErrorIs(t, byFolder["feature-docs"].Err, assert.AnError)
+}
+
+func TestMoveWorktreeZoxide(t *testing.T) {
+	root := t.TempDir()
+	login := filepath.Join(root, "widget_work", "feature-login")
+	require.NoError(t, os.MkdirAll(filepath.Join(login, "src"), 0755))
+	to := filepath.Join(root, "disk", "feature-login")
+
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().ListWorktrees("/code/widget.git").Return([]string{login + "  a1c4d34 [feature/login]"}, nil)
+	mockGit.EXPECT().MoveWorktree("/code/widget.git", login, to, false).RunAndReturn(func(_, from, to string, _ bool) error {
+		require.NoError(t, os.MkdirAll(filepath.Dir(to), 0755))
+		return os.Rename(from, to)
+	})
+
+	// Like zoxide, only the folders that exist are listed
+	known := map[string]float64{login: 12, filepath.Join(login, "src"): 4, filepath.Join(root, "notes"): 20}
+	zoxide := adapters.NewMockZoxide(t)
+	zoxide.EXPECT().Available().Return(true)
+	zoxide.EXPECT().Scores().RunAndReturn(func() (map[string]float64, error) {
+		scores := map[string]float64{}
+		for path, score := range known {
+			if _, err := os.Stat(path); err == nil {
+				scores[path] = score
+			}
+		}
+		return scores, nil
+	})
+	zoxide.EXPECT().Remove(login, filepath.Join(login, "src")).Return(nil)
+	zoxide.EXPECT().AddWithScore(to, 12.0).Return(nil)
+	zoxide.EXPECT().AddWithScore(filepath.Join(to, "src"), 4.0).Return(nil)
+
+	_, err := MoveWorktree(mockGit, nil, zoxide, "/code/widget.git", "feature/login", to, false)
+	require.NoError(t, err)
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/naming"
+)
+
+// branchRules collects the branchNamePattern settings from the config.
+func branchRules(cfg config.AppConfig) naming.BranchRules {
+	return naming.BranchRules{
+		Regex:            cfg.BranchNameRegex,
+		MaxLength:        cfg.BranchNameMaxLength,
+		ForbiddenChars:   cfg.BranchNameForbiddenChars,
+		RequiredPrefixes: cfg.BranchNameRequiredPrefixes,
+	}
+}
+
+// NewBranchName renders branchNameTemplate for a branch about to be created
+// and checks the result against branchNamePattern.
+func NewBranchName(cfg config.AppConfig, name string) (string, error) {
+	branchName, err := naming.ExpandBranchName(cfg.BranchNameTemplate, name)
+	if err != nil {
+		return "", err
+	}
+	if branchName != name {
+		log.Debug("Applied branchNameTemplate", "name", name, "branch", branchName)
+	}
+
+	if err := branchRules(cfg).Validate(branchName); err != nil {
+		return "", err
+	}
+	return branchName, nil
+}
+
+// WorktreeFolderName renders worktreeNameTemplate for a branch.
+func WorktreeFolderName(cfg config.AppConfig, branchName string) (string, error) {
+	folderName, err := naming.WorktreeName(cfg.WorktreeNameTemplate, branchName, naming.RepoName(cfg.WorktreeTargetDir))
+	if err != nil {
+		return "", fmt.Errorf("failed to name worktree for '%s': %w", branchName, err)
+	}
+	return folderName, nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/forge"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// NewForgeFromConfig builds the forge configured for the repo, or returns
+// nil when PR lookups are disabled (no `forge` key in the config).
+func NewForgeFromConfig(git git.Git, cfg config.AppConfig) (forge.Forge, error) {
+	if cfg.ForgeType == "" {
+		return nil, nil
+	}
+
+	repoSlug := cfg.ForgeRepo
+	if repoSlug == "" {
+		remoteURL, err := git.GetRemoteURL(cfg.BareRepoPath)
+		if err != nil {
+			return nil, err
+		}
+		repoSlug = remoteURL
+	}
+	owner, repo, err := forge.ParseRepoSlug(repoSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	token := cfg.ForgeToken
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		token = os.Getenv("GH_TOKEN")
+	}
+
+	return forge.NewForge(forge.Options{
+		Type:    cfg.ForgeType,
+		BaseURL: cfg.ForgeBaseURL,
+		Owner:   owner,
+		Repo:    repo,
+		Token:   token,
+	})
+}
+
+// ComputeWorktreePullRequest looks up the pull request for a worktree's
+// branch. A nil forge marks the worktree as loaded with no pull request.
+func ComputeWorktreePullRequest(f forge.Forge, worktree models.Worktree) models.Worktree {
+	worktree.PullRequestLoaded = true
+	if f == nil || worktree.BranchName == "" {
+		return worktree
+	}
+
+	pr, err := f.GetPullRequest(worktree.BranchName)
+	if err != nil {
+		if !errors.Is(err, forge.ErrNotFound) {
+			log.Debug("Failed to get pull request", "branch", worktree.BranchName, "error", err)
+		}
+		return worktree
+	}
+	worktree.PullRequest = &pr
+	return worktree
+}
+
+// ComputeAllWorktreePullRequests looks up pull requests for every worktree.
+// Intended for the CLI's synchronous -v path.
+func ComputeAllWorktreePullRequests(f forge.Forge, worktrees []models.Worktree) []models.Worktree {
+	result := make([]models.Worktree, len(worktrees))
+	for i, wt := range worktrees {
+		result[i] = ComputeWorktreePullRequest(f, wt)
+	}
+	return result
+}
+
+// IsMergedOnForge reports whether the worktree's pull request was merged.
+// This catches rebase-merged PRs, whose rewritten commits defeat both the
+// ancestor and squash patch-id checks in git.IsMerged.
+func IsMergedOnForge(worktree models.Worktree) bool {
+	return worktree.PullRequest != nil && worktree.PullRequest.State == models.PullRequestStateMerged
+}
+
+// describePullRequest renders a pull request for log output.
+func describePullRequest(pr models.PullRequest) string {
+	return fmt.Sprintf("#%d (%s)", pr.Number, pr.State)
+}
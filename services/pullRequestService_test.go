@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/forge"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeWorktreePullRequest(t *testing.T) {
+	t.Run("attaches the pull request for the branch", func(t *testing.T) {
+		mockForge := forge.NewMockForge(t)
+		mockForge.EXPECT().GetPullRequest("feature/a").Return(models.PullRequest{
+			Number: 12,
+			State:  models.PullRequestStateMerged,
+		}, nil)
+
+		wt := ComputeWorktreePullRequest(mockForge, models.Worktree{BranchName: "feature/a"})
+
+		assert.True(t, wt.PullRequestLoaded)
+		assert.Equal(t, 12, wt.PullRequest.Number)
+		assert.True(t, IsMergedOnForge(wt))
+	})
+
+	t.Run("no pull request for the branch", func(t *testing.T) {
+		mockForge := forge.NewMockForge(t)
+		mockForge.EXPECT().GetPullRequest("feature/b").Return(models.PullRequest{}, forge.ErrNotFound)
+
+		wt := ComputeWorktreePullRequest(mockForge, models.Worktree{BranchName: "feature/b"})
+
+		assert.True(t, wt.PullRequestLoaded)
+		assert.Nil(t, wt.PullRequest)
+		assert.False(t, IsMergedOnForge(wt))
+	})
+
+	t.Run("no forge configured", func(t *testing.T) {
+		wt := ComputeWorktreePullRequest(nil, models.Worktree{BranchName: "feature/c"})
+
+		assert.True(t, wt.PullRequestLoaded)
+		assert.Nil(t, wt.PullRequest)
+	})
+}
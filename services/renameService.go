@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -12,95 +13,114 @@ import (
 	"github.com/garrettkrohn/treekanga/confirmer"
 	"github.com/garrettkrohn/treekanga/config"
 	"github.com/garrettkrohn/treekanga/connector"
-	"github.com/garrettkrohn/treekanga/execwrap"
 	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/naming"
 	"github.com/garrettkrohn/treekanga/shell"
-	"github.com/garrettkrohn/treekanga/util"
-	"github.com/garrettkrohn/treekanga/utility"
+	"github.com/garrettkrohn/treekanga/transformer"
 )
 
-// RenameWorktree renames the current worktree's branch and folder
+// RenameResult describes a renamed worktree.
+type RenameResult struct {
+	OldBranch string
+	NewBranch string
+	OldPath   string
+	NewPath   string
+}
+
+// RenameWorktree renames the branch and folder of the worktree at
+// worktreePath. With renameRemote the branch is renamed on origin as well:
+// the new name is pushed and becomes the upstream, then the old one is
+// deleted.
 func RenameWorktree(
 	cfg config.AppConfig,
 	newBranchName string,
-	currentWorktreePath string,
-	conn connector.Connector,
-	conf confirmer.Confirmer,
-	autoSwitchTmux bool,
+	worktreePath string,
+	git git.Git,
+	sh shell.Shell,
 	forceSubmodules bool,
-) error {
-	log.Debug("Starting worktree rename", "newBranchName", newBranchName)
+	renameRemote bool,
+) (RenameResult, error) {
+	log.Debug("Starting worktree rename", "worktree", worktreePath, "newBranchName", newBranchName)
 
 	// Get current branch
-	currentBranch, err := git.GetCurrentBranch(currentWorktreePath)
+	currentBranch, err := git.GetCurrentBranch(worktreePath)
 	if err != nil {
-		return fmt.Errorf("failed to get current branch: %w", err)
+		return RenameResult{}, fmt.Errorf("failed to get current branch: %w", err)
 	}
 
 	if currentBranch == "" {
-		return fmt.Errorf("not on a branch (detached HEAD state) - cannot rename")
+		return RenameResult{}, fmt.Errorf("not on a branch (detached HEAD state) - cannot rename")
 	}
 
 	log.Debug("Current branch", "branch", currentBranch)
 
 	// Validate new branch doesn't already exist
-	localBranches, err := git.GetLocalBranches(cfg.BareRepoPath)
+	refs, err := git.GetRefSnapshot(cfg.BareRepoPath, "")
 	if err != nil {
-		return fmt.Errorf("failed to get local branches: %w", err)
+		return RenameResult{}, fmt.Errorf("failed to get branches: %w", err)
 	}
 
-	remoteBranches, err := git.GetRemoteBranches(cfg.BareRepoPath)
-	if err != nil {
-		return fmt.Errorf("failed to get remote branches: %w", err)
+	if refs.HasLocal(newBranchName) {
+		return RenameResult{}, fmt.Errorf("branch '%s' already exists locally", newBranchName)
 	}
 
-	if slices.Contains(localBranches, newBranchName) {
-		return fmt.Errorf("branch '%s' already exists locally", newBranchName)
+	if refs.HasRemote(newBranchName) {
+		return RenameResult{}, fmt.Errorf("branch '%s' already exists on remote", newBranchName)
 	}
 
-	if slices.Contains(remoteBranches, newBranchName) {
-		return fmt.Errorf("branch '%s' already exists on remote", newBranchName)
+	// Folder name comes from worktreeNameTemplate, which replaces / with -
+	newFolderName, err := WorktreeFolderName(cfg, newBranchName)
+	if err != nil {
+		return RenameResult{}, err
 	}
-
-	// Sanitize new branch name for folder (replace / with -)
-	newFolderName := strings.ReplaceAll(newBranchName, "/", "-")
 	newWorktreePath := filepath.Join(cfg.WorktreeTargetDir, newFolderName)
 
 	// Check if target folder already exists
 	if _, err := os.Stat(newWorktreePath); err == nil {
-		return fmt.Errorf("target folder '%s' already exists", newWorktreePath)
+		return RenameResult{}, fmt.Errorf("target folder '%s' already exists", newWorktreePath)
 	}
 
 	log.Debug("Renaming branch", "from", currentBranch, "to", newBranchName)
 
-	// Rename the branch
+	// Rename the branch, undone when a later step fails
+	tx := NewTransaction(false)
 	err = git.RenameBranch(cfg.BareRepoPath, currentBranch, newBranchName)
 	if err != nil {
-		return fmt.Errorf("failed to rename branch: %w", err)
+		return RenameResult{}, fmt.Errorf("failed to rename branch: %w", err)
 	}
+	tx.OnRollback("rename branch "+newBranchName+" back to "+currentBranch, func() error {
+		return git.RenameBranch(cfg.BareRepoPath, newBranchName, currentBranch)
+	})
 
-	log.Debug("Moving worktree", "from", currentWorktreePath, "to", newWorktreePath)
+	if err := relinkBranch(git, tx, cfg.BareRepoPath, currentBranch, newBranchName); err != nil {
+		return RenameResult{}, tx.Rollback(fmt.Errorf("failed to update the branches stacked on %s: %w", currentBranch, err))
+	}
+
+	log.Debug("Moving worktree", "from", worktreePath, "to", newWorktreePath)
+
+	// zoxide's entries for the folder, taken while it's still there
+	var zoxide adapters.Zoxide
+	if sh != nil {
+		zoxide = adapters.NewZoxide(sh)
+	}
+	zoxideEntries := ZoxideEntries(zoxide, worktreePath)
 
 	// Move the worktree folder
-	err = git.MoveWorktree(cfg.BareRepoPath, currentWorktreePath, newWorktreePath, forceSubmodules)
+	err = git.MoveWorktree(cfg.BareRepoPath, worktreePath, newWorktreePath, forceSubmodules)
 	if err != nil {
-		// Try to rollback the branch rename
-		rollbackErr := git.RenameBranch(cfg.BareRepoPath, newBranchName, currentBranch)
-		if rollbackErr != nil {
-			log.Error("Failed to rollback branch rename after worktree move failure", "error", rollbackErr)
-		}
-		return fmt.Errorf("failed to move worktree: %w", err)
+		return RenameResult{}, tx.Rollback(fmt.Errorf("failed to move worktree: %w", err))
 	}
+	tx.Commit()
 
-	// Update upstream tracking: point to origin/<newBranch> if it exists, otherwise unset
-	if slices.Contains(remoteBranches, newBranchName) {
-		if upstreamErr := git.SetUpstream(newWorktreePath, newBranchName); upstreamErr != nil {
-			log.Warn("Failed to set upstream after rename", "error", upstreamErr)
-		}
-	} else {
-		if upstreamErr := git.UnsetUpstream(newWorktreePath, newBranchName); upstreamErr != nil {
-			log.Warn("Failed to unset upstream after rename", "error", upstreamErr)
-		}
+	// Point zoxide's entries for the old folder at the new one
+	MoveInZoxide(zoxide, zoxideEntries, worktreePath, newWorktreePath)
+
+	// Pushing the new name makes origin/<newBranch> the upstream, otherwise
+	// the upstream still points at the old name and is unset
+	if renameRemote && renameRemoteBranch(git, cfg.BareRepoPath, newWorktreePath, currentBranch, newBranchName, refs.HasRemote(currentBranch)) {
+		log.Debug("Renamed branch on origin", "from", currentBranch, "to", newBranchName)
+	} else if upstreamErr := git.UnsetUpstream(newWorktreePath, newBranchName); upstreamErr != nil {
+		log.Warn("Failed to unset upstream after rename", "error", upstreamErr)
 	}
 
 	log.Info("Worktree renamed successfully",
@@ -108,155 +128,229 @@ func RenameWorktree(
 		"newBranch", newBranchName,
 		"newPath", newWorktreePath)
 
-	// Handle tmux session rename if user is in tmux
-	handleTmuxSessionRename(newBranchName, newWorktreePath, conn, conf, autoSwitchTmux)
-
-	// Inform user about path change
-	fmt.Printf("\n✓ Worktree renamed successfully!\n")
-	fmt.Printf("  Branch: %s → %s\n", currentBranch, newBranchName)
-	fmt.Printf("  Folder: %s → %s\n", filepath.Base(currentWorktreePath), newFolderName)
-	fmt.Printf("\nNote: Your current directory is now invalid. Navigate to the new location:\n")
-	fmt.Printf("  cd %s\n\n", newWorktreePath)
-
-	return nil
+	return RenameResult{
+		OldBranch: currentBranch,
+		NewBranch: newBranchName,
+		OldPath:   worktreePath,
+		NewPath:   newWorktreePath,
+	}, nil
 }
 
-// GetCurrentWorktreePath returns the current worktree path by calling GetBareRepoPath and resolving
-func GetCurrentWorktreePath() (string, error) {
-	// Get the git common dir (which points to the bare repo or main .git)
-	gitCommonDir, err := git.GetBareRepoPath("")
+// relinkBranch points the branches stacked on or based on oldBranch at
+// newBranch, registering how to point them back. git branch -m only carries
+// the renamed branch's own config along.
+func relinkBranch(git git.Git, tx *Transaction, bareRepoPath, oldBranch, newBranch string) error {
+	parents, err := git.GetBranchParents(bareRepoPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to get git directory: %w", err)
+		return err
+	}
+	for _, branch := range slices.Sorted(maps.Keys(parents)) {
+		if parents[branch] != oldBranch {
+			continue
+		}
+		if err := git.SetBranchParent(bareRepoPath, branch, newBranch); err != nil {
+			return err
+		}
+		tx.OnRollback("stack "+branch+" back on "+oldBranch, func() error {
+			return git.SetBranchParent(bareRepoPath, branch, oldBranch)
+		})
 	}
 
-	// Check if we're in a worktree or bare repo
-	// In a worktree, gitCommonDir will be something like /path/to/bare.git/worktrees/branch-name
-	// We want the actual worktree directory
-	currentDir, err := os.Getwd()
+	bases, err := git.GetBranchBases(bareRepoPath)
 	if err != nil {
-		return "", fmt.Errorf("failed to get current directory: %w", err)
+		return err
 	}
+	for _, branch := range slices.Sorted(maps.Keys(bases)) {
+		if bases[branch] != oldBranch {
+			continue
+		}
+		if err := git.SetBranchBase(bareRepoPath, branch, newBranch); err != nil {
+			return err
+		}
+		tx.OnRollback("set the base of "+branch+" back to "+oldBranch, func() error {
+			return git.SetBranchBase(bareRepoPath, branch, oldBranch)
+		})
+	}
+	return nil
+}
 
-	// If gitCommonDir contains "/worktrees/", we're in a worktree
-	if strings.Contains(gitCommonDir, "/worktrees/") {
-		// We're in a worktree, return the current directory
-		return currentDir, nil
+// renameRemoteBranch pushes newBranch and deletes oldBranch on origin. The
+// local rename is done by now, so failures are only warned about. It
+// reports whether newBranch was pushed.
+func renameRemoteBranch(git git.Git, bareRepoPath, worktreePath, oldBranch, newBranch string, oldOnRemote bool) bool {
+	if !oldOnRemote {
+		log.Info("Branch isn't on origin, only renamed it locally", "branch", oldBranch)
+		return false
 	}
 
-	// Check if we're in a bare repo by seeing if we have a working tree
-	_, err = git.GetCurrentBranch(currentDir)
-	if err != nil {
-		return "", fmt.Errorf("not in a worktree - cannot rename from bare repository")
+	if err := git.PushBranch(worktreePath, newBranch); err != nil {
+		log.Warn("Failed to push the renamed branch, origin still has the old one", "error", err)
+		return false
 	}
 
-	return currentDir, nil
+	if err := git.DeleteRemoteBranch(bareRepoPath, oldBranch); err != nil {
+		log.Warn("Failed to delete the old branch on origin", "branch", oldBranch, "error", err)
+	}
+	return true
 }
 
-// SetConfigForRenameService sets up configuration for rename command
-func SetConfigForRenameService(cfg config.AppConfig) (config.AppConfig, error) {
-	log.Debug("Running configuration for rename command")
-
-	// Get current worktree path
-	currentWorktreePath, err := GetCurrentWorktreePath()
+// GetCurrentWorktreePath returns the root of the worktree the current
+// directory is in, which may be a folder inside it.
+func GetCurrentWorktreePath(git git.Git) (string, error) {
+	// Get the git common dir (which points to the bare repo or main .git)
+	gitCommonDir, err := git.GetBareRepoPath("")
 	if err != nil {
-		return cfg, err
+		return "", fmt.Errorf("failed to get git directory: %w", err)
 	}
 
-	// Determine WorktreeTargetDir from current path
-	cfg.WorktreeTargetDir = filepath.Dir(currentWorktreePath)
-	log.Debug("Set WorktreeTargetDir", "dir", cfg.WorktreeTargetDir)
+	worktrees, err := git.ListWorktrees(gitCommonDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
 
-	return cfg, nil
+	// The bare repo isn't listed with a branch, so it never matches
+	wt, ok := findWorktree(transformer.TransformWorktrees(worktrees), "", workingDir())
+	if !ok {
+		return "", fmt.Errorf("not in a worktree - run this from a worktree, not the bare repository")
+	}
+	return wt.FullPath, nil
 }
 
-// ValidateRenameArgs validates the arguments for rename command
-func ValidateRenameArgs(args []string) (string, error) {
+// ValidateRenameArgs validates the arguments for rename command, either the
+// new branch name or the worktree to rename followed by it. The worktree is
+// empty for the current one.
+func ValidateRenameArgs(args []string) (string, string, error) {
 	if len(args) == 0 {
-		return "", fmt.Errorf("please provide new branch name as an argument")
+		return "", "", fmt.Errorf("please provide new branch name as an argument")
 	}
 
-	if len(args) > 1 {
-		return "", fmt.Errorf("too many arguments - expected 1, got %d", len(args))
+	if len(args) > 2 {
+		return "", "", fmt.Errorf("too many arguments - expected at most 2, got %d", len(args))
 	}
 
-	newBranchName := strings.TrimSpace(args[0])
+	worktree := ""
+	if len(args) == 2 {
+		worktree = strings.TrimSpace(args[0])
+		if worktree == "" {
+			return "", "", fmt.Errorf("worktree name cannot be empty")
+		}
+	}
+
+	newBranchName := strings.TrimSpace(args[len(args)-1])
 	if newBranchName == "" {
-		return "", fmt.Errorf("branch name cannot be empty")
+		return "", "", fmt.Errorf("branch name cannot be empty")
 	}
 
-	return newBranchName, nil
+	return worktree, newBranchName, nil
 }
 
 // ExecuteRename executes the full rename workflow
 func ExecuteRename(
 	cfg config.AppConfig,
 	args []string,
+	git git.Git,
 	conn connector.Connector,
+	sh shell.Shell,
 	conf confirmer.Confirmer,
 	autoSwitchTmux bool,
 	forceSubmodules bool,
+	renameRemote bool,
 ) error {
 	// Validate arguments
-	newBranchName, err := ValidateRenameArgs(args)
+	worktree, newBranchName, err := ValidateRenameArgs(args)
 	if err != nil {
 		return err
 	}
 
-	// Set up configuration
-	cfg, err = SetConfigForRenameService(cfg)
-	if err != nil {
-		return err
+	// Where the shell is, to follow the worktree once it moved
+	cwd := workingDir()
+
+	// The named worktree, or the one we're in
+	var worktreePath string
+	if worktree != "" {
+		wt, ok := findWorktree(getWorktrees(git, cfg.BareRepoPath), worktree, "")
+		if !ok {
+			return fmt.Errorf("no worktree named %s", worktree)
+		}
+		worktreePath = wt.FullPath
+	} else {
+		worktreePath, err = GetCurrentWorktreePath(git)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Get current worktree path
-	currentWorktreePath, err := GetCurrentWorktreePath()
+	// The renamed worktree stays next to the old one
+	cfg.WorktreeTargetDir = filepath.Dir(worktreePath)
+	log.Debug("Set WorktreeTargetDir", "dir", cfg.WorktreeTargetDir)
+
+	// Apply branchNameTemplate and branchNamePattern like add does
+	newBranchName, err = NewBranchName(cfg, newBranchName)
 	if err != nil {
 		return err
 	}
 
 	// Execute rename
-	err = RenameWorktree(cfg, newBranchName, currentWorktreePath, conn, conf, autoSwitchTmux, forceSubmodules)
-	utility.CheckError(err)
+	result, err := RenameWorktree(cfg, newBranchName, worktreePath, git, sh, forceSubmodules, renameRemote)
+	if err != nil {
+		return fmt.Errorf("failed to rename %s: %w", filepath.Base(worktreePath), err)
+	}
 
-	return nil
-}
+	// Handle tmux session rename if user is in tmux, in the renamed worktree
+	_, inWorktree := relativeTo(cwd, worktreePath)
+	if inWorktree {
+		handleTmuxSessionRename(cfg.SessionNameTemplate, result.NewBranch, result.NewPath, conn, sh, conf, autoSwitchTmux)
+	}
 
-// generateSessionName creates a tmux session name for a worktree
-// Format: "repo-branch" (e.g., "treekanga-feature-api-users")
-func generateSessionName(worktreePath, branchName string) string {
-	// Get parent directory as repo name
-	parentDir := filepath.Dir(worktreePath)
-	repoName := filepath.Base(parentDir)
+	// Inform user about path change
+	fmt.Printf("\n✓ Worktree renamed successfully!\n")
+	fmt.Printf("  Branch: %s → %s\n", result.OldBranch, result.NewBranch)
+	fmt.Printf("  Folder: %s → %s\n", filepath.Base(result.OldPath), filepath.Base(result.NewPath))
+	if followMove(git, cwd, result.OldPath, result.NewPath) {
+		fmt.Printf("\nYour shell follows to %s\n\n", result.NewPath)
+	} else if inWorktree {
+		fmt.Printf("\nNote: Your current directory is now invalid. Navigate to the new location:\n")
+		fmt.Printf("  cd %s\n\n", result.NewPath)
+	}
 
-	// Clean up common suffixes
-	repoName = strings.TrimSuffix(repoName, "_work")
-	repoName = strings.TrimSuffix(repoName, "_worktrees")
-	repoName = strings.TrimSuffix(repoName, "-bare")
-	repoName = strings.TrimSuffix(repoName, ".git")
+	return nil
+}
 
-	// Sanitize both parts
-	safeRepoName := util.SanitizeForSessionName(repoName)
-	safeBranchName := util.SanitizeForSessionName(branchName)
+// generateSessionName creates a tmux session name for a worktree from
+// sessionNameTemplate, "repo-branch" by default (e.g., "treekanga-feature-api-users")
+func generateSessionName(sessionNameTemplate, worktreePath, branchName string) string {
+	data := naming.SessionNameData{
+		Repo:   naming.RepoName(filepath.Dir(worktreePath)),
+		Branch: branchName,
+		Folder: filepath.Base(worktreePath),
+	}
 
-	return fmt.Sprintf("%s-%s", safeRepoName, safeBranchName)
+	sessionName, err := naming.SessionName(sessionNameTemplate, data)
+	if err != nil {
+		log.Warn("Falling back to the default session name", "error", err)
+		sessionName, _ = naming.SessionName("", data)
+	}
+	return sessionName
 }
 
 // handleTmuxSessionRename prompts user to close current session and connect to new one
 func handleTmuxSessionRename(
+	sessionNameTemplate string,
 	newBranch string,
 	newWorktreePath string,
 	conn connector.Connector,
+	sh shell.Shell,
 	conf confirmer.Confirmer,
 	autoSwitch bool,
 ) {
-	// Skip if connector is nil (e.g., in tests)
-	if conn == nil {
+	// Skip if connector or shell is nil (e.g., in tests)
+	if conn == nil || sh == nil {
 		log.Debug("Skipping tmux handling (no connector provided)")
 		return
 	}
 
 	// Check if we're in a tmux session
-	tmux := adapters.NewTmux(shell.NewShell(execwrap.NewExec()))
+	tmux := adapters.NewTmux(sh)
 	if !tmux.IsAttached() {
 		log.Debug("Not in a tmux session, skipping tmux handling")
 		return
@@ -270,7 +364,7 @@ func handleTmuxSessionRename(
 	}
 
 	// Generate new session name
-	newSessionName := generateSessionName(newWorktreePath, newBranch)
+	newSessionName := generateSessionName(sessionNameTemplate, newWorktreePath, newBranch)
 
 	log.Info("Currently in tmux session", "current", currentSessionName, "new", newSessionName)
 
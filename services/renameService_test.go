@@ -7,6 +7,7 @@ import (
 
 	"github.com/garrettkrohn/treekanga/config"
 	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/testfixture"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -17,6 +18,8 @@ func TestRenameWorktree(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := git.NewGit()
+
 	t.Run("successful rename with simple branch name", func(t *testing.T) {
 		// Create a temporary directory for test repo
 		tempDir, err := os.MkdirTemp("", "treekanga-rename-simple-*")
@@ -25,15 +28,15 @@ func TestRenameWorktree(t *testing.T) {
 
 		// Initialize a bare repo
 		bareRepoPath := filepath.Join(tempDir, "test.git")
-		err = git.CloneBare("https://github.com/octocat/Hello-World.git", bareRepoPath)
+		err = g.CloneBare(testfixture.NewRemote(t, "Hello-World").URL(), bareRepoPath)
 		require.NoError(t, err)
 
-		err = git.ConfigureBare(bareRepoPath)
+		err = g.ConfigureBare(bareRepoPath)
 		require.NoError(t, err)
 
 		// Create a worktree
 		worktreePath := filepath.Join(tempDir, "old-branch")
-		err = git.AddWorktree(bareRepoPath, tempDir, "old-branch", []string{"-b", "old-branch", "origin/master", "--no-track"})
+		err = g.AddWorktree(bareRepoPath, tempDir, "old-branch", []string{"-b", "old-branch", "origin/main", "--no-track"})
 		require.NoError(t, err)
 
 		// Create config
@@ -42,12 +45,12 @@ func TestRenameWorktree(t *testing.T) {
 			WorktreeTargetDir: tempDir,
 		}
 
-		// Rename the worktree (pass nil for connector and confirmer since we don't need tmux handling in tests)
-		err = RenameWorktree(cfg, "new-branch", worktreePath, nil, nil, false, false)
+		// Rename the worktree (pass nil for the shell since we don't need zoxide in tests)
+		_, err = RenameWorktree(cfg, "new-branch", worktreePath, g, nil, false, false)
 		assert.NoError(t, err, "Should successfully rename worktree")
 
 		// Verify branch was renamed
-		branches, err := git.GetLocalBranches(bareRepoPath)
+		branches, err := g.GetLocalBranches(bareRepoPath)
 		require.NoError(t, err)
 		assert.Contains(t, branches, "new-branch", "New branch should exist")
 		assert.NotContains(t, branches, "old-branch", "Old branch should not exist")
@@ -69,15 +72,15 @@ func TestRenameWorktree(t *testing.T) {
 
 		// Initialize a bare repo
 		bareRepoPath := filepath.Join(tempDir, "test.git")
-		err = git.CloneBare("https://github.com/octocat/Hello-World.git", bareRepoPath)
+		err = g.CloneBare(testfixture.NewRemote(t, "Hello-World").URL(), bareRepoPath)
 		require.NoError(t, err)
 
-		err = git.ConfigureBare(bareRepoPath)
+		err = g.ConfigureBare(bareRepoPath)
 		require.NoError(t, err)
 
 		// Create a worktree with sanitized folder name
 		worktreePath := filepath.Join(tempDir, "old-branch")
-		err = git.AddWorktree(bareRepoPath, tempDir, "old-branch", []string{"-b", "old/branch", "origin/master", "--no-track"})
+		err = g.AddWorktree(bareRepoPath, tempDir, "old-branch", []string{"-b", "old/branch", "origin/main", "--no-track"})
 		require.NoError(t, err)
 
 		// Create config
@@ -87,11 +90,11 @@ func TestRenameWorktree(t *testing.T) {
 		}
 
 		// Rename to a branch with slashes
-		err = RenameWorktree(cfg, "feature/api/users", worktreePath, nil, nil, false, false)
+		_, err = RenameWorktree(cfg, "feature/api/users", worktreePath, g, nil, false, false)
 		assert.NoError(t, err, "Should successfully rename worktree with slashes")
 
 		// Verify branch was renamed (with slashes preserved)
-		branches, err := git.GetLocalBranches(bareRepoPath)
+		branches, err := g.GetLocalBranches(bareRepoPath)
 		require.NoError(t, err)
 		assert.Contains(t, branches, "feature/api/users", "New branch should exist with slashes")
 
@@ -109,18 +112,18 @@ func TestRenameWorktree(t *testing.T) {
 
 		// Initialize a bare repo
 		bareRepoPath := filepath.Join(tempDir, "test.git")
-		err = git.CloneBare("https://github.com/octocat/Hello-World.git", bareRepoPath)
+		err = g.CloneBare(testfixture.NewRemote(t, "Hello-World").URL(), bareRepoPath)
 		require.NoError(t, err)
 
-		err = git.ConfigureBare(bareRepoPath)
+		err = g.ConfigureBare(bareRepoPath)
 		require.NoError(t, err)
 
 		// Create two worktrees
 		worktreePath := filepath.Join(tempDir, "old-branch")
-		err = git.AddWorktree(bareRepoPath, tempDir, "old-branch", []string{"-b", "old-branch", "origin/master", "--no-track"})
+		err = g.AddWorktree(bareRepoPath, tempDir, "old-branch", []string{"-b", "old-branch", "origin/main", "--no-track"})
 		require.NoError(t, err)
 
-		err = git.AddWorktree(bareRepoPath, tempDir, "existing-branch", []string{"-b", "existing-branch", "origin/master", "--no-track"})
+		err = g.AddWorktree(bareRepoPath, tempDir, "existing-branch", []string{"-b", "existing-branch", "origin/main", "--no-track"})
 		require.NoError(t, err)
 
 		// Create config
@@ -130,7 +133,7 @@ func TestRenameWorktree(t *testing.T) {
 		}
 
 		// Try to rename to existing branch
-		err = RenameWorktree(cfg, "existing-branch", worktreePath, nil, nil, false, false)
+		_, err = RenameWorktree(cfg, "existing-branch", worktreePath, g, nil, false, false)
 		assert.Error(t, err, "Should error when new branch already exists")
 		assert.Contains(t, err.Error(), "already exists", "Error should mention branch exists")
 	})
@@ -143,15 +146,15 @@ func TestRenameWorktree(t *testing.T) {
 
 		// Initialize a bare repo
 		bareRepoPath := filepath.Join(tempDir, "test.git")
-		err = git.CloneBare("https://github.com/octocat/Hello-World.git", bareRepoPath)
+		err = g.CloneBare(testfixture.NewRemote(t, "Hello-World").URL(), bareRepoPath)
 		require.NoError(t, err)
 
-		err = git.ConfigureBare(bareRepoPath)
+		err = g.ConfigureBare(bareRepoPath)
 		require.NoError(t, err)
 
 		// Create a worktree
 		worktreePath := filepath.Join(tempDir, "old-branch")
-		err = git.AddWorktree(bareRepoPath, tempDir, "old-branch", []string{"-b", "old-branch", "origin/master", "--no-track"})
+		err = g.AddWorktree(bareRepoPath, tempDir, "old-branch", []string{"-b", "old-branch", "origin/main", "--no-track"})
 		require.NoError(t, err)
 
 		// Create a conflicting folder
@@ -166,8 +169,158 @@ func TestRenameWorktree(t *testing.T) {
 		}
 
 		// Try to rename to existing folder
-		err = RenameWorktree(cfg, "new/folder", worktreePath, nil, nil, false, false)
+		_, err = RenameWorktree(cfg, "new/folder", worktreePath, g, nil, false, false)
 		assert.Error(t, err, "Should error when target folder already exists")
 		assert.Contains(t, err.Error(), "already exists", "Error should mention folder exists")
 	})
 }
+
+func TestRenameWorktreeBranchChecks(t *testing.T) {
+	cfg := config.AppConfig{BareRepoPath: "/bare", WorktreeTargetDir: t.TempDir()}
+
+	t.Run("detached HEAD can't be renamed", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetCurrentBranch("/work/old").Return("", nil)
+
+		_, err := RenameWorktree(cfg, "new", "/work/old", g, nil, false, false)
+		assert.ErrorContains(t, err, "detached HEAD")
+	})
+
+	t.Run("new branch exists on remote", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetCurrentBranch("/work/old").Return("old", nil)
+		g.EXPECT().GetRefSnapshot("/bare", "").Return(snapshot([]string{"old"}, []string{"main", "new"}), nil)
+
+		_, err := RenameWorktree(cfg, "new", "/work/old", g, nil, false, false)
+		assert.ErrorContains(t, err, "already exists on remote")
+	})
+
+	t.Run("failed move renames the branch back", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetCurrentBranch("/work/old").Return("old", nil)
+		g.EXPECT().GetRefSnapshot("/bare", "").Return(snapshot([]string{"old"}, []string{"main"}), nil)
+		g.EXPECT().RenameBranch("/bare", "old", "new").Return(nil)
+		g.EXPECT().GetBranchParents("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().GetBranchBases("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().MoveWorktree("/bare", "/work/old", filepath.Join(cfg.WorktreeTargetDir, "new"), false).Return(assert.AnError)
+		g.EXPECT().RenameBranch("/bare", "new", "old").Return(nil)
+
+		_, err := RenameWorktree(cfg, "new", "/work/old", g, nil, false, false)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("renamed branch without a remote loses its upstream", func(t *testing.T) {
+		newPath := filepath.Join(cfg.WorktreeTargetDir, "new")
+		g := git.NewMockGit(t)
+		g.EXPECT().GetCurrentBranch("/work/old").Return("old", nil)
+		g.EXPECT().GetRefSnapshot("/bare", "").Return(snapshot([]string{"old"}, []string{"main", "old"}), nil)
+		g.EXPECT().RenameBranch("/bare", "old", "new").Return(nil)
+		g.EXPECT().GetBranchParents("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().GetBranchBases("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().MoveWorktree("/bare", "/work/old", newPath, false).Return(nil)
+		g.EXPECT().UnsetUpstream(newPath, "new").Return(nil)
+
+		_, err := RenameWorktree(cfg, "new", "/work/old", g, nil, false, false)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRenameWorktreeRelinksStack(t *testing.T) {
+	cfg := config.AppConfig{BareRepoPath: "/bare", WorktreeTargetDir: t.TempDir()}
+	newPath := filepath.Join(cfg.WorktreeTargetDir, "new")
+	parents := map[string]string{"child": "old", "other": "main"}
+	bases := map[string]string{"child": "old", "fix": "old", "other": "main"}
+
+	expectRename := func(g *git.MockGit) {
+		g.EXPECT().GetCurrentBranch("/work/old").Return("old", nil)
+		g.EXPECT().GetRefSnapshot("/bare", "").Return(snapshot([]string{"old"}, []string{"main"}), nil)
+		g.EXPECT().RenameBranch("/bare", "old", "new").Return(nil)
+		g.EXPECT().GetBranchParents("/bare").Return(parents, nil)
+		g.EXPECT().SetBranchParent("/bare", "child", "new").Return(nil)
+		g.EXPECT().GetBranchBases("/bare").Return(bases, nil)
+		g.EXPECT().SetBranchBase("/bare", "child", "new").Return(nil)
+		g.EXPECT().SetBranchBase("/bare", "fix", "new").Return(nil)
+	}
+
+	t.Run("points children and based branches at the new name", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		expectRename(g)
+		g.EXPECT().MoveWorktree("/bare", "/work/old", newPath, false).Return(nil)
+		g.EXPECT().UnsetUpstream(newPath, "new").Return(nil)
+
+		_, err := RenameWorktree(cfg, "new", "/work/old", g, nil, false, false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("a failed move points them back", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		expectRename(g)
+		g.EXPECT().MoveWorktree("/bare", "/work/old", newPath, false).Return(assert.AnError)
+		g.EXPECT().SetBranchBase("/bare", "fix", "old").Return(nil)
+		g.EXPECT().SetBranchBase("/bare", "child", "old").Return(nil)
+		g.EXPECT().SetBranchParent("/bare", "child", "old").Return(nil)
+		g.EXPECT().RenameBranch("/bare", "new", "old").Return(nil)
+
+		_, err := RenameWorktree(cfg, "new", "/work/old", g, nil, false, false)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestRenameWorktreeRemote(t *testing.T) {
+	cfg := config.AppConfig{BareRepoPath: "/bare", WorktreeTargetDir: t.TempDir()}
+	newPath := filepath.Join(cfg.WorktreeTargetDir, "new")
+
+	t.Run("pushes the new name and deletes the old one", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetCurrentBranch("/work/old").Return("old", nil)
+		g.EXPECT().GetRefSnapshot("/bare", "").Return(snapshot([]string{"old"}, []string{"main", "old"}), nil)
+		g.EXPECT().RenameBranch("/bare", "old", "new").Return(nil)
+		g.EXPECT().GetBranchParents("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().GetBranchBases("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().MoveWorktree("/bare", "/work/old", newPath, false).Return(nil)
+		g.EXPECT().PushBranch(newPath, "new").Return(nil)
+		g.EXPECT().DeleteRemoteBranch("/bare", "old").Return(nil)
+
+		result, err := RenameWorktree(cfg, "new", "/work/old", g, nil, false, true)
+		require.NoError(t, err)
+		assert.Equal(t, RenameResult{OldBranch: "old", NewBranch: "new", OldPath: "/work/old", NewPath: newPath}, result)
+	})
+
+	t.Run("a failed push keeps the old name on origin", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetCurrentBranch("/work/old").Return("old", nil)
+		g.EXPECT().GetRefSnapshot("/bare", "").Return(snapshot([]string{"old"}, []string{"main", "old"}), nil)
+		g.EXPECT().RenameBranch("/bare", "old", "new").Return(nil)
+		g.EXPECT().GetBranchParents("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().GetBranchBases("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().MoveWorktree("/bare", "/work/old", newPath, false).Return(nil)
+		g.EXPECT().PushBranch(newPath, "new").Return(assert.AnError)
+		g.EXPECT().UnsetUpstream(newPath, "new").Return(nil)
+
+		_, err := RenameWorktree(cfg, "new", "/work/old", g, nil, false, true)
+		assert.NoError(t, err)
+	})
+
+	t.Run("a branch that isn't on origin is only renamed locally", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetCurrentBranch("/work/old").Return("old", nil)
+		g.EXPECT().GetRefSnapshot("/bare", "").Return(snapshot([]string{"old"}, []string{"main"}), nil)
+		g.EXPECT().RenameBranch("/bare", "old", "new").Return(nil)
+		g.EXPECT().GetBranchParents("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().GetBranchBases("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().MoveWorktree("/bare", "/work/old", newPath, false).Return(nil)
+		g.EXPECT().UnsetUpstream(newPath, "new").Return(nil)
+
+		_, err := RenameWorktree(cfg, "new", "/work/old", g, nil, false, true)
+		assert.NoError(t, err)
+	})
+}
+
+func TestExecuteRenameReturnsErrors(t *testing.T) {
+	g := git.NewMockGit(t)
+	g.EXPECT().ListWorktrees("/bare").Return([]string{"/work/old  a1c4d34 [old]"}, nil)
+	g.EXPECT().GetCurrentBranch("/work/old").Return("", nil)
+
+	err := ExecuteRename(config.AppConfig{BareRepoPath: "/bare"}, []string{"old", "new"}, g, nil, nil, nil, false, false, false)
+	assert.ErrorContains(t, err, "failed to rename old: not on a branch")
+}
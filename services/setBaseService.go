@@ -0,0 +1,42 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// SetBase records base as the branch a worktree's branch is compared
+// against and synced onto. The worktree is named by branch or folder, or
+// with no name the one containing dir. base has to exist locally or on the
+// remote.
+func SetBase(git git.Git, bareRepoPath, base, name, dir string) (models.Worktree, error) {
+	wt, ok := findWorktree(getWorktrees(git, bareRepoPath), name, dir)
+	if !ok && name == "" {
+		return models.Worktree{}, fmt.Errorf("not inside a worktree, name the worktree to set the base of")
+	}
+	if !ok {
+		return models.Worktree{}, fmt.Errorf("no worktree named %s", name)
+	}
+	if wt.Detached {
+		return models.Worktree{}, fmt.Errorf("%s is detached, there is no branch to set the base of", wt.Folder)
+	}
+	if base == wt.BranchName {
+		return models.Worktree{}, fmt.Errorf("%s can't be its own base", base)
+	}
+
+	refs, err := git.GetRefSnapshot(bareRepoPath, "")
+	if err != nil {
+		return models.Worktree{}, err
+	}
+	if !refs.HasLocal(base) && !refs.HasRemote(base) {
+		return models.Worktree{}, fmt.Errorf("base branch '%s' not found locally or on remote", base)
+	}
+
+	if err := git.SetBranchBase(bareRepoPath, wt.BranchName, base); err != nil {
+		return models.Worktree{}, err
+	}
+	wt.BaseBranch = base
+	return wt, nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetBase(t *testing.T) {
+	listing := []string{
+		"/code/widget.git  (bare)",
+		"/code/widget_work/hotfix  a1c4d34 [hotfix]",
+		"/code/widget_work/v1  b2d5e45 (detached HEAD)",
+	}
+	newMock := func(t *testing.T) *git.MockGit {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().ListWorktrees("/code/widget.git").Return(listing, nil)
+		return mockGit
+	}
+
+	t.Run("records a base that exists on the remote", func(t *testing.T) {
+		mockGit := newMock(t)
+		mockGit.EXPECT().GetRefSnapshot("/code/widget.git", "").Return(snapshot([]string{"hotfix", "main"}, []string{"main", "release/2.0"}), nil)
+		mockGit.EXPECT().SetBranchBase("/code/widget.git", "hotfix", "release/2.0").Return(nil)
+
+		wt, err := SetBase(mockGit, "/code/widget.git", "release/2.0", "", "/code/widget_work/hotfix/src")
+
+		require.NoError(t, err)
+		assert.Equal(t, "hotfix", wt.BranchName)
+		assert.Equal(t, "release/2.0", wt.BaseBranch)
+	})
+
+	t.Run("unknown base", func(t *testing.T) {
+		mockGit := newMock(t)
+		mockGit.EXPECT().GetRefSnapshot("/code/widget.git", "").Return(snapshot([]string{"hotfix", "main"}, []string{"main"}), nil)
+
+		_, err := SetBase(mockGit, "/code/widget.git", "release/9.9", "hotfix", "/code/widget.git")
+
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("detached worktree", func(t *testing.T) {
+		_, err := SetBase(newMock(t), "/code/widget.git", "main", "v1", "/code/widget.git")
+
+		assert.ErrorContains(t, err, "detached")
+	})
+
+	t.Run("outside a worktree", func(t *testing.T) {
+		_, err := SetBase(newMock(t), "/code/widget.git", "main", "", "/code/widget.git")
+
+		assert.ErrorContains(t, err, "not inside a worktree")
+	})
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// restackStateFile keeps a restack stopped by a conflict, in the bare repo
+const restackStateFile = "treekanga-restack.json"
+
+// RestackState is what's left of a restack stopped by a conflict, so
+// stack restack --continue can pick it up once the conflict is resolved.
+type RestackState struct {
+	Pending    []string          `json:"pending"`    // branches still to restack, parents first, the first is the one that stopped
+	ForkPoints map[string]string `json:"forkPoints"` // the commit each branch was cut from its parent at, found before any rebase
+}
+
+// stackLinks are the recorded parents of stacked branches, and the other
+// way around.
+type stackLinks struct {
+	parents  map[string]string
+	children map[string][]string
+}
+
+func loadStackLinks(git git.Git, bareRepoPath string) (stackLinks, error) {
+	parents, err := git.GetBranchParents(bareRepoPath)
+	if err != nil {
+		return stackLinks{}, err
+	}
+	links := stackLinks{parents: parents, children: map[string][]string{}}
+	for branch, parent := range parents {
+		links.children[parent] = append(links.children[parent], branch)
+	}
+	for _, children := range links.children {
+		slices.Sort(children)
+	}
+	return links, nil
+}
+
+// roots are the branches stacks are built on that aren't stacked themselves.
+func (l stackLinks) roots() []string {
+	var roots []string
+	for parent := range l.children {
+		if _, stacked := l.parents[parent]; !stacked {
+			roots = append(roots, parent)
+		}
+	}
+	slices.Sort(roots)
+	return roots
+}
+
+// order is every stacked branch, each after its parent.
+func (l stackLinks) order() []string {
+	var order []string
+	queue := l.roots()
+	for len(queue) > 0 {
+		branch := queue[0]
+		queue = queue[1:]
+		for _, child := range l.children[branch] {
+			order = append(order, child)
+			queue = append(queue, child)
+		}
+	}
+	return order
+}
+
+// BuildStacks returns the stacks of the repo as trees, each on top of the
+// base branch of its root, or defaultBase when the root has none recorded.
+// Every branch carries how far it is ahead/behind its parent.
+func BuildStacks(git git.Git, bareRepoPath, defaultBase string) ([]*models.StackNode, error) {
+	links, err := loadStackLinks(git, bareRepoPath)
+	if err != nil || len(links.parents) == 0 {
+		return nil, err
+	}
+
+	bases, err := git.GetBranchBases(bareRepoPath)
+	if err != nil {
+		log.Debug("Failed to get base branches, stacking on the default branch", "error", err)
+	}
+	worktrees := worktreesByBranch(getWorktrees(git, bareRepoPath))
+
+	var stacks []*models.StackNode
+	trunks := map[string]*models.StackNode{}
+	for _, root := range links.roots() {
+		trunk := bases[root]
+		if trunk == "" {
+			trunk = defaultBase
+		}
+		if trunk == root {
+			stacks = append(stacks, stackNode(git, links, worktrees, root, ""))
+			continue
+		}
+		top, ok := trunks[trunk]
+		if !ok {
+			top = &models.StackNode{Branch: trunk}
+			if wt, ok := worktrees[trunk]; ok {
+				top.Worktree = &wt
+			}
+			trunks[trunk] = top
+			stacks = append(stacks, top)
+		}
+		top.Children = append(top.Children, stackNode(git, links, worktrees, root, trunk))
+	}
+	return stacks, nil
+}
+
+// stackNode builds the tree of branch, counting it against parent unless
+// parent is "".
+func stackNode(git git.Git, links stackLinks, worktrees map[string]models.Worktree, branch, parent string) *models.StackNode {
+	node := &models.StackNode{Branch: branch}
+	if wt, ok := worktrees[branch]; ok {
+		node.Worktree = &wt
+		if parent != "" {
+			ahead, behind, err := git.GetAheadBehind(wt.FullPath, parent)
+			if err != nil {
+				log.Debug("Failed to get ahead/behind parent", "branch", branch, "parent", parent, "error", err)
+			} else {
+				node.Ahead, node.Behind, node.Counted = ahead, behind, true
+			}
+		}
+	}
+	for _, child := range links.children[branch] {
+		node.Children = append(node.Children, stackNode(git, links, worktrees, child, branch))
+	}
+	return node
+}
+
+func worktreesByBranch(worktrees []models.Worktree) map[string]models.Worktree {
+	byBranch := make(map[string]models.Worktree, len(worktrees))
+	for _, wt := range worktrees {
+		if !wt.Detached {
+			byBranch[wt.BranchName] = wt
+		}
+	}
+	return byBranch
+}
+
+// Restack rebases every stacked branch onto its parent, parents first, so
+// each picks up what its parent gained or was rewritten to. Only the commits
+// a branch added on top of its parent are replayed. A conflict stops the
+// restack with the rebase left in progress and the rest saved for
+// ContinueRestack.
+func Restack(git git.Git, bareRepoPath string) ([]SyncResult, error) {
+	if _, err := loadRestackState(bareRepoPath); err == nil {
+		return nil, fmt.Errorf("a restack is already in progress, finish it with stack restack --continue or drop it with --abort")
+	}
+
+	links, err := loadStackLinks(git, bareRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	order := links.order()
+	if len(order) == 0 {
+		return nil, fmt.Errorf("no stacked branches, stack one with add --stack-on")
+	}
+
+	worktrees := worktreesByBranch(getWorktrees(git, bareRepoPath))
+	for _, branch := range order {
+		wt, ok := worktrees[branch]
+		if !ok {
+			continue
+		}
+		staged, modified, _, err := git.GetWorkingTreeStatus(wt.FullPath)
+		if err != nil {
+			return nil, err
+		}
+		if staged || modified {
+			return nil, fmt.Errorf("%s has uncommitted changes, commit or stash them before restacking", wt.Folder)
+		}
+	}
+
+	// Find where every branch was cut before any rebase moves its parent
+	state := RestackState{Pending: order, ForkPoints: map[string]string{}}
+	for _, branch := range order {
+		forkPoint, err := git.ForkPoint(bareRepoPath, links.parents[branch], branch)
+		if err != nil {
+			return nil, err
+		}
+		state.ForkPoints[branch] = forkPoint
+	}
+
+	return runRestack(git, bareRepoPath, links, worktrees, state)
+}
+
+// ContinueRestack picks a restack stopped by a conflict up again, once the
+// rebase that stopped it was continued to the end.
+func ContinueRestack(git git.Git, bareRepoPath string) ([]SyncResult, error) {
+	state, err := loadRestackState(bareRepoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	worktrees := worktreesByBranch(getWorktrees(git, bareRepoPath))
+	if wt, ok := worktrees[state.Pending[0]]; ok {
+		inProgress, err := git.RebaseInProgress(wt.FullPath)
+		if err != nil {
+			return nil, err
+		}
+		if inProgress {
+			return nil, fmt.Errorf("%s is still being rebased, resolve the conflict and run git rebase --continue there first", wt.FullPath)
+		}
+	}
+
+	links, err := loadStackLinks(git, bareRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	return runRestack(git, bareRepoPath, links, worktrees, state)
+}
+
+// AbortRestack drops a restack stopped by a conflict, aborting the rebase
+// that stopped it. Branches restacked before it stay restacked.
+func AbortRestack(git git.Git, bareRepoPath string) error {
+	state, err := loadRestackState(bareRepoPath)
+	if err != nil {
+		return err
+	}
+
+	if wt, ok := worktreesByBranch(getWorktrees(git, bareRepoPath))[state.Pending[0]]; ok {
+		inProgress, err := git.RebaseInProgress(wt.FullPath)
+		if err != nil {
+			return err
+		}
+		if inProgress {
+			if err := git.AbortRebase(wt.FullPath); err != nil {
+				return err
+			}
+		}
+	}
+	return os.Remove(filepath.Join(bareRepoPath, restackStateFile))
+}
+
+func runRestack(git git.Git, bareRepoPath string, links stackLinks, worktrees map[string]models.Worktree, state RestackState) ([]SyncResult, error) {
+	var results []SyncResult
+	for len(state.Pending) > 0 {
+		branch := state.Pending[0]
+		parent := links.parents[branch]
+
+		result := restackBranch(git, worktrees, branch, parent, state.ForkPoints[branch])
+		log.Debug("Restacked branch", "branch", branch, "parent", parent, "status", result.Status, "reason", result.Reason)
+		results = append(results, result)
+
+		if result.Failed() {
+			if err := saveRestackState(bareRepoPath, state); err != nil {
+				return results, err
+			}
+			return results, fmt.Errorf("restacking %s onto %s stopped, resolve it in %s and run stack restack --continue, or stack restack --abort",
+				branch, parent, result.Worktree.FullPath)
+		}
+		state.Pending = state.Pending[1:]
+	}
+
+	if err := os.Remove(filepath.Join(bareRepoPath, restackStateFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return results, err
+	}
+	return results, nil
+}
+
+// restackBranch rebases the commits branch added after forkPoint onto where
+// its parent is now.
+func restackBranch(git git.Git, worktrees map[string]models.Worktree, branch, parent, forkPoint string) SyncResult {
+	wt, ok := worktrees[branch]
+	if !ok {
+		return SyncResult{Worktree: models.Worktree{BranchName: branch}, Status: SyncStatusSkipped, Reason: "no worktree to rebase in"}
+	}
+	result := SyncResult{Worktree: wt}
+	if parent == "" {
+		result.Status = SyncStatusSkipped
+		result.Reason = "no longer stacked"
+		return result
+	}
+
+	_, behind, err := git.GetAheadBehind(wt.FullPath, parent)
+	if err != nil {
+		log.Debug("Failed to get ahead/behind parent, restacking anyway", "branch", branch, "error", err)
+	} else if behind == 0 {
+		result.Status = SyncStatusUpToDate
+		return result
+	}
+
+	if forkPoint == "" {
+		forkPoint = parent
+	}
+	if err := git.RebaseOnto(wt.FullPath, parent, forkPoint); err != nil {
+		result.Reason = err.Error()
+		result.Status = SyncStatusFailed
+		if inProgress, _ := git.RebaseInProgress(wt.FullPath); inProgress {
+			result.Status = SyncStatusConflict
+		}
+		return result
+	}
+
+	result.Status = SyncStatusSynced
+	return result
+}
+
+func loadRestackState(bareRepoPath string) (RestackState, error) {
+	var state RestackState
+	data, err := os.ReadFile(filepath.Join(bareRepoPath, restackStateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return state, fmt.Errorf("no restack in progress")
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to read %s: %w", restackStateFile, err)
+	}
+	if len(state.Pending) == 0 {
+		return state, fmt.Errorf("no restack in progress")
+	}
+	return state, nil
+}
+
+func saveRestackState(bareRepoPath string, state RestackState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(bareRepoPath, restackStateFile), data, 0644)
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/testfixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var stackListing = []string{
+	"/code/widget.git  (bare)",
+	"/code/widget_work/api  a1c4d34 [feature/api]",
+	"/code/widget_work/ui  b2d5e45 [feature/ui]",
+}
+
+func TestBuildStacks(t *testing.T) {
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().GetBranchParents("/code/widget.git").Return(map[string]string{
+		"feature/ui":   "feature/api",
+		"feature/docs": "feature/api",
+		"hotfix/b":     "hotfix/a",
+	}, nil)
+	mockGit.EXPECT().GetBranchBases("/code/widget.git").Return(map[string]string{"hotfix/a": "release/2.0"}, nil)
+	mockGit.EXPECT().ListWorktrees("/code/widget.git").Return(stackListing, nil)
+	mockGit.EXPECT().GetAheadBehind("/code/widget_work/api", "main").Return(2, 0, nil)
+	mockGit.EXPECT().GetAheadBehind("/code/widget_work/ui", "feature/api").Return(1, 3, nil)
+
+	stacks, err := BuildStacks(mockGit, "/code/widget.git", "main")
+	require.NoError(t, err)
+
+	require.Len(t, stacks, 2)
+	assert.Equal(t, "main", stacks[0].Branch)
+	api := stacks[0].Children[0]
+	assert.Equal(t, "feature/api", api.Branch)
+	assert.Equal(t, 2, api.Ahead)
+	require.Len(t, api.Children, 2)
+	assert.Equal(t, "feature/docs", api.Children[0].Branch)
+	assert.Nil(t, api.Children[0].Worktree)
+	assert.Equal(t, &models.StackNode{
+		Branch:   "feature/ui",
+		Worktree: &models.Worktree{FullPath: "/code/widget_work/ui", Folder: "ui", BranchName: "feature/ui", CommitHash: "b2d5e45"},
+		Ahead:    1,
+		Behind:   3,
+		Counted:  true,
+	}, api.Children[1])
+
+	assert.Equal(t, "release/2.0", stacks[1].Branch)
+	assert.Equal(t, "hotfix/a", stacks[1].Children[0].Branch)
+	assert.Equal(t, "hotfix/b", stacks[1].Children[0].Children[0].Branch)
+}
+
+func TestRestack(t *testing.T) {
+	newMock := func(t *testing.T) *git.MockGit {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().GetBranchParents(mock.Anything).Return(map[string]string{
+			"feature/api": "main",
+			"feature/ui":  "feature/api",
+		}, nil)
+		mockGit.EXPECT().ListWorktrees(mock.Anything).Return(stackListing, nil)
+		return mockGit
+	}
+	forkPoints := func(mockGit *git.MockGit, bare string) {
+		mockGit.EXPECT().ForkPoint(bare, "main", "feature/api").Return("m1", nil)
+		mockGit.EXPECT().ForkPoint(bare, "feature/api", "feature/ui").Return("a1", nil)
+	}
+
+	t.Run("rebases children onto the new tip of their parent", func(t *testing.T) {
+		bare := t.TempDir()
+		mockGit := newMock(t)
+		mockGit.EXPECT().GetWorkingTreeStatus("/code/widget_work/api").Return(false, false, true, nil)
+		mockGit.EXPECT().GetWorkingTreeStatus("/code/widget_work/ui").Return(false, false, false, nil)
+		forkPoints(mockGit, bare)
+		mockGit.EXPECT().GetAheadBehind("/code/widget_work/api", "main").Return(2, 1, nil)
+		mockGit.EXPECT().RebaseOnto("/code/widget_work/api", "main", "m1").Return(nil)
+		mockGit.EXPECT().GetAheadBehind("/code/widget_work/ui", "feature/api").Return(1, 3, nil)
+		mockGit.EXPECT().RebaseOnto("/code/widget_work/ui", "feature/api", "a1").Return(nil)
+
+		results, err := Restack(mockGit, bare)
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, "feature/api", results[0].Worktree.BranchName)
+		assert.Equal(t, SyncStatusSynced, results[1].Status)
+		assert.NoFileExists(t, filepath.Join(bare, restackStateFile))
+	})
+
+	t.Run("refuses worktrees with uncommitted changes", func(t *testing.T) {
+		mockGit := newMock(t)
+		mockGit.EXPECT().GetWorkingTreeStatus("/code/widget_work/api").Return(false, true, false, nil)
+
+		_, err := Restack(mockGit, t.TempDir())
+
+		assert.ErrorContains(t, err, "api has uncommitted changes")
+	})
+
+	t.Run("stops on a conflict and continues after it", func(t *testing.T) {
+		bare := t.TempDir()
+		mockGit := newMock(t)
+		mockGit.EXPECT().GetWorkingTreeStatus(mock.Anything).Return(false, false, false, nil)
+		forkPoints(mockGit, bare)
+		mockGit.EXPECT().GetAheadBehind("/code/widget_work/api", "main").Return(2, 1, nil).Once()
+		mockGit.EXPECT().RebaseOnto("/code/widget_work/api", "main", "m1").Return(errors.New("rebase failed: CONFLICT (content): Merge conflict in a.txt"))
+		mockGit.EXPECT().RebaseInProgress("/code/widget_work/api").Return(true, nil).Once()
+
+		results, err := Restack(mockGit, bare)
+
+		assert.ErrorContains(t, err, "stack restack --continue")
+		require.Len(t, results, 1)
+		assert.Equal(t, SyncStatusConflict, results[0].Status)
+		state, err := loadRestackState(bare)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"feature/api", "feature/ui"}, state.Pending)
+		assert.Equal(t, "a1", state.ForkPoints["feature/ui"])
+
+		_, err = Restack(mockGit, bare)
+		assert.ErrorContains(t, err, "already in progress")
+
+		// still rebasing
+		mockGit.EXPECT().RebaseInProgress("/code/widget_work/api").Return(true, nil).Once()
+		_, err = ContinueRestack(mockGit, bare)
+		assert.ErrorContains(t, err, "still being rebased")
+
+		// the rebase was continued, ui is rebased from where it was cut
+		// from api before the restack started
+		mockGit.EXPECT().RebaseInProgress("/code/widget_work/api").Return(false, nil).Once()
+		mockGit.EXPECT().GetAheadBehind("/code/widget_work/api", "main").Return(2, 0, nil).Once()
+		mockGit.EXPECT().GetAheadBehind("/code/widget_work/ui", "feature/api").Return(1, 3, nil)
+		mockGit.EXPECT().RebaseOnto("/code/widget_work/ui", "feature/api", "a1").Return(nil)
+
+		results, err = ContinueRestack(mockGit, bare)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{SyncStatusUpToDate, SyncStatusSynced}, []string{results[0].Status, results[1].Status})
+		assert.NoFileExists(t, filepath.Join(bare, restackStateFile))
+	})
+}
+
+func TestAbortRestack(t *testing.T) {
+	bare := t.TempDir()
+	require.NoError(t, saveRestackState(bare, RestackState{Pending: []string{"feature/ui"}}))
+
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().ListWorktrees(bare).Return(stackListing, nil)
+	mockGit.EXPECT().RebaseInProgress("/code/widget_work/ui").Return(true, nil)
+	mockGit.EXPECT().AbortRebase("/code/widget_work/ui").Return(nil)
+
+	require.NoError(t, AbortRestack(mockGit, bare))
+	assert.NoFileExists(t, filepath.Join(bare, restackStateFile))
+
+	assert.ErrorContains(t, AbortRestack(mockGit, bare), "no restack in progress")
+}
+
+func TestRestackRewrittenParent(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := testfixture.NewEnv(t)
+	remote := testfixture.NewRemote(t, "widget")
+	bareRepoPath := env.Clone(remote, "widget")
+	g := git.NewGit()
+
+	api := filepath.Join(env.Home, "api")
+	ui := filepath.Join(env.Home, "ui")
+	testfixture.Git(t, bareRepoPath, "worktree", "add", "-q", "-b", "feature/api", api, "main")
+	commitFile(t, api, "api.txt", "api")
+	testfixture.Git(t, bareRepoPath, "worktree", "add", "-q", "-b", "feature/ui", ui, "feature/api")
+	commitFile(t, ui, "ui.txt", "ui")
+	require.NoError(t, g.SetBranchParent(bareRepoPath, "feature/ui", "feature/api"))
+
+	// rewrite api, rebasing all of ui onto it would replay the old api
+	// commit and conflict
+	require.NoError(t, os.WriteFile(filepath.Join(api, "api.txt"), []byte("api v2\n"), 0644))
+	testfixture.Git(t, api, "commit", "-q", "-a", "--amend", "-m", "api v2")
+	commitFile(t, api, "api2.txt", "more api")
+
+	results, err := Restack(g, bareRepoPath)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, SyncStatusSynced, results[0].Status)
+
+	assert.Equal(t, "ui\nmore api\napi v2", testfixture.Git(t, ui, "log", "--format=%s", "main..HEAD"))
+}
+
+func commitFile(t *testing.T, dir, name, message string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(message+"\n"), 0644))
+	testfixture.Git(t, dir, "add", name)
+	testfixture.Git(t, dir, "commit", "-q", "-m", message)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// StashGroup is the stashes made on one branch, and the worktree that
+// branch is checked out in.
+type StashGroup struct {
+	Branch   string           // "" for stashes made on a detached HEAD
+	Worktree *models.Worktree // nil when no worktree has the branch checked out
+	Stashes  []git.Stash
+}
+
+// ListStashGroups returns the repo's stashes grouped by the branch they were
+// made on.
+func ListStashGroups(git git.Git, bareRepoPath string) ([]StashGroup, error) {
+	stashes, err := git.ListStashes(bareRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	return GroupStashes(getWorktrees(git, bareRepoPath), stashes), nil
+}
+
+// GroupStashes groups stashes by the branch they were made on: branches
+// checked out in a worktree first, in worktree order, then branches without
+// one, then stashes made on a detached HEAD. Stashes keep their order, newest
+// first.
+func GroupStashes(worktrees []models.Worktree, stashes []git.Stash) []StashGroup {
+	byBranch := map[string][]git.Stash{}
+	for _, stash := range stashes {
+		byBranch[stash.Branch] = append(byBranch[stash.Branch], stash)
+	}
+
+	var groups []StashGroup
+	for _, wt := range worktrees {
+		if wt.Detached || byBranch[wt.BranchName] == nil {
+			continue
+		}
+		groups = append(groups, StashGroup{Branch: wt.BranchName, Worktree: &wt, Stashes: byBranch[wt.BranchName]})
+		delete(byBranch, wt.BranchName)
+	}
+
+	var rest []string
+	for branch := range byBranch {
+		rest = append(rest, branch)
+	}
+	slices.SortFunc(rest, func(a, b string) int {
+		// detached last
+		if a == "" || b == "" {
+			return len(b) - len(a)
+		}
+		return strings.Compare(a, b)
+	})
+	for _, branch := range rest {
+		groups = append(groups, StashGroup{Branch: branch, Stashes: byBranch[branch]})
+	}
+	return groups
+}
+
+// StashCounts returns how many stashes were made on each branch.
+func StashCounts(git git.Git, bareRepoPath string) (map[string]int, error) {
+	stashes, err := git.ListStashes(bareRepoPath)
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, stash := range stashes {
+		counts[stash.Branch]++
+	}
+	return counts, nil
+}
+
+// MoveStash applies a stash to another worktree, named by branch or folder,
+// and drops it from the stash list. The stash is given as stash@{n} or just
+// n. When applying it conflicts the stash is kept.
+func MoveStash(git git.Git, bareRepoPath, ref, name, dir string) (stash git.Stash, wt models.Worktree, err error) {
+	if !strings.HasPrefix(ref, "stash@{") {
+		ref = "stash@{" + ref + "}"
+	}
+
+	stashes, err := git.ListStashes(bareRepoPath)
+	if err != nil {
+		return stash, wt, err
+	}
+	found := false
+	for _, s := range stashes {
+		if s.Ref == ref {
+			stash, found = s, true
+			break
+		}
+	}
+	if !found {
+		return stash, wt, fmt.Errorf("no stash %s", ref)
+	}
+
+	wt, ok := findWorktree(getWorktrees(git, bareRepoPath), name, dir)
+	if !ok {
+		return stash, wt, fmt.Errorf("no worktree named %s", name)
+	}
+
+	if err := git.PopStash(wt.FullPath, stash.Commit); err != nil {
+		return stash, wt, fmt.Errorf("%w, %s was kept", err, ref)
+	}
+	return stash, wt, nil
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupStashes(t *testing.T) {
+	worktrees := []models.Worktree{
+		{Folder: "feature-b", BranchName: "feature/b"},
+		{Folder: "v1", Detached: true},
+		{Folder: "feature-a", BranchName: "feature/a"},
+		{Folder: "clean", BranchName: "clean"},
+	}
+	stashes := []git.Stash{
+		{Ref: "stash@{0}", Branch: "feature/a"},
+		{Ref: "stash@{1}"},
+		{Ref: "stash@{2}", Branch: "main"},
+		{Ref: "stash@{3}", Branch: "feature/b"},
+		{Ref: "stash@{4}", Branch: "feature/a"},
+		{Ref: "stash@{5}", Branch: "deleted"},
+	}
+
+	groups := GroupStashes(worktrees, stashes)
+
+	var branches []string
+	for _, group := range groups {
+		branches = append(branches, group.Branch)
+	}
+	assert.Equal(t, []string{"feature/b", "feature/a", "deleted", "main", ""}, branches)
+	assert.Equal(t, "feature-a", groups[1].Worktree.Folder)
+	assert.Equal(t, []git.Stash{stashes[0], stashes[4]}, groups[1].Stashes)
+	assert.Nil(t, groups[2].Worktree)
+}
+
+func TestMoveStash(t *testing.T) {
+	stashes := []git.Stash{
+		{Ref: "stash@{0}", Commit: "a1c4d34", Branch: "feature/a", Message: "wip"},
+		{Ref: "stash@{1}", Commit: "b2d5e45", Branch: "feature/b", Message: "half done"},
+	}
+	listing := []string{
+		"/code/widget.git  (bare)",
+		"/code/widget_work/feature-a  a1c4d34 [feature/a]",
+	}
+
+	t.Run("pops the stash in the named worktree", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().ListStashes("/code/widget.git").Return(stashes, nil)
+		mockGit.EXPECT().ListWorktrees("/code/widget.git").Return(listing, nil)
+		mockGit.EXPECT().PopStash("/code/widget_work/feature-a", "b2d5e45").Return(nil)
+
+		stash, wt, err := MoveStash(mockGit, "/code/widget.git", "1", "feature-a", "/code/widget.git")
+
+		require.NoError(t, err)
+		assert.Equal(t, "half done", stash.Message)
+		assert.Equal(t, "feature/a", wt.BranchName)
+	})
+
+	t.Run("keeps the stash on a conflict", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().ListStashes("/code/widget.git").Return(stashes, nil)
+		mockGit.EXPECT().ListWorktrees("/code/widget.git").Return(listing, nil)
+		mockGit.EXPECT().PopStash("/code/widget_work/feature-a", "b2d5e45").Return(errors.New("stash failed: CONFLICT (content): Merge conflict in a.txt"))
+
+		_, _, err := MoveStash(mockGit, "/code/widget.git", "stash@{1}", "feature/a", "/code/widget.git")
+
+		assert.ErrorContains(t, err, "stash@{1} was kept")
+	})
+
+	t.Run("rejects unknown stashes and worktrees", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().ListStashes("/code/widget.git").Return(stashes, nil)
+
+		_, _, err := MoveStash(mockGit, "/code/widget.git", "7", "feature/a", "/code/widget.git")
+		assert.ErrorContains(t, err, "no stash stash@{7}")
+
+		mockGit.EXPECT().ListWorktrees("/code/widget.git").Return(listing, nil)
+		_, _, err = MoveStash(mockGit, "/code/widget.git", "0", "feature/c", "/code/widget.git")
+		assert.ErrorContains(t, err, "no worktree named feature/c")
+	})
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/transformer"
+)
+
+// Outcomes of syncing a worktree
+const (
+	SyncStatusSynced   = "synced"
+	SyncStatusUpToDate = "up to date"
+	SyncStatusSkipped  = "skipped"
+	SyncStatusConflict = "conflict"
+	SyncStatusFailed   = "failed"
+)
+
+// SyncResult is the outcome of syncing one worktree.
+type SyncResult struct {
+	Worktree models.Worktree
+	Status   string
+	Reason   string // why it was skipped, or what git reported
+}
+
+// Failed reports whether the worktree could not be synced, as opposed to
+// being synced, already up to date or skipped on purpose.
+func (r SyncResult) Failed() bool {
+	return r.Status == SyncStatusConflict || r.Status == SyncStatusFailed
+}
+
+// SelectSyncWorktrees picks the worktrees sync should work on: every one
+// with all, the ones named by branch or folder, or else the one containing
+// dir.
+func SelectSyncWorktrees(git git.Git, bareRepoPath string, names []string, all bool, dir string) ([]models.Worktree, error) {
+	worktrees := getWorktrees(git, bareRepoPath)
+	if all {
+		return worktrees, nil
+	}
+
+	if len(names) > 0 {
+		var selected []models.Worktree
+		for _, name := range names {
+			wt, ok := findWorktree(worktrees, name, dir)
+			if !ok {
+				return nil, fmt.Errorf("no worktree named %s", name)
+			}
+			selected = append(selected, wt)
+		}
+		return selected, nil
+	}
+
+	if wt, ok := findWorktree(worktrees, "", dir); ok {
+		return []models.Worktree{wt}, nil
+	}
+	return nil, fmt.Errorf("not inside a worktree, name the worktrees to sync or pass --all")
+}
+
+// findWorktree returns the worktree named by branch or folder, or with no
+// name the one containing dir.
+func findWorktree(worktrees []models.Worktree, name, dir string) (models.Worktree, bool) {
+	i := slices.IndexFunc(worktrees, func(wt models.Worktree) bool {
+		if name == "" {
+			return dir == wt.FullPath || strings.HasPrefix(dir, wt.FullPath+string(filepath.Separator))
+		}
+		return name == wt.BranchName || name == wt.Folder || name == transformer.DisplayBranch(wt)
+	})
+	if i < 0 {
+		return models.Worktree{}, false
+	}
+	return worktrees[i], true
+}
+
+// SyncWorktrees fetches the base branches once, then rebases or merges each
+// worktree onto origin/<base> following cfg.SyncStrategy, where base is the
+// one recorded for its branch or cfg.BaseBranch. Stacked branches are
+// skipped, stack restack keeps them on their parents.
+func SyncWorktrees(git git.Git, cfg config.AppConfig, worktrees []models.Worktree) ([]SyncResult, error) {
+	bases, err := git.GetBranchBases(cfg.BareRepoPath)
+	if err != nil {
+		log.Debug("Failed to get base branches, syncing onto the default branch", "error", err)
+	}
+	parents, err := git.GetBranchParents(cfg.BareRepoPath)
+	if err != nil {
+		log.Debug("Failed to get parent branches", "error", err)
+	}
+	worktrees = applyBaseBranches(worktrees, bases, parents, cfg.BaseBranch)
+
+	if err := fetchBaseBranches(git, cfg.BareRepoPath, cfg.BaseBranch, worktrees); err != nil {
+		return nil, err
+	}
+
+	results := make([]SyncResult, 0, len(worktrees))
+	for _, wt := range worktrees {
+		if wt.StackParent != "" {
+			// origin/<parent> may not exist, or be older than the parent
+			// after a restack
+			results = append(results, SyncResult{
+				Worktree: wt,
+				Status:   SyncStatusSkipped,
+				Reason:   fmt.Sprintf("stacked on %s, use stack restack", wt.StackParent),
+			})
+			continue
+		}
+		result := SyncWorktree(git, wt, "origin/"+wt.BaseBranch, cfg.SyncStrategy)
+		log.Debug("Synced worktree", "worktree", wt.Folder, "status", result.Status, "reason", result.Reason)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// SyncWorktree rebases or merges a single worktree onto onto. Worktrees
+// with uncommitted changes are skipped, and a conflict is aborted so the
+// worktree is left as it was.
+func SyncWorktree(git git.Git, wt models.Worktree, onto, strategy string) SyncResult {
+	result := SyncResult{Worktree: wt}
+
+	if wt.Detached {
+		result.Status = SyncStatusSkipped
+		result.Reason = "detached, no branch to sync"
+		return result
+	}
+
+	staged, modified, _, err := git.GetWorkingTreeStatus(wt.FullPath)
+	if err != nil {
+		result.Status = SyncStatusFailed
+		result.Reason = err.Error()
+		return result
+	}
+	if staged || modified {
+		result.Status = SyncStatusSkipped
+		result.Reason = "uncommitted changes"
+		return result
+	}
+
+	_, behind, err := git.GetAheadBehind(wt.FullPath, onto)
+	if err != nil {
+		log.Debug("Failed to get ahead/behind, syncing anyway", "worktree", wt.Folder, "error", err)
+	} else if behind == 0 {
+		result.Status = SyncStatusUpToDate
+		return result
+	}
+
+	sync, abort := git.Rebase, git.AbortRebase
+	if strategy == config.SyncMerge {
+		sync, abort = git.Merge, git.AbortMerge
+	}
+
+	if err := sync(wt.FullPath, onto); err != nil {
+		result.Reason = err.Error()
+		// Nothing to abort when git refused to start, e.g. because untracked
+		// files would be overwritten
+		if abortErr := abort(wt.FullPath); abortErr != nil {
+			log.Debug("Nothing to abort", "worktree", wt.Folder, "error", abortErr)
+			result.Status = SyncStatusFailed
+			return result
+		}
+		result.Status = SyncStatusConflict
+		return result
+	}
+
+	result.Status = SyncStatusSynced
+	return result
+}
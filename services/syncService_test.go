@@ -0,0 +1,215 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/testfixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncWorktree(t *testing.T) {
+	feature := models.Worktree{FullPath: "/wt/feature", Folder: "feature", BranchName: "feature"}
+
+	t.Run("rebases a worktree behind the base branch", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().GetWorkingTreeStatus("/wt/feature").Return(false, false, true, nil)
+		mockGit.EXPECT().GetAheadBehind("/wt/feature", "origin/main").Return(1, 2, nil)
+		mockGit.EXPECT().Rebase("/wt/feature", "origin/main").Return(nil)
+
+		result := SyncWorktree(mockGit, feature, "origin/main", config.SyncRebase)
+
+		assert.Equal(t, SyncStatusSynced, result.Status)
+		assert.False(t, result.Failed())
+	})
+
+	t.Run("merges with the merge strategy", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().GetWorkingTreeStatus("/wt/feature").Return(false, false, false, nil)
+		mockGit.EXPECT().GetAheadBehind("/wt/feature", "origin/main").Return(0, 1, nil)
+		mockGit.EXPECT().Merge("/wt/feature", "origin/main").Return(nil)
+
+		result := SyncWorktree(mockGit, feature, "origin/main", config.SyncMerge)
+
+		assert.Equal(t, SyncStatusSynced, result.Status)
+	})
+
+	t.Run("leaves a worktree that is up to date alone", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().GetWorkingTreeStatus("/wt/feature").Return(false, false, false, nil)
+		mockGit.EXPECT().GetAheadBehind("/wt/feature", "origin/main").Return(3, 0, nil)
+
+		result := SyncWorktree(mockGit, feature, "origin/main", config.SyncRebase)
+
+		assert.Equal(t, SyncStatusUpToDate, result.Status)
+	})
+
+	t.Run("skips uncommitted changes", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().GetWorkingTreeStatus("/wt/feature").Return(false, true, false, nil)
+
+		result := SyncWorktree(mockGit, feature, "origin/main", config.SyncRebase)
+
+		assert.Equal(t, SyncStatusSkipped, result.Status)
+		assert.Equal(t, "uncommitted changes", result.Reason)
+		assert.False(t, result.Failed())
+	})
+
+	t.Run("skips detached worktrees", func(t *testing.T) {
+		result := SyncWorktree(git.NewMockGit(t), models.Worktree{FullPath: "/wt/v1", Detached: true}, "origin/main", config.SyncRebase)
+
+		assert.Equal(t, SyncStatusSkipped, result.Status)
+	})
+
+	t.Run("aborts a conflict", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().GetWorkingTreeStatus("/wt/feature").Return(false, false, false, nil)
+		mockGit.EXPECT().GetAheadBehind("/wt/feature", "origin/main").Return(1, 1, nil)
+		mockGit.EXPECT().Rebase("/wt/feature", "origin/main").Return(errors.New("rebase failed: CONFLICT (add/add): Merge conflict in a.txt"))
+		mockGit.EXPECT().AbortRebase("/wt/feature").Return(nil)
+
+		result := SyncWorktree(mockGit, feature, "origin/main", config.SyncRebase)
+
+		assert.Equal(t, SyncStatusConflict, result.Status)
+		assert.Contains(t, result.Reason, "CONFLICT")
+		assert.True(t, result.Failed())
+	})
+
+	t.Run("fails when git refuses to start", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().GetWorkingTreeStatus("/wt/feature").Return(false, false, true, nil)
+		mockGit.EXPECT().GetAheadBehind("/wt/feature", "origin/main").Return(0, 1, nil)
+		mockGit.EXPECT().Merge("/wt/feature", "origin/main").Return(errors.New("merge failed: untracked working tree files would be overwritten"))
+		mockGit.EXPECT().AbortMerge("/wt/feature").Return(errors.New("no merge to abort"))
+
+		result := SyncWorktree(mockGit, feature, "origin/main", config.SyncMerge)
+
+		assert.Equal(t, SyncStatusFailed, result.Status)
+		assert.True(t, result.Failed())
+	})
+}
+
+func TestSyncWorktreesFetchFails(t *testing.T) {
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().GetBranchBases("/code/widget.git").Return(map[string]string{}, nil)
+	mockGit.EXPECT().GetBranchParents("/code/widget.git").Return(map[string]string{}, nil)
+	mockGit.EXPECT().Fetch("/code/widget.git", "main").Return(errors.New("could not read from remote"))
+
+	cfg := config.AppConfig{BareRepoPath: "/code/widget.git", BaseBranch: "main", SyncStrategy: config.SyncRebase}
+	results, err := SyncWorktrees(mockGit, cfg, []models.Worktree{{FullPath: "/wt/feature", BranchName: "feature"}})
+
+	assert.Error(t, err)
+	assert.Empty(t, results)
+}
+
+func TestSyncWorktreesOntoRecordedBase(t *testing.T) {
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().GetBranchBases("/code/widget.git").Return(map[string]string{"hotfix": "release/2.0"}, nil)
+	mockGit.EXPECT().GetBranchParents("/code/widget.git").Return(map[string]string{}, nil)
+	mockGit.EXPECT().FetchBranches("/code/widget.git", []string{"main", "release/2.0"}).Return(nil)
+	mockGit.EXPECT().GetWorkingTreeStatus("/wt/hotfix").Return(false, false, false, nil)
+	mockGit.EXPECT().GetAheadBehind("/wt/hotfix", "origin/release/2.0").Return(1, 1, nil)
+	mockGit.EXPECT().Rebase("/wt/hotfix", "origin/release/2.0").Return(nil)
+
+	cfg := config.AppConfig{BareRepoPath: "/code/widget.git", BaseBranch: "main", SyncStrategy: config.SyncRebase}
+	results, err := SyncWorktrees(mockGit, cfg, []models.Worktree{{FullPath: "/wt/hotfix", BranchName: "hotfix"}})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, SyncStatusSynced, results[0].Status)
+	assert.Equal(t, "release/2.0", results[0].Worktree.BaseBranch)
+}
+
+func TestSyncWorktreesSkipsStackedBranches(t *testing.T) {
+	mockGit := git.NewMockGit(t)
+	mockGit.EXPECT().GetBranchBases("/code/widget.git").Return(map[string]string{"feature/ui": "feature/api"}, nil)
+	mockGit.EXPECT().GetBranchParents("/code/widget.git").Return(map[string]string{"feature/ui": "feature/api"}, nil)
+	mockGit.EXPECT().Fetch("/code/widget.git", "main").Return(nil)
+	mockGit.EXPECT().GetWorkingTreeStatus("/wt/api").Return(false, false, false, nil)
+	mockGit.EXPECT().GetAheadBehind("/wt/api", "origin/main").Return(1, 0, nil)
+
+	cfg := config.AppConfig{BareRepoPath: "/code/widget.git", BaseBranch: "main", SyncStrategy: config.SyncRebase}
+	results, err := SyncWorktrees(mockGit, cfg, []models.Worktree{
+		{FullPath: "/wt/api", BranchName: "feature/api"},
+		{FullPath: "/wt/ui", BranchName: "feature/ui"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, SyncStatusUpToDate, results[0].Status)
+	assert.Equal(t, SyncStatusSkipped, results[1].Status)
+	assert.Equal(t, "stacked on feature/api, use stack restack", results[1].Reason)
+	assert.False(t, results[1].Failed())
+}
+
+func TestSelectSyncWorktrees(t *testing.T) {
+	listing := []string{
+		"/code/widget.git  (bare)",
+		"/code/widget_work/feature-a  a1c4d34 [feature/a]",
+		"/code/widget_work/feature-b  b2d5e45 [feature/b]",
+	}
+	newMock := func(t *testing.T) *git.MockGit {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().ListWorktrees("/code/widget.git").Return(listing, nil)
+		return mockGit
+	}
+
+	selected, err := SelectSyncWorktrees(newMock(t), "/code/widget.git", nil, true, "/code/widget.git")
+	require.NoError(t, err)
+	assert.Len(t, selected, 2)
+
+	// by branch or folder
+	selected, err = SelectSyncWorktrees(newMock(t), "/code/widget.git", []string{"feature/a", "feature-b"}, false, "/code/widget.git")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"feature/a", "feature/b"}, []string{selected[0].BranchName, selected[1].BranchName})
+
+	_, err = SelectSyncWorktrees(newMock(t), "/code/widget.git", []string{"feature/c"}, false, "/code/widget.git")
+	assert.Error(t, err)
+
+	// the worktree the command runs in
+	selected, err = SelectSyncWorktrees(newMock(t), "/code/widget.git", nil, false, "/code/widget_work/feature-b/src")
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "feature/b", selected[0].BranchName)
+
+	_, err = SelectSyncWorktrees(newMock(t), "/code/widget.git", nil, false, "/code/widget.git")
+	assert.Error(t, err)
+}
+
+func TestSyncWorktreeConflictIsAborted(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := testfixture.NewEnv(t)
+	remote := testfixture.NewRemote(t, "widget").Branch("feature", testfixture.DefaultBranch)
+	bareRepoPath := env.Clone(remote, "widget")
+	remote.Commit(testfixture.DefaultBranch, "move main ahead")
+	testfixture.Git(t, bareRepoPath, "fetch", "-q", "origin")
+
+	// commit the file main just added, with other content
+	conflicting := testfixture.Git(t, bareRepoPath, "diff-tree", "--no-commit-id", "--name-only", "-r", "origin/main")
+	path := filepath.Join(env.Home, "feature")
+	testfixture.Git(t, bareRepoPath, "worktree", "add", "-q", path, "feature")
+	require.NoError(t, os.WriteFile(filepath.Join(path, conflicting), []byte("mine\n"), 0644))
+	testfixture.Git(t, path, "add", conflicting)
+	testfixture.Git(t, path, "commit", "-q", "-m", "conflicting change")
+	head := testfixture.Git(t, path, "rev-parse", "HEAD")
+
+	g := git.NewGit()
+	wt := models.Worktree{FullPath: path, Folder: "feature", BranchName: "feature"}
+	for _, strategy := range []string{config.SyncRebase, config.SyncMerge} {
+		result := SyncWorktree(g, wt, "origin/main", strategy)
+
+		assert.Equal(t, SyncStatusConflict, result.Status, strategy)
+		assert.Contains(t, result.Reason, "CONFLICT", strategy)
+		assert.Equal(t, head, testfixture.Git(t, path, "rev-parse", "HEAD"), strategy)
+		assert.Empty(t, testfixture.Git(t, path, "status", "--porcelain"), strategy)
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/git"
+)
+
+// ErrInterrupted is the cause of a rollback triggered by Ctrl-C.
+var ErrInterrupted = errors.New("interrupted")
+
+type compensation struct {
+	description string
+	undo        func() error
+}
+
+// Transaction collects the compensating action of every step of a multi-step
+// change, e.g. removing the worktree that was just added, so a failure can
+// undo the steps that already succeeded instead of leaving them half done.
+type Transaction struct {
+	keepOnFailure bool
+
+	mu            sync.Mutex
+	compensations []compensation
+	finished      bool
+}
+
+// NewTransaction starts a transaction. With keepOnFailure a rollback only
+// reports what it would have undone, so the leftovers can be inspected.
+func NewTransaction(keepOnFailure bool) *Transaction {
+	return &Transaction{keepOnFailure: keepOnFailure}
+}
+
+// OnRollback registers how to undo a step that just succeeded. Compensations
+// run in reverse order.
+func (t *Transaction) OnRollback(description string, undo func() error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.compensations = append(t.compensations, compensation{description, undo})
+}
+
+// Commit ends the transaction, keeping every step.
+func (t *Transaction) Commit() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.finished = true
+	t.compensations = nil
+}
+
+// Rollback undoes the registered steps, newest first, and returns cause so
+// callers can report it. A compensation that fails is logged and the rest
+// still run. Rolling back a finished transaction does nothing.
+func (t *Transaction) Rollback(cause error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.finished {
+		return cause
+	}
+	t.finished = true
+
+	if t.keepOnFailure {
+		for i := len(t.compensations) - 1; i >= 0; i-- {
+			log.Warn("Keeping partial change for debugging, undo it by hand", "step", t.compensations[i].description)
+		}
+		return cause
+	}
+
+	// Ctrl-C cancelled the git context, undoing still has to run
+	if errors.Is(cause, ErrInterrupted) || errors.Is(cause, context.Canceled) {
+		git.IgnoreCancel()
+	}
+
+	if len(t.compensations) > 0 {
+		log.Warn("Rolling back", "cause", cause)
+	}
+	for i := len(t.compensations) - 1; i >= 0; i-- {
+		c := t.compensations[i]
+		if err := c.undo(); err != nil {
+			log.Error("Rollback step failed", "step", c.description, "error", err)
+			continue
+		}
+		log.Info("Rolled back", "step", c.description)
+	}
+	return cause
+}
+
+// interruptContext returns a context that is cancelled with parent or on
+// Ctrl-C or SIGTERM. While it's active the signals don't kill the process,
+// so the running step can finish and be rolled back; call stop once the
+// transaction is done.
+func interruptContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
+
+// checkInterrupted returns ErrInterrupted once ctx has been cancelled, by a
+// signal or its parent.
+func checkInterrupted(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return ErrInterrupted
+	}
+	return nil
+}
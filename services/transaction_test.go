@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/config"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction(t *testing.T) {
+	cause := errors.New("post script failed")
+
+	t.Run("rollback undoes steps newest first", func(t *testing.T) {
+		var undone []string
+		tx := NewTransaction(false)
+		tx.OnRollback("first", func() error { undone = append(undone, "first"); return nil })
+		tx.OnRollback("second", func() error { undone = append(undone, "second"); return errors.New("boom") })
+		tx.OnRollback("third", func() error { undone = append(undone, "third"); return nil })
+
+		assert.Equal(t, cause, tx.Rollback(cause))
+		// a failing compensation doesn't stop the others
+		assert.Equal(t, []string{"third", "second", "first"}, undone)
+
+		// rolling back twice doesn't undo anything again
+		tx.Rollback(cause)
+		assert.Len(t, undone, 3)
+	})
+
+	t.Run("commit keeps every step", func(t *testing.T) {
+		undone := false
+		tx := NewTransaction(false)
+		tx.OnRollback("step", func() error { undone = true; return nil })
+		tx.Commit()
+		tx.Rollback(cause)
+		assert.False(t, undone)
+	})
+
+	t.Run("keep on failure only reports", func(t *testing.T) {
+		undone := false
+		tx := NewTransaction(true)
+		tx.OnRollback("step", func() error { undone = true; return nil })
+		assert.Equal(t, cause, tx.Rollback(cause))
+		assert.False(t, undone)
+	})
+}
+
+func TestCreateWorktreeRollback(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	g := git.NewGit()
+
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "source")
+	bareRepoPath := filepath.Join(tempDir, "source.git")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", sourcePath},
+		{"-C", sourcePath, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "--allow-empty", "-m", "initial"},
+		{"clone", "-q", "--bare", sourcePath, bareRepoPath},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	require.NoError(t, g.ConfigureBare(bareRepoPath))
+
+	cfg := config.AppConfig{
+		BareRepoPath:            bareRepoPath,
+		WorktreeTargetDir:       filepath.Join(tempDir, "worktrees"),
+		BaseBranch:              "main",
+		BaseBranchExistsLocally: true,
+		NewBranchName:           "feature",
+		NewWorktreeName:         "feature",
+	}
+
+	tx := NewTransaction(false)
+	path, err := createWorktree(g, cfg, tx)
+	require.NoError(t, err)
+	assert.DirExists(t, path)
+
+	tx.Rollback(errors.New("post script failed"))
+
+	assert.NoDirExists(t, path)
+	branches, err := g.GetLocalBranches(bareRepoPath)
+	require.NoError(t, err)
+	assert.NotContains(t, branches, "feature")
+	remote, err := exec.Command("git", "-C", bareRepoPath, "config", "--get", "branch.feature.remote").Output()
+	assert.Error(t, err, "upstream config should be gone, got %q", remote)
+}
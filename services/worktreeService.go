@@ -8,7 +8,7 @@ import (
 	"github.com/garrettkrohn/treekanga/util"
 )
 
-func getWorktrees(bareRepoPath string) []models.Worktree {
+func getWorktrees(git git.Git, bareRepoPath string) []models.Worktree {
 	worktreeStrings, err := git.ListWorktrees(bareRepoPath)
 	if err != nil {
 		log.Fatal(err)
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/charmbracelet/log"
 	"github.com/garrettkrohn/treekanga/git"
@@ -10,14 +11,39 @@ import (
 
 // FetchDefaultBranch fetches origin/<defaultBranch> so subsequent merge and
 // ahead/behind comparisons reflect the remote's current state (R5).
-func FetchDefaultBranch(bareRepoPath, defaultBranch string) error {
+func FetchDefaultBranch(git git.Git, bareRepoPath, defaultBranch string) error {
 	return git.Fetch(bareRepoPath, defaultBranch)
 }
 
 // ComputeWorktreeStatus fills in the R1-R4 status fields on a worktree by
-// shelling out to git. defaultBranch is AppConfig.BaseBranch; callers should
-// fetch it first via FetchDefaultBranch for an up-to-date merge comparison.
-func ComputeWorktreeStatus(worktree models.Worktree, defaultBranch string) models.Worktree {
+// shelling out to git. Ahead/behind and merge status are against the base
+// branch recorded for the worktree's branch, defaultBranch
+// (AppConfig.BaseBranch) when none is, or for a stacked branch its parent;
+// callers should fetch it first via FetchDefaultBranch for an up-to-date
+// merge comparison.
+func ComputeWorktreeStatus(git git.Git, worktree models.Worktree, defaultBranch string) models.Worktree {
+	worktree.BaseBranch = defaultBranch
+	if !worktree.Detached {
+		base, err := git.GetBranchBase(worktree.FullPath, worktree.BranchName)
+		if err != nil {
+			log.Debug("Failed to get base branch", "worktree", worktree.Folder, "error", err)
+		}
+		if base != "" {
+			worktree.BaseBranch = base
+		}
+		parent, err := git.GetBranchParent(worktree.FullPath, worktree.BranchName)
+		if err != nil {
+			log.Debug("Failed to get parent branch", "worktree", worktree.Folder, "error", err)
+		}
+		worktree.StackParent = parent
+	}
+	return computeWorktreeStatus(git, worktree, nil)
+}
+
+// computeWorktreeStatus is ComputeWorktreeStatus for a worktree whose
+// BaseBranch is already set, taking the ahead/behind counts and upstream of
+// the worktree's branch from refs when it has them.
+func computeWorktreeStatus(git git.Git, worktree models.Worktree, refs *git.RefSnapshot) models.Worktree {
 	staged, modified, untracked, err := git.GetWorkingTreeStatus(worktree.FullPath)
 	if err != nil {
 		log.Debug("Failed to get working tree status", "worktree", worktree.Folder, "error", err)
@@ -26,29 +52,53 @@ func ComputeWorktreeStatus(worktree models.Worktree, defaultBranch string) model
 	worktree.HasModified = modified
 	worktree.HasUntracked = untracked
 
-	aheadDefault, behindDefault, err := git.GetAheadBehind(worktree.FullPath, defaultBranch)
-	if err != nil {
-		log.Debug("Failed to get ahead/behind default branch", "worktree", worktree.Folder, "error", err)
-	}
-	worktree.AheadDefault = aheadDefault
-	worktree.BehindDefault = behindDefault
+	branchRef, inSnapshot := refs.LocalRef(worktree.BranchName)
 
-	upstream, err := git.GetUpstreamBranch(worktree.FullPath)
-	if err != nil {
-		log.Debug("Failed to get upstream branch", "worktree", worktree.Folder, "error", err)
+	if inSnapshot && refs.BaseCounted && refs.Base == worktree.BaseBranch {
+		worktree.AheadDefault = branchRef.AheadBase
+		worktree.BehindDefault = branchRef.BehindBase
+	} else {
+		aheadDefault, behindDefault, err := git.GetAheadBehind(worktree.FullPath, worktree.BaseBranch)
+		if err != nil {
+			log.Debug("Failed to get ahead/behind default branch", "worktree", worktree.Folder, "error", err)
+		}
+		worktree.AheadDefault = aheadDefault
+		worktree.BehindDefault = behindDefault
 	}
-	if upstream != "" {
-		worktree.HasUpstream = true
-		aheadRemote, behindRemote, err := git.GetAheadBehind(worktree.FullPath, upstream)
+
+	if inSnapshot {
+		worktree.UpstreamGone = branchRef.UpstreamGone
+		if branchRef.Upstream != "" && !branchRef.UpstreamGone {
+			worktree.HasUpstream = true
+			worktree.AheadRemote = branchRef.AheadUpstream
+			worktree.BehindRemote = branchRef.BehindUpstream
+		}
+	} else {
+		upstream, err := git.GetUpstreamBranch(worktree.FullPath)
 		if err != nil {
-			log.Debug("Failed to get ahead/behind remote", "worktree", worktree.Folder, "error", err)
+			log.Debug("Failed to get upstream branch", "worktree", worktree.Folder, "error", err)
+		}
+		if upstream != "" {
+			worktree.HasUpstream = true
+			aheadRemote, behindRemote, err := git.GetAheadBehind(worktree.FullPath, upstream)
+			if err != nil {
+				log.Debug("Failed to get ahead/behind remote", "worktree", worktree.Folder, "error", err)
+			}
+			worktree.AheadRemote = aheadRemote
+			worktree.BehindRemote = behindRemote
 		}
-		worktree.AheadRemote = aheadRemote
-		worktree.BehindRemote = behindRemote
 	}
 
-	targetRef := fmt.Sprintf("origin/%s", defaultBranch)
-	merged, err := git.IsMerged(worktree.FullPath, worktree.BranchName, targetRef)
+	// Detached worktrees have no branch, so compare whatever HEAD points at
+	ref := worktree.BranchName
+	if worktree.Detached {
+		ref = "HEAD"
+		if tag, err := git.DescribeHead(worktree.FullPath); err == nil {
+			worktree.DetachedRef = tag
+		}
+	}
+
+	merged, err := git.IsMerged(worktree.FullPath, ref, mergeTarget(worktree))
 	if err != nil {
 		log.Debug("Failed to compute merge status", "worktree", worktree.Folder, "error", err)
 		worktree.Merged = models.MergeStatusUnknown
@@ -62,16 +112,86 @@ func ComputeWorktreeStatus(worktree models.Worktree, defaultBranch string) model
 	return worktree
 }
 
-// ComputeAllWorktreeStatuses fetches the default branch once, then computes
+// ComputeAllWorktreeStatuses fetches the base branches once, then computes
 // status for every worktree. Intended for the CLI's synchronous -v path.
-func ComputeAllWorktreeStatuses(bareRepoPath, defaultBranch string, worktrees []models.Worktree) []models.Worktree {
-	if err := FetchDefaultBranch(bareRepoPath, defaultBranch); err != nil {
-		log.Debug("Failed to fetch default branch before computing status", "branch", defaultBranch, "error", err)
+// Ahead/behind counts and upstreams come from one ref snapshot instead of
+// several git commands per worktree.
+func ComputeAllWorktreeStatuses(git git.Git, bareRepoPath, defaultBranch string, worktrees []models.Worktree) []models.Worktree {
+	bases, err := git.GetBranchBases(bareRepoPath)
+	if err != nil {
+		log.Debug("Failed to get base branches, comparing against the default branch", "error", err)
+	}
+	parents, err := git.GetBranchParents(bareRepoPath)
+	if err != nil {
+		log.Debug("Failed to get parent branches, comparing stacked branches against their base", "error", err)
 	}
+	worktrees = applyBaseBranches(worktrees, bases, parents, defaultBranch)
 
+	if err := fetchBaseBranches(git, bareRepoPath, defaultBranch, worktrees); err != nil {
+		log.Debug("Failed to fetch base branches before computing status", "error", err)
+	}
+
+	refs, err := git.GetRefSnapshot(bareRepoPath, defaultBranch)
+	if err != nil {
+		log.Debug("Failed to take ref snapshot, computing status per worktree", "error", err)
+	}
+
+	result := make([]models.Worktree, len(worktrees))
+	for i, wt := range worktrees {
+		result[i] = computeWorktreeStatus(git, wt, refs)
+	}
+	return result
+}
+
+// applyBaseBranches sets each worktree's BaseBranch from the recorded bases,
+// defaultBranch for branches without one, and the StackParent of stacked
+// branches.
+func applyBaseBranches(worktrees []models.Worktree, bases, parents map[string]string, defaultBranch string) []models.Worktree {
 	result := make([]models.Worktree, len(worktrees))
 	for i, wt := range worktrees {
-		result[i] = ComputeWorktreeStatus(wt, defaultBranch)
+		wt.BaseBranch = defaultBranch
+		if base, ok := bases[wt.BranchName]; ok && !wt.Detached {
+			wt.BaseBranch = base
+		}
+		if !wt.Detached {
+			wt.StackParent = parents[wt.BranchName]
+		}
+		result[i] = wt
 	}
 	return result
 }
+
+// mergeTarget is the ref a worktree's branch counts as merged into: its
+// stack parent, which is only local, or else origin/<base>.
+func mergeTarget(wt models.Worktree) string {
+	if wt.StackParent != "" {
+		return wt.StackParent
+	}
+	return fmt.Sprintf("origin/%s", wt.BaseBranch)
+}
+
+// fetchBaseBranches fetches every base branch the worktrees compare against
+// in one go. If one of them isn't on the remote that fetch fails, and only
+// the default branch is fetched.
+func fetchBaseBranches(git git.Git, bareRepoPath, defaultBranch string, worktrees []models.Worktree) error {
+	branches := []string{defaultBranch}
+	for _, wt := range worktrees {
+		// a stacked branch compares against its local parent, which may
+		// not be on the remote
+		if wt.StackParent != "" {
+			continue
+		}
+		if !slices.Contains(branches, wt.BaseBranch) {
+			branches = append(branches, wt.BaseBranch)
+		}
+	}
+	if len(branches) == 1 {
+		return FetchDefaultBranch(git, bareRepoPath, defaultBranch)
+	}
+
+	if err := git.FetchBranches(bareRepoPath, branches); err != nil {
+		log.Debug("Failed to fetch base branches, fetching the default branch only", "branches", branches, "error", err)
+		return FetchDefaultBranch(git, bareRepoPath, defaultBranch)
+	}
+	return nil
+}
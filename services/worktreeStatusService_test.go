@@ -22,6 +22,7 @@ func run(t *testing.T, args ...string) {
 
 func setupComputeStatusRepo(t *testing.T) (bareRepoPath, worktreePath string) {
 	t.Helper()
+	g := git.NewGit()
 
 	tempDir, err := os.MkdirTemp("", "treekanga-compute-status-test-*")
 	require.NoError(t, err)
@@ -29,10 +30,10 @@ func setupComputeStatusRepo(t *testing.T) (bareRepoPath, worktreePath string) {
 
 	bareRepoPath = filepath.Join(tempDir, "test.git")
 	run(t, "git", "init", "--bare", bareRepoPath)
-	require.NoError(t, git.ConfigureBare(bareRepoPath))
+	require.NoError(t, g.ConfigureBare(bareRepoPath))
 
 	worktreePath = filepath.Join(tempDir, "main")
-	require.NoError(t, git.AddWorktree(bareRepoPath, tempDir, "main", []string{"-b", "main"}))
+	require.NoError(t, g.AddWorktree(bareRepoPath, tempDir, "main", []string{"-b", "main"}))
 	run(t, "git", "-C", bareRepoPath, "symbolic-ref", "HEAD", "refs/heads/main")
 
 	run(t, "git", "-C", worktreePath, "config", "user.email", "test@example.com")
@@ -48,10 +49,12 @@ func TestComputeWorktreeStatus(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := git.NewGit()
+
 	bareRepoPath, worktreePath := setupComputeStatusRepo(t)
 
 	featurePath := filepath.Join(filepath.Dir(worktreePath), "feature")
-	require.NoError(t, git.AddWorktree(bareRepoPath, filepath.Dir(worktreePath), "feature", []string{"-b", "feature"}))
+	require.NoError(t, g.AddWorktree(bareRepoPath, filepath.Dir(worktreePath), "feature", []string{"-b", "feature"}))
 	run(t, "git", "-C", featurePath, "config", "user.email", "test@example.com")
 	run(t, "git", "-C", featurePath, "config", "user.name", "Test User")
 	run(t, "sh", "-c", fmt.Sprintf("cd %s && echo 'feature' > feature.txt && git add feature.txt && git commit -m 'feature commit'", featurePath))
@@ -64,7 +67,7 @@ func TestComputeWorktreeStatus(t *testing.T) {
 		BranchName: "feature",
 	}
 
-	result := ComputeWorktreeStatus(worktree, "main")
+	result := ComputeWorktreeStatus(g, worktree, "main")
 
 	assert.True(t, result.StatusLoaded)
 	assert.True(t, result.HasStaged)
@@ -81,10 +84,12 @@ func TestComputeWorktreeStatusMerged(t *testing.T) {
 		t.Skip("Skipping integration test")
 	}
 
+	g := git.NewGit()
+
 	bareRepoPath, worktreePath := setupComputeStatusRepo(t)
 
 	featurePath := filepath.Join(filepath.Dir(worktreePath), "feature")
-	require.NoError(t, git.AddWorktree(bareRepoPath, filepath.Dir(worktreePath), "feature", []string{"-b", "feature"}))
+	require.NoError(t, g.AddWorktree(bareRepoPath, filepath.Dir(worktreePath), "feature", []string{"-b", "feature"}))
 	run(t, "git", "-C", featurePath, "config", "user.email", "test@example.com")
 	run(t, "git", "-C", featurePath, "config", "user.name", "Test User")
 	run(t, "sh", "-c", fmt.Sprintf("cd %s && echo 'feature' > feature.txt && git add feature.txt && git commit -m 'feature commit'", featurePath))
@@ -98,7 +103,7 @@ func TestComputeWorktreeStatusMerged(t *testing.T) {
 		BranchName: "feature",
 	}
 
-	result := ComputeWorktreeStatus(worktree, "main")
+	result := ComputeWorktreeStatus(g, worktree, "main")
 
 	assert.True(t, result.StatusLoaded)
 	assert.False(t, result.HasStaged)
@@ -106,3 +111,122 @@ func TestComputeWorktreeStatusMerged(t *testing.T) {
 	assert.False(t, result.HasUntracked)
 	assert.Equal(t, models.MergeStatusMerged, result.Merged)
 }
+
+// snapshot builds a RefSnapshot holding just the given branch names.
+func snapshot(local, remote []string) *git.RefSnapshot {
+	refs := &git.RefSnapshot{Local: map[string]git.Ref{}, Remote: map[string]git.Ref{}}
+	for _, name := range local {
+		refs.Local[name] = git.Ref{Name: name}
+	}
+	for _, name := range remote {
+		refs.Remote[name] = git.Ref{Name: name}
+	}
+	return refs
+}
+
+func TestComputeAllWorktreeStatusesFromSnapshot(t *testing.T) {
+	worktrees := []models.Worktree{
+		{FullPath: "/work/feature", Folder: "feature", BranchName: "feature"},
+		{FullPath: "/work/shipped", Folder: "shipped", BranchName: "shipped"},
+	}
+	refs := &git.RefSnapshot{
+		Base:        "main",
+		BaseCounted: true,
+		Local: map[string]git.Ref{
+			"feature": {Name: "feature", Upstream: "origin/feature", AheadUpstream: 1, AheadBase: 2, BehindBase: 3},
+			"shipped": {Name: "shipped", Upstream: "origin/shipped", UpstreamGone: true, BehindBase: 4},
+		},
+	}
+
+	expectStatus := func(g *git.MockGit) {
+		for _, wt := range worktrees {
+			g.EXPECT().GetWorkingTreeStatus(wt.FullPath).Return(false, false, false, nil)
+			g.EXPECT().IsMerged(wt.FullPath, wt.BranchName, "origin/main").Return(false, nil)
+		}
+	}
+
+	t.Run("counts and upstreams come from the snapshot", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetBranchBases("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().GetBranchParents("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().Fetch("/bare", "main").Return(nil)
+		g.EXPECT().GetRefSnapshot("/bare", "main").Return(refs, nil)
+		expectStatus(g)
+
+		result := ComputeAllWorktreeStatuses(g, "/bare", "main", worktrees)
+
+		assert.Equal(t, 2, result[0].AheadDefault)
+		assert.Equal(t, 3, result[0].BehindDefault)
+		assert.True(t, result[0].HasUpstream)
+		assert.Equal(t, 1, result[0].AheadRemote)
+		assert.False(t, result[0].UpstreamGone)
+
+		assert.Equal(t, 4, result[1].BehindDefault)
+		assert.False(t, result[1].HasUpstream)
+		assert.True(t, result[1].UpstreamGone)
+	})
+
+	t.Run("git without ahead-behind counts per worktree", func(t *testing.T) {
+		uncounted := *refs
+		uncounted.BaseCounted = false
+
+		g := git.NewMockGit(t)
+		g.EXPECT().GetBranchBases("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().GetBranchParents("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().Fetch("/bare", "main").Return(nil)
+		g.EXPECT().GetRefSnapshot("/bare", "main").Return(&uncounted, nil)
+		g.EXPECT().GetAheadBehind("/work/feature", "main").Return(5, 0, nil)
+		g.EXPECT().GetAheadBehind("/work/shipped", "main").Return(0, 6, nil)
+		expectStatus(g)
+
+		result := ComputeAllWorktreeStatuses(g, "/bare", "main", worktrees)
+
+		assert.Equal(t, 5, result[0].AheadDefault)
+		assert.Equal(t, 6, result[1].BehindDefault)
+		assert.Equal(t, 1, result[0].AheadRemote)
+	})
+
+	t.Run("branches with a recorded base compare against it", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetBranchBases("/bare").Return(map[string]string{"feature": "release/2.0"}, nil)
+		g.EXPECT().GetBranchParents("/bare").Return(map[string]string{}, nil)
+		g.EXPECT().FetchBranches("/bare", []string{"main", "release/2.0"}).Return(nil)
+		g.EXPECT().GetRefSnapshot("/bare", "main").Return(refs, nil)
+		g.EXPECT().GetAheadBehind("/work/feature", "release/2.0").Return(1, 7, nil)
+		g.EXPECT().GetWorkingTreeStatus("/work/feature").Return(false, false, false, nil)
+		g.EXPECT().IsMerged("/work/feature", "feature", "origin/release/2.0").Return(true, nil)
+		g.EXPECT().GetWorkingTreeStatus("/work/shipped").Return(false, false, false, nil)
+		g.EXPECT().IsMerged("/work/shipped", "shipped", "origin/main").Return(false, nil)
+
+		result := ComputeAllWorktreeStatuses(g, "/bare", "main", worktrees)
+
+		assert.Equal(t, "release/2.0", result[0].BaseBranch)
+		assert.Equal(t, 1, result[0].AheadDefault)
+		assert.Equal(t, 7, result[0].BehindDefault)
+		assert.Equal(t, models.MergeStatusMerged, result[0].Merged)
+
+		assert.Equal(t, "main", result[1].BaseBranch)
+		assert.Equal(t, 4, result[1].BehindDefault)
+	})
+
+	t.Run("stacked branches compare against their local parent", func(t *testing.T) {
+		g := git.NewMockGit(t)
+		g.EXPECT().GetBranchBases("/bare").Return(map[string]string{"feature": "feature/api"}, nil)
+		g.EXPECT().GetBranchParents("/bare").Return(map[string]string{"feature": "feature/api"}, nil)
+		// feature/api isn't pushed yet, only the default branch is fetched
+		g.EXPECT().Fetch("/bare", "main").Return(nil)
+		g.EXPECT().GetRefSnapshot("/bare", "main").Return(refs, nil)
+		g.EXPECT().GetAheadBehind("/work/feature", "feature/api").Return(2, 0, nil)
+		g.EXPECT().GetWorkingTreeStatus("/work/feature").Return(false, false, false, nil)
+		g.EXPECT().IsMerged("/work/feature", "feature", "feature/api").Return(false, nil)
+		g.EXPECT().GetWorkingTreeStatus("/work/shipped").Return(false, false, false, nil)
+		g.EXPECT().IsMerged("/work/shipped", "shipped", "origin/main").Return(false, nil)
+
+		result := ComputeAllWorktreeStatuses(g, "/bare", "main", worktrees)
+
+		assert.Equal(t, "feature/api", result[0].StackParent)
+		assert.Equal(t, 2, result[0].AheadDefault)
+		assert.Equal(t, models.MergeStatusNotMerged, result[0].Merged)
+		assert.Empty(t, result[1].StackParent)
+	})
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/adapters"
+	"github.com/garrettkrohn/treekanga/directoryReader"
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// RegisterWithZoxide adds a new worktree and its zoxideFolders to zoxide, so
+// z can jump there before it was ever visited. Does nothing without zoxide.
+func RegisterWithZoxide(zoxide adapters.Zoxide, worktreePath string, zoxideFolders []string, dirReader directoryReader.DirectoryReader) {
+	if zoxide == nil || !zoxide.Available() {
+		return
+	}
+	paths := ExpandWorktreesWithZoxideFolders([]models.Worktree{{FullPath: worktreePath}}, zoxideFolders, dirReader)
+	if err := zoxide.Add(paths...); err != nil {
+		log.Warn("Failed to add the worktree to zoxide", "path", worktreePath, "error", err)
+	}
+}
+
+// ForgetInZoxide removes a worktree and every folder inside it from zoxide.
+// Does nothing without zoxide.
+func ForgetInZoxide(zoxide adapters.Zoxide, worktreePath string) {
+	if zoxide == nil || !zoxide.Available() {
+		return
+	}
+	entries, err := zoxideEntriesIn(zoxide, worktreePath)
+	if err == nil {
+		err = zoxide.Remove(slices.Sorted(maps.Keys(entries))...)
+	}
+	if err != nil {
+		log.Warn("Failed to remove the worktree from zoxide", "path", worktreePath, "error", err)
+	}
+}
+
+// ZoxideEntries returns zoxide's entries for a worktree and the folders
+// inside it, with their scores. Take them before moving the worktree and
+// hand them to MoveInZoxide after. Nil without zoxide.
+func ZoxideEntries(zoxide adapters.Zoxide, worktreePath string) map[string]float64 {
+	if zoxide == nil || !zoxide.Available() {
+		return nil
+	}
+	entries, err := zoxideEntriesIn(zoxide, worktreePath)
+	if err != nil {
+		log.Warn("Failed to get the worktree's zoxide entries", "path", worktreePath, "error", err)
+	}
+	return entries
+}
+
+// MoveInZoxide points the entries ZoxideEntries returned for a worktree at
+// where it was moved to, keeping their scores so connect ranks them the
+// same.
+func MoveInZoxide(zoxide adapters.Zoxide, entries map[string]float64, from, to string) {
+	if zoxide == nil || len(entries) == 0 {
+		return
+	}
+
+	paths := slices.Sorted(maps.Keys(entries))
+	if err := zoxide.Remove(paths...); err != nil {
+		log.Warn("Failed to remove the old worktree path from zoxide", "path", from, "error", err)
+	}
+	for _, path := range paths {
+		moved := to + strings.TrimPrefix(path, from)
+		if err := zoxide.AddWithScore(moved, entries[path]); err != nil {
+			// zoxide before 0.9.5 can't add with a score
+			log.Debug("Failed to add with the old score, adding without it", "path", moved, "error", err)
+			if err := zoxide.Add(moved); err != nil {
+				log.Warn("Failed to add the new worktree path to zoxide", "path", moved, "error", err)
+			}
+		}
+	}
+}
+
+// zoxideEntriesIn returns the paths zoxide knows in dir, dir included, with
+// their scores.
+func zoxideEntriesIn(zoxide adapters.Zoxide, dir string) (map[string]float64, error) {
+	scores, err := zoxide.Scores()
+	if err != nil {
+		return nil, err
+	}
+	entries := map[string]float64{}
+	for path, score := range scores {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			entries[path] = score
+		}
+	}
+	return entries, nil
+}
+
+// zoxideScores returns zoxide's scores, or nil without zoxide.
+func zoxideScores(zoxide adapters.Zoxide) map[string]float64 {
+	if zoxide == nil || !zoxide.Available() {
+		return nil
+	}
+	scores, err := zoxide.Scores()
+	if err != nil {
+		log.Debug("Failed to get zoxide scores", "error", err)
+	}
+	return scores
+}
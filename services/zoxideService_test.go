@@ -0,0 +1,95 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/garrettkrohn/treekanga/adapters"
+	"github.com/garrettkrohn/treekanga/directoryReader"
+	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoxide(t *testing.T) {
+	scores := map[string]float64{
+		"/code/widget_work/feature-a":     12,
+		"/code/widget_work/feature-a/api": 4,
+		"/code/widget_work/feature-ab":    8,
+		"/home/me/notes":                  20,
+	}
+
+	t.Run("registers the worktree and its zoxideFolders", func(t *testing.T) {
+		worktree := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(worktree, "api"), 0755))
+
+		zoxide := adapters.NewMockZoxide(t)
+		zoxide.EXPECT().Available().Return(true)
+		zoxide.EXPECT().Add(worktree, filepath.Join(worktree, "api")).Return(nil)
+
+		RegisterWithZoxide(zoxide, worktree, []string{"api", "web"}, directoryReader.NewMockDirectoryReader(t))
+	})
+
+	t.Run("does nothing without zoxide", func(t *testing.T) {
+		zoxide := adapters.NewMockZoxide(t)
+		zoxide.EXPECT().Available().Return(false)
+
+		RegisterWithZoxide(zoxide, "/code/widget_work/feature-a", nil, nil)
+		ForgetInZoxide(zoxide, "/code/widget_work/feature-a")
+		assert.Nil(t, ZoxideEntries(zoxide, "/code/widget_work/feature-a"))
+		RegisterWithZoxide(nil, "/code/widget_work/feature-a", nil, nil)
+	})
+
+	t.Run("forgets the worktree and the folders in it", func(t *testing.T) {
+		zoxide := adapters.NewMockZoxide(t)
+		zoxide.EXPECT().Available().Return(true)
+		zoxide.EXPECT().Scores().Return(scores, nil)
+		zoxide.EXPECT().Remove("/code/widget_work/feature-a", "/code/widget_work/feature-a/api").Return(nil)
+
+		ForgetInZoxide(zoxide, "/code/widget_work/feature-a")
+	})
+
+	t.Run("moves the worktree and the folders in it", func(t *testing.T) {
+		zoxide := adapters.NewMockZoxide(t)
+		zoxide.EXPECT().Available().Return(true)
+		zoxide.EXPECT().Scores().Return(scores, nil)
+		zoxide.EXPECT().Remove("/code/widget_work/feature-a", "/code/widget_work/feature-a/api").Return(nil)
+		zoxide.EXPECT().AddWithScore("/code/widget_work/login", 12.0).Return(nil)
+		zoxide.EXPECT().AddWithScore("/code/widget_work/login/api", 4.0).Return(assert.AnError)
+		zoxide.EXPECT().Add("/code/widget_work/login/api").Return(nil)
+
+		// Taken before the move, zoxide doesn't list folders that are gone
+		entries := ZoxideEntries(zoxide, "/code/widget_work/feature-a")
+		assert.Equal(t, map[string]float64{"/code/widget_work/feature-a": 12, "/code/widget_work/feature-a/api": 4}, entries)
+		MoveInZoxide(zoxide, entries, "/code/widget_work/feature-a", "/code/widget_work/login")
+	})
+
+	t.Run("ranks connect candidates by score", func(t *testing.T) {
+		mockGit := git.NewMockGit(t)
+		mockGit.EXPECT().ListWorktrees("/code/widget.git").Return([]string{
+			"/code/widget_work/feature-ab  a1c4d34 [feature/ab]",
+			"/code/widget_work/main        b2d5e45 [main]",
+			"/code/widget_work/feature-a   c3e6f56 [feature/a]",
+		}, nil)
+		zoxide := adapters.NewMockZoxide(t)
+		zoxide.EXPECT().Available().Return(true)
+		zoxide.EXPECT().Scores().Return(scores, nil)
+
+		candidates := ConnectCandidates(mockGit, nil, zoxide,
+			[]models.Session{{Name: "notes", Path: "/home/me/notes", Src: "tmux"}},
+			[]string{"/code/widget.git"}, nil)
+
+		var paths []string
+		for _, c := range candidates {
+			paths = append(paths, c.Session.Path)
+		}
+		assert.Equal(t, []string{
+			"/home/me/notes",
+			"/code/widget_work/feature-a",
+			"/code/widget_work/feature-ab",
+			"/code/widget_work/main",
+		}, paths)
+	})
+}
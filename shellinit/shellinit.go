@@ -0,0 +1,89 @@
+package shellinit
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// CdFileEnv names the file the shell function reads the directory to cd
+// into from, once treekanga exits. The function sets it for every run.
+const CdFileEnv = "TREEKANGA_CD_FILE"
+
+const posixScript = `# treekanga shell integration, lets treekanga change the shell's directory
+treekanga() {
+  local cd_file rc
+  cd_file="$(mktemp -t treekanga-cd.XXXXXX)" || {
+    command treekanga "$@"
+    return
+  }
+  TREEKANGA_CD_FILE="$cd_file" command treekanga "$@"
+  rc=$?
+  if [ -s "$cd_file" ]; then
+    cd -- "$(cat "$cd_file")" || rc=$?
+  fi
+  rm -f -- "$cd_file"
+  return $rc
+}
+`
+
+const fishScript = `# treekanga shell integration, lets treekanga change the shell's directory
+function treekanga --wraps treekanga
+    set -l cd_file (mktemp -t treekanga-cd.XXXXXX)
+    or begin
+        command treekanga $argv
+        return
+    end
+    TREEKANGA_CD_FILE=$cd_file command treekanga $argv
+    set -l code $status
+    if test -s $cd_file
+        cd (cat $cd_file)
+        or set code $status
+    end
+    rm -f -- $cd_file
+    return $code
+end
+`
+
+var scripts = map[string]string{
+	"bash": posixScript,
+	"zsh":  posixScript,
+	"fish": fishScript,
+}
+
+// Shells lists the shells Script supports.
+func Shells() []string {
+	shells := make([]string, 0, len(scripts))
+	for shell := range scripts {
+		shells = append(shells, shell)
+	}
+	slices.Sort(shells)
+	return shells
+}
+
+// Script returns the shell function that wraps treekanga for shell.
+func Script(shell string) (string, error) {
+	script, ok := scripts[shell]
+	if !ok {
+		return "", fmt.Errorf("unsupported shell '%s', use one of %s", shell, strings.Join(Shells(), ", "))
+	}
+	return script, nil
+}
+
+// RequestCd asks the shell function to cd into dir once treekanga exits. It
+// reports false when treekanga wasn't run through the function, or dir
+// doesn't exist, e.g. in a dry run.
+func RequestCd(dir string) (bool, error) {
+	cdFile := os.Getenv(CdFileEnv)
+	if cdFile == "" {
+		return false, nil
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return false, nil
+	}
+	if err := os.WriteFile(cdFile, []byte(dir+"\n"), 0600); err != nil {
+		return false, fmt.Errorf("failed to tell the shell to cd into %s: %w", dir, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,85 @@
+package shellinit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScript(t *testing.T) {
+	for _, shell := range Shells() {
+		script, err := Script(shell)
+		require.NoError(t, err)
+		assert.Contains(t, script, CdFileEnv)
+		assert.Contains(t, script, "command treekanga")
+	}
+
+	// zsh makes status read-only, assigning it fails every call
+	script, err := Script("zsh")
+	require.NoError(t, err)
+	assert.NotRegexp(t, `(^|[\s;])(local [^\n]*\bstatus\b|status=)`, script)
+
+	_, err = Script("powershell")
+	assert.EqualError(t, err, "unsupported shell 'powershell', use one of bash, fish, zsh")
+}
+
+func TestRequestCd(t *testing.T) {
+	dir := t.TempDir()
+	cdFile := filepath.Join(t.TempDir(), "cd")
+
+	t.Setenv(CdFileEnv, "")
+	requested, err := RequestCd(dir)
+	require.NoError(t, err)
+	assert.False(t, requested)
+
+	t.Setenv(CdFileEnv, cdFile)
+	requested, err = RequestCd(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.False(t, requested)
+	assert.NoFileExists(t, cdFile)
+
+	requested, err = RequestCd(dir)
+	require.NoError(t, err)
+	assert.True(t, requested)
+	data, err := os.ReadFile(cdFile)
+	require.NoError(t, err)
+	assert.Equal(t, dir+"\n", string(data))
+}
+
+// TestScriptChangesDirectory runs the function in each installed shell with a
+// stand-in treekanga that asks to cd into a folder with a space in its name.
+func TestScriptChangesDirectory(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	bin := t.TempDir()
+	target := filepath.Join(t.TempDir(), "new worktree")
+	require.NoError(t, os.Mkdir(target, 0755))
+	fake := "#!/bin/sh\nprintf '%s\\n' \"" + target + "\" > \"$" + CdFileEnv + "\"\nexit 3\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "treekanga"), []byte(fake), 0755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	for _, shell := range Shells() {
+		t.Run(shell, func(t *testing.T) {
+			if _, err := exec.LookPath(shell); err != nil {
+				t.Skipf("%s isn't installed", shell)
+			}
+			script, err := Script(shell)
+			require.NoError(t, err)
+
+			status := "echo $?"
+			if shell == "fish" {
+				status = "echo $status"
+			}
+			output, err := exec.Command(shell, "-c", script+"\ntreekanga add x\n"+status+"\npwd\n").CombinedOutput()
+			require.NoError(t, err, string(output))
+			assert.Equal(t, []string{"3", target}, strings.Split(strings.TrimSpace(string(output)), "\n"))
+		})
+	}
+}
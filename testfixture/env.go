@@ -0,0 +1,96 @@
+package testfixture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// Env is an isolated environment for one test: HOME points at a temp dir
+// holding the git and treekanga config, and tmux on PATH is a FakeTmux.
+// It uses t.Setenv, so tests using it can't run in parallel.
+type Env struct {
+	t    testing.TB
+	Home string
+	Tmux *FakeTmux
+}
+
+// NewEnv sets up the environment and changes into HOME.
+func NewEnv(t testing.TB) *Env {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	// A zoxide on PATH keeps its database in HOME instead of the user's
+	t.Setenv("_ZO_DATA_DIR", filepath.Join(home, ".local", "share", "zoxide"))
+
+	// Only the config written here applies, not the user's or the system's
+	gitConfig := filepath.Join(home, ".gitconfig")
+	t.Setenv("GIT_CONFIG_GLOBAL", gitConfig)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	Git(t, "", "config", "--file", gitConfig, "user.name", "treekanga")
+	Git(t, "", "config", "--file", gitConfig, "user.email", "treekanga@example.com")
+	Git(t, "", "config", "--file", gitConfig, "init.defaultBranch", DefaultBranch)
+
+	// Not inside tmux, so connecting attaches instead of switching
+	t.Setenv("TMUX", "")
+	tmux := NewFakeTmux(t)
+	t.Setenv("PATH", tmux.Dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	chdir(t, home)
+	return &Env{t: t, Home: home, Tmux: tmux}
+}
+
+// WriteConfig writes ~/.config/treekanga/treekanga.yml with settings under
+// repos.<repo> and loads it the way main does. The config is reset when the
+// test ends.
+func (e *Env) WriteConfig(repo string, settings map[string]any) string {
+	e.t.Helper()
+
+	dir := filepath.Join(e.Home, ".config", "treekanga")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		e.t.Fatalf("failed to create %s: %v", dir, err)
+	}
+	path := filepath.Join(dir, "treekanga.yml")
+
+	viper.Reset()
+	e.t.Cleanup(viper.Reset)
+	viper.Set("repos."+repo, settings)
+	if err := viper.WriteConfigAs(path); err != nil {
+		e.t.Fatalf("failed to write %s: %v", path, err)
+	}
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		e.t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return path
+}
+
+// Clone clones remote bare into HOME as <name>_bare, configured like
+// treekanga clone does, and returns its path.
+func (e *Env) Clone(remote *Remote, name string) string {
+	e.t.Helper()
+
+	bareRepoPath := filepath.Join(e.Home, name+"_bare")
+	Git(e.t, "", "clone", "-q", "--bare", remote.URL(), bareRepoPath)
+	Git(e.t, bareRepoPath, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	Git(e.t, bareRepoPath, "fetch", "-q", "origin")
+	return bareRepoPath
+}
+
+// chdir changes the working directory until the test ends.
+func chdir(t testing.TB, dir string) {
+	t.Helper()
+
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("failed to change to %s: %v", dir, err)
+	}
+	t.Cleanup(func() { os.Chdir(previous) })
+}
@@ -0,0 +1,140 @@
+// Package testfixture builds hermetic environments for integration tests: a
+// local bare repository standing in for the origin remote, an isolated HOME
+// with its own git and treekanga config, and a fake tmux on PATH. Nothing
+// touches the network or the user's real config.
+package testfixture
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// DefaultBranch is the branch every Remote starts with.
+const DefaultBranch = "main"
+
+// Remote is a bare repository on disk that tests clone instead of a real
+// remote. Branches, commits and merges are made in a scratch clone and
+// pushed, so the remote only ever changes the way a real one would.
+type Remote struct {
+	t       testing.TB
+	dir     string
+	scratch string
+	commits int
+}
+
+// NewRemote creates <tempdir>/<name>.git with one commit on DefaultBranch.
+// The project name treekanga derives from its url is name.
+func NewRemote(t testing.TB, name string) *Remote {
+	t.Helper()
+
+	root := t.TempDir()
+	r := &Remote{
+		t:       t,
+		dir:     filepath.Join(root, name+".git"),
+		scratch: filepath.Join(root, name+"-scratch"),
+	}
+
+	Git(t, "", "init", "-q", "--bare", "-b", DefaultBranch, r.dir)
+	Git(t, "", "init", "-q", "-b", DefaultBranch, r.scratch)
+	Git(t, r.scratch, "remote", "add", "origin", r.dir)
+	r.commit("initial commit")
+	Git(t, r.scratch, "push", "-q", "origin", DefaultBranch)
+	return r
+}
+
+// URL returns what to pass to clone.
+func (r *Remote) URL() string {
+	return r.dir
+}
+
+// Branch creates branch from the tip of from with one commit of its own and
+// pushes it.
+func (r *Remote) Branch(branch, from string) *Remote {
+	r.t.Helper()
+	Git(r.t, r.scratch, "checkout", "-q", "-b", branch, from)
+	r.commit("start " + branch)
+	Git(r.t, r.scratch, "push", "-q", "origin", branch)
+	return r
+}
+
+// Commit adds a commit on top of branch and pushes it.
+func (r *Remote) Commit(branch, message string) *Remote {
+	r.t.Helper()
+	Git(r.t, r.scratch, "checkout", "-q", branch)
+	r.commit(message)
+	Git(r.t, r.scratch, "push", "-q", "origin", branch)
+	return r
+}
+
+// Merge merges branch into into with a merge commit, like a pull request
+// merged with "Create a merge commit".
+func (r *Remote) Merge(branch, into string) *Remote {
+	r.t.Helper()
+	Git(r.t, r.scratch, "checkout", "-q", into)
+	Git(r.t, r.scratch, "merge", "-q", "--no-ff", "-m", "Merge "+branch, branch)
+	Git(r.t, r.scratch, "push", "-q", "origin", into)
+	return r
+}
+
+// SquashMerge applies branch to into as a single new commit, like a pull
+// request merged with "Squash and merge". branch itself isn't an ancestor
+// of into afterwards.
+func (r *Remote) SquashMerge(branch, into string) *Remote {
+	r.t.Helper()
+	Git(r.t, r.scratch, "checkout", "-q", into)
+	Git(r.t, r.scratch, "merge", "-q", "--squash", branch)
+	Git(r.t, r.scratch, "commit", "-q", "-m", "Squash "+branch)
+	Git(r.t, r.scratch, "push", "-q", "origin", into)
+	return r
+}
+
+// Tag tags ref and pushes the tag.
+func (r *Remote) Tag(tag, ref string) *Remote {
+	r.t.Helper()
+	Git(r.t, r.scratch, "tag", tag, ref)
+	Git(r.t, r.scratch, "push", "-q", "origin", tag)
+	return r
+}
+
+// DeleteBranch deletes branch on the remote, like deleting it after its pull
+// request was merged.
+func (r *Remote) DeleteBranch(branch string) *Remote {
+	r.t.Helper()
+	Git(r.t, r.scratch, "push", "-q", "origin", "--delete", branch)
+	return r
+}
+
+// commit writes a new file so every commit has its own change, which keeps
+// squash merges and patch-ids distinct.
+func (r *Remote) commit(message string) {
+	r.t.Helper()
+	r.commits++
+	name := fmt.Sprintf("%02d-%s.txt", r.commits, strings.NewReplacer("/", "-", " ", "-").Replace(message))
+	err := os.WriteFile(filepath.Join(r.scratch, name), []byte(message+"\n"), 0644)
+	if err != nil {
+		r.t.Fatalf("failed to write %s: %v", name, err)
+	}
+	Git(r.t, r.scratch, "add", name)
+	Git(r.t, r.scratch, "commit", "-q", "-m", message)
+}
+
+// Git runs git in dir (the current directory when empty) and fails the test
+// if it doesn't succeed. It returns the trimmed output.
+func Git(t testing.TB, dir string, args ...string) string {
+	t.Helper()
+	command := exec.Command("git", args...)
+	command.Dir = dir
+	command.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=treekanga", "GIT_AUTHOR_EMAIL=treekanga@example.com",
+		"GIT_COMMITTER_NAME=treekanga", "GIT_COMMITTER_EMAIL=treekanga@example.com",
+	)
+	output, err := command.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
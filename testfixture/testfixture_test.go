@@ -0,0 +1,83 @@
+package testfixture
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemote(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := NewEnv(t)
+	remote := NewRemote(t, "widget").
+		Branch("merged", DefaultBranch).
+		Branch("squashed", DefaultBranch).
+		Merge("merged", DefaultBranch).
+		SquashMerge("squashed", DefaultBranch).
+		Tag("v1.0.0", DefaultBranch)
+	bareRepoPath := env.Clone(remote, "widget")
+
+	branches := Git(t, bareRepoPath, "branch", "-r", "--format=%(refname:short)")
+	assert.ElementsMatch(t, []string{"origin/main", "origin/merged", "origin/squashed"}, strings.Split(branches, "\n"))
+	assert.Equal(t, Git(t, bareRepoPath, "rev-parse", "origin/main"), Git(t, bareRepoPath, "rev-parse", "v1.0.0"))
+
+	// a merge keeps the branch as an ancestor, a squash merge doesn't
+	Git(t, bareRepoPath, "merge-base", "--is-ancestor", "origin/merged", "origin/main")
+	err := exec.Command("git", "-C", bareRepoPath, "merge-base", "--is-ancestor", "origin/squashed", "origin/main").Run()
+	assert.Error(t, err)
+
+	remote.DeleteBranch("merged")
+	Git(t, bareRepoPath, "fetch", "-q", "--prune", "origin")
+	assert.NotContains(t, Git(t, bareRepoPath, "branch", "-r"), "origin/merged")
+}
+
+func TestEnv(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := NewEnv(t)
+
+	path := env.WriteConfig("widget", map[string]any{"defaultBranch": "main"})
+	assert.Equal(t, filepath.Join(env.Home, ".config", "treekanga", "treekanga.yml"), path)
+	assert.FileExists(t, path)
+	assert.Equal(t, "main", viper.GetString("repos.widget.defaultBranch"))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, env.Home, wd)
+}
+
+func TestFakeTmux(t *testing.T) {
+	if os.Getenv("SKIP_INTEGRATION_TESTS") != "" {
+		t.Skip("Skipping integration test")
+	}
+
+	env := NewEnv(t)
+
+	tmux := func(args ...string) string {
+		output, err := exec.Command("tmux", args...).Output()
+		require.NoError(t, err)
+		return string(output)
+	}
+
+	tmux("new-session", "-d", "-s", "widget-main", "-c", "/code/widget_work/main")
+	tmux("send-keys", "-t", "widget-main", "echo hello world", "Enter")
+	assert.Equal(t, "widget-main:/code/widget_work/main\n", tmux("list-sessions", "-F", "#{session_name}:#{session_path}"))
+	assert.Equal(t, []string{"widget-main"}, env.Tmux.Sessions())
+	assert.True(t, env.Tmux.Called("send-keys", "echo hello world"))
+
+	tmux("kill-session", "-t", "widget-main")
+	assert.Empty(t, env.Tmux.Sessions())
+	assert.Len(t, env.Tmux.Calls(), 4)
+	assert.Equal(t, []string{"kill-session", "-t", "widget-main"}, env.Tmux.Calls()[3])
+}
@@ -0,0 +1,144 @@
+package testfixture
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeTmuxScript records every invocation, one line of tab separated
+// arguments each, and keeps just enough session state for the connector:
+// new-session adds "name:path" to the sessions file, kill-session removes
+// it and list-sessions prints it. list-panes prints the panes added with
+// AddPane. Everything else succeeds silently.
+const fakeTmuxScript = `#!/bin/sh
+dir=$(dirname "$0")
+for arg in "$@"; do printf '%s\t' "$arg"; done >> "$dir/calls"
+printf '\n' >> "$dir/calls"
+touch "$dir/sessions" "$dir/panes"
+
+case "$1" in
+list-sessions)
+	cat "$dir/sessions"
+	;;
+list-panes)
+	cat "$dir/panes"
+	;;
+new-session)
+	while [ $# -gt 0 ]; do
+		case "$1" in
+		-s) name=$2; shift ;;
+		-c) path=$2; shift ;;
+		esac
+		shift
+	done
+	echo "$name:$path" >> "$dir/sessions"
+	;;
+kill-session)
+	grep -v "^$3:" "$dir/sessions" > "$dir/sessions.tmp"
+	mv "$dir/sessions.tmp" "$dir/sessions"
+	;;
+display-message)
+	echo "treekanga-test"
+	;;
+esac
+exit 0
+`
+
+// FakeTmux is a tmux stand-in that records how it was called.
+type FakeTmux struct {
+	t   testing.TB
+	Dir string
+}
+
+// NewFakeTmux writes the fake tmux binary to a temp dir. NewEnv puts it on
+// PATH.
+func NewFakeTmux(t testing.TB) *FakeTmux {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tmux"), []byte(fakeTmuxScript), 0755); err != nil {
+		t.Fatalf("failed to write fake tmux: %v", err)
+	}
+	return &FakeTmux{t: t, Dir: dir}
+}
+
+// Calls returns the arguments of every tmux invocation so far.
+func (f *FakeTmux) Calls() [][]string {
+	f.t.Helper()
+
+	var calls [][]string
+	for _, line := range f.lines("calls") {
+		calls = append(calls, strings.Split(strings.TrimSuffix(line, "\t"), "\t"))
+	}
+	return calls
+}
+
+// Called reports whether tmux was run with a subcommand whose arguments
+// include all of args.
+func (f *FakeTmux) Called(subcommand string, args ...string) bool {
+	f.t.Helper()
+
+	for _, call := range f.Calls() {
+		if call[0] != subcommand {
+			continue
+		}
+		found := true
+		for _, arg := range args {
+			if !slices.Contains(call[1:], arg) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// AddPane adds a pane list-panes lists, running command in path.
+func (f *FakeTmux) AddPane(id, session, command, path string) {
+	f.t.Helper()
+
+	file, err := os.OpenFile(filepath.Join(f.Dir, "panes"), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		f.t.Fatalf("failed to open fake tmux panes: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(strings.Join([]string{id, session, command, path}, "\t") + "\n"); err != nil {
+		f.t.Fatalf("failed to add fake tmux pane: %v", err)
+	}
+}
+
+// Sessions returns the names of the sessions that are currently open.
+func (f *FakeTmux) Sessions() []string {
+	f.t.Helper()
+
+	var sessions []string
+	for _, line := range f.lines("sessions") {
+		name, _, _ := strings.Cut(line, ":")
+		sessions = append(sessions, name)
+	}
+	return sessions
+}
+
+func (f *FakeTmux) lines(file string) []string {
+	data, err := os.ReadFile(filepath.Join(f.Dir, file))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		f.t.Fatalf("failed to read fake tmux %s: %v", file, err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,72 @@
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+const defaultGitHubBaseURL = "https://api.github.com"
+
+// GitHubTracker implements Tracker against GitHub issues.
+type GitHubTracker struct {
+	baseURL string
+	owner   string
+	repo    string
+	token   string
+}
+
+// NewGitHubTracker creates a GitHub issues client for owner/repo.
+func NewGitHubTracker(baseURL, owner, repo, token string) *GitHubTracker {
+	if baseURL == "" {
+		baseURL = defaultGitHubBaseURL
+	}
+	return &GitHubTracker{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		owner:   owner,
+		repo:    repo,
+		token:   token,
+	}
+}
+
+type githubIssue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	HTMLURL string `json:"html_url"`
+	Labels  []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// GetIssue accepts "123" or "#123". The first label is used as the issue
+// type, since GitHub issues have no type of their own.
+func (g *GitHubTracker) GetIssue(key string) (models.Issue, error) {
+	number := strings.TrimPrefix(key, "#")
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/issues/%s", g.baseURL, g.owner, g.repo, number)
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return models.Issue{}, err
+	}
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	var issue githubIssue
+	if err := doJSON(req, &issue); err != nil {
+		return models.Issue{}, err
+	}
+
+	issueType := ""
+	if len(issue.Labels) > 0 {
+		issueType = issue.Labels[0].Name
+	}
+
+	return models.Issue{
+		Key:   fmt.Sprintf("%d", issue.Number),
+		Title: issue.Title,
+		Type:  issueType,
+		URL:   issue.HTMLURL,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package tracker
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// JiraTracker implements Tracker against the Jira REST API.
+type JiraTracker struct {
+	baseURL string
+	user    string
+	token   string
+}
+
+// NewJiraTracker creates a Jira client. With a user the token is sent as
+// basic auth (Jira Cloud), otherwise as a bearer token (Data Center PAT).
+func NewJiraTracker(baseURL, user, token string) *JiraTracker {
+	return &JiraTracker{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		user:    user,
+		token:   token,
+	}
+}
+
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary   string `json:"summary"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+	} `json:"fields"`
+}
+
+func (j *JiraTracker) GetIssue(key string) (models.Issue, error) {
+	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=summary,issuetype", j.baseURL, url.PathEscape(key))
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return models.Issue{}, err
+	}
+	if j.user != "" {
+		req.SetBasicAuth(j.user, j.token)
+	} else if j.token != "" {
+		req.Header.Set("Authorization", "Bearer "+j.token)
+	}
+
+	var issue jiraIssue
+	if err := doJSON(req, &issue); err != nil {
+		return models.Issue{}, err
+	}
+
+	return models.Issue{
+		Key:   issue.Key,
+		Title: issue.Fields.Summary,
+		Type:  issue.Fields.IssueType.Name,
+		URL:   j.baseURL + "/browse/" + issue.Key,
+	}, nil
+}
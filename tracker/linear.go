@@ -0,0 +1,93 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+const defaultLinearBaseURL = "https://api.linear.app"
+
+// LinearTracker implements Tracker against the Linear GraphQL API.
+type LinearTracker struct {
+	baseURL string
+	token   string
+}
+
+// NewLinearTracker creates a Linear client. token is a personal API key.
+func NewLinearTracker(baseURL, token string) *LinearTracker {
+	if baseURL == "" {
+		baseURL = defaultLinearBaseURL
+	}
+	return &LinearTracker{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}
+}
+
+const linearIssueQuery = `query Issue($id: String!) {
+  issue(id: $id) {
+    identifier
+    title
+    url
+    labels { nodes { name } }
+  }
+}`
+
+type linearResponse struct {
+	Data struct {
+		Issue *struct {
+			Identifier string `json:"identifier"`
+			Title      string `json:"title"`
+			URL        string `json:"url"`
+			Labels     struct {
+				Nodes []struct {
+					Name string `json:"name"`
+				} `json:"nodes"`
+			} `json:"labels"`
+		} `json:"issue"`
+	} `json:"data"`
+}
+
+func (l *LinearTracker) GetIssue(key string) (models.Issue, error) {
+	body, err := json.Marshal(map[string]any{
+		"query":     linearIssueQuery,
+		"variables": map[string]string{"id": key},
+	})
+	if err != nil {
+		return models.Issue{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, l.baseURL+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		return models.Issue{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if l.token != "" {
+		req.Header.Set("Authorization", l.token)
+	}
+
+	var resp linearResponse
+	if err := doJSON(req, &resp); err != nil {
+		return models.Issue{}, err
+	}
+	if resp.Data.Issue == nil {
+		return models.Issue{}, ErrNotFound
+	}
+
+	issue := resp.Data.Issue
+	issueType := ""
+	if len(issue.Labels.Nodes) > 0 {
+		issueType = issue.Labels.Nodes[0].Name
+	}
+
+	return models.Issue{
+		Key:   issue.Identifier,
+		Title: issue.Title,
+		Type:  issueType,
+		URL:   issue.URL,
+	}, nil
+}
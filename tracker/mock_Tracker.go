@@ -0,0 +1,91 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package tracker
+
+import (
+	models "github.com/garrettkrohn/treekanga/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockTracker is an autogenerated mock type for the Tracker type
+type MockTracker struct {
+	mock.Mock
+}
+
+type MockTracker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTracker) EXPECT() *MockTracker_Expecter {
+	return &MockTracker_Expecter{mock: &_m.Mock}
+}
+
+// GetIssue provides a mock function with given fields: key
+func (_m *MockTracker) GetIssue(key string) (models.Issue, error) {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIssue")
+	}
+
+	var r0 models.Issue
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (models.Issue, error)); ok {
+		return rf(key)
+	}
+	if rf, ok := ret.Get(0).(func(string) models.Issue); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Get(0).(models.Issue)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTracker_GetIssue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIssue'
+type MockTracker_GetIssue_Call struct {
+	*mock.Call
+}
+
+// GetIssue is a helper method to define mock.On call
+//   - key string
+func (_e *MockTracker_Expecter) GetIssue(key interface{}) *MockTracker_GetIssue_Call {
+	return &MockTracker_GetIssue_Call{Call: _e.mock.On("GetIssue", key)}
+}
+
+func (_c *MockTracker_GetIssue_Call) Run(run func(key string)) *MockTracker_GetIssue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockTracker_GetIssue_Call) Return(_a0 models.Issue, _a1 error) *MockTracker_GetIssue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTracker_GetIssue_Call) RunAndReturn(run func(string) (models.Issue, error)) *MockTracker_GetIssue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTracker creates a new instance of MockTracker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTracker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTracker {
+	mock := &MockTracker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
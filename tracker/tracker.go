@@ -0,0 +1,76 @@
+package tracker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// ErrNotFound is returned when the tracker has no issue with the given key.
+var ErrNotFound = errors.New("issue not found")
+
+// Tracker resolves issue keys to issues on an issue tracking service.
+type Tracker interface {
+	GetIssue(key string) (models.Issue, error)
+}
+
+// Options configures a Tracker implementation.
+type Options struct {
+	Type    string // "jira", "github" or "linear"
+	BaseURL string // API base url, required for jira
+	User    string // jira account email used for basic auth
+	Token   string // API token
+	Owner   string // github repository owner
+	Repo    string // github repository name
+}
+
+// NewTracker returns the Tracker implementation selected by opts.Type.
+func NewTracker(opts Options) (Tracker, error) {
+	switch strings.ToLower(opts.Type) {
+	case "jira":
+		if opts.BaseURL == "" {
+			return nil, fmt.Errorf("jira issue tracker requires issueTrackerBaseUrl")
+		}
+		return NewJiraTracker(opts.BaseURL, opts.User, opts.Token), nil
+	case "github":
+		return NewGitHubTracker(opts.BaseURL, opts.Owner, opts.Repo, opts.Token), nil
+	case "linear":
+		return NewLinearTracker(opts.BaseURL, opts.Token), nil
+	default:
+		return nil, fmt.Errorf("unsupported issue tracker: %q", opts.Type)
+	}
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// doJSON sends req and decodes a 200 response into out. A 404 maps to
+// ErrNotFound so callers can report a bad key rather than a broken setup.
+func doJSON(req *http.Request, out any) error {
+	req.Header.Set("Accept", "application/json")
+
+	log.Debug("Issue tracker request", "method", req.Method, "url", req.URL.String())
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("issue tracker request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("issue tracker returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode issue tracker response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJiraTrackerGetIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, token, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "me@example.com", user)
+		assert.Equal(t, "secret", token)
+
+		if r.URL.Path != "/rest/api/2/issue/JIRA-123" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"key": "JIRA-123", "fields": {"summary": "Short slug", "issuetype": {"name": "Bug"}}}`)
+	}))
+	defer server.Close()
+
+	tr := NewJiraTracker(server.URL, "me@example.com", "secret")
+
+	issue, err := tr.GetIssue("JIRA-123")
+	require.NoError(t, err)
+	assert.Equal(t, "JIRA-123", issue.Key)
+	assert.Equal(t, "Short slug", issue.Title)
+	assert.Equal(t, "Bug", issue.Type)
+	assert.Equal(t, server.URL+"/browse/JIRA-123", issue.URL)
+
+	_, err = tr.GetIssue("JIRA-999")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGitHubTrackerGetIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/octo/app/issues/42", r.URL.Path)
+		fmt.Fprint(w, `{"number": 42, "title": "Crash on save", "html_url": "https://github.com/octo/app/issues/42", "labels": [{"name": "bug"}]}`)
+	}))
+	defer server.Close()
+
+	issue, err := NewGitHubTracker(server.URL, "octo", "app", "").GetIssue("#42")
+	require.NoError(t, err)
+	assert.Equal(t, "42", issue.Key)
+	assert.Equal(t, "Crash on save", issue.Title)
+	assert.Equal(t, "bug", issue.Type)
+}
+
+func TestLinearTrackerGetIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+		assert.Equal(t, "lin_key", r.Header.Get("Authorization"))
+
+		var body struct {
+			Variables map[string]string `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		if body.Variables["id"] != "ENG-7" {
+			fmt.Fprint(w, `{"data": {"issue": null}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"issue": {"identifier": "ENG-7", "title": "Speed up sync", "url": "https://linear.app/eng/issue/ENG-7", "labels": {"nodes": [{"name": "Feature"}]}}}}`)
+	}))
+	defer server.Close()
+
+	tr := NewLinearTracker(server.URL, "lin_key")
+
+	issue, err := tr.GetIssue("ENG-7")
+	require.NoError(t, err)
+	assert.Equal(t, "ENG-7", issue.Key)
+	assert.Equal(t, "Speed up sync", issue.Title)
+	assert.Equal(t, "Feature", issue.Type)
+
+	_, err = tr.GetIssue("ENG-8")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,48 @@
+package transformer
+
+import (
+	"strings"
+
+	"github.com/garrettkrohn/treekanga/models"
+)
+
+// StackLines renders stacks as a tree, one branch per line, with the commits
+// each branch is ahead/behind its parent.
+//
+//	main
+//	└── feature/api ↑2
+//	    ├── feature/ui ↑1↓3
+//	    └── feature/docs (no worktree)
+func StackLines(stacks []*models.StackNode) []string {
+	var lines []string
+	for _, stack := range stacks {
+		lines = append(lines, stack.Branch)
+		lines = appendStackChildren(lines, stack.Children, "")
+	}
+	return lines
+}
+
+func appendStackChildren(lines []string, children []*models.StackNode, indent string) []string {
+	for i, child := range children {
+		branch, next := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, next = "└── ", "    "
+		}
+		lines = append(lines, indent+branch+stackEdge(child))
+		lines = appendStackChildren(lines, child.Children, indent+next)
+	}
+	return lines
+}
+
+// stackEdge renders a branch with how far it has drifted from its parent.
+func stackEdge(node *models.StackNode) string {
+	parts := []string{node.Branch}
+	if node.Worktree == nil {
+		parts = append(parts, "(no worktree)")
+	} else if node.Counted {
+		if symbols := aheadBehindSymbols('↑', '↓', node.Ahead, node.Behind); symbols != "" {
+			parts = append(parts, symbols)
+		}
+	}
+	return strings.Join(parts, " ")
+}
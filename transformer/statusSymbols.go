@@ -3,12 +3,13 @@ package transformer
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/garrettkrohn/treekanga/models"
 )
 
 // StatusLegend documents the compact symbols rendered by WorktreeStatusSymbols.
-const StatusLegend = "status legend: + staged, * modified, ? untracked, ↑/↓ ahead/behind default branch, ⇡/⇣ ahead/behind remote, ✓ merged"
+const StatusLegend = "status legend: + staged, * modified, ? untracked, ↑/↓ ahead/behind base branch, ⇡/⇣ ahead/behind remote, ⊘ upstream gone, ✓ merged"
 
 // WorktreeStatusSymbols renders a worktree's R1-R4 status fields as a
 // compact, worktrunk-style symbol string. Indicators that carry no signal
@@ -48,14 +49,18 @@ func DirtySymbols(worktree models.Worktree) string {
 }
 
 // DefaultAheadBehindSymbols renders the R2 indicator: commits ahead/behind
-// the default branch.
+// the worktree's base branch.
 func DefaultAheadBehindSymbols(worktree models.Worktree) string {
 	return aheadBehindSymbols('↑', '↓', worktree.AheadDefault, worktree.BehindDefault)
 }
 
 // RemoteAheadBehindSymbols renders the R3 indicator: commits ahead/behind
-// the remote tracking branch. Returns "" when no upstream is configured.
+// the remote tracking branch, or ⊘ when it was deleted on the remote.
+// Returns "" when no upstream is configured.
 func RemoteAheadBehindSymbols(worktree models.Worktree) string {
+	if worktree.UpstreamGone {
+		return "⊘"
+	}
 	if !worktree.HasUpstream {
 		return ""
 	}
@@ -63,7 +68,7 @@ func RemoteAheadBehindSymbols(worktree models.Worktree) string {
 }
 
 // MergedSymbol renders the R4 indicator: a check mark when the branch's
-// content is already present in the default branch.
+// content is already present in its base branch.
 func MergedSymbol(worktree models.Worktree) string {
 	if worktree.Merged == models.MergeStatusMerged {
 		return "✓"
@@ -71,6 +76,66 @@ func MergedSymbol(worktree models.Worktree) string {
 	return ""
 }
 
+// StashSymbols renders the stash column: how many stashes were made on the
+// worktree's branch, or "" when there are none.
+func StashSymbols(worktree models.Worktree) string {
+	if worktree.Stashes == 0 {
+		return ""
+	}
+	return fmt.Sprintf("≡%d", worktree.Stashes)
+}
+
+// PullRequestSymbols renders the forge column: PR number and state, the
+// branch it targets when that isn't the worktree's base branch, a CI glyph
+// (✓ passing, ✗ failing, ● running) and the review decision. Returns ""
+// when the branch has no pull request.
+func PullRequestSymbols(worktree models.Worktree) string {
+	pr := worktree.PullRequest
+	if pr == nil {
+		return ""
+	}
+
+	parts := []string{fmt.Sprintf("#%d %s", pr.Number, pr.State)}
+	if pr.BaseBranch != "" && worktree.BaseBranch != "" && pr.BaseBranch != worktree.BaseBranch {
+		parts = append(parts, "→ "+pr.BaseBranch)
+	}
+	switch pr.CI {
+	case models.CIStatusSuccess:
+		parts = append(parts, "✓")
+	case models.CIStatusFailure:
+		parts = append(parts, "✗")
+	case models.CIStatusPending:
+		parts = append(parts, "●")
+	}
+	switch pr.Review {
+	case models.ReviewStateApproved:
+		parts = append(parts, "approved")
+	case models.ReviewStateChangesRequested:
+		parts = append(parts, "changes")
+	}
+	return strings.Join(parts, " ")
+}
+
+// LastFetchAge describes how long ago a repo was last fetched, e.g.
+// "fetched 12m ago", so stale status comparisons are recognizable.
+func LastFetchAge(lastFetch, now time.Time) string {
+	if lastFetch.IsZero() {
+		return "never fetched"
+	}
+
+	age := now.Sub(lastFetch)
+	switch {
+	case age < time.Minute:
+		return "fetched just now"
+	case age < time.Hour:
+		return fmt.Sprintf("fetched %dm ago", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("fetched %dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("fetched %dd ago", int(age.Hours()/24))
+	}
+}
+
 func aheadBehindSymbols(aheadGlyph, behindGlyph rune, ahead, behind int) string {
 	var b strings.Builder
 	if ahead > 0 {
@@ -25,6 +25,17 @@ func TransformWorktrees(worktreeStrings []string) []models.Worktree {
 
 		folder := strings.Split(fullPath, "/")[len(strings.Split(fullPath, "/"))-1]
 
+		// Detached worktrees are listed as "(detached HEAD)" instead of "[branch]"
+		if parts[2] == "(detached" {
+			worktrees = append(worktrees, models.Worktree{
+				FullPath:   fullPath,
+				Folder:     folder,
+				CommitHash: commitHash,
+				Detached:   true,
+			})
+			continue
+		}
+
 		branchName := strings.Trim(parts[2], "[]")
 
 		worktrees = append(worktrees, models.Worktree{
@@ -38,6 +49,18 @@ func TransformWorktrees(worktreeStrings []string) []models.Worktree {
 	return worktrees
 }
 
+// DisplayBranch returns the branch name to show for a worktree, or
+// "(detached <tag|commit>)" for worktrees that aren't on a branch.
+func DisplayBranch(worktree models.Worktree) string {
+	if !worktree.Detached {
+		return worktree.BranchName
+	}
+	if worktree.DetachedRef != "" {
+		return "(detached " + worktree.DetachedRef + ")"
+	}
+	return "(detached " + worktree.CommitHash + ")"
+}
+
 func RemoveOriginPrefix(branchStrings []string) []string {
 	for i, branch := range branchStrings {
 		branchStrings[i] = strings.TrimSpace(strings.Replace(branch, "origin/", "", -1))
@@ -2,6 +2,7 @@ package transformer
 
 import (
 	"testing"
+	"time"
 
 	"github.com/garrettkrohn/treekanga/models"
 	"github.com/stretchr/testify/assert"
@@ -27,6 +28,22 @@ func TestTransformer(t *testing.T) {
 		assert.Equal(t, result, expectedWt)
 	})
 
+	t.Run("detached worktrees have no branch", func(t *testing.T) {
+		result := TransformWorktrees([]string{
+			"/Users/gkrohn/code/platform_work/v2.3.1                                            a1c4d34 (detached HEAD)",
+		})
+		assert.Equal(t, []models.Worktree{{
+			FullPath:   "/Users/gkrohn/code/platform_work/v2.3.1",
+			Folder:     "v2.3.1",
+			CommitHash: "a1c4d34",
+			Detached:   true,
+		}}, result)
+
+		assert.Equal(t, "(detached a1c4d34)", DisplayBranch(result[0]))
+		result[0].DetachedRef = "v2.3.1"
+		assert.Equal(t, "(detached v2.3.1)", DisplayBranch(result[0]))
+	})
+
 	branchStrings := []string{
 		"  origin/main",
 		"origin/develop",
@@ -42,3 +59,47 @@ func TestTransformer(t *testing.T) {
 		assert.Equal(t, result, expectedB)
 	})
 }
+
+func TestLastFetchAge(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "never fetched", LastFetchAge(time.Time{}, now))
+	assert.Equal(t, "fetched just now", LastFetchAge(now.Add(-30*time.Second), now))
+	assert.Equal(t, "fetched 12m ago", LastFetchAge(now.Add(-12*time.Minute), now))
+	assert.Equal(t, "fetched 5h ago", LastFetchAge(now.Add(-5*time.Hour-10*time.Minute), now))
+	assert.Equal(t, "fetched 3d ago", LastFetchAge(now.Add(-3*24*time.Hour), now))
+}
+
+func TestPullRequestSymbolsTarget(t *testing.T) {
+	pr := &models.PullRequest{Number: 12, State: models.PullRequestStateOpen, BaseBranch: "main"}
+
+	assert.Equal(t, "#12 open", PullRequestSymbols(models.Worktree{BaseBranch: "main", PullRequest: pr}))
+	assert.Equal(t, "#12 open → main", PullRequestSymbols(models.Worktree{BaseBranch: "release/2.0", PullRequest: pr}))
+}
+
+func TestStackLines(t *testing.T) {
+	stacks := []*models.StackNode{{
+		Branch: "main",
+		Children: []*models.StackNode{{
+			Branch: "feature/api", Worktree: &models.Worktree{}, Counted: true, Ahead: 2,
+			Children: []*models.StackNode{
+				{Branch: "feature/ui", Worktree: &models.Worktree{}, Counted: true, Ahead: 1, Behind: 3,
+					Children: []*models.StackNode{{Branch: "feature/ui-tests", Worktree: &models.Worktree{}, Counted: true}}},
+				{Branch: "feature/docs"},
+			},
+		}},
+	}}
+
+	assert.Equal(t, []string{
+		"main",
+		"└── feature/api ↑2",
+		"    ├── feature/ui ↑1↓3",
+		"    │   └── feature/ui-tests",
+		"    └── feature/docs (no worktree)",
+	}, StackLines(stacks))
+}
+
+func TestStashSymbols(t *testing.T) {
+	assert.Equal(t, "", StashSymbols(models.Worktree{}))
+	assert.Equal(t, "≡3", StashSymbols(models.Worktree{Stashes: 3}))
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/services"
+)
+
+// BatchAddUpdateMsg is sent whenever an entry of a batch add changes state.
+type BatchAddUpdateMsg struct {
+	Index  int
+	Result services.BatchAddResult
+}
+
+// BatchAddDoneMsg is sent once every entry of a batch add has finished.
+type BatchAddDoneMsg struct{}
+
+// BatchAddProgressModel renders a live table of a batch add: one row per
+// branch with its base and current state.
+type BatchAddProgressModel struct {
+	spinner  spinner.Model
+	results  []services.BatchAddResult
+	theme    *models.Theme
+	cancel   context.CancelFunc // stops the batch add
+	stopping bool
+	done     bool
+}
+
+// NewBatchAddProgressModel creates the progress table with every entry
+// pending. ctrl+c calls cancel, which should stop the batch add.
+func NewBatchAddProgressModel(entries []services.BatchAddEntry, defaultBase string, theme *models.Theme, cancel context.CancelFunc) BatchAddProgressModel {
+	results := make([]services.BatchAddResult, len(entries))
+	for i, entry := range entries {
+		if entry.Base == "" {
+			entry.Base = defaultBase
+		}
+		results[i] = services.BatchAddResult{Entry: entry, State: services.BatchAddPending}
+	}
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(theme.Accent)
+
+	return BatchAddProgressModel{spinner: s, results: results, theme: theme, cancel: cancel}
+}
+
+func (m BatchAddProgressModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m BatchAddProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case BatchAddUpdateMsg:
+		// keep the base shown when the entry uses the configured default
+		base := m.results[msg.Index].Entry.Base
+		m.results[msg.Index] = msg.Result
+		m.results[msg.Index].Entry.Base = base
+		return m, nil
+	case BatchAddDoneMsg:
+		m.done = true
+		return m, tea.Quit
+	case tea.KeyMsg:
+		// the worktrees being created are rolled back and the others
+		// skipped, the display stays until that's done
+		if msg.String() == "ctrl+c" {
+			m.stopping = true
+			m.cancel()
+		}
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m BatchAddProgressModel) View() string {
+	branchWidth, baseWidth := len("Branch"), len("Base")
+	for _, r := range m.results {
+		branchWidth = max(branchWidth, len(r.Entry.Branch))
+		baseWidth = max(baseWidth, len(r.Entry.Base))
+	}
+
+	header := lipgloss.NewStyle().Foreground(m.theme.Cyan).Bold(true)
+	muted := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	success := lipgloss.NewStyle().Foreground(m.theme.SuccessFg)
+	failure := lipgloss.NewStyle().Foreground(m.theme.ErrorFg)
+
+	var b strings.Builder
+	b.WriteString(header.Render(fmt.Sprintf("%-*s  %-*s  %s", branchWidth, "Branch", baseWidth, "Base", "Status")))
+	b.WriteString("\n")
+
+	for _, r := range m.results {
+		var status string
+		switch r.State {
+		case services.BatchAddPending:
+			status = muted.Render("waiting")
+		case services.BatchAddRunning:
+			status = m.spinner.View() + " creating"
+		case services.BatchAddCreated:
+			status = success.Render("✓ " + r.Path)
+		case services.BatchAddFailed:
+			// git failures carry the command on a second line, the first says why
+			reason := strings.SplitN(r.Err.Error(), "\n", 2)[0]
+			status = failure.Render("✗ " + reason)
+		}
+		fmt.Fprintf(&b, "%-*s  %-*s  %s\n", branchWidth, r.Entry.Branch, baseWidth, r.Entry.Base, status)
+	}
+	if m.stopping && !m.done {
+		b.WriteString(muted.Render("stopping, rolling back the worktrees being created…"))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -17,7 +17,7 @@ import (
 const statusPlaceholder = "…"
 
 // FetchWorktrees fetches and sorts worktree data without computing status.
-func FetchWorktrees(appConfig config.AppConfig) ([]models.Worktree, error) {
+func FetchWorktrees(git git.Git, appConfig config.AppConfig) ([]models.Worktree, error) {
 	rawWorktrees, err := git.ListWorktrees(appConfig.BareRepoPath)
 	if err != nil {
 		return nil, err
@@ -31,8 +31,8 @@ func FetchWorktrees(appConfig config.AppConfig) ([]models.Worktree, error) {
 
 // BuildWorktreeTableRows fetches worktree data into table rows. Status
 // columns render placeholders until updated in the background (R9).
-func BuildWorktreeTableRows(appConfig config.AppConfig) ([]table.Row, error) {
-	worktreeObjects, err := FetchWorktrees(appConfig)
+func BuildWorktreeTableRows(git git.Git, appConfig config.AppConfig) ([]table.Row, error) {
+	worktreeObjects, err := FetchWorktrees(git, appConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -47,13 +47,15 @@ func WorktreeTableRows(worktrees []models.Worktree) []table.Row {
 	for _, worktree := range worktrees {
 		rows = append(rows, table.Row{
 			worktree.Folder,
-			worktree.BranchName,
+			transformer.DisplayBranch(worktree),
 			worktree.FullPath,
 			worktree.CommitHash,
 			statusOrPlaceholder(worktree, transformer.DirtySymbols),
 			statusOrPlaceholder(worktree, transformer.DefaultAheadBehindSymbols),
 			statusOrPlaceholder(worktree, transformer.RemoteAheadBehindSymbols),
 			statusOrPlaceholder(worktree, transformer.MergedSymbol),
+			pullRequestOrPlaceholder(worktree),
+			stashesOrPlaceholder(worktree),
 		})
 	}
 	return rows
@@ -65,3 +67,17 @@ func statusOrPlaceholder(worktree models.Worktree, render func(models.Worktree)
 	}
 	return render(worktree)
 }
+
+func pullRequestOrPlaceholder(worktree models.Worktree) string {
+	if !worktree.PullRequestLoaded {
+		return statusPlaceholder
+	}
+	return transformer.PullRequestSymbols(worktree)
+}
+
+func stashesOrPlaceholder(worktree models.Worktree) string {
+	if !worktree.StashesLoaded {
+		return statusPlaceholder
+	}
+	return transformer.StashSymbols(worktree)
+}
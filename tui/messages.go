@@ -3,12 +3,64 @@ Copyright © 2024 Garrett Krohn <garrettkrohn@gmail.com>
 */
 package tui
 
-import "github.com/garrettkrohn/treekanga/models"
+import (
+	"time"
+
+	"github.com/garrettkrohn/treekanga/models"
+	"github.com/garrettkrohn/treekanga/services"
+)
 
 // statusFetchDoneMsg is sent once the default branch has been fetched from
 // origin (R5), signalling it's safe to start computing per-worktree status.
 type statusFetchDoneMsg struct{}
 
+// stashCountsMsg carries how many stashes were made on each branch.
+type stashCountsMsg struct {
+	counts map[string]int
+	err    error
+}
+
+// lastFetchMsg carries when the repo was last fetched, for the header.
+type lastFetchMsg struct {
+	at time.Time
+}
+
+// backgroundFetchTickMsg is sent every backgroundFetch interval to fetch the
+// repo while the TUI is open.
+type backgroundFetchTickMsg struct{}
+
+// backgroundFetchDoneMsg is sent when a background fetch has finished.
+type backgroundFetchDoneMsg struct {
+	at     time.Time
+	err    error
+	output string
+}
+
+// syncDoneMsg is sent when syncing a worktree with the base branch has
+// finished.
+type syncDoneMsg struct {
+	worktree models.Worktree
+	result   services.SyncResult
+	err      error // the fetch failed, nothing was synced
+	output   string
+}
+
+// renameDoneMsg is sent when renaming a worktree has finished.
+type renameDoneMsg struct {
+	result services.RenameResult
+	input  string
+	err    error
+	output string
+}
+
+// editorOpenedMsg is sent when opening a worktree in an editor has finished.
+type editorOpenedMsg struct {
+	editor string
+	path   string
+	err    error
+	output string
+}
+
 // worktreeStatusMsg is sent when a single worktree's status (R1-R4) has
 // finished computing in the background, so its table row can be updated
 // without blocking the rest of the table (R9).
@@ -17,6 +69,14 @@ type worktreeStatusMsg struct {
 	worktree models.Worktree
 }
 
+// worktreePullRequestMsg is sent when the forge lookup for a worktree's
+// branch has finished, so the PR column can be updated independently of the
+// git status columns.
+type worktreePullRequestMsg struct {
+	fullPath    string
+	pullRequest *models.PullRequest
+}
+
 // deleteCompleteMsg is sent when deletion is complete
 type deleteCompleteMsg struct {
 	err          error
@@ -37,6 +97,7 @@ type deleteErrorMsg struct {
 type addCompleteMsg struct {
 	err        error
 	branchName string
+	path       string
 	output     string
 }
 
@@ -14,6 +14,8 @@ import (
 	"github.com/garrettkrohn/treekanga/config"
 	"github.com/garrettkrohn/treekanga/connector"
 	"github.com/garrettkrohn/treekanga/directoryReader"
+	"github.com/garrettkrohn/treekanga/forge"
+	"github.com/garrettkrohn/treekanga/git"
 	"github.com/garrettkrohn/treekanga/models"
 	"github.com/garrettkrohn/treekanga/shell"
 )
@@ -57,6 +59,20 @@ type Model struct {
 	// Folder selection state
 	showFolderSelection bool
 	pendingConnectPath  string
+	// Rename state
+	showRenameInput bool
+	renameInput     textinput.Model
+	renameError     string
+	renamingPath    string
+	renaming        bool
+	// Editor selection state
+	showEditorSelection bool
+	pendingEditorPath   string
+	// Background fetch state
+	lastFetch          time.Time // zero until loaded, or if the repo was never fetched
+	backgroundFetching bool
+	// Sync state
+	syncing bool
 	// Log viewer state
 	logsFocused   bool
 	logsViewport  viewport.Model
@@ -64,8 +80,10 @@ type Model struct {
 	// Dependencies
 	connector connector.Connector
 	shell     shell.Shell
+	git       git.Git
 	appConfig config.AppConfig
 	dirReader directoryReader.DirectoryReader
+	forge     forge.Forge // nil when no forge is configured
 
 	// worktrees tracks the underlying data behind each table row, keyed by
 	// FullPath, so background status updates (R9) can patch the right row.
@@ -83,8 +101,10 @@ func NewModel(
 	spinner spinner.Model,
 	conn connector.Connector,
 	shell shell.Shell,
+	git git.Git,
 	appConfig config.AppConfig,
 	dirReader directoryReader.DirectoryReader,
+	forge forge.Forge,
 	worktrees []models.Worktree,
 ) Model {
 	// Initialize text input for add command
@@ -94,6 +114,12 @@ func NewModel(
 	ti.CharLimit = 156
 	ti.Width = 80
 
+	// Initialize text input for rename, focused when it opens
+	ri := textinput.New()
+	ri.Placeholder = "new_branch_name --remote"
+	ri.CharLimit = 156
+	ri.Width = 80
+
 	// Initialize viewport for logs
 	vp := viewport.New(80, 10)
 	vp.SetContent("No operations logged yet.")
@@ -105,6 +131,7 @@ func NewModel(
 		isDeleting:          false,
 		showAddInput:        false,
 		addInput:            ti,
+		renameInput:         ri,
 		isAdding:            false,
 		showBranchSelection: false,
 		showFolderSelection: false,
@@ -113,8 +140,10 @@ func NewModel(
 		operationLogs:       []OperationLog{},
 		connector:           conn,
 		shell:               shell,
+		git:                 git,
 		appConfig:           appConfig,
 		dirReader:           dirReader,
+		forge:               forge,
 		worktrees:           worktrees,
 	}
 }
@@ -7,16 +7,19 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
+	"github.com/garrettkrohn/treekanga/adapters"
 	"github.com/garrettkrohn/treekanga/config"
-	"github.com/garrettkrohn/treekanga/git"
+	"github.com/garrettkrohn/treekanga/editor"
 	"github.com/garrettkrohn/treekanga/models"
 	"github.com/garrettkrohn/treekanga/services"
 	"github.com/garrettkrohn/treekanga/transformer"
@@ -25,7 +28,62 @@ import (
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(m.spinner.Tick, m.fetchDefaultBranchCmd())
+	return tea.Batch(m.spinner.Tick, m.fetchDefaultBranchCmd(), m.loadLastFetchCmd(), m.scheduleBackgroundFetch())
+}
+
+// loadLastFetchCmd reads when the repo was last fetched for the header.
+func (m Model) loadLastFetchCmd() tea.Cmd {
+	return func() tea.Msg {
+		at, err := m.git.GetLastFetch(m.appConfig.BareRepoPath)
+		if err != nil {
+			log.Debug("Failed to read last fetch", "error", err)
+		}
+		return lastFetchMsg{at: at}
+	}
+}
+
+// scheduleBackgroundFetch waits out the backgroundFetch interval before the
+// next background fetch; without one configured nothing is fetched.
+func (m Model) scheduleBackgroundFetch() tea.Cmd {
+	if m.appConfig.BackgroundFetch <= 0 {
+		return nil
+	}
+	return tea.Tick(m.appConfig.BackgroundFetch, func(time.Time) tea.Msg {
+		return backgroundFetchTickMsg{}
+	})
+}
+
+// logMu serializes the Cmds that capture the log. The services log through
+// the package logger, so each swaps its output, and two at once would hand
+// the output back to stderr while the other is still running.
+var logMu sync.Mutex
+
+// captureLog runs fn with the log going to a buffer rather than stderr,
+// where stray output corrupts the alt-screen display, and returns what was
+// logged. Cmds capturing the log run one at a time.
+func captureLog(fn func()) string {
+	logMu.Lock()
+	defer logMu.Unlock()
+
+	var logBuffer bytes.Buffer
+	log.SetOutput(&logBuffer)
+	defer log.SetOutput(os.Stderr)
+	fn()
+	return logBuffer.String()
+}
+
+// backgroundFetchCmd fetches every branch of the repo and records when,
+// capturing git's output like fetchDefaultBranchCmd does.
+func (m Model) backgroundFetchCmd() tea.Cmd {
+	return func() tea.Msg {
+		var at time.Time
+		var err error
+		output := captureLog(func() {
+			at, err = services.FetchRepo(m.git, m.appConfig.BareRepoPath)
+		})
+
+		return backgroundFetchDoneMsg{at: at, err: err, output: output}
+	}
 }
 
 // fetchDefaultBranchCmd fetches origin/<default-branch> once (R5) before any
@@ -35,13 +93,13 @@ func (m Model) Init() tea.Cmd {
 // corrupts the alt-screen display.
 func (m Model) fetchDefaultBranchCmd() tea.Cmd {
 	return func() tea.Msg {
-		var logBuffer bytes.Buffer
-		log.SetOutput(&logBuffer)
-		err := services.FetchDefaultBranch(m.appConfig.BareRepoPath, m.appConfig.BaseBranch)
-		log.SetOutput(os.Stderr)
+		var err error
+		output := captureLog(func() {
+			err = services.FetchDefaultBranch(m.git, m.appConfig.BareRepoPath, m.appConfig.BaseBranch)
+		})
 
 		if err != nil {
-			log.Debug("Failed to fetch default branch", "branch", m.appConfig.BaseBranch, "error", err, "output", logBuffer.String())
+			log.Debug("Failed to fetch default branch", "branch", m.appConfig.BaseBranch, "error", err, "output", output)
 		}
 		return statusFetchDoneMsg{}
 	}
@@ -51,16 +109,35 @@ func (m Model) fetchDefaultBranchCmd() tea.Cmd {
 // background and reports it without blocking the rest of the table (R9).
 func (m Model) loadWorktreeStatusCmd(worktree models.Worktree) tea.Cmd {
 	return func() tea.Msg {
-		updated := services.ComputeWorktreeStatus(worktree, m.appConfig.BaseBranch)
+		updated := services.ComputeWorktreeStatus(m.git, worktree, m.appConfig.BaseBranch)
 		return worktreeStatusMsg{fullPath: updated.FullPath, worktree: updated}
 	}
 }
 
+// loadWorktreePullRequestCmd looks up the worktree's pull request on the
+// forge in the background. Without a forge it resolves immediately to an
+// empty PR column.
+func (m Model) loadWorktreePullRequestCmd(worktree models.Worktree) tea.Cmd {
+	return func() tea.Msg {
+		updated := services.ComputeWorktreePullRequest(m.forge, worktree)
+		return worktreePullRequestMsg{fullPath: updated.FullPath, pullRequest: updated.PullRequest}
+	}
+}
+
+// loadStashCountsCmd counts the repo's stashes per branch in the background
+// for the stash column.
+func (m Model) loadStashCountsCmd() tea.Cmd {
+	return func() tea.Msg {
+		counts, err := services.StashCounts(m.git, m.appConfig.BareRepoPath)
+		return stashCountsMsg{counts: counts, err: err}
+	}
+}
+
 // refreshWorktrees re-fetches the worktree list, resets the table to
 // placeholder status, and returns a Cmd that re-triggers background status
 // loading (via statusFetchDoneMsg) for the refreshed set.
 func (m *Model) refreshWorktrees() (tea.Cmd, error) {
-	worktrees, err := FetchWorktrees(m.appConfig)
+	worktrees, err := FetchWorktrees(m.git, m.appConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +158,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Split the screen: 60% for table, 40% for logs
 		tableHeight := (msg.Height * 6) / 10
-		logsHeight := msg.Height - tableHeight - 8 // Account for borders and help text
+		logsHeight := msg.Height - tableHeight - 9 // Account for header, borders and help text
 
 		if logsHeight < 5 {
 			logsHeight = 5
@@ -95,15 +172,97 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case statusFetchDoneMsg:
 		// Default branch is fetched - now compute each worktree's status
 		// concurrently; each one patches its own row as it resolves (R9).
-		cmds := make([]tea.Cmd, 0, len(m.worktrees))
+		cmds := make([]tea.Cmd, 0, 2*len(m.worktrees)+1)
+		for _, worktree := range m.worktrees {
+			cmds = append(cmds, m.loadWorktreeStatusCmd(worktree), m.loadWorktreePullRequestCmd(worktree))
+		}
+		cmds = append(cmds, m.loadStashCountsCmd())
+		return m, tea.Batch(cmds...)
+	case stashCountsMsg:
+		if msg.err != nil {
+			log.Debug("Failed to count stashes", "error", msg.err)
+		}
+		for i, worktree := range m.worktrees {
+			// stashes made on a detached HEAD belong to no worktree
+			if !worktree.Detached {
+				m.worktrees[i].Stashes = msg.counts[worktree.BranchName]
+			}
+			m.worktrees[i].StashesLoaded = true
+		}
+		m.table.SetRows(WorktreeTableRows(m.worktrees))
+		return m, nil
+	case lastFetchMsg:
+		m.lastFetch = msg.at
+		return m, nil
+	case backgroundFetchTickMsg:
+		if m.backgroundFetching {
+			return m, m.scheduleBackgroundFetch()
+		}
+		m.backgroundFetching = true
+		return m, m.backgroundFetchCmd()
+	case backgroundFetchDoneMsg:
+		m.backgroundFetching = false
+		if msg.err != nil {
+			m.addOperationLog(OperationLog{
+				Timestamp: time.Now(),
+				Operation: "fetch",
+				Target:    m.appConfig.RepoNameForConfig,
+				Command:   "git fetch --prune origin",
+				Status:    "error",
+				Message:   msg.err.Error() + "\n" + msg.output,
+			})
+			return m, m.scheduleBackgroundFetch()
+		}
+		// Compare against what was just fetched
+		m.lastFetch = msg.at
+		cmds := []tea.Cmd{m.scheduleBackgroundFetch()}
 		for _, worktree := range m.worktrees {
 			cmds = append(cmds, m.loadWorktreeStatusCmd(worktree))
 		}
 		return m, tea.Batch(cmds...)
+	case syncDoneMsg:
+		m.syncing = false
+		logEntry := OperationLog{
+			Timestamp: time.Now(),
+			Operation: "sync",
+			Target:    transformer.DisplayBranch(msg.worktree),
+			Command:   m.appConfig.SyncStrategy + " onto origin/" + msg.result.Worktree.BaseBranch,
+			Status:    "success",
+			Message:   msg.result.Status,
+		}
+		switch {
+		case msg.err != nil:
+			logEntry.Status = "error"
+			logEntry.Message = msg.err.Error() + "\n" + msg.output
+		case msg.result.Failed():
+			logEntry.Status = "error"
+			logEntry.Message = msg.result.Status + ": " + msg.result.Reason + "\n" + msg.output
+		case msg.result.Reason != "":
+			logEntry.Message = msg.result.Status + ": " + msg.result.Reason
+		}
+		m.addOperationLog(logEntry)
+		return m, m.loadWorktreeStatusCmd(msg.worktree)
 	case worktreeStatusMsg:
 		for i, worktree := range m.worktrees {
 			if worktree.FullPath == msg.fullPath {
-				m.worktrees[i] = msg.worktree
+				// Keep the PR lookup and stash count, which resolve
+				// independently.
+				updated := msg.worktree
+				updated.PullRequest = worktree.PullRequest
+				updated.PullRequestLoaded = worktree.PullRequestLoaded
+				updated.Stashes = worktree.Stashes
+				updated.StashesLoaded = worktree.StashesLoaded
+				m.worktrees[i] = updated
+				break
+			}
+		}
+		m.table.SetRows(WorktreeTableRows(m.worktrees))
+		return m, nil
+	case worktreePullRequestMsg:
+		for i, worktree := range m.worktrees {
+			if worktree.FullPath == msg.fullPath {
+				m.worktrees[i].PullRequest = msg.pullRequest
+				m.worktrees[i].PullRequestLoaded = true
 				break
 			}
 		}
@@ -145,77 +304,62 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case branchSelectionReadyMsg:
 		// Show the branch selection popup
-		items := make([]list.Item, len(msg.branches))
-		for i, branch := range msg.branches {
-			items[i] = popupItem{title: branch, desc: ""}
-		}
-
-		delegate := list.NewDefaultDelegate()
-		delegate.SetSpacing(0)
-		delegate.ShowDescription = false
-		delegate.SetHeight(1)
-
-		delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
-			Foreground(m.theme().AccentFg).
-			Background(m.theme().Accent).
-			Bold(true)
-		delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.
-			Foreground(lipgloss.Color("#ffffff"))
-
-		popupHeight := m.termHeight - 4
-		m.popupList = list.New(items, delegate, m.termWidth, popupHeight)
-		m.popupList.Title = "Select base branch for new worktree"
-		m.popupList.SetShowStatusBar(false)
-		m.popupList.SetFilteringEnabled(false)
-
-		m.popupList.Styles.Title = m.popupList.Styles.Title.
-			Foreground(m.theme().Cyan).
-			Bold(true).
-			Padding(0, 1).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(m.theme().BorderDim).
-			BorderBottom(true)
-
+		m.popupList = m.newPopupList(msg.branches, "Select base branch for new worktree")
 		m.showBranchSelection = true
 		return m, nil
 	case folderSelectionReadyMsg:
 		// Show the folder selection popup
-		items := make([]list.Item, len(msg.folders))
-		for i, folder := range msg.folders {
-			items[i] = popupItem{title: folder, desc: ""}
-		}
-
-		delegate := list.NewDefaultDelegate()
-		delegate.SetSpacing(0)
-		delegate.ShowDescription = false
-		delegate.SetHeight(1)
-
-		delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
-			Foreground(m.theme().AccentFg).
-			Background(m.theme().Accent).
-			Bold(true)
-		delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.
-			Foreground(lipgloss.Color("#ffffff"))
-
-		popupHeight := m.termHeight - 4
-		m.popupList = list.New(items, delegate, m.termWidth, popupHeight)
-		m.popupList.Title = "Select folder to connect to"
-		m.popupList.SetShowStatusBar(false)
-		m.popupList.SetFilteringEnabled(false)
-
-		m.popupList.Styles.Title = m.popupList.Styles.Title.
-			Foreground(m.theme().Cyan).
-			Bold(true).
-			Padding(0, 1).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(m.theme().BorderDim).
-			BorderBottom(true)
-
+		m.popupList = m.newPopupList(msg.folders, "Select folder to connect to")
 		m.showFolderSelection = true
 		return m, nil
+	case renameDoneMsg:
+		m.renaming = false
+		if msg.err != nil {
+			m.renameError = msg.err.Error()
+			m.renameInput.SetValue(msg.input)
+			m.renameInput.Focus()
+			m.showRenameInput = true
+			m.addOperationLog(OperationLog{
+				Timestamp: time.Now(),
+				Operation: "rename",
+				Target:    filepath.Base(m.renamingPath),
+				Command:   msg.input,
+				Status:    "error",
+				Message:   msg.err.Error() + "\n" + msg.output,
+			})
+			return m, nil
+		}
+		m.addOperationLog(OperationLog{
+			Timestamp: time.Now(),
+			Operation: "rename",
+			Target:    msg.result.OldBranch,
+			Command:   msg.input,
+			Status:    "success",
+			Message:   msg.result.OldBranch + " → " + msg.result.NewBranch + "\n" + msg.output,
+		})
+		refreshCmd, err := m.refreshWorktrees()
+		if err != nil {
+			return m, tea.Printf("Error refreshing worktrees: %v", err)
+		}
+		return m, refreshCmd
+	case editorOpenedMsg:
+		logEntry := OperationLog{
+			Timestamp: time.Now(),
+			Operation: "open",
+			Target:    filepath.Base(msg.path),
+			Command:   msg.editor + " " + msg.path,
+			Status:    "success",
+			Message:   msg.output,
+		}
+		if msg.err != nil {
+			logEntry.Status = "error"
+			logEntry.Message = msg.err.Error() + "\n" + msg.output
+		}
+		m.addOperationLog(logEntry)
+		return m, nil
 	case addCompleteMsg:
 		m.isAdding = false
-		if msg.err != nil {
+		if msg.err != nil && msg.path == "" {
 			m.addError = msg.err.Error()
 			m.showAddInput = true
 			// Log the error
@@ -225,7 +369,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Target:    msg.branchName,
 				Command:   m.addingCommand,
 				Status:    "error",
-				Message:   msg.output,
+				Message:   msg.err.Error() + "\n" + msg.output,
 			})
 			return m, nil
 		}
@@ -238,6 +382,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			Status:    "success",
 			Message:   msg.output,
 		})
+		// The worktree is there, only opening it in the editor failed
+		if msg.err != nil {
+			m.addOperationLog(OperationLog{
+				Timestamp: time.Now(),
+				Operation: "open",
+				Target:    filepath.Base(msg.path),
+				Command:   m.addingCommand,
+				Status:    "error",
+				Message:   msg.err.Error(),
+			})
+		}
 		// Rebuild the table with updated data
 		refreshCmd, err := m.refreshWorktrees()
 		if err != nil {
@@ -288,6 +443,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	// If editor selection popup is showing, handle it first
+	if m.showEditorSelection {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "esc", "q":
+				m.showEditorSelection = false
+				return m, nil
+			case "enter", "o":
+				m.showEditorSelection = false
+				if item, ok := m.popupList.SelectedItem().(popupItem); ok {
+					return m, m.performOpenEditor(item.title, m.pendingEditorPath)
+				}
+				return m, nil
+			}
+		}
+		m.popupList, cmd = m.popupList.Update(msg)
+		return m, cmd
+	}
+
 	// If branch selection popup is showing, handle it first
 	if m.showBranchSelection {
 		switch msg := msg.(type) {
@@ -307,7 +482,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					log.Debug("Selected base branch from popup", "branch", item.title)
 
 					// Update BaseBranchExistsLocally flag
-					localBranches, err := git.GetLocalBranches(m.pendingAddConfig.BareRepoPath)
+					localBranches, err := m.git.GetLocalBranches(m.pendingAddConfig.BareRepoPath)
 					if err == nil {
 						m.pendingAddConfig.BaseBranchExistsLocally = slices.Contains(localBranches, m.pendingAddConfig.BaseBranch)
 					}
@@ -378,6 +553,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	// If rename input is showing, handle it first
+	if m.showRenameInput {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "enter":
+				input := strings.TrimSpace(m.renameInput.Value())
+				if input == "" {
+					return m, nil
+				}
+				m.showRenameInput = false
+				m.renameError = ""
+				m.renameInput.SetValue("")
+				m.renaming = true
+				return m, m.performRename(m.renamingPath, input)
+			case "esc", "ctrl+c":
+				m.showRenameInput = false
+				m.renameError = ""
+				m.renameInput.SetValue("")
+				return m, nil
+			}
+		}
+		m.renameInput, cmd = m.renameInput.Update(msg)
+		return m, cmd
+	}
+
 	// If delete confirmation is showing, handle it first
 	if m.showDeleteConfirm {
 		switch msg := msg.(type) {
@@ -497,7 +698,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			worktreePath := selectedRow[2]
 			worktreeName := selectedRow[0]
-			branchName := selectedRow[1]
+			branchName := m.branchNameForPath(worktreePath)
 
 			// Start the deletion process with spinner
 			m.isDeleting = true
@@ -510,12 +711,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			worktreePath := selectedRow[2]
 			worktreeName := selectedRow[0]
-			branchName := selectedRow[1]
+			branchName := m.branchNameForPath(worktreePath)
 
 			// Start the deletion process with spinner
 			m.isDeleting = true
 			m.deletingName = worktreeName
 			return m, tea.Batch(m.performDelete(worktreePath, worktreeName, branchName, false, true), m.spinner.Tick)
+		case "s":
+			selectedRow := m.table.SelectedRow()
+			if len(selectedRow) < 3 {
+				return m, tea.Printf("No worktree selected")
+			}
+			if m.syncing {
+				return m, nil
+			}
+			worktreePath := selectedRow[2]
+			i := slices.IndexFunc(m.worktrees, func(wt models.Worktree) bool { return wt.FullPath == worktreePath })
+			if i < 0 {
+				return m, nil
+			}
+
+			m.syncing = true
+			return m, m.performSync(m.worktrees[i])
 		case "o":
 			selectedRow := m.table.SelectedRow()
 			if len(selectedRow) < 3 {
@@ -540,6 +757,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			// Fetch folder options and show selection popup
 			return m, m.fetchFoldersForSelection()
+		case "r":
+			selectedRow := m.table.SelectedRow()
+			if len(selectedRow) < 3 {
+				return m, tea.Printf("No worktree selected")
+			}
+			if m.renaming {
+				return m, nil
+			}
+
+			m.renamingPath = selectedRow[2]
+			m.showRenameInput = true
+			m.renameInput.Focus()
+			return m, nil
+		case "e":
+			selectedRow := m.table.SelectedRow()
+			if len(selectedRow) < 3 {
+				return m, tea.Printf("No worktree selected")
+			}
+			editors := editor.Installed(m.appConfig.Editors)
+			if len(editors) == 0 {
+				return m, tea.Printf("No editors found, add one to editors in the config")
+			}
+
+			m.pendingEditorPath = selectedRow[2]
+			m.popupList = m.newPopupList(editors, "Open "+filepath.Base(selectedRow[2])+" in")
+			m.showEditorSelection = true
+			return m, nil
 		case "enter":
 			return m, tea.Batch(
 				tea.Printf("Let's go to %s!", m.table.SelectedRow()[1]),
@@ -557,6 +801,121 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// branchNameForPath returns the branch checked out in the worktree at
+// path, or "" for detached worktrees. The table shows "(detached ...)"
+// for those, which must never be passed to git as a branch name.
+func (m Model) branchNameForPath(path string) string {
+	for _, wt := range m.worktrees {
+		if wt.FullPath == path {
+			return wt.BranchName
+		}
+	}
+	return ""
+}
+
+// performRename renames a worktree in the background. input is the new
+// branch name, optionally followed by --remote to rename it on origin too.
+func (m Model) performRename(worktreePath, input string) tea.Cmd {
+	return func() tea.Msg {
+		newBranchName, renameRemote := parseRenameInput(input)
+		cfg := m.appConfig
+		cfg.WorktreeTargetDir = filepath.Dir(worktreePath)
+
+		var msg renameDoneMsg
+		msg.output = captureLog(func() {
+			newBranchName, err := services.NewBranchName(cfg, newBranchName)
+			if err != nil {
+				msg.err = err
+				return
+			}
+			msg.result, msg.err = services.RenameWorktree(cfg, newBranchName, worktreePath, m.git, m.shell, false, renameRemote)
+		})
+		msg.input = input
+		return msg
+	}
+}
+
+// parseRenameInput splits the rename input into the new branch name and
+// whether --remote (or -r) was given.
+func parseRenameInput(input string) (string, bool) {
+	var newBranchName string
+	renameRemote := false
+	for _, part := range strings.Fields(input) {
+		switch {
+		case part == "--remote" || part == "-r":
+			renameRemote = true
+		case newBranchName == "" && !strings.HasPrefix(part, "-"):
+			newBranchName = part
+		}
+	}
+	return newBranchName, renameRemote
+}
+
+// performOpenEditor opens a worktree in an editor in the background
+func (m Model) performOpenEditor(name, path string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		output := captureLog(func() {
+			err = m.connector.OpenEditor(name, path)
+		})
+
+		return editorOpenedMsg{editor: name, path: path, err: err, output: output}
+	}
+}
+
+// newPopupList builds the single-line list shown in the selection popups
+func (m Model) newPopupList(titles []string, title string) list.Model {
+	items := make([]list.Item, len(titles))
+	for i, t := range titles {
+		items[i] = popupItem{title: t, desc: ""}
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.SetSpacing(0)
+	delegate.ShowDescription = false
+	delegate.SetHeight(1)
+
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(m.theme().AccentFg).
+		Background(m.theme().Accent).
+		Bold(true)
+	delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.
+		Foreground(lipgloss.Color("#ffffff"))
+
+	popupHeight := m.termHeight - 4
+	popupList := list.New(items, delegate, m.termWidth, popupHeight)
+	popupList.Title = title
+	popupList.SetShowStatusBar(false)
+	popupList.SetFilteringEnabled(false)
+
+	popupList.Styles.Title = popupList.Styles.Title.
+		Foreground(m.theme().Cyan).
+		Bold(true).
+		Padding(0, 1).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme().BorderDim).
+		BorderBottom(true)
+	return popupList
+}
+
+// performSync rebases or merges a worktree onto the base branch in the
+// background, after fetching it
+func (m Model) performSync(worktree models.Worktree) tea.Cmd {
+	return func() tea.Msg {
+		var results []services.SyncResult
+		var err error
+		output := captureLog(func() {
+			results, err = services.SyncWorktrees(m.git, m.appConfig, []models.Worktree{worktree})
+		})
+
+		msg := syncDoneMsg{worktree: worktree, err: err, output: output}
+		if len(results) > 0 {
+			msg.result = results[0]
+		}
+		return msg
+	}
+}
+
 // performDelete performs the deletion in the background
 func (m Model) performDelete(worktreePath, worktreeName, branchName string, force bool, deleteBranch bool) tea.Cmd {
 	return func() tea.Msg {
@@ -565,16 +924,29 @@ func (m Model) performDelete(worktreePath, worktreeName, branchName string, forc
 		minDisplayTime := 1 * time.Second
 
 		// Capture log output - write ONLY to buffer, not to stderr
-		var logBuffer bytes.Buffer
-		log.SetOutput(&logBuffer)
+		var err error
+		output := captureLog(func() {
+			log.Debug("Removing worktree", "fullPath", worktreePath, "force", force)
+
+			err = m.git.RemoveWorktree(m.appConfig.BareRepoPath, worktreePath, force)
+			if err != nil {
+				return
+			}
 
-		log.Debug("Removing worktree", "fullPath", worktreePath, "force", force)
+			log.Debug("Worktree removed successfully")
+			services.ForgetInZoxide(adapters.NewZoxide(m.shell), worktreePath)
 
-		err := git.RemoveWorktree(m.appConfig.BareRepoPath, worktreePath, force)
+			if deleteBranch && branchName == "" {
+				log.Debug("Detached worktree has no branch to delete", "worktreePath", worktreePath)
+			} else if deleteBranch {
+				log.Debug("Deleting branch", "branchName", branchName)
+				if err := m.git.DeleteBranch(m.appConfig.BareRepoPath, branchName, force); err != nil {
+					log.Warn("Failed to delete branch", "branchName", branchName, "error", err)
+				}
+			}
+		})
 
 		if err != nil {
-			log.SetOutput(os.Stderr)
-			output := logBuffer.String()
 			// Ensure spinner shows for at least minDisplayTime before showing error
 			elapsed := time.Since(startTime)
 			if elapsed < minDisplayTime {
@@ -589,20 +961,6 @@ func (m Model) performDelete(worktreePath, worktreeName, branchName string, forc
 			}
 		}
 
-		log.Debug("Worktree removed successfully")
-
-		if deleteBranch {
-			log.Debug("Deleting branch", "branchName", branchName)
-			err = git.DeleteBranch(m.appConfig.BareRepoPath, branchName, force)
-			if err != nil {
-				log.Warn("Failed to delete branch", "branchName", branchName, "error", err)
-			}
-		}
-
-		// Restore stderr as log output
-		log.SetOutput(os.Stderr)
-		output := logBuffer.String()
-
 		// Ensure spinner shows for at least minDisplayTime
 		elapsed := time.Since(startTime)
 		if elapsed < minDisplayTime {
@@ -637,15 +995,12 @@ func (m Model) performAdd(input string) tea.Cmd {
 		log.Debug("Adding worktree", "input", input, "branch", args[0])
 
 		// Configure the add service
-		cfg = services.SetConfigForAddService(cfg, args)
-
-		// Capture log output - write ONLY to buffer, not to stderr
-		var logBuffer bytes.Buffer
-		log.SetOutput(&logBuffer)
+		cfg = services.SetConfigForAddService(m.git, cfg, args)
 
-		// Call the add service with panic recovery
+		// Call the add service, capturing its log with panic recovery
+		var path string
 		var addErr error
-		func() {
+		output := captureLog(func() {
 			defer func() {
 				if r := recover(); r != nil {
 					log.Error("Panic during add worktree", "error", r)
@@ -656,14 +1011,8 @@ func (m Model) performAdd(input string) tea.Cmd {
 					}
 				}
 			}()
-			services.AddWorktree(m.connector, m.shell, cfg)
-		}()
-
-		// Restore stderr as log output
-		log.SetOutput(os.Stderr)
-
-		// Capture the output
-		output := logBuffer.String()
+			path, addErr = services.AddWorktree(m.git, m.connector, m.shell, cfg)
+		})
 
 		log.Debug("Worktree added successfully")
 
@@ -676,6 +1025,7 @@ func (m Model) performAdd(input string) tea.Cmd {
 		return addCompleteMsg{
 			err:        addErr,
 			branchName: cfg.NewBranchName,
+			path:       path,
 			output:     output,
 		}
 	}
@@ -716,9 +1066,14 @@ func parseAddCommand(input string, baseConfig config.AppConfig) ([]string, confi
 			case "-p", "--pull":
 				cfg.PullBeforeCuttingNewBranch = true
 			case "-c", "--cursor":
-				cfg.CursorConnect = true
+				cfg.OpenEditor = "cursor"
 			case "-v", "--vscode":
-				cfg.VsCodeConnect = true
+				cfg.OpenEditor = "code"
+			case "-o", "--open":
+				if i+1 < len(parts) && !strings.HasPrefix(parts[i+1], "-") {
+					cfg.OpenEditor = parts[i+1]
+					i++
+				}
 			case "-x", "--script":
 				cfg.RunPostScript = true
 			case "-f", "--from":
@@ -758,6 +1113,11 @@ func parseAddCommand(input string, baseConfig config.AppConfig) ([]string, confi
 		return nil, cfg, nil
 	}
 
+	// Catch template and branchNamePattern problems while the input is still open
+	if _, err := services.ResolveNewBranchName(cfg, branchName); err != nil {
+		return nil, cfg, err
+	}
+
 	args = []string{branchName}
 	return args, cfg, nil
 }
@@ -827,7 +1187,7 @@ func (m *Model) updateLogsViewport() {
 // fetchBranchesForSelection fetches the list of branches for selection
 func (m Model) fetchBranchesForSelection() tea.Cmd {
 	return func() tea.Msg {
-		worktrees, err := git.ListWorktrees(m.pendingAddConfig.BareRepoPath)
+		worktrees, err := m.git.ListWorktrees(m.pendingAddConfig.BareRepoPath)
 		if err != nil {
 			log.Error("Failed to fetch worktrees", "error", err)
 			return addErrorMsg{err: err, branchName: m.addingBranchName}
@@ -838,6 +1198,9 @@ func (m Model) fetchBranchesForSelection() tea.Cmd {
 
 		var branchStrings []string
 		for _, wt := range worktreeObjects {
+			if wt.Detached {
+				continue
+			}
 			branchStrings = append(branchStrings, wt.BranchName)
 		}
 
@@ -855,15 +1218,12 @@ func (m Model) performAddWithConfig(args []string, cfg config.AppConfig) tea.Cmd
 		log.Debug("Adding worktree with selected base branch", "branch", args[0], "baseBranch", cfg.BaseBranch)
 
 		// Configure the add service
-		cfg = services.SetConfigForAddService(cfg, args)
-
-		// Capture log output - write ONLY to buffer, not to stderr
-		var logBuffer bytes.Buffer
-		log.SetOutput(&logBuffer)
+		cfg = services.SetConfigForAddService(m.git, cfg, args)
 
-		// Call the add service with panic recovery, but skip the form part
+		// Call the add service, capturing its log with panic recovery, but skip the form part
+		var path string
 		var addErr error
-		func() {
+		output := captureLog(func() {
 			defer func() {
 				if r := recover(); r != nil {
 					log.Error("Panic during add worktree", "error", r)
@@ -876,14 +1236,8 @@ func (m Model) performAddWithConfig(args []string, cfg config.AppConfig) tea.Cmd
 			}()
 			// Call AddWorktree but the form won't show since BaseBranch is already set
 			cfg.UseFormToSetBaseBranch = false
-			services.AddWorktree(m.connector, m.shell, cfg)
-		}()
-
-		// Restore stderr as log output
-		log.SetOutput(os.Stderr)
-
-		// Capture the output
-		output := logBuffer.String()
+			path, addErr = services.AddWorktree(m.git, m.connector, m.shell, cfg)
+		})
 
 		log.Debug("Worktree added successfully")
 
@@ -896,6 +1250,7 @@ func (m Model) performAddWithConfig(args []string, cfg config.AppConfig) tea.Cmd
 		return addCompleteMsg{
 			err:        addErr,
 			branchName: cfg.NewBranchName,
+			path:       path,
 			output:     output,
 		}
 	}
@@ -5,8 +5,11 @@ package tui
 
 import (
 	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/garrettkrohn/treekanga/transformer"
 )
 
 // View renders the TUI based on the current model state
@@ -27,9 +30,14 @@ func (m Model) View() string {
 		return m.renderAddInputPopup(baseView)
 	}
 
-	// Show folder selection popup
-	if m.showFolderSelection {
-		return m.renderFolderSelectionPopup()
+	// Show rename input prompt
+	if m.showRenameInput {
+		return m.renderRenameInputPopup()
+	}
+
+	// Show folder or editor selection popup
+	if m.showFolderSelection || m.showEditorSelection {
+		return m.renderSelectionPopup()
 	}
 
 	// Show branch selection popup
@@ -68,6 +76,7 @@ func (m Model) renderSplitView() string {
 	// Combine vertically
 	splitView := lipgloss.JoinVertical(
 		lipgloss.Left,
+		m.renderHeader(),
 		tableView,
 		logsView,
 	)
@@ -76,6 +85,18 @@ func (m Model) renderSplitView() string {
 	return splitView + "\n" + helpText(m) + "\n"
 }
 
+// renderHeader renders the repo name and how long ago it was last fetched
+func (m Model) renderHeader() string {
+	repoStyle := lipgloss.NewStyle().Foreground(m.theme().Cyan).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme().MutedFg)
+
+	freshness := transformer.LastFetchAge(m.lastFetch, time.Now())
+	if m.backgroundFetching {
+		freshness = "fetching..."
+	}
+	return " " + repoStyle.Render(m.appConfig.RepoNameForConfig) + " " + mutedStyle.Render(freshness)
+}
+
 // renderLogsPane renders the logs section as a pane
 func (m Model) renderLogsPane() string {
 	// Style for the logs header
@@ -235,6 +256,21 @@ func (m Model) renderModalPopup() string {
 
 // renderAddInputPopup shows the add worktree input prompt as a popup
 func (m Model) renderAddInputPopup(background string) string {
+	return m.renderInputPopup("Add Worktree", "Enter command (e.g., branch_name -p -s client-ui):",
+		m.addInput.View(), m.addError, "Press Enter to add • ESC to cancel")
+}
+
+// renderRenameInputPopup shows the input for the selected worktree's new
+// branch name
+func (m Model) renderRenameInputPopup() string {
+	return m.renderInputPopup("Rename "+filepath.Base(m.renamingPath),
+		"Enter the new branch name, add --remote to rename it on origin too:",
+		m.renameInput.View(), m.renameError, "Press Enter to rename • ESC to cancel")
+}
+
+// renderInputPopup shows a centered popup with a text input, and the error
+// of the last attempt if there was one
+func (m Model) renderInputPopup(titleText, promptText, input, errText, hintText string) string {
 	titleStyle := lipgloss.NewStyle().
 		Foreground(m.theme().Accent).
 		Bold(true)
@@ -242,22 +278,22 @@ func (m Model) renderAddInputPopup(background string) string {
 	messageStyle := lipgloss.NewStyle().
 		Foreground(m.theme().TextFg)
 
-	title := titleStyle.Render("Add Worktree")
-	prompt := messageStyle.Render("Enter command (e.g., branch_name -p -s client-ui):")
+	title := titleStyle.Render(titleText)
+	prompt := messageStyle.Render(promptText)
 
 	// Show error if present
 	errorMsg := ""
-	if m.addError != "" {
+	if errText != "" {
 		errorStyle := lipgloss.NewStyle().
 			Foreground(m.theme().ErrorFg).
 			Bold(true)
-		errorMsg = "\n" + errorStyle.Render("⚠ Error: "+m.addError) + "\n"
+		errorMsg = "\n" + errorStyle.Render("⚠ Error: "+errText) + "\n"
 	}
 
 	content := fmt.Sprintf("\n%s\n\n%s\n\n%s\n%s",
 		title,
 		prompt,
-		m.addInput.View(),
+		input,
 		errorMsg)
 
 	hintStyle := lipgloss.NewStyle().
@@ -265,7 +301,7 @@ func (m Model) renderAddInputPopup(background string) string {
 		Italic(true).
 		Align(lipgloss.Center)
 
-	hint := hintStyle.Render("\nPress Enter to add • ESC to cancel")
+	hint := hintStyle.Render("\n" + hintText)
 
 	fullContent := content + hint
 
@@ -344,8 +380,11 @@ func helpText(m Model) string {
 			m.renderKeyHint("a", "Add"),
 			m.renderKeyHint("o", "Open"),
 			m.renderKeyHint("O", "Open options"),
+			m.renderKeyHint("e", "Editor"),
+			m.renderKeyHint("r", "Rename"),
 			m.renderKeyHint("d", "Delete"),
 			m.renderKeyHint("D", "Delete+Branch"),
+			m.renderKeyHint("s", "Sync"),
 			m.renderKeyHint("l", "Focus logs"),
 			m.renderKeyHint("q", "Quit"),
 		}
@@ -420,8 +459,9 @@ func (m Model) renderBranchSelectionPopup() string {
 	)
 }
 
-// renderFolderSelectionPopup shows a centered popup for selecting folder to connect to
-func (m Model) renderFolderSelectionPopup() string {
+// renderSelectionPopup shows a centered popup for selecting the folder to
+// connect to or the editor to open
+func (m Model) renderSelectionPopup() string {
 	// Create popup (60% width, 70% height to leave visible margins)
 	popupWidth := (m.termWidth * 3) / 5
 	popupHeight := (m.termHeight * 7) / 10
@@ -26,6 +26,17 @@ func ParseWorktrees(worktreeStrings []string) []models.Worktree {
 		// Get folder name from path
 		folder := strings.Split(fullPath, "/")[len(strings.Split(fullPath, "/"))-1]
 		
+		// Detached worktrees have no branch
+		if parts[2] == "(detached" {
+			worktrees = append(worktrees, models.Worktree{
+				FullPath:   fullPath,
+				Folder:     folder,
+				CommitHash: commitHash,
+				Detached:   true,
+			})
+			continue
+		}
+
 		// Remove brackets from branch name
 		branchName := strings.Trim(parts[2], "[]")
 